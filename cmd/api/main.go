@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/dispatch"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// defaultAddr keeps the API on this machine unless -addr says otherwise
+const defaultAddr = "127.0.0.1:8089"
+
+func main() {
+	var dbPath string
+	var addr string
+	var configPath string
+
+	flag.StringVar(&dbPath, "db", "", "Path to database (defaults to the configured database)")
+	flag.StringVar(&configPath, "config", "", "Config file (defaults to $MEDIA_BASE/pipeline/config.yaml)")
+	flag.StringVar(&addr, "addr", defaultAddr, "Address to listen on")
+	flag.Parse()
+
+	if err := run(dbPath, configPath, addr); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dbPath, configPath, addr string) error {
+	cfg, err := loadConfig(dbPath, configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if dbPath == "" {
+		dbPath = cfg.DatabasePath()
+	}
+	if cfg.APIToken == "" {
+		log.Printf("api_token is not set, POST endpoints are disabled")
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+
+	log.Printf("Serving pipeline status on http://%s", addr)
+	return http.ListenAndServe(addr, newHandler(repo, cfg, dispatch.NewDispatcher(cfg)))
+}
+
+// loadConfig loads the API's config. Without a config the status endpoints
+// still work when -db is given, but starting jobs stays disabled as there is
+// no token. Only a missing default config falls back like that: a config
+// given with -config, or one that exists but is broken, has to load.
+func loadConfig(dbPath, configPath string) (*config.Config, error) {
+	cfg, err := config.LoadFromFlag(configPath)
+	if err != nil {
+		if dbPath == "" || configPath != "" || !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		return &config.Config{}, nil
+	}
+	return cfg, nil
+}
+
+// server answers the status endpoints and starts jobs
+type server struct {
+	repo       db.Repository
+	cfg        *config.Config
+	dispatcher *dispatch.Dispatcher
+}
+
+// newHandler returns the API routes. GET endpoints are open; POST endpoints
+// start jobs and require cfg.APIToken.
+func newHandler(repo db.Repository, cfg *config.Config, dispatcher *dispatch.Dispatcher) http.Handler {
+	s := &server{repo: repo, cfg: cfg, dispatcher: dispatcher}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /items", s.listItems)
+	mux.HandleFunc("GET /items/{id}", s.getItem)
+	mux.HandleFunc("GET /jobs/{id}", s.getJob)
+	mux.HandleFunc("GET /jobs/{id}/log", s.getJobLog)
+	mux.HandleFunc("POST /items/{id}/advance", s.authorized(s.advanceItem))
+	mux.HandleFunc("POST /items/{id}/rip", s.authorized(s.ripItem))
+	return mux
+}
+
+// itemList is the response of GET /items
+type itemList struct {
+	Total int            `json:"total"` // Items matching the filters, ignoring limit and offset
+	Items []itemResponse `json:"items"`
+}
+
+// listItems lists media items. Query parameters: search, type (movie or tv),
+// active (true for items with a pending or running job), limit and offset.
+func (s *server) listItems(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := db.ListOptions{
+		Search:     q.Get("search"),
+		ActiveOnly: q.Get("active") == "true",
+	}
+	if t := q.Get("type"); t != "" {
+		mediaType := model.MediaType(t)
+		if mediaType != model.MediaTypeMovie && mediaType != model.MediaTypeTV {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unknown type %q", t))
+			return
+		}
+		opts.Type = &mediaType
+	}
+	for name, dst := range map[string]*int{"limit": &opts.Limit, "offset": &opts.Offset} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s %q", name, v))
+				return
+			}
+			*dst = n
+		}
+	}
+
+	ctx := r.Context()
+	total, err := s.repo.CountMediaItems(ctx, opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	items, err := s.repo.ListMediaItems(ctx, opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	list := itemList{Total: total, Items: make([]itemResponse, 0, len(items))}
+	for i := range items {
+		list.Items = append(list.Items, newItemResponse(&items[i]))
+	}
+	writeJSON(w, list)
+}
+
+// getItem returns an item with its seasons, jobs and recent log events
+func (s *server) getItem(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return
+	}
+	record, err := s.repo.GetItemFull(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if record == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("media item %d not found", id))
+		return
+	}
+	writeJSON(w, newItemDetail(record))
+}
+
+// getJob returns a single job
+func (s *server) getJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.lookupJob(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, newJobResponse(job))
+}
+
+// getJobLog returns the job's log file as plain text
+func (s *server) getJobLog(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.lookupJob(w, r)
+	if !ok {
+		return
+	}
+
+	path := job.LogPath
+	if path == "" {
+		path = s.cfg.JobLogPath(job.ID)
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Jobs dispatched over SSH log on the host that ran them
+		writeError(w, http.StatusNotFound, fmt.Errorf("log for job %d not found on this host", job.ID))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.Copy(w, f)
+}
+
+// lookupJob loads the job named in the path, writing the error response if
+// there is none
+func (s *server) lookupJob(w http.ResponseWriter, r *http.Request) (*model.Job, bool) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return nil, false
+	}
+	job, err := s.repo.GetJob(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, false
+	}
+	if job == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("job %d not found", id))
+		return nil, false
+	}
+	return job, true
+}
+
+// pathID parses the {id} path segment, writing a 400 if it isn't a positive integer
+func pathID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid id %q", r.PathValue("id")))
+		return 0, false
+	}
+	return id, true
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		log.Printf("failed to encode response: %v", err)
+	}
+}
+
+// writeError writes err as a JSON error body with status
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/dispatch"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestAPI(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception"}
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Show", SafeName: "Show"}
+	for _, item := range []*model.MediaItem{movie, show} {
+		if err := repo.CreateMediaItem(ctx, item); err != nil {
+			t.Fatalf("CreateMediaItem() error = %v", err)
+		}
+	}
+	job := &model.Job{MediaItemID: movie.ID, Stage: model.StageRip, Status: model.JobStatusCompleted}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	noLog := &model.Job{MediaItemID: show.ID, Stage: model.StageRip, Status: model.JobStatusPending}
+	if err := repo.CreateJob(ctx, noLog); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	if err := repo.UpdateMediaItemStage(ctx, movie.ID, model.StageTranscode, model.StatusCompleted); err != nil {
+		t.Fatalf("UpdateMediaItemStage() error = %v", err)
+	}
+
+	t.Setenv("MEDIA_BASE", t.TempDir())
+	cfg := &config.Config{}
+	if err := cfg.EnsureJobLogDir(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfg.JobLogPath(job.ID), []byte("[INFO] Rip complete\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(newHandler(repo, cfg, dispatch.NewDispatcher(cfg)))
+	defer srv.Close()
+
+	get := func(t *testing.T, path string, wantStatus int) []byte {
+		t.Helper()
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("reading GET %s: %v", path, err)
+		}
+		if resp.StatusCode != wantStatus {
+			t.Fatalf("GET %s status = %d, want %d: %s", path, resp.StatusCode, wantStatus, body)
+		}
+		return body
+	}
+
+	t.Run("items", func(t *testing.T) {
+		var list itemList
+		if err := json.Unmarshal(get(t, "/items?type=movie", http.StatusOK), &list); err != nil {
+			t.Fatal(err)
+		}
+		if list.Total != 1 || len(list.Items) != 1 || list.Items[0].Name != "Inception" {
+			t.Errorf("GET /items?type=movie = %+v", list)
+		}
+		if got := list.Items[0]; got.CurrentStage != "transcode" || got.StageStatus != model.StatusCompleted || got.Status != model.ItemStatusNotStarted {
+			t.Errorf("GET /items?type=movie state = %s, %s/%s; want not_started, transcode/completed",
+				got.Status, got.CurrentStage, got.StageStatus)
+		}
+
+		if err := json.Unmarshal(get(t, "/items?limit=1", http.StatusOK), &list); err != nil {
+			t.Fatal(err)
+		}
+		if list.Total != 2 || len(list.Items) != 1 {
+			t.Errorf("GET /items?limit=1 total = %d, items = %d, want 2 and 1", list.Total, len(list.Items))
+		}
+
+		get(t, "/items?type=music", http.StatusBadRequest)
+	})
+
+	t.Run("item", func(t *testing.T) {
+		body := get(t, fmt.Sprintf("/items/%d", movie.ID), http.StatusOK)
+		var record itemDetail
+		if err := json.Unmarshal(body, &record); err != nil {
+			t.Fatal(err)
+		}
+		if record.Item.Name != "Inception" || record.Item.CurrentStage != "transcode" || len(record.Jobs) != 1 {
+			t.Errorf("GET /items/%d = %+v", movie.ID, record)
+		}
+		for _, key := range []string{`"safe_name": `, `"stage_status": "completed"`, `"stage": "rip"`} {
+			if !strings.Contains(string(body), key) {
+				t.Errorf("GET /items/%d missing %s:\n%s", movie.ID, key, body)
+			}
+		}
+		get(t, "/items/999", http.StatusNotFound)
+		get(t, "/items/abc", http.StatusBadRequest)
+	})
+
+	t.Run("job", func(t *testing.T) {
+		var got jobResponse
+		if err := json.Unmarshal(get(t, fmt.Sprintf("/jobs/%d", job.ID), http.StatusOK), &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != job.ID || got.Stage != "rip" {
+			t.Errorf("GET /jobs/%d = %+v", job.ID, got)
+		}
+		get(t, "/jobs/999", http.StatusNotFound)
+	})
+
+	t.Run("job log", func(t *testing.T) {
+		if body := string(get(t, fmt.Sprintf("/jobs/%d/log", job.ID), http.StatusOK)); body != "[INFO] Rip complete\n" {
+			t.Errorf("GET /jobs/%d/log = %q", job.ID, body)
+		}
+		get(t, fmt.Sprintf("/jobs/%d/log", noLog.ID), http.StatusNotFound)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		resp, err := http.Post(srv.URL+"/items", "application/json", strings.NewReader("{}"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("POST /items status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// recordingRunner records dispatched commands instead of running them
+type recordingRunner struct {
+	started []string
+	err     error // Returned by Start
+}
+
+func (r *recordingRunner) Start(name string, args []string) error {
+	r.started = append(r.started, name+" "+strings.Join(args, " "))
+	return r.err
+}
+
+func (r *recordingRunner) Run(ctx context.Context, name string, args []string) (string, error) {
+	return "", nil
+}
+
+func TestAPI_StartJobs(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception"}
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Show", SafeName: "Show"}
+	for _, item := range []*model.MediaItem{movie, show} {
+		if err := repo.CreateMediaItem(ctx, item); err != nil {
+			t.Fatalf("CreateMediaItem() error = %v", err)
+		}
+	}
+	season := &model.Season{ItemID: show.ID, Number: 1, CurrentStage: model.StageRip, StageStatus: model.StatusPending}
+	if err := repo.CreateSeason(ctx, season); err != nil {
+		t.Fatalf("CreateSeason() error = %v", err)
+	}
+
+	t.Setenv("MEDIA_BASE", t.TempDir())
+	cfg := &config.Config{APIToken: "secret"}
+	runner := &recordingRunner{}
+	dispatcher := dispatch.NewDispatcher(cfg)
+	dispatcher.SetCommandRunner(runner)
+
+	srv := httptest.NewServer(newHandler(repo, cfg, dispatcher))
+	defer srv.Close()
+
+	post := func(t *testing.T, path, token string, wantStatus int) []byte {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, srv.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST %s error = %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("reading POST %s: %v", path, err)
+		}
+		if resp.StatusCode != wantStatus {
+			t.Fatalf("POST %s status = %d, want %d: %s", path, resp.StatusCode, wantStatus, body)
+		}
+		return body
+	}
+	moviePath := func(action string) string { return fmt.Sprintf("/items/%d/%s", movie.ID, action) }
+
+	t.Run("token required", func(t *testing.T) {
+		post(t, moviePath("rip"), "", http.StatusUnauthorized)
+		post(t, moviePath("rip"), "wrong", http.StatusUnauthorized)
+		if len(runner.started) != 0 {
+			t.Errorf("started %v without a valid token", runner.started)
+		}
+	})
+
+	t.Run("rip and advance movie", func(t *testing.T) {
+		var rip jobResponse
+		if err := json.Unmarshal(post(t, moviePath("rip"), "secret", http.StatusAccepted), &rip); err != nil {
+			t.Fatal(err)
+		}
+		if rip.ID == 0 || rip.Stage != "rip" || rip.Status != model.JobStatusPending {
+			t.Errorf("rip job = %+v", rip)
+		}
+		want := fmt.Sprintf("ripper -job-id %d -db %s", rip.ID, cfg.DatabasePath())
+		if len(runner.started) != 1 || runner.started[0] != want {
+			t.Errorf("started = %v, want [%s]", runner.started, want)
+		}
+
+		// The rip is still pending
+		post(t, moviePath("rip"), "secret", http.StatusConflict)
+		post(t, moviePath("advance"), "secret", http.StatusConflict)
+
+		// Organizing is manual
+		if err := repo.UpdateJobStatus(ctx, rip.ID, model.JobStatusCompleted, ""); err != nil {
+			t.Fatal(err)
+		}
+		post(t, moviePath("advance"), "secret", http.StatusConflict)
+
+		organize := &model.Job{MediaItemID: movie.ID, Stage: model.StageOrganize, Status: model.JobStatusCompleted}
+		if err := repo.CreateJob(ctx, organize); err != nil {
+			t.Fatal(err)
+		}
+		var remux jobResponse
+		if err := json.Unmarshal(post(t, moviePath("advance"), "secret", http.StatusAccepted), &remux); err != nil {
+			t.Fatal(err)
+		}
+		if remux.Stage != "remux" {
+			t.Errorf("advance started %s, want remux", remux.Stage)
+		}
+	})
+
+	t.Run("season needs a number", func(t *testing.T) {
+		post(t, fmt.Sprintf("/items/%d/rip", show.ID), "secret", http.StatusBadRequest)
+		post(t, fmt.Sprintf("/items/%d/rip?season=2", show.ID), "secret", http.StatusNotFound)
+
+		var rip jobResponse
+		if err := json.Unmarshal(post(t, fmt.Sprintf("/items/%d/rip?season=1", show.ID), "secret", http.StatusAccepted), &rip); err != nil {
+			t.Fatal(err)
+		}
+		if rip.SeasonID == nil || *rip.SeasonID != season.ID || rip.Disc == nil || *rip.Disc != 1 {
+			t.Errorf("season rip job = %+v", rip)
+		}
+		got, err := repo.GetSeason(ctx, season.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.StageStatus != model.StatusInProgress {
+			t.Errorf("season status = %s, want in_progress", got.StageStatus)
+		}
+	})
+
+	t.Run("dispatch failure fails the job", func(t *testing.T) {
+		other := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Heat", SafeName: "Heat"}
+		if err := repo.CreateMediaItem(ctx, other); err != nil {
+			t.Fatal(err)
+		}
+		runner.err = errors.New("exec: no such file")
+		defer func() { runner.err = nil }()
+
+		post(t, fmt.Sprintf("/items/%d/rip", other.ID), "secret", http.StatusBadGateway)
+		jobs, err := repo.ListJobsForMedia(ctx, other.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(jobs) != 1 || jobs[0].Status != model.JobStatusFailed {
+			t.Errorf("jobs = %+v, want one failed job", jobs)
+		}
+	})
+}
+
+func TestAPI_StartJobsDisabledWithoutToken(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	cfg := &config.Config{}
+	srv := httptest.NewServer(newHandler(db.NewSQLiteRepository(database), cfg, dispatch.NewDispatcher(cfg)))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/items/1/rip", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer ")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("POST without a configured token status = %d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("MEDIA_BASE", t.TempDir())
+
+	// With -db, a missing default config serves status with POST disabled
+	cfg, err := loadConfig("/tmp/pipeline.db", "")
+	if err != nil || cfg == nil || cfg.APIToken != "" {
+		t.Errorf("loadConfig() = %v, %v; want defaults", cfg, err)
+	}
+	if _, err := loadConfig("", ""); err == nil {
+		t.Error("loadConfig() without -db or a config should fail")
+	}
+
+	// A config given with -config has to load, even with -db
+	broken := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(broken, []byte("api_token: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig("/tmp/pipeline.db", broken); err == nil {
+		t.Error("loadConfig() of a broken -config file should fail")
+	}
+}
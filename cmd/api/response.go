@@ -0,0 +1,141 @@
+package main
+
+import (
+	"time"
+
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// The API answers with these types rather than the model's, so the JSON
+// has stable snake_case names and stages by name
+
+// itemResponse is a media item; Seasons is set for TV shows
+type itemResponse struct {
+	ID           int64            `json:"id"`
+	Type         model.MediaType  `json:"type"`
+	Name         string           `json:"name"`
+	SafeName     string           `json:"safe_name"`
+	Edition      string           `json:"edition,omitempty"`
+	RipMode      model.RipMode    `json:"rip_mode"`
+	TmdbID       *int             `json:"tmdb_id,omitempty"`
+	TvdbID       *int             `json:"tvdb_id,omitempty"`
+	Status       model.ItemStatus `json:"status"`
+	CurrentStage string           `json:"current_stage"`
+	StageStatus  model.Status     `json:"stage_status"`
+	Seasons      []seasonResponse `json:"seasons,omitempty"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// seasonResponse is a TV season
+type seasonResponse struct {
+	ID               int64        `json:"id"`
+	Number           int          `json:"number"`
+	CurrentStage     string       `json:"current_stage"`
+	StageStatus      model.Status `json:"stage_status"`
+	ExpectedEpisodes int          `json:"expected_episodes,omitempty"`
+	ExpectedDiscs    int          `json:"expected_discs,omitempty"`
+}
+
+// jobResponse is a single stage run
+type jobResponse struct {
+	ID           int64           `json:"id"`
+	MediaItemID  int64           `json:"media_item_id"`
+	SeasonID     *int64          `json:"season_id,omitempty"`
+	Stage        string          `json:"stage"`
+	Status       model.JobStatus `json:"status"`
+	Disc         *int            `json:"disc,omitempty"`
+	Worker       string          `json:"worker,omitempty"`
+	Progress     int             `json:"progress"`
+	InputDir     string          `json:"input_dir,omitempty"`
+	OutputDir    string          `json:"output_dir,omitempty"`
+	Error        string          `json:"error,omitempty"`
+	SupersededBy *int64          `json:"superseded_by,omitempty"`
+	StartedAt    *time.Time      `json:"started_at,omitempty"`
+	CompletedAt  *time.Time      `json:"completed_at,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// logEventResponse is a log line recorded for a job
+type logEventResponse struct {
+	JobID     int64     `json:"job_id"`
+	Level     string    `json:"level"`
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// itemDetail is the response of GET /items/{id}
+type itemDetail struct {
+	Item      itemResponse       `json:"item"`
+	Jobs      []jobResponse      `json:"jobs"`       // Oldest first
+	LogEvents []logEventResponse `json:"log_events"` // Most recent events of each job
+}
+
+func newItemResponse(item *model.MediaItem) itemResponse {
+	resp := itemResponse{
+		ID:           item.ID,
+		Type:         item.Type,
+		Name:         item.Name,
+		SafeName:     item.SafeName,
+		Edition:      item.Edition,
+		RipMode:      item.RipMode,
+		TmdbID:       item.TmdbID,
+		TvdbID:       item.TvdbID,
+		Status:       item.ItemStatus,
+		CurrentStage: item.CurrentStage.String(),
+		StageStatus:  item.StageStatus,
+		CreatedAt:    item.CreatedAt,
+		UpdatedAt:    item.UpdatedAt,
+	}
+	for _, s := range item.Seasons {
+		resp.Seasons = append(resp.Seasons, seasonResponse{
+			ID:               s.ID,
+			Number:           s.Number,
+			CurrentStage:     s.CurrentStage.String(),
+			StageStatus:      s.StageStatus,
+			ExpectedEpisodes: s.ExpectedEpisodes,
+			ExpectedDiscs:    s.ExpectedDiscs,
+		})
+	}
+	return resp
+}
+
+func newJobResponse(job *model.Job) jobResponse {
+	return jobResponse{
+		ID:           job.ID,
+		MediaItemID:  job.MediaItemID,
+		SeasonID:     job.SeasonID,
+		Stage:        job.Stage.String(),
+		Status:       job.Status,
+		Disc:         job.Disc,
+		Worker:       job.WorkerID,
+		Progress:     job.Progress,
+		InputDir:     job.InputDir,
+		OutputDir:    job.OutputDir,
+		Error:        job.ErrorMessage,
+		SupersededBy: job.SupersededBy,
+		StartedAt:    job.StartedAt,
+		CompletedAt:  job.CompletedAt,
+		CreatedAt:    job.CreatedAt,
+	}
+}
+
+func newItemDetail(record *model.ItemRecord) itemDetail {
+	detail := itemDetail{
+		Item:      newItemResponse(&record.Item),
+		Jobs:      []jobResponse{},
+		LogEvents: []logEventResponse{},
+	}
+	for i := range record.Jobs {
+		detail.Jobs = append(detail.Jobs, newJobResponse(&record.Jobs[i]))
+	}
+	for _, e := range record.LogEvents {
+		detail.LogEvents = append(detail.LogEvents, logEventResponse{
+			JobID:     e.JobID,
+			Level:     e.Level,
+			Message:   e.Message,
+			Timestamp: e.Timestamp,
+		})
+	}
+	return detail
+}
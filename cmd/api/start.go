@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cuivienor/media-pipeline/internal/dispatch"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// authorized wraps a handler that starts jobs, requiring the configured API
+// token as a bearer token. Without a configured token every request is refused.
+func (s *server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.cfg.APIToken == "" {
+			writeError(w, http.StatusForbidden, errors.New("starting jobs is disabled: set api_token in the config"))
+			return
+		}
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.APIToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, errors.New("missing or invalid API token"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// advanceItem starts the stage after the item's last completed one. TV shows
+// take the season number in the season query parameter. Organizing is manual,
+// so an item waiting on it is refused.
+func (s *server) advanceItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	item, season, ok := s.lookupTarget(w, r)
+	if !ok {
+		return
+	}
+
+	stage, status, err := s.currentStage(ctx, item, season)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	switch {
+	case status != model.StatusCompleted:
+		writeError(w, http.StatusConflict, fmt.Errorf("%s is %s, not completed", stage, status))
+		return
+	case stage == model.StagePublish:
+		writeError(w, http.StatusConflict, errors.New("already published"))
+		return
+	case stage.NextStage() == model.StageOrganize:
+		writeError(w, http.StatusConflict, errors.New("organize is done in the TUI"))
+		return
+	}
+
+	job := &model.Job{MediaItemID: item.ID, Stage: stage.NextStage(), Status: model.JobStatusPending}
+	if season != nil {
+		job.SeasonID = &season.ID
+	}
+	s.startJob(w, r, job, true)
+}
+
+// ripItem starts a rip. A TV season (the season query parameter) is ripped
+// one disc at a time, each call ripping the disc after the last one started.
+func (s *server) ripItem(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	item, season, ok := s.lookupTarget(w, r)
+	if !ok {
+		return
+	}
+
+	jobs, err := s.repo.ListJobsForMedia(ctx, item.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// As in the TUI, a movie ripped again is ripping its next disc
+	job := &model.Job{
+		MediaItemID: item.ID,
+		Stage:       model.StageRip,
+		Status:      model.JobStatusPending,
+		Disc:        dispatch.NextRipDisc(jobs, nil),
+	}
+	markStage := item.StageStatus == model.StatusPending || item.StageStatus == model.StatusCompleted
+	if season != nil {
+		job.SeasonID = &season.ID
+		job.Disc = dispatch.NextRipDisc(jobs, &season.ID)
+		markStage = season.StageStatus == model.StatusPending || season.StageStatus == model.StatusCompleted
+	}
+	s.startJob(w, r, job, markStage)
+}
+
+// lookupTarget loads the item named in the path and, for TV shows, the season
+// named by the season query parameter, writing the error response if either
+// is missing
+func (s *server) lookupTarget(w http.ResponseWriter, r *http.Request) (*model.MediaItem, *model.Season, bool) {
+	id, ok := pathID(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	ctx := r.Context()
+	item, err := s.repo.GetMediaItem(ctx, id)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, nil, false
+	}
+	if item == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("media item %d not found", id))
+		return nil, nil, false
+	}
+	if item.Type != model.MediaTypeTV {
+		return item, nil, true
+	}
+
+	number, err := strconv.Atoi(r.URL.Query().Get("season"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%s is a TV show, pass the season number as ?season=N", item.Name))
+		return nil, nil, false
+	}
+	season, err := s.repo.GetSeasonByNumber(ctx, item.ID, number)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return nil, nil, false
+	}
+	if season == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%s has no season %d", item.Name, number))
+		return nil, nil, false
+	}
+	return item, season, true
+}
+
+// currentStage returns the stage a season is at, or for a movie the stage
+// and outcome of its latest job unless overridden since, as the TUI shows
+// them
+func (s *server) currentStage(ctx context.Context, item *model.MediaItem, season *model.Season) (model.Stage, model.Status, error) {
+	if season != nil {
+		return season.CurrentStage, season.StageStatus, nil
+	}
+	jobs, err := s.repo.ListJobsForMedia(ctx, item.ID)
+	if err != nil {
+		return 0, "", err
+	}
+	stage, status := item.JobStage(jobs)
+	return stage, status, nil
+}
+
+// startJob creates job and dispatches it, answering 202 with the job. It
+// refuses when a job for the same stage is already active or the previous
+// stage hasn't completed. If markStage is set, the item or season is moved
+// to the job's stage, in progress.
+func (s *server) startJob(w http.ResponseWriter, r *http.Request, job *model.Job, markStage bool) {
+	ctx := r.Context()
+
+	// Fail fast if the dispatch target is down, before creating a job
+	target := s.cfg.DispatchTarget(job.Stage.String())
+	if err := s.dispatcher.CheckTarget(ctx, target); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	if err := dispatch.CreateJob(ctx, s.repo, job, markStage); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, dispatch.ErrJobActive) || errors.Is(err, dispatch.ErrStageNotReady) {
+			status = http.StatusConflict
+		}
+		writeError(w, status, err)
+		return
+	}
+
+	// The job exists now, so it is launched even if the client goes away
+	if err := s.dispatcher.Launch(ctx, s.repo, job, target); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	writeJSON(w, newJobResponse(job))
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+)
+
+func main() {
+	var dbPath string
+	var itemID int64
+	var outPath string
+	var configPath string
+
+	flag.StringVar(&dbPath, "db", "", "Path to database (defaults to the configured database)")
+	flag.StringVar(&configPath, "config", "", "Config file (defaults to $MEDIA_BASE/pipeline/config.yaml)")
+	flag.Int64Var(&itemID, "item-id", 0, "Media item ID to export (required)")
+	flag.StringVar(&outPath, "o", "", "Write the JSON record to this file instead of stdout")
+	flag.Parse()
+
+	if itemID <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: -item-id is required")
+		fmt.Fprintln(os.Stderr, "Usage: export -item-id <id> [-db <path> | -config <path>] [-o <file>]")
+		os.Exit(1)
+	}
+
+	if err := run(dbPath, configPath, itemID, outPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dbPath, configPath string, itemID int64, outPath string) error {
+	ctx := context.Background()
+
+	if dbPath == "" {
+		cfg, err := config.LoadFromFlag(configPath)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		dbPath = cfg.DatabasePath()
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+
+	if outPath == "" {
+		return export(ctx, repo, itemID, os.Stdout)
+	}
+
+	f, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	if err := export(ctx, repo, itemID, f); err != nil {
+		f.Close()
+		os.Remove(outPath)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// export writes the item's full record to w as indented JSON
+func export(ctx context.Context, repo db.Repository, itemID int64, w io.Writer) error {
+	record, err := repo.GetItemFull(ctx, itemID)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return fmt.Errorf("media item %d not found", itemID)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(record); err != nil {
+		return fmt.Errorf("failed to encode record: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestExport(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	job := &model.Job{MediaItemID: item.ID, Stage: model.StageRemux, Status: model.JobStatusCompleted, OutputDir: "/staging/2-remuxed/movies/Movie"}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := export(ctx, repo, item.ID, &buf); err != nil {
+		t.Fatalf("export() error = %v", err)
+	}
+
+	var got model.ItemRecord
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not a valid record: %v\n%s", err, buf.String())
+	}
+	if got.Item.Name != "Movie" || len(got.Jobs) != 1 {
+		t.Fatalf("record = %+v, want Movie with one job", got)
+	}
+	if got.Jobs[0].Stage != model.StageRemux || got.Jobs[0].OutputDir != job.OutputDir {
+		t.Errorf("job = %+v, want the remux job", got.Jobs[0])
+	}
+	if !strings.Contains(buf.String(), `"Stage": "remux"`) {
+		t.Errorf("stages should be exported by name:\n%s", buf.String())
+	}
+
+	if err := export(ctx, repo, item.ID+1, &buf); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("export(missing) error = %v, want not found", err)
+	}
+}
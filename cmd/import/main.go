@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func main() {
+	var dbPath string
+	var configPath string
+
+	flag.StringVar(&dbPath, "db", "", "Path to database (defaults to the configured database)")
+	flag.StringVar(&configPath, "config", "", "Config file (defaults to $MEDIA_BASE/pipeline/config.yaml)")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: import [-db <path> | -config <path>] <record.json>")
+		os.Exit(1)
+	}
+
+	if err := run(dbPath, configPath, flag.Arg(0)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dbPath, configPath, recordPath string) error {
+	ctx := context.Background()
+
+	data, err := os.ReadFile(recordPath)
+	if err != nil {
+		return fmt.Errorf("failed to read record: %w", err)
+	}
+	var record model.ItemRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return fmt.Errorf("failed to parse record: %w", err)
+	}
+
+	if dbPath == "" {
+		cfg, err := config.LoadFromFlag(configPath)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		dbPath = cfg.DatabasePath()
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+
+	result, err := importRecord(ctx, repo, &record)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported %s as item %d\n", result.Item.Name, result.Item.ID)
+	fmt.Printf("  Seasons: %d\n", len(result.Item.Seasons))
+	fmt.Printf("  Jobs:    %d\n", result.Jobs)
+	for _, w := range result.Warnings {
+		fmt.Printf("Warning: %s\n", w)
+	}
+	return nil
+}
+
+// importResult describes what importRecord created
+type importResult struct {
+	Item     model.MediaItem
+	Jobs     int
+	Warnings []string // Stage outputs of imported jobs missing on disk
+}
+
+// importRecord recreates the record's item, seasons and completed jobs under
+// new IDs. Failed and unfinished jobs are left behind, and a stage that was
+// in progress goes back to pending so it can be started again here.
+func importRecord(ctx context.Context, repo db.Repository, record *model.ItemRecord) (*importResult, error) {
+	src := record.Item
+	existing, err := repo.GetMediaItemBySafeName(ctx, src.SafeName, src.Season)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("item %s already exists as item %d", src.SafeName, existing.ID)
+	}
+
+	result := &importResult{}
+	err = repo.WithTx(ctx, func(tx db.Repository) error {
+		item := src
+		item.ID = 0
+		item.Seasons = nil
+		item.StageStatus = resetInProgress(item.StageStatus)
+		if err := tx.CreateMediaItem(ctx, &item); err != nil {
+			return err
+		}
+
+		// Old season ID -> new season ID
+		seasonIDs := make(map[int64]int64)
+		for _, s := range src.Seasons {
+			season := s
+			season.ID = 0
+			season.ItemID = item.ID
+			season.StageStatus = resetInProgress(season.StageStatus)
+			if err := tx.CreateSeason(ctx, &season); err != nil {
+				return err
+			}
+			seasonIDs[s.ID] = season.ID
+			item.Seasons = append(item.Seasons, season)
+		}
+
+		// Old job ID -> new job ID
+		jobIDs := make(map[int64]int64)
+		for _, j := range record.Jobs {
+			if j.Status != model.JobStatusCompleted {
+				continue
+			}
+			job := j
+			job.ID = 0
+			job.MediaItemID = item.ID
+			job.SupersededBy = nil
+			if j.SeasonID != nil {
+				newID, ok := seasonIDs[*j.SeasonID]
+				if !ok {
+					return fmt.Errorf("job %d belongs to season %d, which is not in the record", j.ID, *j.SeasonID)
+				}
+				job.SeasonID = &newID
+			}
+			if err := tx.CreateJob(ctx, &job); err != nil {
+				return err
+			}
+			jobIDs[j.ID] = job.ID
+			result.Jobs++
+
+			if opts := record.JobOptions[j.ID]; len(opts) > 0 {
+				if err := tx.SetJobOptions(ctx, job.ID, opts); err != nil {
+					return err
+				}
+			}
+
+			if job.OutputDir != "" {
+				if _, err := os.Stat(job.OutputDir); err != nil {
+					result.Warnings = append(result.Warnings,
+						fmt.Sprintf("output of %s job %d not found: %s", job.Stage, j.ID, job.OutputDir))
+				}
+			}
+		}
+
+		for _, f := range record.TranscodeFiles {
+			jobID, ok := jobIDs[f.JobID]
+			if !ok {
+				continue
+			}
+			file := f
+			file.ID = 0
+			file.JobID = jobID
+			if err := tx.CreateTranscodeFile(ctx, &file); err != nil {
+				return err
+			}
+			// Creating only records the input; copy the results over too
+			if err := tx.UpdateTranscodeFile(ctx, &file); err != nil {
+				return err
+			}
+		}
+
+		result.Item = item
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// resetInProgress turns an in-progress stage status into pending, as the job
+// running it is not imported
+func resetInProgress(status model.Status) model.Status {
+	if status == model.StatusInProgress {
+		return model.StatusPending
+	}
+	return status
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func newTestRepo(t *testing.T) db.Repository {
+	t.Helper()
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return db.NewSQLiteRepository(database)
+}
+
+func TestImportRecord(t *testing.T) {
+	ctx := context.Background()
+	ripped := t.TempDir()
+	seasonID := int64(7)
+
+	record := &model.ItemRecord{
+		Item: model.MediaItem{
+			ID: 42, Type: model.MediaTypeTV, Name: "Show", SafeName: "Show",
+			Seasons: []model.Season{
+				{ID: seasonID, ItemID: 42, Number: 1, CurrentStage: model.StageRemux, StageStatus: model.StatusInProgress},
+			},
+		},
+		Jobs: []model.Job{
+			{ID: 100, MediaItemID: 42, SeasonID: &seasonID, Stage: model.StageRip, Status: model.JobStatusCompleted, OutputDir: ripped},
+			{ID: 101, MediaItemID: 42, SeasonID: &seasonID, Stage: model.StageTranscode, Status: model.JobStatusCompleted, OutputDir: filepath.Join(ripped, "missing")},
+			{ID: 102, MediaItemID: 42, SeasonID: &seasonID, Stage: model.StageRemux, Status: model.JobStatusInProgress},
+		},
+		JobOptions: map[int64]map[string]interface{}{101: {"profile": "mobile"}},
+		TranscodeFiles: []model.TranscodeFile{
+			{ID: 5, JobID: 101, RelativePath: "S01E01.mkv", Status: model.TranscodeFileStatusCompleted, InputSize: 100, OutputSize: 40},
+		},
+	}
+
+	repo := newTestRepo(t)
+	result, err := importRecord(ctx, repo, record)
+	if err != nil {
+		t.Fatalf("importRecord() error = %v", err)
+	}
+
+	if result.Jobs != 2 {
+		t.Errorf("Jobs = %d, want the 2 completed jobs", result.Jobs)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "missing") {
+		t.Errorf("Warnings = %v, want one for the missing transcode output", result.Warnings)
+	}
+
+	got, err := repo.GetItemFull(ctx, result.Item.ID)
+	if err != nil {
+		t.Fatalf("GetItemFull() error = %v", err)
+	}
+	if len(got.Item.Seasons) != 1 {
+		t.Fatalf("Seasons = %d, want 1", len(got.Item.Seasons))
+	}
+	season := got.Item.Seasons[0]
+	if season.CurrentStage != model.StageRemux || season.StageStatus != model.StatusPending {
+		t.Errorf("season = %s/%s, want remux/pending", season.CurrentStage, season.StageStatus)
+	}
+	for _, job := range got.Jobs {
+		if job.SeasonID == nil || *job.SeasonID != season.ID {
+			t.Errorf("job %d season = %v, want %d", job.ID, job.SeasonID, season.ID)
+		}
+	}
+
+	transcodeID := got.Jobs[1].ID
+	if got.JobOptions[transcodeID]["profile"] != "mobile" {
+		t.Errorf("JobOptions = %v, want the transcode profile carried over", got.JobOptions)
+	}
+	if len(got.TranscodeFiles) != 1 || got.TranscodeFiles[0].JobID != transcodeID || got.TranscodeFiles[0].OutputSize != 40 {
+		t.Errorf("TranscodeFiles = %+v, want S01E01.mkv under job %d", got.TranscodeFiles, transcodeID)
+	}
+
+	// Importing the same item twice is refused
+	if _, err := importRecord(ctx, repo, record); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("second importRecord() error = %v, want already exists", err)
+	}
+}
+
+func TestImportRecord_ExportedMovie(t *testing.T) {
+	ctx := context.Background()
+
+	// Export a published movie the way cmd/export does
+	src := newTestRepo(t)
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	if err := src.CreateMediaItem(ctx, movie); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	publish := &model.Job{MediaItemID: movie.ID, Stage: model.StagePublish, Status: model.JobStatusCompleted}
+	if err := src.CreateJob(ctx, publish); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	if err := src.UpdateMediaItemStage(ctx, movie.ID, model.StagePublish, model.StatusCompleted); err != nil {
+		t.Fatalf("UpdateMediaItemStage() error = %v", err)
+	}
+	if err := src.UpdateMediaItemStatus(ctx, movie.ID, model.ItemStatusCompleted); err != nil {
+		t.Fatalf("UpdateMediaItemStatus() error = %v", err)
+	}
+	exported, err := src.GetItemFull(ctx, movie.ID)
+	if err != nil {
+		t.Fatalf("GetItemFull() error = %v", err)
+	}
+	data, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var record model.ItemRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	repo := newTestRepo(t)
+	result, err := importRecord(ctx, repo, &record)
+	if err != nil {
+		t.Fatalf("importRecord() error = %v", err)
+	}
+
+	got, err := repo.GetMediaItem(ctx, result.Item.ID)
+	if err != nil {
+		t.Fatalf("GetMediaItem() error = %v", err)
+	}
+	if got.ItemStatus != model.ItemStatusCompleted || got.CurrentStage != model.StagePublish || got.StageStatus != model.StatusCompleted {
+		t.Errorf("imported item = %s, %s/%s; want completed, publish/completed",
+			got.ItemStatus, got.CurrentStage, got.StageStatus)
+	}
+}
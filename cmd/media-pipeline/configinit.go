@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+)
+
+// runConfigInit writes a commented default config to configPath, or to
+// $MEDIA_BASE/pipeline/config.yaml if it is empty
+func runConfigInit(w io.Writer, configPath string, args []string) error {
+	fs := flag.NewFlagSet("config init", flag.ContinueOnError)
+	force := fs.Bool("force", false, "Overwrite an existing config file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	mediaBase := config.MediaBaseFromEnv()
+	path := configPath
+	if path == "" {
+		path = config.ConfigPath(mediaBase)
+	}
+	if err := config.WriteDefault(path, mediaBase, *force); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Wrote default config to %s\n", path)
+	return nil
+}
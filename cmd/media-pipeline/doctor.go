@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/ripper"
+)
+
+// doctorTool is an external tool run by one of the stages
+type doctorTool struct {
+	name    string
+	stage   string // Dispatch stage that runs it
+	path    string
+	version func(path string) (string, error)
+}
+
+// doctorTools lists the tools the stages need, at their configured paths
+func doctorTools(cfg *config.Config) []doctorTool {
+	return []doctorTool{
+		{name: "makemkvcon", stage: "rip", path: "makemkvcon", version: ripper.CheckMakeMKV},
+		{name: "mkvmerge", stage: "remux", path: "mkvmerge", version: versionFlag("--version")},
+		{name: "ffmpeg", stage: "transcode", path: cfg.FFmpegPath(), version: versionFlag("-version")},
+		{name: "ffprobe", stage: "transcode", path: cfg.FFprobePath(), version: versionFlag("-version")},
+		{name: "filebot", stage: "publish", path: "filebot", version: versionFlag("-version")},
+	}
+}
+
+// versionFlag returns a version check running the tool with arg and
+// reporting the first line it prints
+func versionFlag(arg string) func(path string) (string, error) {
+	return func(path string) (string, error) {
+		if _, err := exec.LookPath(path); err != nil {
+			return "", fmt.Errorf("not found: %w", err)
+		}
+		output, err := exec.Command(path, arg).CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("%s %s failed: %w", path, arg, err)
+		}
+		line, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+		return strings.TrimSpace(line), nil
+	}
+}
+
+// doctorPaths returns the directories the pipeline writes to on this host
+func doctorPaths(cfg *config.Config) []string {
+	paths := []string{cfg.DataDir()}
+	if cfg.StagingBase != "" {
+		paths = append(paths, cfg.StagingBase)
+	}
+	if cfg.LibraryBase != "" && cfg.IsLocal("publish") {
+		paths = append(paths, cfg.LibraryMoviesPath(), cfg.LibraryTVPath())
+		if movies4K := cfg.LibraryMovies4KPath(); movies4K != "" {
+			paths = append(paths, movies4K)
+		}
+	}
+	return paths
+}
+
+// checkWritable creates and removes a file in dir
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// runDoctor checks that every tool a local stage runs is installed and that
+// the pipeline directories are writable, printing one line per check. Tools
+// of stages dispatched over SSH are skipped, as they run on another host.
+// It returns false if anything failed.
+func runDoctor(w io.Writer, cfg *config.Config) bool {
+	ok := true
+
+	fmt.Fprintln(w, "Tools:")
+	for _, tool := range doctorTools(cfg) {
+		if target := cfg.DispatchTarget(tool.stage); target != "" {
+			fmt.Fprintf(w, "  %-8s %-11s %s runs on %s\n", "skipped", tool.name, tool.stage, target)
+			continue
+		}
+		version, err := tool.version(tool.path)
+		if err != nil {
+			ok = false
+			fmt.Fprintf(w, "  %-8s %-11s %v\n", "FAIL", tool.name, err)
+			continue
+		}
+		fmt.Fprintf(w, "  %-8s %-11s %s\n", "ok", tool.name, version)
+	}
+
+	fmt.Fprintln(w, "Paths:")
+	for _, dir := range doctorPaths(cfg) {
+		if err := checkWritable(dir); err != nil {
+			ok = false
+			fmt.Fprintf(w, "  %-8s %s: %v\n", "FAIL", dir, err)
+			continue
+		}
+		fmt.Fprintf(w, "  %-8s %s\n", "ok", dir)
+	}
+
+	return ok
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+)
+
+// writeTool writes an executable shell script named name into dir
+func writeTool(t *testing.T, dir, name, script string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunDoctor(t *testing.T) {
+	binDir := t.TempDir()
+	writeTool(t, binDir, "makemkvcon", `echo 'MSG:1005,0,1,"MakeMKV v1.17.7 linux(x64-release) started","%1 started","MakeMKV v1.17.7 linux(x64-release)"'`)
+	writeTool(t, binDir, "mkvmerge", `echo "mkvmerge v82.0 ('I'm The President') 64-bit"`)
+	writeTool(t, binDir, "ffmpeg", `echo "ffmpeg version 6.1.1"; echo "built with gcc"`)
+	writeTool(t, binDir, "ffprobe", `echo "ffprobe version 6.1.1"`)
+	t.Setenv("PATH", binDir)
+
+	mediaBase := t.TempDir()
+	t.Setenv("MEDIA_BASE", mediaBase)
+	if err := os.MkdirAll(filepath.Join(mediaBase, "pipeline"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("filebot missing", func(t *testing.T) {
+		var out bytes.Buffer
+		if runDoctor(&out, &config.Config{}) {
+			t.Errorf("runDoctor() = true with filebot missing:\n%s", out.String())
+		}
+		for _, want := range []string{"ok       makemkvcon  1.17.7", "ok       ffmpeg      ffmpeg version 6.1.1\n", "FAIL     filebot", "ok       " + filepath.Join(mediaBase, "pipeline")} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("output missing %q:\n%s", want, out.String())
+			}
+		}
+	})
+
+	t.Run("publish runs elsewhere", func(t *testing.T) {
+		var out bytes.Buffer
+		cfg := &config.Config{Dispatch: map[string]string{"publish": "nas"}}
+		if !runDoctor(&out, cfg) {
+			t.Errorf("runDoctor() = false:\n%s", out.String())
+		}
+		if !strings.Contains(out.String(), "skipped  filebot     publish runs on nas") {
+			t.Errorf("filebot should be skipped:\n%s", out.String())
+		}
+	})
+
+	t.Run("library not writable", func(t *testing.T) {
+		var out bytes.Buffer
+		cfg := &config.Config{Dispatch: map[string]string{"publish": ""}, LibraryBase: filepath.Join(mediaBase, "missing")}
+		writeTool(t, binDir, "filebot", `echo "FileBot 5.1.3"`)
+		if runDoctor(&out, cfg) {
+			t.Errorf("runDoctor() = true with a missing library:\n%s", out.String())
+		}
+		if !strings.Contains(out.String(), "FAIL     "+cfg.LibraryMoviesPath()) {
+			t.Errorf("library path should fail:\n%s", out.String())
+		}
+	})
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"os"
 
@@ -11,15 +12,49 @@ import (
 	"github.com/cuivienor/media-pipeline/internal/tui"
 )
 
+const usage = "Usage: media-pipeline [-config <path>] [stats | doctor | config init [-force]]"
+
 func main() {
+	var configPath string
+	var doctor bool
+
+	flag.StringVar(&configPath, "config", "", "Config file (defaults to $MEDIA_BASE/pipeline/config.yaml)")
+	flag.BoolVar(&doctor, "doctor", false, "Same as the doctor command")
+	flag.Parse()
+	args := flag.Args()
+
+	// Commands that run before a config exists
+	if len(args) > 0 && args[0] == "config" {
+		if len(args) < 2 || args[1] != "init" {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(1)
+		}
+		if err := runConfigInit(os.Stdout, configPath, args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Load configuration
-	cfg, err := config.LoadFromMediaBase()
+	cfg, err := config.LoadFromFlag(configPath)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-		fmt.Fprintf(os.Stderr, "Expected config at: $MEDIA_BASE/pipeline/config.yaml\n")
+		if configPath == "" {
+			fmt.Fprintf(os.Stderr, "Expected config at: $MEDIA_BASE/pipeline/config.yaml\n")
+			fmt.Fprintf(os.Stderr, "Run 'media-pipeline config init' to create one\n")
+		}
 		os.Exit(1)
 	}
 
+	// Pre-flight check of the tools and directories, without the database
+	if doctor || len(args) > 0 && args[0] == "doctor" {
+		if !runDoctor(os.Stdout, cfg) {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Open database
 	database, err := db.Open(cfg.DatabasePath())
 	if err != nil {
@@ -31,6 +66,22 @@ func main() {
 	// Create repository
 	repo := db.NewSQLiteRepository(database)
 
+	// Subcommands
+	if len(args) > 0 {
+		switch args[0] {
+		case "stats":
+			if err := printStats(context.Background(), os.Stdout, repo); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		default:
+			fmt.Fprintf(os.Stderr, "Unknown command: %s\n", args[0])
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(1)
+		}
+	}
+
 	// Run data migration for existing TV shows
 	if err := repo.MigrateToItemCentric(context.Background()); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: data migration failed: %v\n", err)
@@ -47,4 +98,9 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
 		os.Exit(1)
 	}
+
+	// Leave the last path picked with [p] on screen after the alt screen closes
+	if path := app.SelectedPath(); path != "" {
+		fmt.Fprintln(os.Stderr, path)
+	}
 }
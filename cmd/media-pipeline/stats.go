@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// statsStages lists stages in pipeline order for display
+var statsStages = []model.Stage{
+	model.StageRip,
+	model.StageOrganize,
+	model.StageRemux,
+	model.StageTranscode,
+	model.StagePublish,
+}
+
+// statsStatuses lists job statuses in display order
+var statsStatuses = []model.JobStatus{
+	model.JobStatusPending,
+	model.JobStatusInProgress,
+	model.JobStatusCompleted,
+	model.JobStatusFailed,
+}
+
+// printStats writes an at-a-glance summary of job counts
+func printStats(ctx context.Context, w io.Writer, repo db.Repository) error {
+	stats, err := repo.GetJobStats(ctx)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "%-10s", "STAGE")
+	for _, status := range statsStatuses {
+		fmt.Fprintf(w, " %12s", status)
+	}
+	fmt.Fprintln(w)
+
+	for _, stage := range statsStages {
+		fmt.Fprintf(w, "%-10s", stage)
+		for _, status := range statsStatuses {
+			fmt.Fprintf(w, " %12d", stats.Count(stage, status))
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "\nTotal jobs: %d\n", stats.Total)
+	fmt.Fprintf(w, "Bytes processed: %.2f GB\n", float64(stats.BytesProcessed)/(1024*1024*1024))
+	return nil
+}
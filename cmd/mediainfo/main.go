@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/mediainfo"
+)
+
+func main() {
+	var ffprobePath string
+	var configPath string
+
+	flag.StringVar(&ffprobePath, "ffprobe", "", "ffprobe binary (defaults to the configured ffprobe)")
+	flag.StringVar(&configPath, "config", "", "Config file (defaults to $MEDIA_BASE/pipeline/config.yaml)")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Fprintln(os.Stderr, "Usage: mediainfo [-ffprobe <path> | -config <path>] <file>...")
+		os.Exit(1)
+	}
+
+	if ffprobePath == "" {
+		// Config is optional here, the tool is useful on any machine with
+		// ffprobe, but one given with -config has to load
+		cfg, err := config.LoadFromFlag(configPath)
+		switch {
+		case err == nil:
+			ffprobePath = cfg.FFprobePath()
+		case configPath != "":
+			fmt.Fprintf(os.Stderr, "Error: failed to load config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	failed := false
+	for i, path := range flag.Args() {
+		if i > 0 {
+			fmt.Println()
+		}
+		info, err := mediainfo.ProbeWith(ffprobePath, path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", path, err)
+			failed = true
+			continue
+		}
+		printInfo(os.Stdout, path, info)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// printInfo writes a file's container summary followed by one line per stream
+func printInfo(w io.Writer, path string, info *mediainfo.MediaInfo) {
+	fmt.Fprintln(w, path)
+	fmt.Fprintf(w, "  Format:   %s\n", info.Format.Name)
+	if info.Format.Duration > 0 {
+		d := time.Duration(info.Format.Duration * float64(time.Second)).Round(time.Second)
+		fmt.Fprintf(w, "  Duration: %s\n", d)
+	}
+	if info.Format.Size > 0 {
+		fmt.Fprintf(w, "  Size:     %.2f GB\n", float64(info.Format.Size)/1e9)
+	}
+	if info.Format.BitRate > 0 {
+		fmt.Fprintf(w, "  Bit rate: %.1f Mb/s\n", float64(info.Format.BitRate)/1e6)
+	}
+	fmt.Fprintf(w, "  Streams:  %d video, %d audio, %d subtitle\n",
+		len(info.Video()), len(info.Audio()), len(info.Subtitles()))
+	for _, s := range info.Streams {
+		fmt.Fprintf(w, "    %s\n", s)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/mediainfo"
+)
+
+func TestPrintInfo(t *testing.T) {
+	info := &mediainfo.MediaInfo{
+		Format: mediainfo.Format{Name: "matroska,webm", Duration: 5430.4, Size: 24_500_000_000, BitRate: 36_100_000},
+		Streams: []mediainfo.Stream{
+			{Index: 0, Type: mediainfo.StreamVideo, Codec: "hevc", Language: "und", Width: 1920, Height: 1080},
+			{Index: 1, Type: mediainfo.StreamAudio, Codec: "ac3", Language: "eng", Channels: 6},
+		},
+	}
+
+	var buf bytes.Buffer
+	printInfo(&buf, "/media/movie.mkv", info)
+	out := buf.String()
+
+	for _, want := range []string{
+		"/media/movie.mkv\n",
+		"Format:   matroska,webm",
+		"Duration: 1h30m30s",
+		"Size:     24.50 GB",
+		"Bit rate: 36.1 Mb/s",
+		"Streams:  1 video, 1 audio, 0 subtitle",
+		"    #0 video hevc 1920x1080\n",
+		"    #1 audio ac3 eng 6ch\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -140,13 +142,18 @@ func RunMkv(w io.Writer, opts *Options) error {
 		return simulateFailure(w, out, profile, opts)
 	}
 
+	titles, err := selectTitles(profile.Titles, opts.Titles)
+	if err != nil {
+		return err
+	}
+
 	// Rip each title
-	for i, title := range profile.Titles {
+	for i, title := range titles {
 		outputPath := filepath.Join(opts.OutputDir, title.Filename)
 
 		// Write progress: starting title
-		out.WriteMSG(5021, fmt.Sprintf("Saving %d titles", len(profile.Titles)))
-		out.WritePRGT(5022, fmt.Sprintf("Saving title %d of %d", i+1, len(profile.Titles)))
+		out.WriteMSG(5021, fmt.Sprintf("Saving %d titles", len(titles)))
+		out.WritePRGT(5022, fmt.Sprintf("Saving title %d of %d", i+1, len(titles)))
 
 		// Generate actual MKV file if not skipped
 		if !opts.SkipFFmpeg {
@@ -174,11 +181,29 @@ func RunMkv(w io.Writer, opts *Options) error {
 	}
 
 	// Write completion message
-	out.WriteMSG(5010, fmt.Sprintf("Copy complete. %d titles saved.", len(profile.Titles)))
+	out.WriteMSG(5010, fmt.Sprintf("Copy complete. %d titles saved.", len(titles)))
 
 	return nil
 }
 
+// selectTitles returns the titles named by spec: "all" (or empty) or
+// comma-separated title indices
+func selectTitles(titles []TitleInfo, spec string) ([]TitleInfo, error) {
+	if spec == "" || spec == "all" {
+		return titles, nil
+	}
+
+	var selected []TitleInfo
+	for _, part := range strings.Split(spec, ",") {
+		idx, err := strconv.Atoi(part)
+		if err != nil || idx < 0 || idx >= len(titles) {
+			return nil, fmt.Errorf("invalid title %q", part)
+		}
+		selected = append(selected, titles[idx])
+	}
+	return selected, nil
+}
+
 // simulateFailure simulates a disc read failure
 func simulateFailure(w io.Writer, out *OutputWriter, profile *DiscProfile, opts *Options) error {
 	// Progress up to failure point
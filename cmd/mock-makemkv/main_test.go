@@ -215,3 +215,31 @@ func TestIntegration_MkvCreatesRealFiles(t *testing.T) {
 		t.Error("No MKV files were created")
 	}
 }
+
+func TestRunMkv_SelectedTitles(t *testing.T) {
+	outputDir := t.TempDir()
+
+	var buf bytes.Buffer
+	opts := &Options{
+		ProfileName: "big_buck_bunny",
+		DiscPath:    "disc:0",
+		Titles:      "1",
+		OutputDir:   outputDir,
+		SkipFFmpeg:  true,
+	}
+
+	if err := RunMkv(&buf, opts); err != nil {
+		t.Fatalf("RunMkv failed: %v", err)
+	}
+
+	entries, _ := os.ReadDir(outputDir)
+	want := GetProfile("big_buck_bunny").Titles[1].Filename
+	if len(entries) != 1 || entries[0].Name() != want {
+		t.Errorf("output = %v, want only %s", entries, want)
+	}
+
+	opts.Titles = "9"
+	if err := RunMkv(&buf, opts); err == nil {
+		t.Error("RunMkv should reject a title the disc doesn't have")
+	}
+}
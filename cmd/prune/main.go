@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+)
+
+func main() {
+	var dbPath string
+	var olderThan string
+	var onlyCompleted bool
+	var configPath string
+
+	flag.StringVar(&dbPath, "db", "", "Path to database (defaults to the configured database)")
+	flag.StringVar(&configPath, "config", "", "Config file (defaults to $MEDIA_BASE/pipeline/config.yaml)")
+	flag.StringVar(&olderThan, "older-than", "30d", "Delete jobs and log events older than this age (e.g. 30d, 72h)")
+	flag.BoolVar(&onlyCompleted, "only-completed", false, "Only delete completed jobs, keeping failed ones")
+	flag.Parse()
+
+	age, err := parseAge(olderThan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Usage: prune [-db <path> | -config <path>] [-older-than 30d] [-only-completed]")
+		os.Exit(1)
+	}
+
+	if err := run(dbPath, configPath, time.Now().Add(-age), onlyCompleted); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(dbPath, configPath string, cutoff time.Time, onlyCompleted bool) error {
+	ctx := context.Background()
+
+	if dbPath == "" {
+		cfg, err := config.LoadFromFlag(configPath)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		dbPath = cfg.DatabasePath()
+	}
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+
+	// Log events go first so rows belonging to active jobs are still trimmed;
+	// deleting jobs cascades to their remaining log events and transcode files.
+	logEvents, err := repo.DeleteLogEventsBefore(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+
+	jobs, err := repo.DeleteJobsBefore(ctx, cutoff, onlyCompleted)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Pruned records older than %s\n", cutoff.Format(time.RFC3339))
+	fmt.Printf("  Jobs removed:       %d\n", jobs)
+	fmt.Printf("  Log events removed: %d\n", logEvents)
+	return nil
+}
+
+// parseAge parses an age like "30d" or "72h". A "d" suffix means days;
+// anything else is handed to time.ParseDuration.
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid age %q: days must be a positive integer", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid age %q: %w", s, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid age %q: must be positive", s)
+	}
+	return d, nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/cuivienor/media-pipeline/internal/config"
 	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/fsutil"
 	"github.com/cuivienor/media-pipeline/internal/logging"
 	"github.com/cuivienor/media-pipeline/internal/model"
 	"github.com/cuivienor/media-pipeline/internal/publish"
@@ -17,23 +19,35 @@ import (
 func main() {
 	var jobID int64
 	var dbPath string
+	var configPath string
+	var keepStaging bool
+	var inputDir string
+	var logLevel string
 
 	flag.Int64Var(&jobID, "job-id", 0, "Job ID to execute")
 	flag.StringVar(&dbPath, "db", "", "Path to database")
+	flag.StringVar(&configPath, "config", "", "Config file (defaults to $MEDIA_BASE/pipeline/config.yaml)")
+	flag.StringVar(&inputDir, "input-dir", "", "Use this directory as input instead of the previous stage's recorded output")
+	flag.StringVar(&logLevel, "log-level", "info", "Minimum level to log: error, info or debug")
+	flag.BoolVar(&keepStaging, "keep-staging", false, "Keep staging directories even if publish.cleanup_staging is set (logs what would be removed)")
 	flag.Parse()
 
 	if jobID == 0 || dbPath == "" {
-		fmt.Fprintln(os.Stderr, "Usage: publish -job-id <id> -db <path>")
+		fmt.Fprintln(os.Stderr, "Usage: publish -job-id <id> -db <path> [-config <path>] [-input-dir <path>] [-keep-staging] [-log-level <level>]")
+		os.Exit(1)
+	}
+	if _, err := logging.ParseLevel(logLevel); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	if err := run(jobID, dbPath); err != nil {
+	if err := run(jobID, dbPath, configPath, keepStaging, inputDir, logLevel); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run(jobID int64, dbPath string) error {
+func run(jobID int64, dbPath, configPath string, keepStaging bool, inputOverride, logLevel string) error {
 	ctx := context.Background()
 
 	// Open database
@@ -73,7 +87,7 @@ func run(jobID int64, dbPath string) error {
 	}
 
 	// Load config
-	cfg, err := config.LoadFromMediaBase()
+	cfg, err := config.LoadFromFlag(configPath)
 	if err != nil {
 		markFailed(err.Error())
 		return fmt.Errorf("failed to load config: %w", err)
@@ -85,7 +99,13 @@ func run(jobID int64, dbPath string) error {
 		return fmt.Errorf("failed to create log directory: %w", err)
 	}
 	logPath := cfg.JobLogPath(jobID)
-	logger, err := logging.NewForJob(logPath, true, nil)
+	logger, err := logging.NewForJob(logPath, true, nil, logging.JobOptions{
+		JobID:      jobID,
+		Format:     logging.Format(cfg.LogFormat()),
+		Level:      logLevel,
+		MaxBytes:   cfg.LogMaxBytes(),
+		MaxBackups: cfg.LogMaxBackups(),
+	})
 	if err != nil {
 		markFailed(err.Error())
 		return fmt.Errorf("failed to create logger: %w", err)
@@ -94,33 +114,67 @@ func run(jobID int64, dbPath string) error {
 
 	logger.Info("Starting publish: type=%s name=%q dbID=%d", item.Type, item.Name, item.DatabaseID())
 
-	// Find input directory from transcode job
-	inputDir, err := findTranscodeOutput(ctx, repo, job)
+	// Find input directory from transcode job, unless given explicitly
+	inputDir, err := resolveInput(ctx, repo, job, inputOverride, logger)
 	if err != nil {
-		logger.Error("Failed to find input: %v", err)
 		markFailed(err.Error())
-		return fmt.Errorf("failed to find input: %w", err)
+		return err
 	}
 
 	logger.Info("Input directory: %s", inputDir)
 
+	// Create publisher
+	opts := publish.PublishOptions{
+		LibraryMovies:   cfg.LibraryMoviesPath(),
+		LibraryTV:       cfg.LibraryTVPath(),
+		LibraryMovies4K: cfg.LibraryMovies4KPath(),
+		Min4KWidth:      cfg.Publish.Min4KWidth,
+		FFprobePath:     cfg.FFprobePath(),
+		MovieFormat:     cfg.PublishMovieFormat(),
+		TVFormat:        cfg.PublishTVFormat(),
+		WriteNFO:        cfg.Publish.WriteNFO,
+
+		GeneratePoster:  cfg.Publish.GeneratePoster,
+		PosterTimestamp: cfg.PublishPosterTimestamp(),
+		FFmpegPath:      cfg.FFmpegPath(),
+
+		CopyRateLimit:  cfg.Publish.CopyRateLimit,
+		CopyBufferSize: cfg.Publish.CopyBufferSize,
+		PreservePerms:  cfg.Publish.PreservePermissions,
+	}
+	publisher := publish.NewPublisher(repo, logger, opts)
+
+	// Make sure the library has room for everything being published
+	libraryRoot, err := publisher.Library(item, inputDir)
+	if err != nil {
+		logger.Error("Failed to choose library: %v", err)
+		markFailed(err.Error())
+		return err
+	}
+	inputSize, err := fsutil.DirSize(inputDir)
+	if err != nil {
+		logger.Error("Failed to measure input: %v", err)
+		markFailed(err.Error())
+		return fmt.Errorf("failed to measure input: %w", err)
+	}
+	needed := int64(float64(inputSize) * cfg.FreeSpacePublishMultiplier())
+	if err := fsutil.CheckFreeSpace(libraryRoot, needed); err != nil {
+		logger.Error("Preflight failed: %v", err)
+		markFailed(err.Error())
+		return err
+	}
+
 	// Update job to in_progress
 	job.Status = model.JobStatusInProgress
 	job.InputDir = inputDir
 	now := time.Now()
 	job.StartedAt = &now
+	job.SetWorker()
 	if err := repo.UpdateJob(ctx, job); err != nil {
 		markFailed(err.Error())
 		return fmt.Errorf("failed to update job status: %w", err)
 	}
 
-	// Create publisher
-	opts := publish.PublishOptions{
-		LibraryMovies: cfg.LibraryMoviesPath(),
-		LibraryTV:     cfg.LibraryTVPath(),
-	}
-	publisher := publish.NewPublisher(repo, logger, opts)
-
 	// Execute publish
 	result, err := publisher.Publish(ctx, item, inputDir)
 	if err != nil {
@@ -153,10 +207,54 @@ func run(jobID int64, dbPath string) error {
 		logger.Error("Failed to update item status: %v", err)
 	}
 
-	logger.Info("Publish finished successfully")
+	// Publish has verified the library copy, so the staging copies are now
+	// redundant. Without cleanup enabled this only logs what would go.
+	if jobs, err := repo.ListJobsForMedia(ctx, item.ID); err != nil {
+		logger.Warn("Failed to list jobs for staging cleanup: %v", err)
+	} else {
+		dryRun := keepStaging || !cfg.Publish.CleanupStaging
+		dirs := publish.StagingDirs(jobs, job.SeasonID)
+		if _, err := publisher.CleanupStaging(dirs, cfg.StagingBase, dryRun); err != nil {
+			logger.Warn("Staging cleanup failed: %v", err)
+		}
+	}
+
+	logger.Info("Publish finished successfully in %s", job.Duration().Round(time.Second))
 	return nil
 }
 
+// resolveInput returns the -input-dir override if set, bypassing the job
+// history for recovery when it disagrees with the filesystem. Otherwise it
+// checks the previous stage completed and returns its output.
+func resolveInput(ctx context.Context, repo db.Repository, job *model.Job, override string, logger *logging.Logger) (string, error) {
+	if override != "" {
+		dir, err := fsutil.InputDir(override)
+		if err != nil {
+			logger.Error("Invalid -input-dir: %v", err)
+			return "", err
+		}
+		logger.Info("Using -input-dir, skipping the transcode output lookup")
+		return dir, nil
+	}
+
+	// Refuse to run ahead of the previous stage
+	if ok, reason := repo.CanStartStage(ctx, job.MediaItemID, job.Stage, job.SeasonID); !ok {
+		logger.Error("%s", reason)
+		return "", errors.New(reason)
+	}
+
+	inputDir, err := findTranscodeOutput(ctx, repo, job)
+	if err != nil {
+		logger.Error("Failed to find input: %v", err)
+		return "", fmt.Errorf("failed to find input: %w", err)
+	}
+	if err := fsutil.StageOutputDir(model.StageTranscode.String(), inputDir); err != nil {
+		logger.Error("%v (use -input-dir if it was moved)", err)
+		return "", err
+	}
+	return inputDir, nil
+}
+
 // findTranscodeOutput finds the output directory from the transcode stage
 func findTranscodeOutput(ctx context.Context, repo db.Repository, job *model.Job) (string, error) {
 	jobs, err := repo.ListJobsForMedia(ctx, job.MediaItemID)
@@ -2,14 +2,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/cuivienor/media-pipeline/internal/config"
 	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/fsutil"
 	"github.com/cuivienor/media-pipeline/internal/logging"
 	"github.com/cuivienor/media-pipeline/internal/model"
 	"github.com/cuivienor/media-pipeline/internal/remux"
@@ -18,24 +23,43 @@ import (
 func main() {
 	var jobID int64
 	var dbPath string
+	var configPath string
+	var verify bool
+	var jobs int
+	var inputDir string
+	var logLevel string
 
 	flag.Int64Var(&jobID, "job-id", 0, "Job ID to execute")
 	flag.StringVar(&dbPath, "db", "", "Path to database")
+	flag.StringVar(&configPath, "config", "", "Config file (defaults to $MEDIA_BASE/pipeline/config.yaml)")
+	flag.BoolVar(&verify, "verify", false, "Re-probe output files and fail if tracks don't match the selection")
+	flag.IntVar(&jobs, "jobs", 1, "Number of files to remux concurrently")
+	flag.StringVar(&logLevel, "log-level", "info", "Minimum level to log: error, info or debug")
+	flag.StringVar(&inputDir, "input-dir", "", "Use this directory as input instead of the previous stage's recorded output")
 	flag.Parse()
 
-	if jobID == 0 || dbPath == "" {
-		fmt.Fprintln(os.Stderr, "Usage: remux -job-id <id> -db <path>")
+	if jobID == 0 || dbPath == "" || jobs < 1 {
+		fmt.Fprintln(os.Stderr, "Usage: remux -job-id <id> -db <path> [-config <path>] [-verify] [-jobs <n>] [-input-dir <path>] [-log-level <level>]")
+		os.Exit(1)
+	}
+	if _, err := logging.ParseLevel(logLevel); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	if err := run(jobID, dbPath); err != nil {
+	// Cancel running work on Ctrl+C or SIGTERM so the job is left retryable
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, jobID, dbPath, configPath, verify, jobs, inputDir, logLevel)
+	stop()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run(jobID int64, dbPath string) error {
-	ctx := context.Background()
+func run(workCtx context.Context, jobID int64, dbPath, configPath string, verify bool, jobs int, inputOverride, logLevel string) error {
+	// Database updates must still land after cancellation
+	ctx := context.WithoutCancel(workCtx)
 
 	// Open database
 	database, err := db.Open(dbPath)
@@ -67,7 +91,7 @@ func run(jobID int64, dbPath string) error {
 	}
 
 	// Load config for languages
-	cfg, err := config.LoadFromMediaBase()
+	cfg, err := config.LoadFromFlag(configPath)
 	if err != nil {
 		markFailed(err.Error())
 		return fmt.Errorf("failed to load config: %w", err)
@@ -79,7 +103,13 @@ func run(jobID int64, dbPath string) error {
 		return fmt.Errorf("failed to create log directory: %w", err)
 	}
 	logPath := cfg.JobLogPath(jobID)
-	logger, err := logging.NewForJob(logPath, true, nil)
+	logger, err := logging.NewForJob(logPath, true, nil, logging.JobOptions{
+		JobID:      jobID,
+		Format:     logging.Format(cfg.LogFormat()),
+		Level:      logLevel,
+		MaxBytes:   cfg.LogMaxBytes(),
+		MaxBackups: cfg.LogMaxBackups(),
+	})
 	if err != nil {
 		markFailed(err.Error())
 		return fmt.Errorf("failed to create logger: %w", err)
@@ -88,12 +118,11 @@ func run(jobID int64, dbPath string) error {
 
 	logger.Info("Starting remux: type=%s name=%q", item.Type, item.Name)
 
-	// Find input directory from organize job
-	inputDir, err := findOrganizeOutput(ctx, repo, job)
+	// Find input directory from organize job, unless given explicitly
+	inputDir, err := resolveInput(ctx, repo, job, inputOverride, logger)
 	if err != nil {
-		logger.Error("Failed to find input: %v", err)
 		markFailed(err.Error())
-		return fmt.Errorf("failed to find input: %w", err)
+		return err
 	}
 
 	// Determine output directory
@@ -114,19 +143,34 @@ func run(jobID int64, dbPath string) error {
 	job.OutputDir = outputDir
 	now := time.Now()
 	job.StartedAt = &now
+	job.SetWorker()
 	if err := repo.UpdateJob(ctx, job); err != nil {
 		markFailed(err.Error())
 		return fmt.Errorf("failed to update job status: %w", err)
 	}
+	recordLanguages(ctx, repo, jobID, cfg.RemuxLanguages(), logger)
 
 	// Create remuxer and process
 	remuxer := remux.NewRemuxer(cfg.RemuxLanguages())
+	remuxer.SetConcurrency(jobs)
+	remuxer.SetFallbackKeepFirstAudio(cfg.Remux.FallbackKeepFirstAudio)
+	remuxer.SetKeepChapters(cfg.RemuxKeepChapters())
+	remuxer.SetClearTrackTitles(cfg.Remux.ClearTrackTitles)
+	remuxer.SetTrackCaps(cfg.Remux.MaxAudioPerLang, cfg.Remux.MaxSubsPerLang)
+	if cfg.Remux.SetFileTitle {
+		remuxer.SetFileTitle(item.Name)
+	}
 	isTV := item.Type == model.MediaTypeTV
 
-	logger.Info("Starting track filtering...")
+	logger.Info("Starting track filtering (%d concurrent)...", jobs)
 
-	results, err := remuxer.RemuxDirectory(ctx, inputDir, outputDir, isTV)
+	results, err := remuxer.RemuxDirectory(workCtx, inputDir, outputDir, isTV)
 	if err != nil {
+		if workCtx.Err() != nil {
+			logger.Info("Remux cancelled")
+			markFailed("cancelled")
+			return err
+		}
 		logger.Error("Remux failed: %v", err)
 		markFailed(err.Error())
 		return err
@@ -140,10 +184,41 @@ func run(jobID int64, dbPath string) error {
 			r.InputTracks.Audio, r.InputTracks.Subtitles,
 			r.OutputTracks.Audio, r.OutputTracks.Subtitles,
 			r.TracksRemoved)
+		if r.CappedTracks > 0 {
+			logger.Info("  %d of the removed tracks were over the per-language caps", r.CappedTracks)
+		}
+		if r.AudioFallback {
+			logger.Warn("%s has no audio in %s; kept its first audio track (%s) instead",
+				filepath.Base(r.InputPath), strings.Join(cfg.RemuxLanguages(), ", "), r.OutputAudioLangs[0])
+		}
+		logger.Info("  Kept languages: audio [%s], subtitles [%s]",
+			strings.Join(r.OutputAudioLangs, ", "), strings.Join(r.OutputSubtitleLangs, ", "))
+		if missing := r.MissingLanguages(cfg.RemuxLanguages()); len(missing) > 0 {
+			logger.Warn("%s has no audio or subtitle tracks in %s; check the source's track language tags",
+				filepath.Base(r.InputPath), strings.Join(missing, ", "))
+		}
 		totalRemoved += r.TracksRemoved
 	}
 	logger.Info("Total: %d files processed, %d tracks removed", len(results), totalRemoved)
 
+	if verify {
+		logger.Info("Verifying output tracks with ffprobe...")
+		if err := remux.VerifyResults(cfg.FFprobePath(), results); err != nil {
+			logger.Error("Verification failed: %v", err)
+			markFailed(fmt.Sprintf("verification failed: %v", err))
+			return fmt.Errorf("verification failed: %w", err)
+		}
+		logger.Info("Verified %d files", len(results))
+	}
+
+	// The files are already in place, so a manifest failure doesn't fail the job
+	manifest := remux.BuildManifest(jobID, outputDir, cfg.RemuxLanguages(), results)
+	if err := remux.WriteManifest(outputDir, manifest); err != nil {
+		logger.Error("Failed to write manifest: %v", err)
+	} else {
+		logger.Info("Wrote manifest: %s", remux.ManifestPath(outputDir))
+	}
+
 	// Mark job as complete
 	if err := repo.UpdateJobStatus(ctx, jobID, model.JobStatusCompleted, ""); err != nil {
 		return fmt.Errorf("failed to update job status: %w", err)
@@ -154,10 +229,59 @@ func run(jobID int64, dbPath string) error {
 		return fmt.Errorf("failed to update item stage: %w", err)
 	}
 
-	logger.Info("Remux finished successfully")
+	logger.Info("Remux finished successfully in %s", job.Duration().Round(time.Second))
 	return nil
 }
 
+// recordLanguages stores the languages the job keeps in its options, so an
+// audit can tell a later config change from the languages this job used
+func recordLanguages(ctx context.Context, repo db.Repository, jobID int64, languages []string, logger *logging.Logger) {
+	opts, err := repo.GetJobOptions(ctx, jobID)
+	if err != nil {
+		logger.Warn("Failed to record remux languages: %v", err)
+		return
+	}
+	if opts == nil {
+		opts = map[string]interface{}{}
+	}
+	opts[remux.LanguagesOption] = languages
+	if err := repo.SetJobOptions(ctx, jobID, opts); err != nil {
+		logger.Warn("Failed to record remux languages: %v", err)
+	}
+}
+
+// resolveInput returns the -input-dir override if set, bypassing the job
+// history for recovery when it disagrees with the filesystem. Otherwise it
+// checks the previous stage completed and returns its output.
+func resolveInput(ctx context.Context, repo db.Repository, job *model.Job, override string, logger *logging.Logger) (string, error) {
+	if override != "" {
+		dir, err := fsutil.InputDir(override)
+		if err != nil {
+			logger.Error("Invalid -input-dir: %v", err)
+			return "", err
+		}
+		logger.Info("Using -input-dir, skipping the organize output lookup")
+		return dir, nil
+	}
+
+	// Refuse to run ahead of the previous stage
+	if ok, reason := repo.CanStartStage(ctx, job.MediaItemID, job.Stage, job.SeasonID); !ok {
+		logger.Error("%s", reason)
+		return "", errors.New(reason)
+	}
+
+	inputDir, err := findOrganizeOutput(ctx, repo, job)
+	if err != nil {
+		logger.Error("Failed to find input: %v", err)
+		return "", fmt.Errorf("failed to find input: %w", err)
+	}
+	if err := fsutil.StageOutputDir(model.StageOrganize.String(), inputDir); err != nil {
+		logger.Error("%v (use -input-dir if it was moved)", err)
+		return "", err
+	}
+	return inputDir, nil
+}
+
 // findOrganizeOutput finds the output directory from the organize stage
 func findOrganizeOutput(ctx context.Context, repo db.Repository, job *model.Job) (string, error) {
 	// Look for completed organize job for this media item
@@ -2,15 +2,26 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/cuivienor/media-pipeline/internal/config"
 	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/fsutil"
 	"github.com/cuivienor/media-pipeline/internal/logging"
 	"github.com/cuivienor/media-pipeline/internal/model"
+	"github.com/cuivienor/media-pipeline/internal/progress"
 	"github.com/cuivienor/media-pipeline/internal/ripper"
 )
 
@@ -19,26 +30,68 @@ const defaultMediaBase = "/mnt/media"
 func main() {
 	var jobID int64
 	var dbPath string
+	var configPath string
 	var discPath string
+	var discs int
+	var discTimeout time.Duration
+	var logLevel string
+	var overrides ripOverrides
+	var overwrite bool
 
 	flag.Int64Var(&jobID, "job-id", 0, "Job ID to execute")
 	flag.StringVar(&dbPath, "db", "", "Path to database")
-	flag.StringVar(&discPath, "disc-path", "disc:0", "Path to disc device")
+	flag.StringVar(&configPath, "config", "", "Config file (defaults to $MEDIA_BASE/pipeline/config.yaml)")
+	flag.StringVar(&discPath, "disc-path", "disc:0", "Disc to rip: a drive (disc:0, /dev/sr0), an ISO image or a BDMV/VIDEO_TS folder")
+	flag.IntVar(&discs, "discs", 1, "Number of discs to rip in a row (TV only); later discs get new jobs")
+	flag.DurationVar(&discTimeout, "disc-timeout", 30*time.Minute, "How long to wait for each next disc")
+	flag.StringVar(&logLevel, "log-level", "info", "Minimum level to log: error, info or debug")
+	flag.IntVar(&overrides.season, "season", 0, "Rip as this season number instead of the job's (TV only, for recovery)")
+	flag.IntVar(&overrides.disc, "disc", 0, "Rip as this disc number instead of the job's (TV only, for recovery)")
+	flag.BoolVar(&overwrite, "overwrite", false, "Replace an earlier rip of the same disc once this one succeeds")
 	flag.Parse()
 
-	if jobID == 0 || dbPath == "" {
-		fmt.Fprintln(os.Stderr, "Usage: ripper -job-id <id> -db <path> [--disc-path <path>]")
+	if jobID == 0 || dbPath == "" || discs < 1 || overrides.season < 0 || overrides.disc < 0 {
+		fmt.Fprintln(os.Stderr, "Usage: ripper -job-id <id> -db <path> [-config <path>] [--disc-path <path>] [-discs <n> [-disc-timeout <duration>]] [-season <n>] [-disc <n>] [-overwrite] [-log-level <level>]")
+		os.Exit(1)
+	}
+	if discs > 1 && overrides != (ripOverrides{}) {
+		fmt.Fprintln(os.Stderr, "Error: -season and -disc apply to a single job and can't be combined with -discs")
+		os.Exit(1)
+	}
+	if _, err := logging.ParseLevel(logLevel); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	// The disc path is checked by run, so a bad one fails the job rather
+	// than leaving it pending
+	if discs > 1 && ripper.IsImageSource(discPath) {
+		fmt.Fprintln(os.Stderr, "Error: -discs needs a disc drive, not an ISO image or folder")
 		os.Exit(1)
 	}
 
-	if err := run(jobID, dbPath, discPath); err != nil {
+	// Cancel running work on Ctrl+C or SIGTERM so the job is left retryable
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, jobID, dbPath, configPath, discPath, logLevel, overrides, overwrite)
+	if err == nil && discs > 1 {
+		err = runQueue(ctx, jobID, dbPath, configPath, discPath, discs-1, discTimeout, logLevel)
+	}
+	stop()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run(jobID int64, dbPath string, discPath string) error {
-	ctx := context.Background()
+// ripOverrides replaces the season and disc number taken from the job, for
+// recovering a job created with the wrong ones. Zero keeps the job's value.
+type ripOverrides struct {
+	season int
+	disc   int
+}
+
+func run(workCtx context.Context, jobID int64, dbPath, configPath, discPath, logLevel string, overrides ripOverrides, overwrite bool) error {
+	// Database updates must still land after cancellation
+	ctx := context.WithoutCancel(workCtx)
 
 	// Get config from environment
 	mediaBase := os.Getenv("MEDIA_BASE")
@@ -82,6 +135,21 @@ func run(jobID int64, dbPath string, discPath string) error {
 		markFailed(err.Error())
 		return fmt.Errorf("failed to build rip request: %w", err)
 	}
+	warnings, err := applyOverrides(req, overrides)
+	if err != nil {
+		markFailed(err.Error())
+		return err
+	}
+
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		markFailed(err.Error())
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	// Images and folders have no tray to open
+	req.EjectAfterRip = cfg.RipEject && !ripper.IsImageSource(discPath)
+	req.ExtrasFolders = cfg.RipScaffold
+	req.Overwrite = overwrite
 
 	// Set up logging
 	logDir := filepath.Join(mediaBase, "pipeline", "logs", "jobs", fmt.Sprintf("%d", jobID))
@@ -91,7 +159,13 @@ func run(jobID int64, dbPath string, discPath string) error {
 	}
 	logPath := filepath.Join(logDir, "job.log")
 
-	logger, err := logging.NewForJob(logPath, true, nil)
+	logger, err := logging.NewForJob(logPath, true, nil, logging.JobOptions{
+		JobID:      jobID,
+		Format:     logging.Format(cfg.LogFormat()),
+		Level:      logLevel,
+		MaxBytes:   cfg.LogMaxBytes(),
+		MaxBackups: cfg.LogMaxBackups(),
+	})
 	if err != nil {
 		markFailed(err.Error())
 		return fmt.Errorf("failed to create logger: %w", err)
@@ -99,33 +173,76 @@ func run(jobID int64, dbPath string, discPath string) error {
 	defer logger.Close()
 
 	logger.Info("Starting rip: type=%s name=%q", item.Type, item.Name)
+	for _, w := range warnings {
+		logger.Warn("%s", w)
+	}
 	if item.Type == model.MediaTypeTV {
 		logger.Info("TV show: season=%d disc=%d", req.Season, req.Disc)
 	}
+	if req.RipMode == model.RipModeBackup {
+		logger.Info("Backing up the whole disc instead of ripping titles")
+	}
+
+	// Verify makemkvcon runs and its license is valid before touching the disc
+	version, err := ripper.CheckMakeMKV(makeMKVConPath)
+	if err != nil {
+		logger.Error("makemkvcon check failed: %v", err)
+		markFailed(err.Error())
+		return fmt.Errorf("makemkvcon check failed: %w", err)
+	}
+	logger.Info("Using MakeMKV v%s", version)
 
-	// Build output directory
-	stagingBase := filepath.Join(mediaBase, "staging")
+	// Build output directory under the configured staging base, where the
+	// later stages and publish's cleanup expect it
+	stagingBase := cfg.StagingBase
+	if stagingBase == "" {
+		stagingBase = filepath.Join(mediaBase, "staging")
+	}
 	outputDir := buildOutputDir(stagingBase, req)
 	logger.Info("Output directory: %s", outputDir)
 
+	// Make sure staging has room for every title on the disc
+	runner := ripper.NewMakeMKVRunner(makeMKVConPath)
+	discInfo, err := runner.GetDiscInfo(workCtx, req.DiscPath)
+	if err != nil {
+		logger.Error("Failed to read disc: %v", err)
+		markFailed(err.Error())
+		return fmt.Errorf("failed to read disc: %w", err)
+	}
+	req.DiscInfo = discInfo
+	needed := int64(float64(discInfo.TotalSize()) * cfg.FreeSpaceRipMultiplier())
+	if err := fsutil.CheckFreeSpace(stagingBase, needed); err != nil {
+		logger.Error("Preflight failed: %v", err)
+		markFailed(err.Error())
+		return err
+	}
+
 	// Update job to in_progress
 	job.Status = model.JobStatusInProgress
 	job.OutputDir = outputDir
 	now := time.Now()
 	job.StartedAt = &now
+	job.SetWorker()
 	if err := repo.UpdateJob(ctx, job); err != nil {
 		markFailed(err.Error())
 		return fmt.Errorf("failed to update job status: %w", err)
 	}
 
 	// Create ripper and run
-	runner := ripper.NewMakeMKVRunner(makeMKVConPath)
 	r := ripper.NewRipper(stagingBase, runner, &loggerAdapter{logger})
 
 	// Create callbacks for line logging and progress updates
 	onLine := func(line string) {
-		// Log raw MakeMKV output to the job log
-		logger.Info("[makemkv] %s", line)
+		// Log raw MakeMKV output to the job log, at debug level as it is noisy
+		logger.Debug("[makemkv] %s", line)
+	}
+
+	// Optional live progress stream
+	var sink ripper.ProgressSink
+	if webhook := progress.NewWebhookSink(cfg.ProgressWebhook, model.StageRip.String()); webhook != nil {
+		logger.Info("Streaming progress to %s", cfg.ProgressWebhook)
+		defer webhook.Close()
+		sink = webhook
 	}
 
 	lastProgress := 0
@@ -135,11 +252,18 @@ func run(jobID int64, dbPath string, discPath string) error {
 		if percent > lastProgress {
 			lastProgress = percent
 			repo.UpdateJobProgress(ctx, jobID, percent)
+			if sink != nil {
+				sink.Progress(jobID, percent)
+			}
 		}
 	}
 
-	result, err := r.Rip(ctx, req, outputDir, onLine, onProgress)
-	if err != nil {
+	if _, err := r.Rip(workCtx, req, outputDir, onLine, onProgress); err != nil {
+		if workCtx.Err() != nil {
+			logger.Info("Rip cancelled")
+			markFailed("cancelled")
+			return err
+		}
 		logger.Error("Rip failed: %v", err)
 		markFailed(err.Error())
 		return err
@@ -155,7 +279,23 @@ func run(jobID int64, dbPath string, discPath string) error {
 		return fmt.Errorf("failed to update item stage: %w", err)
 	}
 
-	logger.Info("Rip finished successfully in %s", result.Duration())
+	// A movie backup is the archive itself, with no later stages to run
+	if req.RipMode == model.RipModeBackup && item.Type == model.MediaTypeMovie {
+		if err := repo.UpdateMediaItemStatus(ctx, item.ID, model.ItemStatusCompleted); err != nil {
+			return fmt.Errorf("failed to update item status: %w", err)
+		}
+	}
+
+	logger.Info("Rip finished successfully in %s", job.Duration().Round(time.Second))
+
+	// The rip is already recorded, so a stuck tray is only worth a warning
+	if req.EjectAfterRip {
+		if err := ejectDisc(req.DiscPath); err != nil {
+			logger.Warn("Failed to eject disc: %v", err)
+		} else {
+			logger.Info("Ejected %s", req.DiscPath)
+		}
+	}
 	return nil
 }
 
@@ -163,7 +303,9 @@ func run(jobID int64, dbPath string, discPath string) error {
 func buildRipRequest(ctx context.Context, repo db.Repository, job *model.Job, item *model.MediaItem, discPath string) (*ripper.RipRequest, error) {
 	req := &ripper.RipRequest{
 		Name:     item.Name,
+		Edition:  item.Edition,
 		DiscPath: discPath,
+		RipMode:  item.RipMode,
 	}
 
 	// Set type
@@ -191,17 +333,51 @@ func buildRipRequest(ctx context.Context, repo db.Repository, job *model.Job, it
 			return nil, fmt.Errorf("TV show job missing disc number")
 		}
 		req.Disc = *job.Disc
+	} else if job.Disc != nil {
+		// A later disc of a multi-disc movie
+		req.Disc = *job.Disc
+	}
+
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rip request: %w", err)
 	}
 
 	return req, nil
 }
 
+// applyOverrides replaces the request's season and disc with the -season and
+// -disc flags, returning a warning for each value changed. The job record is
+// left as is.
+func applyOverrides(req *ripper.RipRequest, overrides ripOverrides) ([]string, error) {
+	if overrides == (ripOverrides{}) {
+		return nil, nil
+	}
+	if req.Type != ripper.MediaTypeTV {
+		return nil, fmt.Errorf("-season and -disc only apply to TV shows")
+	}
+
+	var warnings []string
+	if overrides.season != 0 && overrides.season != req.Season {
+		warnings = append(warnings, fmt.Sprintf("Ripping as season %d instead of the job's season %d (-season)", overrides.season, req.Season))
+		req.Season = overrides.season
+	}
+	if overrides.disc != 0 && overrides.disc != req.Disc {
+		warnings = append(warnings, fmt.Sprintf("Ripping as disc %d instead of the job's disc %d (-disc)", overrides.disc, req.Disc))
+		req.Disc = overrides.disc
+	}
+	return warnings, nil
+}
+
 // buildOutputDir constructs the output directory path
 func buildOutputDir(stagingBase string, req *ripper.RipRequest) string {
 	safeName := req.SafeName()
 
 	switch req.Type {
 	case ripper.MediaTypeMovie:
+		// Later discs of a multi-disc movie get a directory of their own
+		if req.Disc > 1 {
+			safeName = fmt.Sprintf("%s_Disc%d", safeName, req.Disc)
+		}
 		return filepath.Join(stagingBase, "1-ripped", "movies", safeName)
 	case ripper.MediaTypeTV:
 		season := fmt.Sprintf("S%02d", req.Season)
@@ -212,6 +388,71 @@ func buildOutputDir(stagingBase string, req *ripper.RipRequest) string {
 	}
 }
 
+// loadConfig loads the config for the ripper's optional settings. The ripper
+// is configured from the environment, so without -config a missing default
+// config just means defaults; a default config that exists, or one given
+// with -config, has to load.
+func loadConfig(configPath string) (*config.Config, error) {
+	cfg, err := config.LoadFromFlag(configPath)
+	if err != nil {
+		if configPath != "" || !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		return &config.Config{}, nil
+	}
+	return cfg, nil
+}
+
+// ejectDisc opens the tray of the drive holding discPath
+func ejectDisc(discPath string) error {
+	name, args, err := ejectCommand(runtime.GOOS, discPath)
+	if err != nil {
+		return err
+	}
+	if out, err := exec.Command(name, args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ejectCommand returns the platform eject command for a MakeMKV disc path.
+// "disc:N" is MakeMKV's Nth drive; "dev:/dev/sr0" and plain device paths
+// name the drive directly.
+func ejectCommand(goos, discPath string) (string, []string, error) {
+	index := -1
+	device := ""
+	switch {
+	case strings.HasPrefix(discPath, "disc:"):
+		n, err := strconv.Atoi(strings.TrimPrefix(discPath, "disc:"))
+		if err != nil || n < 0 {
+			return "", nil, fmt.Errorf("invalid disc path %q", discPath)
+		}
+		index = n
+	case strings.HasPrefix(discPath, "dev:"):
+		device = strings.TrimPrefix(discPath, "dev:")
+	case strings.HasPrefix(discPath, "/dev/"):
+		device = discPath
+	default:
+		return "", nil, fmt.Errorf("%q is not a disc drive", discPath)
+	}
+
+	switch goos {
+	case "linux":
+		if device == "" {
+			device = fmt.Sprintf("/dev/sr%d", index)
+		}
+		return "eject", []string{device}, nil
+	case "darwin":
+		// drutil numbers drives from 1 and doesn't take device paths
+		if index < 0 {
+			return "drutil", []string{"tray", "eject"}, nil
+		}
+		return "drutil", []string{"tray", "eject", "-drive", strconv.Itoa(index + 1)}, nil
+	default:
+		return "", nil, fmt.Errorf("ejecting discs is not supported on %s", goos)
+	}
+}
+
 // loggerAdapter adapts logging.Logger to ripper.Logger interface
 type loggerAdapter struct {
 	*logging.Logger
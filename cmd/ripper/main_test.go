@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/cuivienor/media-pipeline/internal/config"
 	"github.com/cuivienor/media-pipeline/internal/db"
 	"github.com/cuivienor/media-pipeline/internal/model"
 	"github.com/cuivienor/media-pipeline/internal/ripper"
@@ -226,6 +229,71 @@ func TestBuildRipRequest_TVMissingDisc(t *testing.T) {
 	}
 }
 
+func TestBuildRipRequest_UnsafeName(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+	repo := db.NewSQLiteRepository(database)
+
+	item := &model.MediaItem{
+		Type:     model.MediaTypeMovie,
+		Name:     "???",
+		SafeName: "???",
+	}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("Failed to create media item: %v", err)
+	}
+
+	job := &model.Job{
+		MediaItemID: item.ID,
+		Stage:       model.StageRip,
+		Status:      model.JobStatusPending,
+	}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	if _, err := buildRipRequest(ctx, repo, job, item, "disc:0"); err == nil {
+		t.Error("buildRipRequest should reject a name with no safe characters")
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	req := &ripper.RipRequest{Type: ripper.MediaTypeTV, Name: "Breaking Bad", Season: 2, Disc: 3}
+
+	// Zero keeps the job's values, and a matching value doesn't warn
+	warnings, err := applyOverrides(req, ripOverrides{disc: 3})
+	if err != nil {
+		t.Fatalf("applyOverrides failed: %v", err)
+	}
+	if len(warnings) != 0 || req.Season != 2 || req.Disc != 3 {
+		t.Errorf("got season=%d disc=%d warnings=%q, want job's values and no warnings", req.Season, req.Disc, warnings)
+	}
+
+	warnings, err = applyOverrides(req, ripOverrides{season: 1, disc: 4})
+	if err != nil {
+		t.Fatalf("applyOverrides failed: %v", err)
+	}
+	if req.Season != 1 || req.Disc != 4 {
+		t.Errorf("got season=%d disc=%d, want season=1 disc=4", req.Season, req.Disc)
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "season 1 instead of the job's season 2") {
+		t.Errorf("warnings = %q, want one per overridden value", warnings)
+	}
+	if got, want := buildOutputDir("/mnt/media/staging", req), "/mnt/media/staging/1-ripped/tv/Breaking_Bad/S01/Disc4"; got != want {
+		t.Errorf("outputDir = %q, want %q", got, want)
+	}
+
+	movie := &ripper.RipRequest{Type: ripper.MediaTypeMovie, Name: "The Matrix"}
+	if _, err := applyOverrides(movie, ripOverrides{disc: 2}); err == nil {
+		t.Error("expected error overriding the disc of a movie")
+	}
+}
+
 func TestBuildOutputDir_Movie(t *testing.T) {
 	req := &ripper.RipRequest{
 		Type: ripper.MediaTypeMovie,
@@ -240,6 +308,21 @@ func TestBuildOutputDir_Movie(t *testing.T) {
 	}
 }
 
+func TestBuildOutputDir_MovieSecondDisc(t *testing.T) {
+	req := &ripper.RipRequest{
+		Type: ripper.MediaTypeMovie,
+		Name: "The Matrix",
+		Disc: 2,
+	}
+
+	outputDir := buildOutputDir("/mnt/media/staging", req)
+	expected := "/mnt/media/staging/1-ripped/movies/The_Matrix_Disc2"
+
+	if outputDir != expected {
+		t.Errorf("outputDir = %q, want %q", outputDir, expected)
+	}
+}
+
 func TestBuildOutputDir_TVShow(t *testing.T) {
 	req := &ripper.RipRequest{
 		Type:   ripper.MediaTypeTV,
@@ -255,3 +338,102 @@ func TestBuildOutputDir_TVShow(t *testing.T) {
 		t.Errorf("outputDir = %q, want %q", outputDir, expected)
 	}
 }
+
+func TestEjectCommand(t *testing.T) {
+	tests := []struct {
+		goos     string
+		discPath string
+		want     string
+		wantErr  bool
+	}{
+		{"linux", "disc:0", "eject /dev/sr0", false},
+		{"linux", "disc:1", "eject /dev/sr1", false},
+		{"linux", "dev:/dev/sr2", "eject /dev/sr2", false},
+		{"linux", "/dev/cdrom", "eject /dev/cdrom", false},
+		{"darwin", "disc:0", "drutil tray eject -drive 1", false},
+		{"darwin", "/dev/disk3", "drutil tray eject", false},
+		{"linux", "file:/isos/movie.iso", "", true},
+		{"linux", "disc:x", "", true},
+		{"windows", "disc:0", "", true},
+	}
+
+	for _, tt := range tests {
+		name, args, err := ejectCommand(tt.goos, tt.discPath)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ejectCommand(%s, %q) expected error", tt.goos, tt.discPath)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ejectCommand(%s, %q) error = %v", tt.goos, tt.discPath, err)
+			continue
+		}
+		if got := strings.Join(append([]string{name}, args...), " "); got != tt.want {
+			t.Errorf("ejectCommand(%s, %q) = %q, want %q", tt.goos, tt.discPath, got, tt.want)
+		}
+	}
+}
+
+func TestRun_InvalidDiscPathFailsJob(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("MEDIA_BASE", tmpDir)
+	dbPath := filepath.Join(tmpDir, "test.db")
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+	repo := db.NewSQLiteRepository(database)
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "The Matrix", SafeName: "The_Matrix"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("Failed to create media item: %v", err)
+	}
+	job := &model.Job{MediaItemID: item.ID, Stage: model.StageRip, Status: model.JobStatusPending}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+
+	discPath := filepath.Join(tmpDir, "missing.iso")
+	if err := run(ctx, job.ID, dbPath, "", discPath, "info", ripOverrides{}, false); err == nil {
+		t.Fatal("expected an error for a missing disc image")
+	}
+
+	got, err := repo.GetJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if got.Status != model.JobStatusFailed {
+		t.Errorf("job status = %s, want failed", got.Status)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("MEDIA_BASE", t.TempDir())
+
+	// No config at the default location just means defaults
+	cfg, err := loadConfig("")
+	if err != nil || cfg == nil {
+		t.Errorf("loadConfig(\"\") = %v, %v; want defaults", cfg, err)
+	}
+
+	if _, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("loadConfig() of a missing -config file should fail")
+	}
+
+	// A default config that exists but doesn't validate isn't ignored
+	path := config.ConfigPath(os.Getenv("MEDIA_BASE"))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("staging_base: relative\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(""); err == nil {
+		t.Error("loadConfig(\"\") of an invalid default config should fail")
+	}
+}
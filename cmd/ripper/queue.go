@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/dispatch"
+	"github.com/cuivienor/media-pipeline/internal/logging"
+	"github.com/cuivienor/media-pipeline/internal/model"
+	"github.com/cuivienor/media-pipeline/internal/ripper"
+)
+
+// discPollInterval is how often the drive is probed while waiting for a disc swap
+const discPollInterval = 5 * time.Second
+
+// errDiscTimeout is returned when no new disc shows up before the timeout
+var errDiscTimeout = errors.New("timed out waiting for the next disc")
+
+// runQueue rips the next remaining discs of the season after jobID. For each
+// one it ejects the finished disc, unless the rip already did, waits for a
+// new disc at discPath, creates a rip job for the next disc number and runs
+// it like any other job.
+func runQueue(workCtx context.Context, jobID int64, dbPath, configPath, discPath string, remaining int, timeout time.Duration, logLevel string) error {
+	ctx := context.WithoutCancel(workCtx)
+
+	database, err := db.Open(dbPath)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+
+	prev, err := repo.GetJob(ctx, jobID)
+	if err != nil {
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+	if prev == nil || prev.SeasonID == nil || prev.Disc == nil {
+		return fmt.Errorf("-discs requires a TV season rip job")
+	}
+
+	level, err := logging.ParseLevel(logLevel)
+	if err != nil {
+		return err
+	}
+	logger := logging.New(logging.Options{Stdout: os.Stdout, MinLevel: level})
+
+	// With rip_eject set, each rip opens the tray itself
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	runner := ripper.NewMakeMKVRunner(os.Getenv("MAKEMKVCON_PATH"))
+	present := func(ctx context.Context) bool {
+		ready, err := runner.DriveReady(ctx, discPath)
+		if err != nil {
+			logger.Debug("Failed to check drive: %v", err)
+		}
+		return ready
+	}
+
+	for i := 0; i < remaining; i++ {
+		if !cfg.RipEject {
+			if err := ejectDisc(discPath); err != nil {
+				logger.Warn("Failed to eject disc: %v", err)
+			}
+		}
+
+		disc := *prev.Disc + 1
+		logger.Info("Insert disc %d and close the tray (waiting up to %s)", disc, timeout)
+		if err := waitForNewDisc(workCtx, present, discPollInterval, timeout); err != nil {
+			return fmt.Errorf("disc %d: %w", disc, err)
+		}
+
+		job := &model.Job{
+			MediaItemID: prev.MediaItemID,
+			SeasonID:    prev.SeasonID,
+			Stage:       model.StageRip,
+			Status:      model.JobStatusPending,
+			Disc:        &disc,
+		}
+		// Same guards as any other start, so a rip started by hand for
+		// this season meanwhile isn't duplicated
+		if err := dispatch.CreateJob(ctx, repo, job, false); err != nil {
+			return fmt.Errorf("failed to create job for disc %d: %w", disc, err)
+		}
+
+		if err := run(workCtx, job.ID, dbPath, configPath, discPath, logLevel, ripOverrides{}, false); err != nil {
+			return fmt.Errorf("disc %d: %w", disc, err)
+		}
+		prev = job
+	}
+
+	return nil
+}
+
+// waitForNewDisc waits for the drive to empty and then for a disc to be
+// present again, so the disc that was just ripped is never picked up twice
+func waitForNewDisc(ctx context.Context, present func(context.Context) bool, interval, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for _, want := range []bool{false, true} {
+		for present(ctx) != want {
+			select {
+			case <-ctx.Done():
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return errDiscTimeout
+				}
+				return ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// probeSequence returns a presence probe that reports each state in turn,
+// then repeats the last one
+func probeSequence(states ...bool) func(context.Context) bool {
+	i := 0
+	return func(context.Context) bool {
+		s := states[min(i, len(states)-1)]
+		i++
+		return s
+	}
+}
+
+func TestWaitForNewDisc(t *testing.T) {
+	tests := []struct {
+		name    string
+		states  []bool
+		wantErr error
+	}{
+		{"swapped", []bool{true, true, false, false, true}, nil},
+		{"already empty then inserted", []bool{false, true}, nil},
+		{"old disc never removed", []bool{true}, errDiscTimeout},
+		{"no disc inserted", []bool{true, false}, errDiscTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := waitForNewDisc(context.Background(), probeSequence(tt.states...), time.Millisecond, 50*time.Millisecond)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("waitForNewDisc() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWaitForNewDisc_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := waitForNewDisc(ctx, probeSequence(true), time.Millisecond, time.Minute)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("waitForNewDisc() error = %v, want context.Canceled", err)
+	}
+}
@@ -2,40 +2,62 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 	"time"
 
 	"github.com/cuivienor/media-pipeline/internal/config"
 	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/fsutil"
 	"github.com/cuivienor/media-pipeline/internal/logging"
 	"github.com/cuivienor/media-pipeline/internal/model"
+	"github.com/cuivienor/media-pipeline/internal/progress"
 	"github.com/cuivienor/media-pipeline/internal/transcode"
 )
 
 func main() {
 	var jobID int64
 	var dbPath string
+	var configPath string
+	var inputDir string
+	var profile string
+	var logLevel string
 
 	flag.Int64Var(&jobID, "job-id", 0, "Job ID to execute")
 	flag.StringVar(&dbPath, "db", "", "Path to database")
+	flag.StringVar(&configPath, "config", "", "Config file (defaults to $MEDIA_BASE/pipeline/config.yaml)")
+	flag.StringVar(&inputDir, "input-dir", "", "Use this directory as input instead of the previous stage's recorded output")
+	flag.StringVar(&logLevel, "log-level", "info", "Minimum level to log: error, info or debug")
+	flag.StringVar(&profile, "profile", "", "Transcode profile from config (defaults to the one chosen for the job, if any)")
 	flag.Parse()
 
 	if jobID == 0 || dbPath == "" {
-		fmt.Fprintln(os.Stderr, "Usage: transcode -job-id <id> -db <path>")
+		fmt.Fprintln(os.Stderr, "Usage: transcode -job-id <id> -db <path> [-config <path>] [-input-dir <path>] [-profile <name>] [-log-level <level>]")
+		os.Exit(1)
+	}
+	if _, err := logging.ParseLevel(logLevel); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	if err := run(jobID, dbPath); err != nil {
+	// Cancel running work on Ctrl+C or SIGTERM so the job is left retryable
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := run(ctx, jobID, dbPath, configPath, inputDir, profile, logLevel)
+	stop()
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run(jobID int64, dbPath string) error {
-	ctx := context.Background()
+func run(workCtx context.Context, jobID int64, dbPath, configPath string, inputOverride, profile, logLevel string) error {
+	// Database updates must still land after cancellation
+	ctx := context.WithoutCancel(workCtx)
 
 	// Open database
 	database, err := db.Open(dbPath)
@@ -67,7 +89,7 @@ func run(jobID int64, dbPath string) error {
 	}
 
 	// Load config
-	cfg, err := config.LoadFromMediaBase()
+	cfg, err := config.LoadFromFlag(configPath)
 	if err != nil {
 		markFailed(err.Error())
 		return fmt.Errorf("failed to load config: %w", err)
@@ -79,7 +101,13 @@ func run(jobID int64, dbPath string) error {
 		return fmt.Errorf("failed to create log directory: %w", err)
 	}
 	logPath := cfg.JobLogPath(jobID)
-	logger, err := logging.NewForJob(logPath, true, nil)
+	logger, err := logging.NewForJob(logPath, true, nil, logging.JobOptions{
+		JobID:      jobID,
+		Format:     logging.Format(cfg.LogFormat()),
+		Level:      logLevel,
+		MaxBytes:   cfg.LogMaxBytes(),
+		MaxBackups: cfg.LogMaxBackups(),
+	})
 	if err != nil {
 		markFailed(err.Error())
 		return fmt.Errorf("failed to create logger: %w", err)
@@ -88,43 +116,69 @@ func run(jobID int64, dbPath string) error {
 
 	logger.Info("Starting transcode: type=%s name=%q", item.Type, item.Name)
 
-	// Get transcode options (defaults from config, overridable per-job)
-	opts := transcode.TranscodeOptions{
-		CRF:      cfg.TranscodeCRF(),
-		Mode:     cfg.TranscodeMode(),
-		Preset:   cfg.TranscodePreset(),
-		HWPreset: cfg.TranscodeHWPreset(),
-	}
-
-	// Check for per-job overrides
+	// Get transcode options: job options > profile > config defaults
 	jobOpts, err := repo.GetJobOptions(ctx, jobID)
-	if err == nil && jobOpts != nil {
-		if crf, ok := jobOpts["crf"].(float64); ok {
-			opts.CRF = int(crf)
-		}
-		if mode, ok := jobOpts["mode"].(string); ok {
-			opts.Mode = mode
+	if err != nil {
+		logger.Warn("Ignoring unreadable job options: %v", err)
+		jobOpts = nil
+	}
+	opts, profile, err := resolveOptions(cfg, profile, jobOpts)
+	if err != nil {
+		logger.Error("Invalid transcode profile: %v", err)
+		markFailed(err.Error())
+		return fmt.Errorf("invalid transcode profile: %w", err)
+	}
+	if profile != "" {
+		logger.Info("Transcode profile: %s", profile)
+		// Record the profile so a retry encodes the same way
+		if jobOpts["profile"] != profile {
+			if jobOpts == nil {
+				jobOpts = map[string]interface{}{}
+			}
+			jobOpts["profile"] = profile
+			if err := repo.SetJobOptions(ctx, jobID, jobOpts); err != nil {
+				logger.Warn("Failed to record transcode profile: %v", err)
+			}
 		}
 	}
 
-	logger.Info("Transcode options: CRF=%d, mode=%s, preset=%s", opts.CRF, opts.Mode, opts.Preset)
+	// Reject unknown values before doing any work (job overrides bypass config validation)
+	if err := validateOptions(opts); err != nil {
+		logger.Error("Invalid transcode options: %v", err)
+		markFailed(err.Error())
+		return fmt.Errorf("invalid transcode options: %w", err)
+	}
 
-	// Check hardware support if requested
-	if opts.Mode == "hardware" {
-		if err := transcode.CheckHardwareSupport(); err != nil {
+	logger.Info("Transcode options: CRF=%d, mode=%s, preset=%s, audio=%s", opts.CRF, opts.Mode, opts.Preset, opts.AudioCodec)
+	if opts.ExtrasCRF > 0 {
+		logger.Info("Extras use CRF=%d", opts.ExtrasCRF)
+	}
+	logger.Info("Using ffmpeg=%s ffprobe=%s", opts.FFmpegPath, opts.FFprobePath)
+
+	// Check hardware support if requested; auto falls back to software
+	switch opts.Mode {
+	case "hardware":
+		if err := transcode.CheckHardwareSupport(opts.FFmpegPath); err != nil {
 			logger.Error("Hardware encoding requested but not available: %v", err)
 			markFailed(fmt.Sprintf("hardware encoding not available: %v", err))
 			return fmt.Errorf("hardware encoding not available: %w", err)
 		}
 		logger.Info("Hardware encoding (QSV) available")
+	case "auto":
+		if err := transcode.CheckHardwareSupport(opts.FFmpegPath); err != nil {
+			logger.Info("Hardware encoding not available, using software: %v", err)
+			opts.Mode = "software"
+		} else {
+			logger.Info("Hardware encoding (QSV) available, using hardware")
+			opts.Mode = "hardware"
+		}
 	}
 
-	// Find input directory from remux job
-	inputDir, err := findRemuxOutput(ctx, repo, job)
+	// Find input directory from remux job, unless given explicitly
+	inputDir, err := resolveInput(ctx, repo, job, inputOverride, logger)
 	if err != nil {
-		logger.Error("Failed to find input: %v", err)
 		markFailed(err.Error())
-		return fmt.Errorf("failed to find input: %w", err)
+		return err
 	}
 
 	// Determine output directory
@@ -138,12 +192,27 @@ func run(jobID int64, dbPath string) error {
 	logger.Info("Input directory: %s", inputDir)
 	logger.Info("Output directory: %s", outputDir)
 
+	// Make sure the output has room before starting a long encode
+	inputSize, err := fsutil.DirSize(inputDir)
+	if err != nil {
+		logger.Error("Failed to measure input: %v", err)
+		markFailed(err.Error())
+		return fmt.Errorf("failed to measure input: %w", err)
+	}
+	needed := int64(float64(inputSize) * cfg.FreeSpaceTranscodeMultiplier())
+	if err := fsutil.CheckFreeSpace(outputDir, needed); err != nil {
+		logger.Error("Preflight failed: %v", err)
+		markFailed(err.Error())
+		return err
+	}
+
 	// Update job to in_progress
 	job.Status = model.JobStatusInProgress
 	job.InputDir = inputDir
 	job.OutputDir = outputDir
 	now := time.Now()
 	job.StartedAt = &now
+	job.SetWorker()
 	if err := repo.UpdateJob(ctx, job); err != nil {
 		markFailed(err.Error())
 		return fmt.Errorf("failed to update job status: %w", err)
@@ -151,10 +220,40 @@ func run(jobID int64, dbPath string) error {
 
 	// Create transcoder and process
 	transcoder := transcode.NewTranscoder(repo, logger, opts)
+	if webhook := progress.NewWebhookSink(cfg.ProgressWebhook, model.StageTranscode.String()); webhook != nil {
+		logger.Info("Streaming progress to %s", cfg.ProgressWebhook)
+		defer webhook.Close()
+		transcoder.SetProgressSink(webhook)
+	}
+
+	// Raw ffmpeg output and progress every 10% go to the job log at debug level
+	transcoder.SetLineCallback(func(line string) {
+		if line != "" {
+			logger.Debug("[ffmpeg] %s", line)
+		}
+	})
+	var progressFile int64
+	lastLogged := 0
+	transcoder.SetFileProgressCallback(func(file *model.TranscodeFile, percent int) {
+		if file.ID != progressFile {
+			progressFile, lastLogged = file.ID, 0
+		}
+		if percent/10 > lastLogged/10 {
+			lastLogged = percent
+			logger.Debug("%s: %d%%", file.RelativePath, percent)
+		}
+	})
+	transcoder.SetExtractSubs(cfg.Transcode.ExtractSubs)
+	transcoder.SetContinueOnError(cfg.TranscodeContinueOnError())
 	isTV := item.Type == model.MediaTypeTV
 
-	err = transcoder.TranscodeJob(ctx, job, inputDir, outputDir, isTV)
+	err = transcoder.TranscodeJob(workCtx, job, inputDir, outputDir, isTV)
 	if err != nil {
+		if workCtx.Err() != nil {
+			logger.Info("Transcode cancelled, unfinished files reset to pending")
+			markFailed("cancelled")
+			return err
+		}
 		logger.Error("Transcode failed: %v", err)
 		markFailed(err.Error())
 		return err
@@ -170,10 +269,52 @@ func run(jobID int64, dbPath string) error {
 		return fmt.Errorf("failed to update item stage: %w", err)
 	}
 
-	logger.Info("Transcode finished successfully")
+	// Report space savings across all files of the job
+	if stats, err := repo.GetTranscodeStats(ctx, jobID); err != nil {
+		logger.Error("Failed to get transcode stats: %v", err)
+	} else if stats.InputBytes > 0 {
+		logger.Info("Saved %.0f%% (%.1f GB → %.1f GB)",
+			stats.SavedPercent(),
+			float64(stats.InputBytes)/(1024*1024*1024),
+			float64(stats.OutputBytes)/(1024*1024*1024))
+	}
+
+	logger.Info("Transcode finished successfully in %s", job.Duration().Round(time.Second))
 	return nil
 }
 
+// resolveInput returns the -input-dir override if set, bypassing the job
+// history for recovery when it disagrees with the filesystem. Otherwise it
+// checks the previous stage completed and returns its output.
+func resolveInput(ctx context.Context, repo db.Repository, job *model.Job, override string, logger *logging.Logger) (string, error) {
+	if override != "" {
+		dir, err := fsutil.InputDir(override)
+		if err != nil {
+			logger.Error("Invalid -input-dir: %v", err)
+			return "", err
+		}
+		logger.Info("Using -input-dir, skipping the remux output lookup")
+		return dir, nil
+	}
+
+	// Refuse to run ahead of the previous stage
+	if ok, reason := repo.CanStartStage(ctx, job.MediaItemID, job.Stage, job.SeasonID); !ok {
+		logger.Error("%s", reason)
+		return "", errors.New(reason)
+	}
+
+	inputDir, err := findRemuxOutput(ctx, repo, job)
+	if err != nil {
+		logger.Error("Failed to find input: %v", err)
+		return "", fmt.Errorf("failed to find input: %w", err)
+	}
+	if err := fsutil.StageOutputDir(model.StageRemux.String(), inputDir); err != nil {
+		logger.Error("%v (use -input-dir if it was moved)", err)
+		return "", err
+	}
+	return inputDir, nil
+}
+
 // findRemuxOutput finds the output directory from the remux stage
 func findRemuxOutput(ctx context.Context, repo db.Repository, job *model.Job) (string, error) {
 	jobs, err := repo.ListJobsForMedia(ctx, job.MediaItemID)
@@ -212,3 +353,57 @@ func buildOutputPath(ctx context.Context, repo db.Repository, cfg *config.Config
 
 	return filepath.Join(cfg.StagingBase, "3-transcoded", mediaTypeDir, baseName), nil
 }
+
+// resolveOptions builds the transcode options for a job. The profile comes
+// from the -profile flag, or else the one stored in the job options; its
+// settings are then overridden by any crf or mode set on the job itself.
+// It returns the profile used, "" for the config defaults.
+func resolveOptions(cfg *config.Config, profile string, jobOpts map[string]interface{}) (transcode.TranscodeOptions, string, error) {
+	if profile == "" {
+		profile, _ = jobOpts["profile"].(string)
+	}
+	settings, err := cfg.TranscodeSettings(profile)
+	if err != nil {
+		return transcode.TranscodeOptions{}, "", err
+	}
+
+	opts := transcode.TranscodeOptions{
+		CRF:          settings.CRF,
+		ExtrasCRF:    settings.ExtrasCRF,
+		Mode:         settings.Mode,
+		Preset:       settings.Preset,
+		HWPreset:     settings.HWPreset,
+		AudioCodec:   settings.AudioCodec,
+		AudioBitrate: settings.AudioBitrate,
+		FFmpegPath:   cfg.FFmpegPath(),
+		FFprobePath:  cfg.FFprobePath(),
+	}
+	if crf, ok := jobOpts["crf"].(float64); ok {
+		opts.CRF = int(crf)
+	}
+	if mode, ok := jobOpts["mode"].(string); ok {
+		opts.Mode = mode
+	}
+	return opts, profile, nil
+}
+
+// validateOptions checks the effective mode, presets and CRF after per-job overrides
+func validateOptions(opts transcode.TranscodeOptions) error {
+	var errs []error
+	if opts.CRF < 0 || opts.CRF > 51 {
+		errs = append(errs, fmt.Errorf("crf must be between 0 and 51, got %d", opts.CRF))
+	}
+	if opts.ExtrasCRF < 0 || opts.ExtrasCRF > 51 {
+		errs = append(errs, fmt.Errorf("extras_crf must be between 0 and 51, got %d", opts.ExtrasCRF))
+	}
+	if err := config.ValidateTranscodeMode(opts.Mode); err != nil {
+		errs = append(errs, err)
+	}
+	if err := config.ValidateTranscodePreset(opts.Preset); err != nil {
+		errs = append(errs, err)
+	}
+	if err := config.ValidateTranscodeHWPreset(opts.HWPreset); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/logging"
+	"github.com/cuivienor/media-pipeline/internal/model"
+	"github.com/cuivienor/media-pipeline/internal/transcode"
+)
+
+func TestValidateOptions(t *testing.T) {
+	valid := transcode.TranscodeOptions{CRF: 20, Mode: "software", Preset: "slow", HWPreset: "medium"}
+
+	tests := []struct {
+		name    string
+		modify  func(o *transcode.TranscodeOptions)
+		wantErr string
+	}{
+		{"valid", func(o *transcode.TranscodeOptions) {}, ""},
+		{"auto mode", func(o *transcode.TranscodeOptions) { o.Mode = "auto" }, ""},
+		{"typo in mode", func(o *transcode.TranscodeOptions) { o.Mode = "hw" }, `unknown transcode mode "hw"`},
+		{"bad preset", func(o *transcode.TranscodeOptions) { o.Preset = "slowest" }, `unknown x265 preset "slowest"`},
+		{"bad hw preset", func(o *transcode.TranscodeOptions) { o.HWPreset = "placebo" }, `unknown QSV preset "placebo"`},
+		{"crf override out of range", func(o *transcode.TranscodeOptions) { o.CRF = 99 }, "crf must be between 0 and 51"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := valid
+			tt.modify(&opts)
+			err := validateOptions(opts)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateOptions() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateOptions() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveInput(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+	logger := logging.New(logging.Options{})
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	job := &model.Job{MediaItemID: item.ID, Stage: model.StageTranscode, Status: model.JobStatusPending}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	// No remux has run, so only an explicit directory works
+	if _, err := resolveInput(ctx, repo, job, "", logger); err == nil || !strings.Contains(err.Error(), "no completed remux job") {
+		t.Errorf("resolveInput() without remux error = %v, want missing remux", err)
+	}
+
+	moved := t.TempDir()
+	got, err := resolveInput(ctx, repo, job, moved, logger)
+	if err != nil {
+		t.Fatalf("resolveInput(override) error = %v", err)
+	}
+	if got != moved {
+		t.Errorf("resolveInput(override) = %q, want %q", got, moved)
+	}
+
+	if _, err := resolveInput(ctx, repo, job, filepath.Join(moved, "missing"), logger); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("resolveInput(missing) error = %v, want not exist", err)
+	}
+
+	// The remux output was deleted after the remux job finished
+	deleted := filepath.Join(moved, "deleted")
+	remux := &model.Job{MediaItemID: item.ID, Stage: model.StageRemux, Status: model.JobStatusCompleted, OutputDir: deleted}
+	if err := repo.CreateJob(ctx, remux); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	if _, err := resolveInput(ctx, repo, job, "", logger); err == nil || !strings.Contains(err.Error(), "input directory from remux job no longer exists: "+deleted) {
+		t.Errorf("resolveInput() with deleted remux output error = %v", err)
+	}
+
+	if err := os.Mkdir(deleted, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := resolveInput(ctx, repo, job, "", logger); err != nil || got != deleted {
+		t.Errorf("resolveInput() = %q, %v; want the remux output", got, err)
+	}
+}
+
+func TestResolveOptions(t *testing.T) {
+	cfg := &config.Config{Transcode: config.TranscodeConfig{
+		CRF:       20,
+		ExtrasCRF: 24,
+		Profiles: map[string]config.TranscodeProfile{
+			"mobile":  {CRF: 26, ExtrasCRF: 30, Mode: "auto", AudioCodec: "aac"},
+			"archive": {CRF: 16},
+		},
+	}}
+
+	tests := []struct {
+		name        string
+		flag        string
+		jobOpts     map[string]interface{}
+		wantProfile string
+		wantCRF     int
+		wantMode    string
+		wantAudio   string
+		wantExtras  int
+	}{
+		{"defaults", "", nil, "", 20, "software", "copy", 24},
+		{"profile flag", "mobile", nil, "mobile", 26, "auto", "aac", 30},
+		{"stored profile", "", map[string]interface{}{"profile": "archive"}, "archive", 16, "software", "copy", 24},
+		{"flag beats stored profile", "mobile", map[string]interface{}{"profile": "archive"}, "mobile", 26, "auto", "aac", 30},
+		{"job options beat profile", "", map[string]interface{}{"profile": "mobile", "crf": float64(30), "mode": "software"}, "mobile", 30, "software", "aac", 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, profile, err := resolveOptions(cfg, tt.flag, tt.jobOpts)
+			if err != nil {
+				t.Fatalf("resolveOptions() error = %v", err)
+			}
+			if profile != tt.wantProfile {
+				t.Errorf("profile = %q, want %q", profile, tt.wantProfile)
+			}
+			if opts.CRF != tt.wantCRF || opts.Mode != tt.wantMode || opts.AudioCodec != tt.wantAudio {
+				t.Errorf("opts = crf %d mode %s audio %s, want crf %d mode %s audio %s",
+					opts.CRF, opts.Mode, opts.AudioCodec, tt.wantCRF, tt.wantMode, tt.wantAudio)
+			}
+			if opts.ExtrasCRF != tt.wantExtras {
+				t.Errorf("opts.ExtrasCRF = %d, want %d", opts.ExtrasCRF, tt.wantExtras)
+			}
+		})
+	}
+
+	if _, _, err := resolveOptions(cfg, "tablet", nil); err == nil {
+		t.Error("resolveOptions() with an unknown profile should fail")
+	}
+}
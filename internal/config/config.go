@@ -1,10 +1,16 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 
+	"github.com/cuivienor/media-pipeline/internal/logging"
 	"gopkg.in/yaml.v3"
 )
 
@@ -12,31 +18,153 @@ const (
 	defaultMediaBase = "/mnt/media"
 	pipelineDirName  = "pipeline"
 	configFileName   = "config.yaml"
+
+	defaultMaxLogBytes   = 50 << 20
+	defaultMaxLogBackups = 3
+)
+
+// Default FileBot naming formats
+const (
+	DefaultMovieFormat = "{n} ({y})/{n} ({y})"
+	DefaultTVFormat    = "{n}/Season {s.pad(2)}/{n} - {s00e00} - {t}"
 )
 
+// DefaultPosterTimestamp is where in the video the poster frame is taken,
+// late enough to be past studio logos
+const DefaultPosterTimestamp = "00:02:00"
+
 // RemuxConfig holds remux-specific configuration
 type RemuxConfig struct {
 	Languages []string `yaml:"languages"`
+
+	// FallbackKeepFirstAudio keeps a file's first audio track when none is in Languages
+	FallbackKeepFirstAudio bool `yaml:"fallback_keep_first_audio"`
+
+	// KeepChapters copies the disc's chapter markers (default true)
+	KeepChapters *bool `yaml:"keep_chapters"`
+
+	ClearTrackTitles bool `yaml:"clear_track_titles"` // Drop disc track titles like "Surround 5.1"
+	SetFileTitle     bool `yaml:"set_file_title"`     // Set each file's title to the item name
+
+	MaxAudioPerLang int `yaml:"max_audio_per_lang"` // Keep at most N audio tracks per language (0 = all)
+	MaxSubsPerLang  int `yaml:"max_subs_per_lang"`  // Keep at most N subtitle tracks per language (0 = all)
 }
 
 // TranscodeConfig holds transcode-specific configuration
 type TranscodeConfig struct {
-	CRF      int    `yaml:"crf"`       // Quality (0-51, default 20)
-	Mode     string `yaml:"mode"`      // "software" or "hardware"
-	Preset   string `yaml:"preset"`    // libx265 preset (default "slow")
-	HWPreset string `yaml:"hw_preset"` // QSV preset (default "medium")
+	CRF         int    `yaml:"crf"`          // Quality (0-51, default 20)
+	ExtrasCRF   int    `yaml:"extras_crf"`   // Quality for _extras/ (0-51, default same as crf)
+	Mode        string `yaml:"mode"`         // "software", "hardware" or "auto"
+	Preset      string `yaml:"preset"`       // libx265 preset (default "slow")
+	HWPreset    string `yaml:"hw_preset"`    // QSV preset (default "medium")
+	FFmpegPath  string `yaml:"ffmpeg_path"`  // ffmpeg binary (default "ffmpeg" from PATH)
+	FFprobePath string `yaml:"ffprobe_path"` // ffprobe binary (default "ffprobe" from PATH)
+	ExtractSubs bool   `yaml:"extract_subs"` // Also write text subtitle tracks to sidecar .srt files
+
+	// ContinueOnError keeps transcoding a job's other files after one fails,
+	// failing the job at the end (default true)
+	ContinueOnError *bool `yaml:"continue_on_error"`
+
+	// Profiles are named bundles of encode settings, picked per job
+	Profiles map[string]TranscodeProfile `yaml:"profiles"`
+}
+
+// TranscodeProfile is a named set of encode settings. Unset fields fall back
+// to the transcode section.
+type TranscodeProfile struct {
+	CRF          int    `yaml:"crf"`
+	ExtrasCRF    int    `yaml:"extras_crf"`
+	Mode         string `yaml:"mode"`
+	Preset       string `yaml:"preset"`
+	HWPreset     string `yaml:"hw_preset"`
+	AudioCodec   string `yaml:"audio_codec"`   // ffmpeg audio encoder, e.g. "aac" (default "copy")
+	AudioBitrate string `yaml:"audio_bitrate"` // e.g. "160k", only used when re-encoding audio
+}
+
+// PublishConfig holds publish-specific configuration
+type PublishConfig struct {
+	MovieFormat string `yaml:"movie_format"` // FileBot format for movies
+	TVFormat    string `yaml:"tv_format"`    // FileBot format for TV shows
+	WriteNFO    bool   `yaml:"write_nfo"`    // Write movie.nfo/tvshow.nfo for Jellyfin/Kodi
+
+	// CleanupStaging removes the item's rip/remux/transcode outputs after a verified publish
+	CleanupStaging bool `yaml:"cleanup_staging"`
+
+	GeneratePoster  bool   `yaml:"generate_poster"`  // Extract a video frame as poster.jpg if none exists
+	PosterTimestamp string `yaml:"poster_timestamp"` // Where to take the frame, e.g. "00:02:00" (default)
+
+	// CopyRateLimit caps the copy of extras into the library, in bytes per second
+	// (0 = unlimited). Main content goes through FileBot and isn't throttled.
+	CopyRateLimit  int64 `yaml:"copy_rate_limit"`
+	CopyBufferSize int   `yaml:"copy_buffer_size"` // Buffer for copying extras in bytes (default 1 MiB)
+
+	// PreservePermissions gives copied extras their staging permissions instead of the umask default
+	PreservePermissions bool `yaml:"preserve_permissions"`
+
+	// Movie libraries: LibraryMovies defaults to library_base/movies, and
+	// movies at least Min4KWidth pixels wide go to LibraryMovies4K when set
+	LibraryMovies   string `yaml:"library_movies"`
+	LibraryMovies4K string `yaml:"library_movies_4k"`
+	Min4KWidth      int    `yaml:"min_4k_width"` // default 3200, so cropped 4K still counts
+}
+
+// LoggingConfig holds per-job log settings
+type LoggingConfig struct {
+	Format        string `yaml:"format"`          // Job log file format: "text" or "json" (default: text)
+	Level         string `yaml:"level"`           // Minimum level dispatched jobs log: error, info or debug (default: info)
+	MaxLogBytes   int64  `yaml:"max_log_bytes"`   // Rotate job.log at this size (default: 50 MiB)
+	MaxLogBackups *int   `yaml:"max_log_backups"` // Rotated job logs to keep; 0 keeps none (default: 3)
+}
+
+// FreeSpaceConfig holds the multipliers used to estimate each stage's output size
+// for the free-space preflight check
+type FreeSpaceConfig struct {
+	RipMultiplier       float64 `yaml:"rip_multiplier"`       // × disc title sizes (default 1.05)
+	TranscodeMultiplier float64 `yaml:"transcode_multiplier"` // × remuxed input size (default 0.8)
+	PublishMultiplier   float64 `yaml:"publish_multiplier"`   // × transcoded input size (default 1.0)
+}
+
+// RemoteConfig describes the filesystem layout of an SSH dispatch target
+type RemoteConfig struct {
+	MediaBase string `yaml:"media_base"` // MEDIA_BASE on the remote host
+	DBPath    string `yaml:"db_path"`    // Database path on the remote host
 }
 
 // Config holds application configuration
 type Config struct {
-	StagingBase string            `yaml:"staging_base"` // Staging directory
-	LibraryBase string            `yaml:"library_base"` // Library directory
-	Dispatch    map[string]string `yaml:"dispatch"`     // SSH targets per stage
-	Remux       RemuxConfig       `yaml:"remux"`        // Remux configuration
-	Transcode   TranscodeConfig   `yaml:"transcode"`    // Transcode configuration
+	StagingBase string                  `yaml:"staging_base"` // Staging directory
+	LibraryBase string                  `yaml:"library_base"` // Library directory
+	Dispatch    map[string]string       `yaml:"dispatch"`     // SSH targets per stage
+	Remotes     map[string]RemoteConfig `yaml:"remotes"`      // Paths per SSH target
+	Remux       RemuxConfig             `yaml:"remux"`        // Remux configuration
+	Transcode   TranscodeConfig         `yaml:"transcode"`    // Transcode configuration
+	Publish     PublishConfig           `yaml:"publish"`      // Publish configuration
+	FreeSpace   FreeSpaceConfig         `yaml:"free_space"`   // Preflight space estimates
+	Logging     LoggingConfig           `yaml:"logging"`      // Job log settings
+
+	// ProgressWebhook is an optional URL that receives live progress updates
+	ProgressWebhook string `yaml:"progress_webhook"`
+
+	// RipEject ejects the disc after a successful rip, signalling it can be swapped
+	RipEject bool `yaml:"rip_eject"`
+
+	// RipScaffold lists the _extras subfolders created after each rip (nil
+	// for every Jellyfin extras type, empty for none)
+	RipScaffold []string `yaml:"rip_scaffold"`
+
+	// API keys for looking up TMDB/TVDB IDs by title (lookups are disabled when empty)
+	TMDBAPIKey string `yaml:"tmdb_api_key"`
+	TVDBAPIKey string `yaml:"tvdb_api_key"`
+
+	// APIToken authorizes requests that start jobs through the HTTP API
+	// (those requests are refused when empty)
+	APIToken string `yaml:"api_token"`
 
 	// Derived from environment, not stored in YAML
 	mediaBase string
+
+	// File given with -config, passed on to jobs dispatched locally
+	configFile string
 }
 
 // MediaBase returns the MEDIA_BASE path
@@ -44,6 +172,11 @@ func (c *Config) MediaBase() string {
 	if c.mediaBase != "" {
 		return c.mediaBase
 	}
+	return MediaBaseFromEnv()
+}
+
+// MediaBaseFromEnv returns $MEDIA_BASE, or /mnt/media if unset
+func MediaBaseFromEnv() string {
 	if base := os.Getenv("MEDIA_BASE"); base != "" {
 		return base
 	}
@@ -75,6 +208,11 @@ func (c *Config) ToolLogPath(jobID int64, tool string) string {
 	return filepath.Join(c.JobLogDir(jobID), fmt.Sprintf("%s.log", tool))
 }
 
+// FailureReportPath returns where the TUI writes a failed job's report
+func (c *Config) FailureReportPath(jobID int64) string {
+	return filepath.Join(c.DataDir(), "reports", fmt.Sprintf("failure-report-%d.txt", jobID))
+}
+
 // EnsureJobLogDir creates the log directory for a specific job
 func (c *Config) EnsureJobLogDir(jobID int64) error {
 	return os.MkdirAll(c.JobLogDir(jobID), 0755)
@@ -93,6 +231,24 @@ func (c *Config) IsLocal(stage string) bool {
 	return c.DispatchTarget(stage) == ""
 }
 
+// RemoteMediaBase returns MEDIA_BASE for an SSH dispatch target
+// Defaults to the local MEDIA_BASE (shared filesystem) if not configured
+func (c *Config) RemoteMediaBase(target string) string {
+	if remote, ok := c.Remotes[target]; ok && remote.MediaBase != "" {
+		return remote.MediaBase
+	}
+	return c.MediaBase()
+}
+
+// RemoteDatabasePath returns the database path for an SSH dispatch target
+// Defaults to the standard location under the target's MEDIA_BASE if not configured
+func (c *Config) RemoteDatabasePath(target string) string {
+	if remote, ok := c.Remotes[target]; ok && remote.DBPath != "" {
+		return remote.DBPath
+	}
+	return filepath.Join(c.RemoteMediaBase(target), pipelineDirName, "pipeline.db")
+}
+
 // RemuxLanguages returns the list of languages to keep during remux
 // Defaults to ["eng"] if not configured
 func (c *Config) RemuxLanguages() []string {
@@ -102,6 +258,15 @@ func (c *Config) RemuxLanguages() []string {
 	return c.Remux.Languages
 }
 
+// RemuxKeepChapters reports whether remux keeps chapter markers
+// Defaults to true if not configured
+func (c *Config) RemuxKeepChapters() bool {
+	if c.Remux.KeepChapters == nil {
+		return true
+	}
+	return *c.Remux.KeepChapters
+}
+
 // TranscodeCRF returns the CRF value for transcoding
 // Defaults to 20 if not configured
 func (c *Config) TranscodeCRF() int {
@@ -111,7 +276,7 @@ func (c *Config) TranscodeCRF() int {
 	return c.Transcode.CRF
 }
 
-// TranscodeMode returns the encoding mode ("software" or "hardware")
+// TranscodeMode returns the encoding mode ("software", "hardware" or "auto")
 // Defaults to "software" if not configured
 func (c *Config) TranscodeMode() string {
 	if c.Transcode.Mode == "" {
@@ -138,16 +303,401 @@ func (c *Config) TranscodeHWPreset() string {
 	return c.Transcode.HWPreset
 }
 
+// TranscodeContinueOnError reports whether a transcode job moves on to its
+// remaining files after one fails
+// Defaults to true if not configured
+func (c *Config) TranscodeContinueOnError() bool {
+	if c.Transcode.ContinueOnError == nil {
+		return true
+	}
+	return *c.Transcode.ContinueOnError
+}
+
+// TranscodeProfileNames returns the configured profile names in sorted order
+func (c *Config) TranscodeProfileNames() []string {
+	return slices.Sorted(maps.Keys(c.Transcode.Profiles))
+}
+
+// TranscodeSettings returns the encode settings for a profile, with anything
+// the profile leaves unset taken from the transcode section's settings. An
+// empty name returns the transcode section's settings alone.
+func (c *Config) TranscodeSettings(profile string) (TranscodeProfile, error) {
+	settings := TranscodeProfile{
+		CRF:        c.TranscodeCRF(),
+		ExtrasCRF:  c.Transcode.ExtrasCRF,
+		Mode:       c.TranscodeMode(),
+		Preset:     c.TranscodePreset(),
+		HWPreset:   c.TranscodeHWPreset(),
+		AudioCodec: "copy",
+	}
+	if profile == "" {
+		return settings, nil
+	}
+
+	p, ok := c.Transcode.Profiles[profile]
+	if !ok {
+		names := c.TranscodeProfileNames()
+		if len(names) == 0 {
+			return TranscodeProfile{}, fmt.Errorf("unknown transcode profile %q (no profiles configured)", profile)
+		}
+		return TranscodeProfile{}, fmt.Errorf("unknown transcode profile %q (expected one of %s)", profile, strings.Join(names, ", "))
+	}
+	if p.CRF != 0 {
+		settings.CRF = p.CRF
+	}
+	if p.ExtrasCRF != 0 {
+		settings.ExtrasCRF = p.ExtrasCRF
+	}
+	if p.Mode != "" {
+		settings.Mode = p.Mode
+	}
+	if p.Preset != "" {
+		settings.Preset = p.Preset
+	}
+	if p.HWPreset != "" {
+		settings.HWPreset = p.HWPreset
+	}
+	if p.AudioCodec != "" {
+		settings.AudioCodec = p.AudioCodec
+	}
+	settings.AudioBitrate = p.AudioBitrate
+	return settings, nil
+}
+
+// FFmpegPath returns the ffmpeg binary to run
+// Defaults to "ffmpeg" (resolved from PATH) if not configured
+func (c *Config) FFmpegPath() string {
+	if c.Transcode.FFmpegPath == "" {
+		return "ffmpeg"
+	}
+	return c.Transcode.FFmpegPath
+}
+
+// FFprobePath returns the ffprobe binary to run
+// Defaults to "ffprobe" (resolved from PATH) if not configured
+func (c *Config) FFprobePath() string {
+	if c.Transcode.FFprobePath == "" {
+		return "ffprobe"
+	}
+	return c.Transcode.FFprobePath
+}
+
+// PublishMovieFormat returns the FileBot format string for movies
+// Defaults to DefaultMovieFormat if not configured
+func (c *Config) PublishMovieFormat() string {
+	if c.Publish.MovieFormat == "" {
+		return DefaultMovieFormat
+	}
+	return c.Publish.MovieFormat
+}
+
+// PublishTVFormat returns the FileBot format string for TV shows
+// Defaults to DefaultTVFormat if not configured
+func (c *Config) PublishTVFormat() string {
+	if c.Publish.TVFormat == "" {
+		return DefaultTVFormat
+	}
+	return c.Publish.TVFormat
+}
+
+// LogFormat returns the format used for job log files
+// Defaults to "text" if not configured
+func (c *Config) LogFormat() string {
+	if c.Logging.Format == "" {
+		return "text"
+	}
+	return c.Logging.Format
+}
+
+// LogMaxBytes returns the size at which a job log file is rotated
+// Defaults to 50 MiB if not configured
+func (c *Config) LogMaxBytes() int64 {
+	if c.Logging.MaxLogBytes == 0 {
+		return defaultMaxLogBytes
+	}
+	return c.Logging.MaxLogBytes
+}
+
+// LogMaxBackups returns the number of rotated job log files to keep
+// Defaults to 3 if not configured; an explicit 0 keeps none
+func (c *Config) LogMaxBackups() int {
+	if c.Logging.MaxLogBackups == nil {
+		return defaultMaxLogBackups
+	}
+	return *c.Logging.MaxLogBackups
+}
+
+// FreeSpaceRipMultiplier returns the factor applied to disc size to estimate rip output
+// Defaults to 1.05 if not configured
+func (c *Config) FreeSpaceRipMultiplier() float64 {
+	if c.FreeSpace.RipMultiplier == 0 {
+		return 1.05
+	}
+	return c.FreeSpace.RipMultiplier
+}
+
+// FreeSpaceTranscodeMultiplier returns the factor applied to input size to estimate transcode output
+// Defaults to 0.8 if not configured
+func (c *Config) FreeSpaceTranscodeMultiplier() float64 {
+	if c.FreeSpace.TranscodeMultiplier == 0 {
+		return 0.8
+	}
+	return c.FreeSpace.TranscodeMultiplier
+}
+
+// PublishPosterTimestamp returns where in the video the poster frame is taken
+// Defaults to DefaultPosterTimestamp if not configured
+func (c *Config) PublishPosterTimestamp() string {
+	if c.Publish.PosterTimestamp == "" {
+		return DefaultPosterTimestamp
+	}
+	return c.Publish.PosterTimestamp
+}
+
+// FreeSpacePublishMultiplier returns the factor applied to input size to estimate publish output
+// Defaults to 1.0 if not configured
+func (c *Config) FreeSpacePublishMultiplier() float64 {
+	if c.FreeSpace.PublishMultiplier == 0 {
+		return 1.0
+	}
+	return c.FreeSpace.PublishMultiplier
+}
+
 // LibraryMoviesPath returns the path to the movies library
+// Defaults to library_base/movies if publish.library_movies is not configured
 func (c *Config) LibraryMoviesPath() string {
+	if c.Publish.LibraryMovies != "" {
+		return c.Publish.LibraryMovies
+	}
 	return filepath.Join(c.LibraryBase, "movies")
 }
 
+// LibraryMovies4KPath returns the path to the 4K movies library, or "" when
+// 4K movies share the movies library
+func (c *Config) LibraryMovies4KPath() string {
+	return c.Publish.LibraryMovies4K
+}
+
 // LibraryTVPath returns the path to the TV library
 func (c *Config) LibraryTVPath() string {
 	return filepath.Join(c.LibraryBase, "tv")
 }
 
+// Recognized transcode settings
+var (
+	TranscodeModes = []string{"software", "hardware", "auto"}
+	X265Presets    = []string{"ultrafast", "superfast", "veryfast", "faster", "fast", "medium", "slow", "slower", "veryslow", "placebo"}
+	QSVPresets     = []string{"veryfast", "faster", "fast", "medium", "slow", "slower", "veryslow"}
+)
+
+// ValidateTranscodeMode returns an error if mode is not a recognized encoding mode
+func ValidateTranscodeMode(mode string) error {
+	if !slices.Contains(TranscodeModes, mode) {
+		return fmt.Errorf("unknown transcode mode %q (expected one of %s)", mode, strings.Join(TranscodeModes, ", "))
+	}
+	return nil
+}
+
+// ValidateTranscodePreset returns an error if preset is not a libx265 preset
+func ValidateTranscodePreset(preset string) error {
+	if !slices.Contains(X265Presets, preset) {
+		return fmt.Errorf("unknown x265 preset %q (expected one of %s)", preset, strings.Join(X265Presets, ", "))
+	}
+	return nil
+}
+
+// ValidateTranscodeHWPreset returns an error if preset is not a QSV preset
+func ValidateTranscodeHWPreset(preset string) error {
+	if !slices.Contains(QSVPresets, preset) {
+		return fmt.Errorf("unknown QSV preset %q (expected one of %s)", preset, strings.Join(QSVPresets, ", "))
+	}
+	return nil
+}
+
+// posterTimestampPattern matches the ffmpeg positions accepted for the
+// poster frame: plain seconds or [HH:]MM:SS, optionally fractional
+var posterTimestampPattern = regexp.MustCompile(`^(\d+:)?(\d+:)?\d+(\.\d+)?$`)
+
+// validateFilebotFormat checks that a FileBot format isn't blank and that
+// its {expression} braces pair up
+func validateFilebotFormat(format string) error {
+	if strings.TrimSpace(format) == "" {
+		return errors.New("must not be blank")
+	}
+	depth := 0
+	for _, r := range format {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		}
+		if depth < 0 {
+			break
+		}
+	}
+	if depth != 0 {
+		return fmt.Errorf("unbalanced braces in %q", format)
+	}
+	return nil
+}
+
+// dispatchStages lists the stages that can be dispatched to an SSH target
+var dispatchStages = []string{"rip", "remux", "transcode", "publish"}
+
+// Validate checks that required settings are present and values are in range.
+// All problems are reported together so a config can be fixed in one pass.
+func (c *Config) Validate() error {
+	var errs []error
+
+	for _, p := range []struct {
+		key   string
+		value string
+	}{
+		{"staging_base", c.StagingBase},
+		{"library_base", c.LibraryBase},
+	} {
+		switch {
+		case p.value == "":
+			errs = append(errs, fmt.Errorf("%s is required", p.key))
+		case !filepath.IsAbs(p.value):
+			errs = append(errs, fmt.Errorf("%s must be an absolute path, got %q", p.key, p.value))
+		}
+	}
+
+	for _, p := range []struct {
+		key   string
+		value string
+	}{
+		{"publish.library_movies", c.Publish.LibraryMovies},
+		{"publish.library_movies_4k", c.Publish.LibraryMovies4K},
+	} {
+		if p.value != "" && !filepath.IsAbs(p.value) {
+			errs = append(errs, fmt.Errorf("%s must be an absolute path, got %q", p.key, p.value))
+		}
+	}
+
+	for _, stage := range slices.Sorted(maps.Keys(c.Dispatch)) {
+		if !slices.Contains(dispatchStages, stage) {
+			errs = append(errs, fmt.Errorf("dispatch: unknown stage %q (expected one of %s)",
+				stage, strings.Join(dispatchStages, ", ")))
+		}
+	}
+
+	if _, err := logging.ParseFormat(c.Logging.Format); err != nil {
+		errs = append(errs, fmt.Errorf("logging.format: %w", err))
+	}
+	switch c.Logging.Level {
+	case "", "error", "info", "debug":
+	default:
+		errs = append(errs, fmt.Errorf("logging.level must be error, info or debug, got %q", c.Logging.Level))
+	}
+	if c.Logging.MaxLogBytes < 0 {
+		errs = append(errs, fmt.Errorf("logging.max_log_bytes must not be negative, got %d", c.Logging.MaxLogBytes))
+	}
+	if c.Logging.MaxLogBackups != nil && *c.Logging.MaxLogBackups < 0 {
+		errs = append(errs, fmt.Errorf("logging.max_log_backups must not be negative, got %d", *c.Logging.MaxLogBackups))
+	}
+	if c.Remux.MaxAudioPerLang < 0 {
+		errs = append(errs, fmt.Errorf("remux.max_audio_per_lang must not be negative, got %d", c.Remux.MaxAudioPerLang))
+	}
+	if c.Remux.MaxSubsPerLang < 0 {
+		errs = append(errs, fmt.Errorf("remux.max_subs_per_lang must not be negative, got %d", c.Remux.MaxSubsPerLang))
+	}
+	if c.Publish.CopyRateLimit < 0 {
+		errs = append(errs, fmt.Errorf("publish.copy_rate_limit must not be negative, got %d", c.Publish.CopyRateLimit))
+	}
+	if c.Publish.CopyBufferSize < 0 {
+		errs = append(errs, fmt.Errorf("publish.copy_buffer_size must not be negative, got %d", c.Publish.CopyBufferSize))
+	}
+	if c.Publish.Min4KWidth < 0 {
+		errs = append(errs, fmt.Errorf("publish.min_4k_width must not be negative, got %d", c.Publish.Min4KWidth))
+	}
+	for _, folder := range c.RipScaffold {
+		if folder == "" || folder == "." || folder == ".." || strings.ContainsAny(folder, `/\`) {
+			errs = append(errs, fmt.Errorf("rip_scaffold entries must be folder names, got %q", folder))
+		}
+	}
+	if c.Transcode.CRF < 0 || c.Transcode.CRF > 51 {
+		errs = append(errs, fmt.Errorf("transcode.crf must be between 0 and 51, got %d", c.Transcode.CRF))
+	}
+	if c.Transcode.ExtrasCRF < 0 || c.Transcode.ExtrasCRF > 51 {
+		errs = append(errs, fmt.Errorf("transcode.extras_crf must be between 0 and 51, got %d", c.Transcode.ExtrasCRF))
+	}
+	for _, m := range []struct {
+		key   string
+		value float64
+	}{
+		{"free_space.rip_multiplier", c.FreeSpace.RipMultiplier},
+		{"free_space.transcode_multiplier", c.FreeSpace.TranscodeMultiplier},
+		{"free_space.publish_multiplier", c.FreeSpace.PublishMultiplier},
+	} {
+		if m.value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %g", m.key, m.value))
+		}
+	}
+	if err := ValidateTranscodeMode(c.TranscodeMode()); err != nil {
+		errs = append(errs, fmt.Errorf("transcode.mode: %w", err))
+	}
+	if err := ValidateTranscodePreset(c.TranscodePreset()); err != nil {
+		errs = append(errs, fmt.Errorf("transcode.preset: %w", err))
+	}
+	if err := ValidateTranscodeHWPreset(c.TranscodeHWPreset()); err != nil {
+		errs = append(errs, fmt.Errorf("transcode.hw_preset: %w", err))
+	}
+	for _, name := range c.TranscodeProfileNames() {
+		errs = append(errs, c.validateTranscodeProfile(name)...)
+	}
+	for _, f := range []struct {
+		key   string
+		value string
+	}{
+		{"publish.movie_format", c.PublishMovieFormat()},
+		{"publish.tv_format", c.PublishTVFormat()},
+	} {
+		if err := validateFilebotFormat(f.value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.key, err))
+		}
+	}
+	if ts := c.PublishPosterTimestamp(); !posterTimestampPattern.MatchString(ts) {
+		errs = append(errs, fmt.Errorf("publish.poster_timestamp must be seconds or [HH:]MM:SS, got %q", ts))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateTranscodeProfile checks the settings a profile sets itself
+func (c *Config) validateTranscodeProfile(name string) []error {
+	p := c.Transcode.Profiles[name]
+	key := "transcode.profiles." + name
+	var errs []error
+	if p.CRF < 0 || p.CRF > 51 {
+		errs = append(errs, fmt.Errorf("%s.crf must be between 0 and 51, got %d", key, p.CRF))
+	}
+	if p.ExtrasCRF < 0 || p.ExtrasCRF > 51 {
+		errs = append(errs, fmt.Errorf("%s.extras_crf must be between 0 and 51, got %d", key, p.ExtrasCRF))
+	}
+	if p.Mode != "" {
+		if err := ValidateTranscodeMode(p.Mode); err != nil {
+			errs = append(errs, fmt.Errorf("%s.mode: %w", key, err))
+		}
+	}
+	if p.Preset != "" {
+		if err := ValidateTranscodePreset(p.Preset); err != nil {
+			errs = append(errs, fmt.Errorf("%s.preset: %w", key, err))
+		}
+	}
+	if p.HWPreset != "" {
+		if err := ValidateTranscodeHWPreset(p.HWPreset); err != nil {
+			errs = append(errs, fmt.Errorf("%s.hw_preset: %w", key, err))
+		}
+	}
+	if p.AudioBitrate != "" && (p.AudioCodec == "" || p.AudioCodec == "copy") {
+		errs = append(errs, fmt.Errorf("%s.audio_bitrate needs an audio_codec other than copy", key))
+	}
+	return errs
+}
+
 // Load reads configuration from a YAML file
 func Load(path string) (*Config, error) {
 	data, err := os.ReadFile(path)
@@ -185,17 +735,49 @@ func LoadDefault() (*Config, error) {
 
 // LoadFromMediaBase loads config from $MEDIA_BASE/pipeline/config.yaml
 func LoadFromMediaBase() (*Config, error) {
-	mediaBase := os.Getenv("MEDIA_BASE")
-	if mediaBase == "" {
-		mediaBase = defaultMediaBase
+	mediaBase := MediaBaseFromEnv()
+	cfg, err := loadValidated(ConfigPath(mediaBase))
+	if err != nil {
+		return nil, err
 	}
 
-	configPath := filepath.Join(mediaBase, pipelineDirName, configFileName)
-	cfg, err := Load(configPath)
+	cfg.mediaBase = mediaBase
+	return cfg, nil
+}
+
+// LoadFromFlag loads config from path, as given with -config, or from
+// $MEDIA_BASE/pipeline/config.yaml if path is empty. MEDIA_BASE still
+// locates the database and logs.
+func LoadFromFlag(path string) (*Config, error) {
+	if path == "" {
+		return LoadFromMediaBase()
+	}
+
+	cfg, err := loadValidated(path)
 	if err != nil {
 		return nil, err
 	}
 
-	cfg.mediaBase = mediaBase
+	cfg.mediaBase = MediaBaseFromEnv()
+	cfg.configFile = path
 	return cfg, nil
 }
+
+// loadValidated loads config from path and validates it
+func loadValidated(path string) (*Config, error) {
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s:\n%w", path, err)
+	}
+	return cfg, nil
+}
+
+// ConfigFile returns the file given with -config, or "" if the config was
+// loaded from MEDIA_BASE
+func (c *Config) ConfigFile() string {
+	return c.configFile
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -74,6 +75,16 @@ func TestConfig_ToolLogPath(t *testing.T) {
 	}
 }
 
+func TestConfig_FailureReportPath(t *testing.T) {
+	cfg := &Config{mediaBase: "/mnt/media"}
+
+	got := cfg.FailureReportPath(123)
+	want := "/mnt/media/pipeline/reports/failure-report-123.txt"
+	if got != want {
+		t.Errorf("FailureReportPath(123) = %q, want %q", got, want)
+	}
+}
+
 func TestConfig_EnsureJobLogDir(t *testing.T) {
 	tmpDir := t.TempDir()
 	// Create pipeline subdir so DataDir() works
@@ -246,6 +257,43 @@ dispatch:
 	}
 }
 
+func TestLoadFromFlag(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("MEDIA_BASE", tmpDir)
+	os.MkdirAll(filepath.Join(tmpDir, "pipeline"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "pipeline", "config.yaml"), []byte("staging_base: /mnt/media/staging\nlibrary_base: /mnt/media/library\n"), 0644)
+
+	staging := filepath.Join(t.TempDir(), "staging.yaml")
+	os.WriteFile(staging, []byte("staging_base: /mnt/test/staging\nlibrary_base: /mnt/test/library\n"), 0644)
+
+	cfg, err := LoadFromFlag(staging)
+	if err != nil {
+		t.Fatalf("LoadFromFlag() error = %v", err)
+	}
+	if cfg.StagingBase != "/mnt/test/staging" || cfg.ConfigFile() != staging {
+		t.Errorf("StagingBase = %q, ConfigFile() = %q, want the -config file", cfg.StagingBase, cfg.ConfigFile())
+	}
+	if cfg.MediaBase() != tmpDir {
+		t.Errorf("MediaBase() = %q, want %q", cfg.MediaBase(), tmpDir)
+	}
+
+	// Without -config the MEDIA_BASE config is used
+	cfg, err = LoadFromFlag("")
+	if err != nil {
+		t.Fatalf("LoadFromFlag(\"\") error = %v", err)
+	}
+	if cfg.StagingBase != "/mnt/media/staging" || cfg.ConfigFile() != "" {
+		t.Errorf("StagingBase = %q, ConfigFile() = %q, want the MEDIA_BASE config", cfg.StagingBase, cfg.ConfigFile())
+	}
+
+	// The file is validated like the default one
+	invalid := filepath.Join(t.TempDir(), "invalid.yaml")
+	os.WriteFile(invalid, []byte("staging_base: /mnt/test/staging\n"), 0644)
+	if _, err := LoadFromFlag(invalid); err == nil || !strings.Contains(err.Error(), invalid) {
+		t.Errorf("LoadFromFlag() error = %v, want it to name the invalid file", err)
+	}
+}
+
 func TestLoadFromMediaBase_DefaultPath(t *testing.T) {
 	// Without MEDIA_BASE set, should use /mnt/media
 	// Unset MEDIA_BASE to test default
@@ -345,6 +393,27 @@ remux:
 	}
 }
 
+func TestLoad_RemuxFallbackKeepFirstAudio(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+staging_base: /mnt/media/staging
+library_base: /mnt/media/library
+remux:
+  fallback_keep_first_audio: true
+`
+	os.WriteFile(configPath, []byte(configContent), 0644)
+
+	cfg, err := Load(configPath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.Remux.FallbackKeepFirstAudio {
+		t.Error("Remux.FallbackKeepFirstAudio = false, want true")
+	}
+}
+
 func TestConfig_RemuxLanguages_Default(t *testing.T) {
 	tmpDir := t.TempDir()
 	pipelineDir := filepath.Join(tmpDir, "pipeline")
@@ -370,6 +439,32 @@ library_base: /mnt/media/library
 	}
 }
 
+func TestConfig_RemuxKeepChapters(t *testing.T) {
+	cfg := &Config{}
+	if !cfg.RemuxKeepChapters() {
+		t.Error("RemuxKeepChapters() default = false, want true")
+	}
+
+	keep := false
+	cfg.Remux.KeepChapters = &keep
+	if cfg.RemuxKeepChapters() {
+		t.Error("RemuxKeepChapters() = true with keep_chapters: false")
+	}
+}
+
+func TestConfig_TranscodeContinueOnError(t *testing.T) {
+	cfg := &Config{}
+	if !cfg.TranscodeContinueOnError() {
+		t.Error("TranscodeContinueOnError() default = false, want true")
+	}
+
+	stop := false
+	cfg.Transcode.ContinueOnError = &stop
+	if cfg.TranscodeContinueOnError() {
+		t.Error("TranscodeContinueOnError() = true with continue_on_error: false")
+	}
+}
+
 func TestConfig_TranscodeDefaults(t *testing.T) {
 	cfg := &Config{}
 
@@ -387,6 +482,53 @@ func TestConfig_TranscodeDefaults(t *testing.T) {
 	}
 }
 
+func TestConfig_TranscodeSettings(t *testing.T) {
+	cfg := &Config{
+		Transcode: TranscodeConfig{
+			CRF:    22,
+			Preset: "medium",
+			Profiles: map[string]TranscodeProfile{
+				"mobile":  {CRF: 26, Mode: "auto", AudioCodec: "aac", AudioBitrate: "160k"},
+				"archive": {CRF: 18, Preset: "slower"},
+			},
+		},
+	}
+
+	if names := cfg.TranscodeProfileNames(); strings.Join(names, ",") != "archive,mobile" {
+		t.Errorf("TranscodeProfileNames() = %v, want [archive mobile]", names)
+	}
+
+	defaults, err := cfg.TranscodeSettings("")
+	if err != nil {
+		t.Fatalf("TranscodeSettings(\"\") error = %v", err)
+	}
+	want := TranscodeProfile{CRF: 22, Mode: "software", Preset: "medium", HWPreset: "medium", AudioCodec: "copy"}
+	if defaults != want {
+		t.Errorf("TranscodeSettings(\"\") = %+v, want %+v", defaults, want)
+	}
+
+	mobile, err := cfg.TranscodeSettings("mobile")
+	if err != nil {
+		t.Fatalf("TranscodeSettings(mobile) error = %v", err)
+	}
+	want = TranscodeProfile{CRF: 26, Mode: "auto", Preset: "medium", HWPreset: "medium", AudioCodec: "aac", AudioBitrate: "160k"}
+	if mobile != want {
+		t.Errorf("TranscodeSettings(mobile) = %+v, want %+v", mobile, want)
+	}
+
+	archive, err := cfg.TranscodeSettings("archive")
+	if err != nil {
+		t.Fatalf("TranscodeSettings(archive) error = %v", err)
+	}
+	if archive.CRF != 18 || archive.Preset != "slower" || archive.Mode != "software" || archive.AudioCodec != "copy" {
+		t.Errorf("TranscodeSettings(archive) = %+v, want crf 18 slower with defaults", archive)
+	}
+
+	if _, err := cfg.TranscodeSettings("tablet"); err == nil || !strings.Contains(err.Error(), "archive, mobile") {
+		t.Errorf("TranscodeSettings(tablet) error = %v, want unknown profile listing the options", err)
+	}
+}
+
 func TestConfig_TranscodeCustom(t *testing.T) {
 	cfg := &Config{
 		Transcode: TranscodeConfig{
@@ -410,3 +552,376 @@ func TestConfig_TranscodeCustom(t *testing.T) {
 		t.Errorf("TranscodeHWPreset() = %q, want %q", got, "fast")
 	}
 }
+
+func TestConfig_PublishFormats(t *testing.T) {
+	cfg := &Config{}
+
+	if got := cfg.PublishMovieFormat(); got != "{n} ({y})/{n} ({y})" {
+		t.Errorf("PublishMovieFormat() default = %q", got)
+	}
+	if got := cfg.PublishTVFormat(); got != "{n}/Season {s.pad(2)}/{n} - {s00e00} - {t}" {
+		t.Errorf("PublishTVFormat() default = %q", got)
+	}
+
+	cfg.Publish = PublishConfig{
+		MovieFormat: "{n} ({y}) {edition}/{n} ({y})",
+		TVFormat:    "{n}/Season {s}/{n} - {sxe} - {t}",
+	}
+
+	if got := cfg.PublishMovieFormat(); got != "{n} ({y}) {edition}/{n} ({y})" {
+		t.Errorf("PublishMovieFormat() = %q", got)
+	}
+	if got := cfg.PublishTVFormat(); got != "{n}/Season {s}/{n} - {sxe} - {t}" {
+		t.Errorf("PublishTVFormat() = %q", got)
+	}
+}
+
+func TestConfig_PublishPosterTimestamp(t *testing.T) {
+	cfg := &Config{}
+	if got := cfg.PublishPosterTimestamp(); got != "00:02:00" {
+		t.Errorf("PublishPosterTimestamp() default = %q", got)
+	}
+
+	cfg.Publish.PosterTimestamp = "330"
+	if got := cfg.PublishPosterTimestamp(); got != "330" {
+		t.Errorf("PublishPosterTimestamp() = %q", got)
+	}
+}
+
+func TestConfig_MovieLibraries(t *testing.T) {
+	cfg := &Config{LibraryBase: "/mnt/library"}
+	if got := cfg.LibraryMoviesPath(); got != "/mnt/library/movies" {
+		t.Errorf("LibraryMoviesPath() default = %q", got)
+	}
+	if got := cfg.LibraryMovies4KPath(); got != "" {
+		t.Errorf("LibraryMovies4KPath() default = %q, want none", got)
+	}
+
+	cfg.Publish.LibraryMovies = "/mnt/hd/movies"
+	cfg.Publish.LibraryMovies4K = "/mnt/uhd/movies"
+	if got := cfg.LibraryMoviesPath(); got != "/mnt/hd/movies" {
+		t.Errorf("LibraryMoviesPath() = %q", got)
+	}
+	if got := cfg.LibraryMovies4KPath(); got != "/mnt/uhd/movies" {
+		t.Errorf("LibraryMovies4KPath() = %q", got)
+	}
+}
+
+func TestConfig_FFmpegPaths(t *testing.T) {
+	cfg := &Config{}
+
+	if got := cfg.FFmpegPath(); got != "ffmpeg" {
+		t.Errorf("FFmpegPath() default = %q, want %q", got, "ffmpeg")
+	}
+	if got := cfg.FFprobePath(); got != "ffprobe" {
+		t.Errorf("FFprobePath() default = %q, want %q", got, "ffprobe")
+	}
+
+	cfg.Transcode.FFmpegPath = "/opt/ffmpeg-6/bin/ffmpeg"
+	cfg.Transcode.FFprobePath = "/opt/ffmpeg-6/bin/ffprobe"
+
+	if got := cfg.FFmpegPath(); got != "/opt/ffmpeg-6/bin/ffmpeg" {
+		t.Errorf("FFmpegPath() = %q", got)
+	}
+	if got := cfg.FFprobePath(); got != "/opt/ffmpeg-6/bin/ffprobe" {
+		t.Errorf("FFprobePath() = %q", got)
+	}
+}
+
+func TestConfig_LogRotation(t *testing.T) {
+	cfg := &Config{}
+
+	if got := cfg.LogMaxBytes(); got != 50<<20 {
+		t.Errorf("LogMaxBytes() default = %d, want %d", got, 50<<20)
+	}
+	if got := cfg.LogMaxBackups(); got != 3 {
+		t.Errorf("LogMaxBackups() default = %d, want 3", got)
+	}
+
+	backups := 1
+	cfg.Logging = LoggingConfig{MaxLogBytes: 1024, MaxLogBackups: &backups}
+
+	if got := cfg.LogMaxBytes(); got != 1024 {
+		t.Errorf("LogMaxBytes() = %d, want 1024", got)
+	}
+	if got := cfg.LogMaxBackups(); got != 1 {
+		t.Errorf("LogMaxBackups() = %d, want 1", got)
+	}
+
+	backups = 0
+	if got := cfg.LogMaxBackups(); got != 0 {
+		t.Errorf("LogMaxBackups() with 0 set = %d, want 0", got)
+	}
+}
+
+func TestConfig_FreeSpaceMultipliers(t *testing.T) {
+	cfg := &Config{}
+
+	if got := cfg.FreeSpaceRipMultiplier(); got != 1.05 {
+		t.Errorf("FreeSpaceRipMultiplier() default = %v, want 1.05", got)
+	}
+	if got := cfg.FreeSpaceTranscodeMultiplier(); got != 0.8 {
+		t.Errorf("FreeSpaceTranscodeMultiplier() default = %v, want 0.8", got)
+	}
+	if got := cfg.FreeSpacePublishMultiplier(); got != 1.0 {
+		t.Errorf("FreeSpacePublishMultiplier() default = %v, want 1.0", got)
+	}
+
+	cfg.FreeSpace = FreeSpaceConfig{RipMultiplier: 1.2, TranscodeMultiplier: 0.5, PublishMultiplier: 2}
+
+	if got := cfg.FreeSpaceRipMultiplier(); got != 1.2 {
+		t.Errorf("FreeSpaceRipMultiplier() = %v, want 1.2", got)
+	}
+	if got := cfg.FreeSpaceTranscodeMultiplier(); got != 0.5 {
+		t.Errorf("FreeSpaceTranscodeMultiplier() = %v, want 0.5", got)
+	}
+	if got := cfg.FreeSpacePublishMultiplier(); got != 2 {
+		t.Errorf("FreeSpacePublishMultiplier() = %v, want 2", got)
+	}
+}
+
+func TestConfig_RemotePaths(t *testing.T) {
+	t.Setenv("MEDIA_BASE", "/mnt/media")
+
+	cfg := &Config{
+		Remotes: map[string]RemoteConfig{
+			"ripper":     {MediaBase: "/srv/media"},
+			"transcoder": {MediaBase: "/data", DBPath: "/net/pipeline.db"},
+		},
+	}
+
+	tests := []struct {
+		target        string
+		wantMediaBase string
+		wantDBPath    string
+	}{
+		{"ripper", "/srv/media", "/srv/media/pipeline/pipeline.db"},
+		{"transcoder", "/data", "/net/pipeline.db"},
+		{"unknown", "/mnt/media", "/mnt/media/pipeline/pipeline.db"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			if got := cfg.RemoteMediaBase(tt.target); got != tt.wantMediaBase {
+				t.Errorf("RemoteMediaBase() = %q, want %q", got, tt.wantMediaBase)
+			}
+			if got := cfg.RemoteDatabasePath(tt.target); got != tt.wantDBPath {
+				t.Errorf("RemoteDatabasePath() = %q, want %q", got, tt.wantDBPath)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() *Config {
+		return &Config{
+			StagingBase: "/mnt/media/staging",
+			LibraryBase: "/mnt/media/library",
+			Dispatch:    map[string]string{"rip": "ripper", "transcode": ""},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr []string
+	}{
+		{
+			name:   "valid",
+			modify: func(c *Config) {},
+		},
+		{
+			name:    "missing staging base",
+			modify:  func(c *Config) { c.StagingBase = "" },
+			wantErr: []string{"staging_base is required"},
+		},
+		{
+			name:    "relative library base",
+			modify:  func(c *Config) { c.LibraryBase = "library" },
+			wantErr: []string{"library_base must be an absolute path"},
+		},
+		{
+			name:    "unknown dispatch stage",
+			modify:  func(c *Config) { c.Dispatch["ripping"] = "ripper" },
+			wantErr: []string{`unknown stage "ripping"`},
+		},
+		{
+			name:    "crf out of range",
+			modify:  func(c *Config) { c.Transcode.CRF = 63 },
+			wantErr: []string{"transcode.crf must be between 0 and 51"},
+		},
+		{
+			name:    "blank filebot format",
+			modify:  func(c *Config) { c.Publish.MovieFormat = "   " },
+			wantErr: []string{"publish.movie_format: must not be blank"},
+		},
+		{
+			name:    "unbalanced filebot format",
+			modify:  func(c *Config) { c.Publish.TVFormat = "{n}/Season {s.pad(2)/{n}" },
+			wantErr: []string{`publish.tv_format: unbalanced braces in "{n}/Season {s.pad(2)/{n}"`},
+		},
+		{
+			name:   "poster timestamp in seconds",
+			modify: func(c *Config) { c.Publish.PosterTimestamp = "90.5" },
+		},
+		{
+			name:    "bad poster timestamp",
+			modify:  func(c *Config) { c.Publish.PosterTimestamp = "2m" },
+			wantErr: []string{`publish.poster_timestamp must be seconds or [HH:]MM:SS, got "2m"`},
+		},
+		{
+			name:    "blank poster timestamp",
+			modify:  func(c *Config) { c.Publish.PosterTimestamp = " " },
+			wantErr: []string{`publish.poster_timestamp must be seconds or [HH:]MM:SS, got " "`},
+		},
+		{
+			name:    "unknown transcode mode",
+			modify:  func(c *Config) { c.Transcode.Mode = "hw" },
+			wantErr: []string{`transcode.mode: unknown transcode mode "hw"`},
+		},
+		{
+			name:   "auto transcode mode",
+			modify: func(c *Config) { c.Transcode.Mode = "auto" },
+		},
+		{
+			name:    "unknown x265 preset",
+			modify:  func(c *Config) { c.Transcode.Preset = "fastest" },
+			wantErr: []string{`transcode.preset: unknown x265 preset "fastest"`},
+		},
+		{
+			name:    "unknown QSV preset",
+			modify:  func(c *Config) { c.Transcode.HWPreset = "ultrafast" },
+			wantErr: []string{`transcode.hw_preset: unknown QSV preset "ultrafast"`},
+		},
+		{
+			name: "valid transcode profile",
+			modify: func(c *Config) {
+				c.Transcode.Profiles = map[string]TranscodeProfile{
+					"mobile": {CRF: 26, Mode: "auto", AudioCodec: "aac", AudioBitrate: "160k"},
+				}
+			},
+		},
+		{
+			name: "invalid transcode profile",
+			modify: func(c *Config) {
+				c.Transcode.Profiles = map[string]TranscodeProfile{
+					"mobile": {CRF: 60, Preset: "quick", AudioBitrate: "160k"},
+				}
+			},
+			wantErr: []string{
+				"transcode.profiles.mobile.crf must be between 0 and 51",
+				`transcode.profiles.mobile.preset: unknown x265 preset "quick"`,
+				"transcode.profiles.mobile.audio_bitrate needs an audio_codec",
+			},
+		},
+		{
+			name:    "unknown log format",
+			modify:  func(c *Config) { c.Logging.Format = "xml" },
+			wantErr: []string{`logging.format: unknown log format "xml" (valid: text, json)`},
+		},
+		{
+			name:    "unknown log level",
+			modify:  func(c *Config) { c.Logging.Level = "verbose" },
+			wantErr: []string{`logging.level must be error, info or debug, got "verbose"`},
+		},
+		{
+			name:   "json log format",
+			modify: func(c *Config) { c.Logging.Format = "json" },
+		},
+		{
+			name:    "negative log rotation settings",
+			modify:  func(c *Config) { c.Logging.MaxLogBytes = -1; n := -2; c.Logging.MaxLogBackups = &n },
+			wantErr: []string{"logging.max_log_bytes must not be negative", "logging.max_log_backups must not be negative"},
+		},
+		{
+			name:    "extras crf out of range",
+			modify:  func(c *Config) { c.Transcode.ExtrasCRF = 60 },
+			wantErr: []string{"transcode.extras_crf must be between 0 and 51"},
+		},
+		{
+			name:    "negative remux track caps",
+			modify:  func(c *Config) { c.Remux.MaxAudioPerLang = -1; c.Remux.MaxSubsPerLang = -1 },
+			wantErr: []string{"remux.max_audio_per_lang must not be negative", "remux.max_subs_per_lang must not be negative"},
+		},
+		{
+			name:    "negative publish copy settings",
+			modify:  func(c *Config) { c.Publish.CopyRateLimit = -1; c.Publish.CopyBufferSize = -1 },
+			wantErr: []string{"publish.copy_rate_limit must not be negative", "publish.copy_buffer_size must not be negative"},
+		},
+		{
+			name: "relative movie libraries",
+			modify: func(c *Config) {
+				c.Publish.LibraryMovies = "movies"
+				c.Publish.LibraryMovies4K = "movies-4k"
+				c.Publish.Min4KWidth = -1
+			},
+			wantErr: []string{
+				`publish.library_movies must be an absolute path, got "movies"`,
+				`publish.library_movies_4k must be an absolute path, got "movies-4k"`,
+				"publish.min_4k_width must not be negative",
+			},
+		},
+		{
+			name:    "rip scaffold path",
+			modify:  func(c *Config) { c.RipScaffold = []string{"featurettes", "../trailers", ""} },
+			wantErr: []string{`rip_scaffold entries must be folder names, got "../trailers"`, `rip_scaffold entries must be folder names, got ""`},
+		},
+		{
+			name:    "negative free space multiplier",
+			modify:  func(c *Config) { c.FreeSpace.TranscodeMultiplier = -1 },
+			wantErr: []string{"free_space.transcode_multiplier must not be negative"},
+		},
+		{
+			name: "multiple problems reported together",
+			modify: func(c *Config) {
+				c.StagingBase = ""
+				c.LibraryBase = ""
+			},
+			wantErr: []string{"staging_base is required", "library_base is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() error = nil, want error")
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %q, want it to contain %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadFromMediaBase_Invalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	pipelineDir := filepath.Join(tmpDir, "pipeline")
+	os.MkdirAll(pipelineDir, 0755)
+
+	configContent := `
+staging_base: staging
+`
+	os.WriteFile(filepath.Join(pipelineDir, "config.yaml"), []byte(configContent), 0644)
+
+	t.Setenv("MEDIA_BASE", tmpDir)
+
+	_, err := LoadFromMediaBase()
+	if err == nil {
+		t.Fatal("LoadFromMediaBase() expected validation error")
+	}
+	if !strings.Contains(err.Error(), "staging_base must be an absolute path") {
+		t.Errorf("error = %v, want staging_base complaint", err)
+	}
+}
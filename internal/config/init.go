@@ -0,0 +1,151 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrConfigExists is returned by WriteDefault when a config file is already present
+var ErrConfigExists = errors.New("config file already exists")
+
+// defaultConfigTemplate documents every recognized key with its default.
+// %[1]s is replaced with MEDIA_BASE.
+const defaultConfigTemplate = `# media-pipeline configuration
+# Commented-out keys show their default values.
+
+# Staging directory holding 1-ripped, 2-remuxed and 3-transcoded (required, absolute)
+staging_base: %[1]s/staging
+
+# Library directory published media is copied into (required, absolute)
+library_base: %[1]s/library
+
+# SSH target per stage; omit or leave empty to run the stage locally.
+# Valid stages: rip, remux, transcode, publish
+dispatch:
+  # rip: ripper-host
+  # remux: ""
+  # transcode: transcoder-host
+  # publish: ""
+
+# Filesystem layout of SSH targets that don't share this host's paths
+remotes:
+  # ripper-host:
+  #   media_base: %[1]s
+  #   db_path: %[1]s/pipeline/pipeline.db
+
+remux:
+  # Audio/subtitle languages to keep (ISO 639-2)
+  # languages:
+  #   - eng
+  # fallback_keep_first_audio: false  # keep the first audio track when none match, instead of a silent file
+  # keep_chapters: true   # false strips the disc's chapter markers
+  # clear_track_titles: false   # drop track titles like "Surround 5.1" (commentary tracks keep "Commentary")
+  # set_file_title: false       # set each file's title to the item name
+  # max_audio_per_lang: 0   # keep at most N audio tracks per language, most channels first (0 = all)
+  # max_subs_per_lang: 0    # keep at most the first N subtitle tracks per language (0 = all)
+
+transcode:
+  # crf: 20              # Quality, 0-51 (lower is better)
+  # mode: software       # "software" (libx265), "hardware" (QSV) or "auto"
+  # preset: slow         # libx265 preset
+  # hw_preset: medium    # QSV preset
+  # ffmpeg_path: ffmpeg  # ffmpeg binary, resolved from PATH by default
+  # ffprobe_path: ffprobe
+  # extras_crf: 24       # lower quality for _extras/ bonus content (default: same as crf)
+  # extract_subs: false  # also write text subtitle tracks to sidecar .srt files (PGS/VOBSUB are skipped)
+  # continue_on_error: true  # keep encoding a job's other files after one fails; false stops at the first failure
+  # Named encode settings picked per job with -profile or in the TUI; unset
+  # fields fall back to the settings above
+  # profiles:
+  #   archive:
+  #     crf: 18
+  #     preset: slower
+  #   mobile:
+  #     crf: 26
+  #     mode: auto
+  #     audio_codec: aac     # re-encode audio instead of copying it
+  #     audio_bitrate: 160k
+
+publish:
+  # movie_format: "%[2]s"
+  # tv_format: "%[3]s"
+  # write_nfo: false   # write movie.nfo/tvshow.nfo with the TMDB/TVDB ID
+  # cleanup_staging: false   # delete the item's 1-ripped/2-remuxed/3-transcoded copies once published
+  # generate_poster: false    # extract a video frame as poster.jpg when the library has none
+  # poster_timestamp: "%[4]s"
+  # preserve_permissions: false   # copied extras keep their staging permissions (modification times are always kept)
+  # copy_buffer_size: 1048576   # bytes read and written at a time when copying extras
+  # copy_rate_limit: 0   # bytes/sec for copying extras into the library, e.g. 50000000 to leave room for streaming (0 = unlimited)
+  #                      # only extras are throttled: the main movie/episodes are moved in by FileBot at full speed
+  # library_movies: %[1]s/library/movies   # default: library_base/movies
+  # library_movies_4k: ""   # separate library for 4K movies (empty = same as library_movies)
+  # min_4k_width: 3200      # movies at least this many pixels wide count as 4K
+
+logging:
+  # format: text   # text or json (one object per line, for log aggregators)
+  # level: info    # error, info or debug (adds raw MakeMKV and ffmpeg output)
+  # max_log_bytes: 52428800   # rotate job.log at this size (50 MiB)
+  # max_log_backups: 3        # keep job.log.1 .. job.log.3 (0 keeps none)
+
+# Estimated output size per stage, as a multiple of its input, checked
+# against free disk space before the stage starts
+free_space:
+  # rip_multiplier: 1.05       # x disc title sizes
+  # transcode_multiplier: 0.8  # x remuxed input
+  # publish_multiplier: 1.0    # x transcoded input
+
+# URL that receives live rip/transcode progress as JSON POSTs
+# progress_webhook: ""
+
+# Eject the disc once a rip succeeds, so it's safe to swap in the next one
+# rip_eject: false
+
+# _extras subfolders created for sorting each rip (default: every Jellyfin
+# extras type; [] for none)
+# rip_scaffold: [featurettes, trailers, "behind the scenes", other]
+
+# API keys for looking up IDs by title in the new item form (Ctrl+F)
+# tmdb_api_key: ""
+# tvdb_api_key: ""
+
+# Bearer token for the HTTP API's POST endpoints, which start jobs
+# (they are disabled while this is empty)
+# api_token: ""
+`
+
+// ConfigPath returns the config file location for a MEDIA_BASE
+func ConfigPath(mediaBase string) string {
+	return filepath.Join(mediaBase, pipelineDirName, configFileName)
+}
+
+// DefaultConfig returns the commented default config for a MEDIA_BASE
+func DefaultConfig(mediaBase string) string {
+	return fmt.Sprintf(defaultConfigTemplate,
+		strings.TrimRight(mediaBase, "/"),
+		DefaultMovieFormat,
+		DefaultTVFormat,
+		DefaultPosterTimestamp,
+	)
+}
+
+// WriteDefault writes the default config for mediaBase to path.
+// It returns ErrConfigExists rather than overwriting an existing file unless force is set.
+func WriteDefault(path, mediaBase string, force bool) error {
+	if !force {
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%w: %s", ErrConfigExists, path)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, []byte(DefaultConfig(mediaBase)), 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteDefault_RoundTrip(t *testing.T) {
+	mediaBase := t.TempDir()
+	path := ConfigPath(mediaBase)
+
+	if err := WriteDefault(path, mediaBase, false); err != nil {
+		t.Fatalf("WriteDefault() error = %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("default config does not validate: %v", err)
+	}
+
+	if cfg.StagingBase != filepath.Join(mediaBase, "staging") {
+		t.Errorf("StagingBase = %q", cfg.StagingBase)
+	}
+	if cfg.LibraryBase != filepath.Join(mediaBase, "library") {
+		t.Errorf("LibraryBase = %q", cfg.LibraryBase)
+	}
+
+	// Commented-out keys must leave every accessor at its built-in default
+	defaults := &Config{StagingBase: cfg.StagingBase, LibraryBase: cfg.LibraryBase}
+	if !reflect.DeepEqual(cfg.RemuxLanguages(), defaults.RemuxLanguages()) ||
+		cfg.TranscodeCRF() != defaults.TranscodeCRF() ||
+		cfg.TranscodeMode() != defaults.TranscodeMode() ||
+		cfg.PublishMovieFormat() != defaults.PublishMovieFormat() ||
+		cfg.PublishTVFormat() != defaults.PublishTVFormat() {
+		t.Error("default config changes accessor defaults")
+	}
+}
+
+func TestWriteDefault_RefusesOverwrite(t *testing.T) {
+	mediaBase := t.TempDir()
+	path := ConfigPath(mediaBase)
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte("staging_base: /custom\n"), 0644)
+
+	err := WriteDefault(path, mediaBase, false)
+	if !errors.Is(err, ErrConfigExists) {
+		t.Fatalf("WriteDefault() error = %v, want ErrConfigExists", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "staging_base: /custom\n" {
+		t.Error("existing config was modified")
+	}
+
+	if err := WriteDefault(path, mediaBase, true); err != nil {
+		t.Fatalf("WriteDefault(force) error = %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if string(data) != DefaultConfig(mediaBase) {
+		t.Error("force did not overwrite existing config")
+	}
+}
@@ -95,16 +95,34 @@ func (d *DB) migrate() error {
 			return fmt.Errorf("failed to read %s: %w", file, err)
 		}
 
-		if _, err := d.db.Exec(string(content)); err != nil {
-			return fmt.Errorf("failed to execute %s: %w", file, err)
+		if err := d.applyMigration(file, string(content)); err != nil {
+			return err
 		}
+	}
 
-		// Record that migration was applied
-		_, err = d.db.Exec("INSERT INTO schema_migrations (version) VALUES (?)", file)
-		if err != nil {
-			return fmt.Errorf("failed to record migration %s: %w", file, err)
-		}
+	return nil
+}
+
+// applyMigration runs a migration and records it in one transaction, so a
+// failed migration leaves the schema as it was and is retried on next open
+func (d *DB) applyMigration(file, content string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %s: %w", file, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(content); err != nil {
+		return fmt.Errorf("failed to execute %s: %w", file, err)
 	}
 
+	// Record that migration was applied
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES (?)", file); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", file, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", file, err)
+	}
 	return nil
 }
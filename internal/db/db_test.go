@@ -1,6 +1,8 @@
 package db
 
 import (
+	"database/sql"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -139,3 +141,96 @@ func TestOpen_ReopensExistingDatabase(t *testing.T) {
 		t.Errorf("jobs table not found in reopened database")
 	}
 }
+
+func TestOpen_UpgradesOlderDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "old.db")
+
+	// Build a database as it was before transcode support and job progress
+	raw, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	old := &DB{db: raw}
+	if _, err := raw.Exec(`CREATE TABLE schema_migrations (
+		version TEXT PRIMARY KEY,
+		applied_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+	)`); err != nil {
+		t.Fatalf("create schema_migrations: %v", err)
+	}
+	for _, file := range []string{"001_initial.sql", "002_item_centric.sql", "003_fix_jobs_unique.sql"} {
+		content, err := fs.ReadFile(migrations, "migrations/"+file)
+		if err != nil {
+			t.Fatalf("read %s: %v", file, err)
+		}
+		if err := old.applyMigration(file, string(content)); err != nil {
+			t.Fatalf("applyMigration(%s) error = %v", file, err)
+		}
+	}
+	raw.Close()
+
+	database, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer database.Close()
+
+	for _, column := range []string{"progress", "options"} {
+		var count int
+		err := database.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('jobs') WHERE name = ?", column).Scan(&count)
+		if err != nil {
+			t.Fatalf("Query error for jobs.%s: %v", column, err)
+		}
+		if count != 1 {
+			t.Errorf("jobs.%s not added to older database", column)
+		}
+	}
+
+	var count int
+	err = database.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='transcode_files'").Scan(&count)
+	if err != nil {
+		t.Fatalf("Query error: %v", err)
+	}
+	if count != 1 {
+		t.Error("transcode_files table not added to older database")
+	}
+
+	entries, err := fs.ReadDir(migrations, "migrations")
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if err := database.db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&count); err != nil {
+		t.Fatalf("Query error: %v", err)
+	}
+	if count != len(entries) {
+		t.Errorf("schema_migrations has %d rows, want %d", count, len(entries))
+	}
+}
+
+func TestApplyMigration_FailureRollsBack(t *testing.T) {
+	database, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	err = database.applyMigration("999_broken.sql", "CREATE TABLE half_done (id INTEGER); NOT VALID SQL;")
+	if err == nil {
+		t.Fatal("applyMigration() error = nil, want error for invalid SQL")
+	}
+
+	var count int
+	err = database.db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='half_done'").Scan(&count)
+	if err != nil {
+		t.Fatalf("Query error: %v", err)
+	}
+	if count != 0 {
+		t.Error("table from failed migration was kept")
+	}
+	err = database.db.QueryRow("SELECT COUNT(*) FROM schema_migrations WHERE version = '999_broken.sql'").Scan(&count)
+	if err != nil {
+		t.Fatalf("Query error: %v", err)
+	}
+	if count != 0 {
+		t.Error("failed migration was recorded as applied")
+	}
+}
@@ -23,7 +23,7 @@ func (r *SQLiteRepository) MigrateToItemCentric(ctx context.Context) error {
 		)
 	`
 
-	rows, err := r.db.db.QueryContext(ctx, query)
+	rows, err := r.q.QueryContext(ctx, query)
 	if err != nil {
 		return fmt.Errorf("failed to find TV shows to migrate: %w", err)
 	}
@@ -56,7 +56,7 @@ func (r *SQLiteRepository) migrateShow(ctx context.Context, safeName string) err
 		ORDER BY season ASC
 	`
 
-	rows, err := r.db.db.QueryContext(ctx, query, safeName)
+	rows, err := r.q.QueryContext(ctx, query, safeName)
 	if err != nil {
 		return err
 	}
@@ -97,7 +97,7 @@ func (r *SQLiteRepository) migrateShow(ctx context.Context, safeName string) err
 	for _, item := range items {
 		// Get latest job to determine stage/status
 		var stage, status string
-		err := r.db.db.QueryRowContext(ctx, `
+		err := r.q.QueryRowContext(ctx, `
 			SELECT stage, status FROM jobs
 			WHERE media_item_id = ?
 			ORDER BY created_at DESC LIMIT 1
@@ -111,7 +111,7 @@ func (r *SQLiteRepository) migrateShow(ctx context.Context, safeName string) err
 		}
 
 		now := time.Now().UTC().Format(time.RFC3339)
-		_, err = r.db.db.ExecContext(ctx, `
+		_, err = r.q.ExecContext(ctx, `
 			INSERT INTO seasons (item_id, number, current_stage, stage_status, created_at, updated_at)
 			VALUES (?, ?, ?, ?, ?, ?)
 		`, parentID, item.season, stage, status, item.createdAt, now)
@@ -121,7 +121,7 @@ func (r *SQLiteRepository) migrateShow(ctx context.Context, safeName string) err
 
 		// Update jobs to reference parent item
 		if item.id != parentID {
-			_, err = r.db.db.ExecContext(ctx, `
+			_, err = r.q.ExecContext(ctx, `
 				UPDATE jobs SET media_item_id = ? WHERE media_item_id = ?
 			`, parentID, item.id)
 			if err != nil {
@@ -129,7 +129,7 @@ func (r *SQLiteRepository) migrateShow(ctx context.Context, safeName string) err
 			}
 
 			// Delete the old media_item
-			_, err = r.db.db.ExecContext(ctx, `
+			_, err = r.q.ExecContext(ctx, `
 				DELETE FROM media_items WHERE id = ?
 			`, item.id)
 			if err != nil {
@@ -139,7 +139,7 @@ func (r *SQLiteRepository) migrateShow(ctx context.Context, safeName string) err
 	}
 
 	// Update parent item to remove season field and set status
-	_, err = r.db.db.ExecContext(ctx, `
+	_, err = r.q.ExecContext(ctx, `
 		UPDATE media_items SET season = NULL, status = 'active' WHERE id = ?
 	`, parentID)
 	if err != nil {
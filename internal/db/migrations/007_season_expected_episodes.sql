@@ -0,0 +1,2 @@
+-- Expected episode count per season, used by organize validation (0 = unknown)
+ALTER TABLE seasons ADD COLUMN expected_episodes INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Edition of a movie (e.g. "Extended"), so several editions of one title can coexist ('' = none)
+ALTER TABLE media_items ADD COLUMN edition TEXT NOT NULL DEFAULT '';
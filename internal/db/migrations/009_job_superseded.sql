@@ -0,0 +1,2 @@
+-- Retry job that replaced a failed job, so history shows it was dealt with
+ALTER TABLE jobs ADD COLUMN superseded_by INTEGER REFERENCES jobs(id);
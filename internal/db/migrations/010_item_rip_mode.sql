@@ -0,0 +1,2 @@
+-- How an item's discs are ripped: 'titles' (MKV per title) or 'backup' (decrypted disc copy)
+ALTER TABLE media_items ADD COLUMN rip_mode TEXT NOT NULL DEFAULT 'titles';
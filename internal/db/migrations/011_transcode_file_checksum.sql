@@ -0,0 +1,3 @@
+-- Checksum of each transcoded output file, for verifying later copies
+ALTER TABLE transcode_files ADD COLUMN checksum TEXT NOT NULL DEFAULT '';
+ALTER TABLE transcode_files ADD COLUMN checksum_algorithm TEXT NOT NULL DEFAULT '';
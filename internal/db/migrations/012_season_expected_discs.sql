@@ -0,0 +1,2 @@
+-- Discs a season is expected to span, checked before marking its rips done (0 = unknown)
+ALTER TABLE seasons ADD COLUMN expected_discs INTEGER NOT NULL DEFAULT 0;
@@ -2,10 +2,22 @@ package db
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/cuivienor/media-pipeline/internal/model"
 )
 
+// ErrDuplicateName is returned when a media item name collides with another item
+var ErrDuplicateName = errors.New("another item already uses this name")
+
+// ErrJobNotActive is returned when forcing the status of a job that has already finished
+var ErrJobNotActive = errors.New("job is not pending or in progress")
+
+// ErrJobNotRetryable is returned when superseding or reopening a job that
+// isn't failed or has already been retried
+var ErrJobNotRetryable = errors.New("job is not failed or was already retried")
+
 // Repository defines persistence operations for the pipeline
 type Repository interface {
 	// Media items
@@ -13,6 +25,7 @@ type Repository interface {
 	GetMediaItem(ctx context.Context, id int64) (*model.MediaItem, error)
 	GetMediaItemBySafeName(ctx context.Context, safeName string, season *int) (*model.MediaItem, error)
 	ListMediaItems(ctx context.Context, opts ListOptions) ([]model.MediaItem, error)
+	CountMediaItems(ctx context.Context, opts ListOptions) (int, error)
 
 	// Jobs
 	CreateJob(ctx context.Context, job *model.Job) error
@@ -21,25 +34,40 @@ type Repository interface {
 	UpdateJob(ctx context.Context, job *model.Job) error
 	UpdateJobStatus(ctx context.Context, id int64, status model.JobStatus, errorMsg string) error
 	UpdateJobProgress(ctx context.Context, id int64, progress int) error
+	ForceCompleteJob(ctx context.Context, id int64) error
+	ForceFailJob(ctx context.Context, id int64, reason string) error
+	SupersedeJob(ctx context.Context, id, supersededBy int64) error
+	ReopenJob(ctx context.Context, id int64) error
 	ListJobsForMedia(ctx context.Context, mediaItemID int64) ([]model.Job, error)
+	GetJobStats(ctx context.Context) (model.JobStats, error)
+	DeleteJobsBefore(ctx context.Context, cutoff time.Time, onlyCompleted bool) (int64, error)
+	CanStartStage(ctx context.Context, itemID int64, stage model.Stage, seasonID *int64) (bool, string)
+	GetItemFull(ctx context.Context, itemID int64) (*model.ItemRecord, error)
 
 	// Log events
 	CreateLogEvent(ctx context.Context, event *model.LogEvent) error
 	ListLogEvents(ctx context.Context, jobID int64, limit int) ([]model.LogEvent, error)
+	DeleteLogEventsBefore(ctx context.Context, cutoff time.Time) (int64, error)
 
 	// Disc progress (TV shows)
 	GetDiscProgress(ctx context.Context, mediaItemID int64) ([]model.DiscProgress, error)
 
 	// Seasons
 	CreateSeason(ctx context.Context, season *model.Season) error
+	CreateSeasons(ctx context.Context, itemID int64, numbers []int) ([]model.Season, error)
 	GetSeason(ctx context.Context, id int64) (*model.Season, error)
+	GetSeasonByNumber(ctx context.Context, itemID int64, number int) (*model.Season, error)
 	ListSeasonsForItem(ctx context.Context, itemID int64) ([]model.Season, error)
 	UpdateSeason(ctx context.Context, season *model.Season) error
 	UpdateSeasonStage(ctx context.Context, id int64, stage model.Stage, status model.Status) error
+	UpdateSeasonExpectedEpisodes(ctx context.Context, id int64, expected int) error
+	UpdateSeasonExpectedDiscs(ctx context.Context, id int64, expected int) error
 
 	// Updated item methods
 	UpdateMediaItemStatus(ctx context.Context, id int64, status model.ItemStatus) error
 	UpdateMediaItemStage(ctx context.Context, id int64, stage model.Stage, status model.Status) error
+	UpdateMediaItemName(ctx context.Context, id int64, name, safeName string) error
+	UpdateMediaItemDatabaseID(ctx context.Context, id int64, tmdbID, tvdbID *int) error
 	ListActiveItems(ctx context.Context) ([]model.MediaItem, error)
 
 	// Transcode files
@@ -49,16 +77,33 @@ type Repository interface {
 	UpdateTranscodeFile(ctx context.Context, file *model.TranscodeFile) error
 	UpdateTranscodeFileProgress(ctx context.Context, id int64, progress int) error
 	UpdateTranscodeFileStatus(ctx context.Context, id int64, status model.TranscodeFileStatus, errorMsg string) error
+	GetTranscodeStats(ctx context.Context, jobID int64) (model.TranscodeStats, error)
 
 	// Job options
 	GetJobOptions(ctx context.Context, jobID int64) (map[string]interface{}, error)
 	SetJobOptions(ctx context.Context, jobID int64, options map[string]interface{}) error
+
+	// Transactions
+	WithTx(ctx context.Context, fn func(Repository) error) error
 }
 
 // ListOptions configures media item listing
 type ListOptions struct {
 	Type       *model.MediaType
 	ActiveOnly bool
+	Search     string    // Case-insensitive substring match on name or safe name
+	WorkerID   string    // Only items with a job run on this worker host
+	SortBy     SortField // Defaults to newest first when empty
+	SortDesc   bool
 	Limit      int
 	Offset     int
 }
+
+// SortField selects the column media items are ordered by
+type SortField string
+
+const (
+	SortByCreated SortField = "created"
+	SortByUpdated SortField = "updated"
+	SortByName    SortField = "name"
+)
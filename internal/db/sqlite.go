@@ -4,27 +4,63 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cuivienor/media-pipeline/internal/model"
 )
 
+// querier is the subset of *sql.DB and *sql.Tx the repository needs
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
 // SQLiteRepository implements Repository using SQLite
 type SQLiteRepository struct {
 	db *DB
+	q  querier // db.db, or the open transaction inside WithTx
 }
 
 // NewSQLiteRepository creates a new SQLite repository
 func NewSQLiteRepository(db *DB) *SQLiteRepository {
-	return &SQLiteRepository{db: db}
+	return &SQLiteRepository{db: db, q: db.db}
+}
+
+// WithTx runs fn inside a transaction, committing if it returns nil and
+// rolling back otherwise. fn must use the Repository it is given; calls
+// nested inside an existing transaction join it.
+func (r *SQLiteRepository) WithTx(ctx context.Context, fn func(Repository) error) error {
+	if _, inTx := r.q.(*sql.Tx); inTx {
+		return fn(r)
+	}
+
+	tx, err := r.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(&SQLiteRepository{db: r.db, q: tx}); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rbErr))
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
 }
 
 // CreateMediaItem creates a new media item
 func (r *SQLiteRepository) CreateMediaItem(ctx context.Context, item *model.MediaItem) error {
 	query := `
-		INSERT INTO media_items (type, name, safe_name, season, tmdb_id, tvdb_id, status, current_stage, stage_status, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO media_items (type, name, safe_name, edition, rip_mode, season, tmdb_id, tvdb_id, status, current_stage, stage_status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	// Set defaults if not provided
@@ -33,21 +69,35 @@ func (r *SQLiteRepository) CreateMediaItem(ctx context.Context, item *model.Medi
 		itemStatus = model.ItemStatusNotStarted
 	}
 
+	if !itemStatus.IsValid() {
+		return fmt.Errorf("invalid item status %q", itemStatus)
+	}
+
 	stageStatus := item.StageStatus
 	if stageStatus == "" {
 		stageStatus = model.StatusPending
 	}
+	if !stageStatus.IsValid() {
+		return fmt.Errorf("invalid stage status %q", stageStatus)
+	}
+
+	ripMode := item.RipMode
+	if ripMode == "" {
+		ripMode = model.RipModeTitles
+	}
 
 	now := time.Now().UTC().Format(time.RFC3339)
-	result, err := r.db.db.ExecContext(ctx, query,
+	result, err := r.q.ExecContext(ctx, query,
 		item.Type,
 		item.Name,
 		item.SafeName,
+		item.Edition,
+		ripMode,
 		item.Season,
 		item.TmdbID,
 		item.TvdbID,
 		itemStatus,
-		item.CurrentStage.String(),
+		item.CurrentStage,
 		stageStatus,
 		now,
 		now,
@@ -65,34 +115,42 @@ func (r *SQLiteRepository) CreateMediaItem(ctx context.Context, item *model.Medi
 	return nil
 }
 
-// GetMediaItem retrieves a media item by ID
-func (r *SQLiteRepository) GetMediaItem(ctx context.Context, id int64) (*model.MediaItem, error) {
-	query := `
-		SELECT id, type, name, safe_name, season, tmdb_id, tvdb_id, created_at, updated_at
-		FROM media_items
-		WHERE id = ?
-	`
+// mediaItemColumns are the media_items columns read by scanMediaItem, in order
+const mediaItemColumns = `id, type, name, safe_name, edition, rip_mode, season, tmdb_id, tvdb_id,
+		status, current_stage, stage_status, created_at, updated_at`
 
+// rowScanner is a single *sql.Row or the current row of *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanMediaItem reads a row selected with mediaItemColumns
+func scanMediaItem(row rowScanner) (*model.MediaItem, error) {
 	var item model.MediaItem
 	var season, tmdbID, tvdbID sql.NullInt64
+	var itemStatus sql.NullString
+	var stageStatus sql.Null[model.Status]
+	var stage sql.Null[model.Stage]
 	var createdAt, updatedAt string
 
-	err := r.db.db.QueryRowContext(ctx, query, id).Scan(
+	err := row.Scan(
 		&item.ID,
 		&item.Type,
 		&item.Name,
 		&item.SafeName,
+		&item.Edition,
+		&item.RipMode,
 		&season,
 		&tmdbID,
 		&tvdbID,
+		&itemStatus,
+		&stage,
+		&stageStatus,
 		&createdAt,
 		&updatedAt,
 	)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get media item: %w", err)
+		return nil, err
 	}
 
 	if season.Valid {
@@ -107,85 +165,92 @@ func (r *SQLiteRepository) GetMediaItem(ctx context.Context, id int64) (*model.M
 		id := int(tvdbID.Int64)
 		item.TvdbID = &id
 	}
+	if stage.Valid {
+		item.CurrentStage = stage.V
+	}
+	item.ItemStatus = model.ItemStatus(itemStatus.String)
+	item.StageStatus = stageStatus.V
+	item.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+	item.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 
 	return &item, nil
 }
 
+// GetMediaItem retrieves a media item by ID
+func (r *SQLiteRepository) GetMediaItem(ctx context.Context, id int64) (*model.MediaItem, error) {
+	query := `SELECT ` + mediaItemColumns + ` FROM media_items WHERE id = ?`
+
+	item, err := scanMediaItem(r.q.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get media item: %w", err)
+	}
+	return item, nil
+}
+
 // GetMediaItemBySafeName retrieves a media item by safe name and season
 func (r *SQLiteRepository) GetMediaItemBySafeName(ctx context.Context, safeName string, season *int) (*model.MediaItem, error) {
-	query := `
-		SELECT id, type, name, safe_name, season, tmdb_id, tvdb_id, created_at, updated_at
+	query := `SELECT ` + mediaItemColumns + `
 		FROM media_items
 		WHERE safe_name = ? AND (? IS NULL AND season IS NULL OR season = ?)
 	`
 
-	var item model.MediaItem
-	var dbSeason, tmdbID, tvdbID sql.NullInt64
-	var createdAt, updatedAt string
-
 	var seasonVal interface{}
 	if season != nil {
 		seasonVal = *season
 	}
 
-	err := r.db.db.QueryRowContext(ctx, query, safeName, seasonVal, seasonVal).Scan(
-		&item.ID,
-		&item.Type,
-		&item.Name,
-		&item.SafeName,
-		&dbSeason,
-		&tmdbID,
-		&tvdbID,
-		&createdAt,
-		&updatedAt,
-	)
+	item, err := scanMediaItem(r.q.QueryRowContext(ctx, query, safeName, seasonVal, seasonVal))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get media item by safe name: %w", err)
 	}
+	return item, nil
+}
 
-	if dbSeason.Valid {
-		s := int(dbSeason.Int64)
-		item.Season = &s
+// likeEscaper escapes LIKE wildcards so search text matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// sortColumns maps each SortField to the column it orders by. ORDER BY is
+// built only from these, never from caller-supplied text.
+var sortColumns = map[SortField]string{
+	SortByCreated: "created_at",
+	SortByUpdated: "updated_at",
+	SortByName:    "name COLLATE NOCASE",
+}
+
+// orderByClause returns the ORDER BY clause for a sort field and direction.
+// An empty field keeps the default newest-first ordering.
+func orderByClause(field SortField, desc bool) (string, error) {
+	if field == "" {
+		return " ORDER BY created_at DESC", nil
 	}
-	if tmdbID.Valid {
-		id := int(tmdbID.Int64)
-		item.TmdbID = &id
+	column, ok := sortColumns[field]
+	if !ok {
+		return "", fmt.Errorf("unknown sort field %q", field)
 	}
-	if tvdbID.Valid {
-		id := int(tvdbID.Int64)
-		item.TvdbID = &id
+	dir := "ASC"
+	if desc {
+		dir = "DESC"
 	}
-
-	return &item, nil
+	// id breaks ties so paging through equal keys is stable
+	return fmt.Sprintf(" ORDER BY %s %s, id %s", column, dir, dir), nil
 }
 
 // ListMediaItems lists media items with optional filters
 func (r *SQLiteRepository) ListMediaItems(ctx context.Context, opts ListOptions) ([]model.MediaItem, error) {
-	query := `
-		SELECT id, type, name, safe_name, season, tmdb_id, tvdb_id, created_at, updated_at
-		FROM media_items
-		WHERE 1=1
-	`
-	args := []interface{}{}
+	where, args := mediaItemFilter(opts)
+	query := `SELECT ` + mediaItemColumns + ` FROM media_items ` + where
 
-	if opts.Type != nil {
-		query += " AND type = ?"
-		args = append(args, *opts.Type)
-	}
-
-	if opts.ActiveOnly {
-		query += `
-			AND EXISTS (
-				SELECT 1 FROM jobs
-				WHERE jobs.media_item_id = media_items.id
-				  AND jobs.status IN ('pending', 'in_progress')
-			)`
+	orderBy, err := orderByClause(opts.SortBy, opts.SortDesc)
+	if err != nil {
+		return nil, err
 	}
-
-	query += " ORDER BY created_at DESC"
+	query += orderBy
 
 	if opts.Limit > 0 {
 		query += " LIMIT ?"
@@ -197,7 +262,7 @@ func (r *SQLiteRepository) ListMediaItems(ctx context.Context, opts ListOptions)
 		args = append(args, opts.Offset)
 	}
 
-	rows, err := r.db.db.QueryContext(ctx, query, args...)
+	rows, err := r.q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list media items: %w", err)
 	}
@@ -205,39 +270,11 @@ func (r *SQLiteRepository) ListMediaItems(ctx context.Context, opts ListOptions)
 
 	var items []model.MediaItem
 	for rows.Next() {
-		var item model.MediaItem
-		var season, tmdbID, tvdbID sql.NullInt64
-		var createdAt, updatedAt string
-
-		err := rows.Scan(
-			&item.ID,
-			&item.Type,
-			&item.Name,
-			&item.SafeName,
-			&season,
-			&tmdbID,
-			&tvdbID,
-			&createdAt,
-			&updatedAt,
-		)
+		item, err := scanMediaItem(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan media item: %w", err)
 		}
-
-		if season.Valid {
-			s := int(season.Int64)
-			item.Season = &s
-		}
-		if tmdbID.Valid {
-			id := int(tmdbID.Int64)
-			item.TmdbID = &id
-		}
-		if tvdbID.Valid {
-			id := int(tvdbID.Int64)
-			item.TvdbID = &id
-		}
-
-		items = append(items, item)
+		items = append(items, *item)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -247,8 +284,63 @@ func (r *SQLiteRepository) ListMediaItems(ctx context.Context, opts ListOptions)
 	return items, nil
 }
 
+// CountMediaItems returns the number of media items matching opts' filters.
+// Sorting, Limit and Offset are ignored.
+func (r *SQLiteRepository) CountMediaItems(ctx context.Context, opts ListOptions) (int, error) {
+	where, args := mediaItemFilter(opts)
+	var count int
+	if err := r.q.QueryRowContext(ctx, `SELECT COUNT(*) FROM media_items `+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count media items: %w", err)
+	}
+	return count, nil
+}
+
+// mediaItemFilter builds the WHERE clause and its arguments for opts'
+// filters, shared by ListMediaItems and CountMediaItems
+func mediaItemFilter(opts ListOptions) (string, []interface{}) {
+	where := "WHERE 1=1"
+	args := []interface{}{}
+
+	if opts.Type != nil {
+		where += " AND type = ?"
+		args = append(args, *opts.Type)
+	}
+
+	if opts.ActiveOnly {
+		where += `
+			AND EXISTS (
+				SELECT 1 FROM jobs
+				WHERE jobs.media_item_id = media_items.id
+				  AND jobs.status IN ('pending', 'in_progress')
+			)`
+	}
+
+	if opts.WorkerID != "" {
+		where += `
+			AND EXISTS (
+				SELECT 1 FROM jobs
+				WHERE jobs.media_item_id = media_items.id
+				  AND jobs.worker_id = ?
+			)`
+		args = append(args, opts.WorkerID)
+	}
+
+	if opts.Search != "" {
+		// SQLite's LIKE is case-insensitive for ASCII; escape the user's wildcards
+		pattern := "%" + likeEscaper.Replace(opts.Search) + "%"
+		where += ` AND (name LIKE ? ESCAPE '\' OR safe_name LIKE ? ESCAPE '\')`
+		args = append(args, pattern, pattern)
+	}
+
+	return where, args
+}
+
 // CreateJob creates a new job
 func (r *SQLiteRepository) CreateJob(ctx context.Context, job *model.Job) error {
+	if !job.Status.IsValid() {
+		return fmt.Errorf("invalid job status %q", job.Status)
+	}
+
 	query := `
 		INSERT INTO jobs (
 			media_item_id, season_id, stage, status, disc, worker_id, pid,
@@ -268,10 +360,10 @@ func (r *SQLiteRepository) CreateJob(ctx context.Context, job *model.Job) error
 		completedAt = job.CompletedAt.UTC().Format(time.RFC3339)
 	}
 
-	result, err := r.db.db.ExecContext(ctx, query,
+	result, err := r.q.ExecContext(ctx, query,
 		job.MediaItemID,
 		job.SeasonID,
-		job.Stage.String(),
+		job.Stage,
 		job.Status,
 		job.Disc,
 		job.WorkerID,
@@ -303,23 +395,22 @@ func (r *SQLiteRepository) GetJob(ctx context.Context, id int64) (*model.Job, er
 	query := `
 		SELECT id, media_item_id, season_id, stage, status, disc, worker_id, pid,
 		       input_dir, output_dir, log_path, error_message, progress,
-		       started_at, completed_at, created_at
+		       superseded_by, started_at, completed_at, created_at
 		FROM jobs
 		WHERE id = ?
 	`
 
 	var job model.Job
-	var stageStr string
-	var seasonID, disc sql.NullInt64
+	var seasonID, disc, supersededBy sql.NullInt64
 	var workerID, inputDir, outputDir, logPath, errorMessage sql.NullString
 	var pid sql.NullInt64
 	var startedAt, completedAt, createdAt sql.NullString
 
-	err := r.db.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.q.QueryRowContext(ctx, query, id).Scan(
 		&job.ID,
 		&job.MediaItemID,
 		&seasonID,
-		&stageStr,
+		&job.Stage,
 		&job.Status,
 		&disc,
 		&workerID,
@@ -329,6 +420,7 @@ func (r *SQLiteRepository) GetJob(ctx context.Context, id int64) (*model.Job, er
 		&logPath,
 		&errorMessage,
 		&job.Progress,
+		&supersededBy,
 		&startedAt,
 		&completedAt,
 		&createdAt,
@@ -340,9 +432,6 @@ func (r *SQLiteRepository) GetJob(ctx context.Context, id int64) (*model.Job, er
 		return nil, fmt.Errorf("failed to get job: %w", err)
 	}
 
-	// Parse stage
-	job.Stage = parseStage(stageStr)
-
 	// Handle nullable fields
 	if seasonID.Valid {
 		s := seasonID.Int64
@@ -370,6 +459,10 @@ func (r *SQLiteRepository) GetJob(ctx context.Context, id int64) (*model.Job, er
 	if errorMessage.Valid {
 		job.ErrorMessage = errorMessage.String
 	}
+	if supersededBy.Valid {
+		id := supersededBy.Int64
+		job.SupersededBy = &id
+	}
 	if startedAt.Valid {
 		t, err := time.Parse(time.RFC3339, startedAt.String)
 		if err == nil {
@@ -412,16 +505,15 @@ func (r *SQLiteRepository) GetActiveJobForStage(ctx context.Context, mediaItemID
 	}
 
 	var job model.Job
-	var stageStr string
 	var dbDisc sql.NullInt64
 	var workerID, inputDir, outputDir, logPath, errorMessage sql.NullString
 	var pid sql.NullInt64
 	var startedAt, completedAt, createdAt sql.NullString
 
-	err := r.db.db.QueryRowContext(ctx, query, mediaItemID, stage.String(), discVal, discVal).Scan(
+	err := r.q.QueryRowContext(ctx, query, mediaItemID, stage, discVal, discVal).Scan(
 		&job.ID,
 		&job.MediaItemID,
-		&stageStr,
+		&job.Stage,
 		&job.Status,
 		&dbDisc,
 		&workerID,
@@ -442,9 +534,6 @@ func (r *SQLiteRepository) GetActiveJobForStage(ctx context.Context, mediaItemID
 		return nil, fmt.Errorf("failed to get active job: %w", err)
 	}
 
-	// Parse stage
-	job.Stage = parseStage(stageStr)
-
 	// Handle nullable fields
 	if dbDisc.Valid {
 		d := int(dbDisc.Int64)
@@ -492,6 +581,10 @@ func (r *SQLiteRepository) GetActiveJobForStage(ctx context.Context, mediaItemID
 
 // UpdateJob updates all fields of a job
 func (r *SQLiteRepository) UpdateJob(ctx context.Context, job *model.Job) error {
+	if !job.Status.IsValid() {
+		return fmt.Errorf("invalid job status %q", job.Status)
+	}
+
 	query := `
 		UPDATE jobs
 		SET media_item_id = ?, stage = ?, status = ?, disc = ?,
@@ -508,9 +601,9 @@ func (r *SQLiteRepository) UpdateJob(ctx context.Context, job *model.Job) error
 		completedAt = job.CompletedAt.UTC().Format(time.RFC3339)
 	}
 
-	_, err := r.db.db.ExecContext(ctx, query,
+	_, err := r.q.ExecContext(ctx, query,
 		job.MediaItemID,
-		job.Stage.String(),
+		job.Stage,
 		job.Status,
 		job.Disc,
 		job.WorkerID,
@@ -532,6 +625,10 @@ func (r *SQLiteRepository) UpdateJob(ctx context.Context, job *model.Job) error
 
 // UpdateJobStatus updates a job's status and optionally sets error message and completion time
 func (r *SQLiteRepository) UpdateJobStatus(ctx context.Context, id int64, status model.JobStatus, errorMsg string) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid job status %q", status)
+	}
+
 	query := `
 		UPDATE jobs
 		SET status = ?, error_message = ?, completed_at = ?
@@ -543,7 +640,7 @@ func (r *SQLiteRepository) UpdateJobStatus(ctx context.Context, id int64, status
 		completedAt = time.Now().UTC().Format(time.RFC3339)
 	}
 
-	_, err := r.db.db.ExecContext(ctx, query, status, errorMsg, completedAt, id)
+	_, err := r.q.ExecContext(ctx, query, status, errorMsg, completedAt, id)
 	if err != nil {
 		return fmt.Errorf("failed to update job status: %w", err)
 	}
@@ -555,7 +652,7 @@ func (r *SQLiteRepository) UpdateJobStatus(ctx context.Context, id int64, status
 func (r *SQLiteRepository) UpdateJobProgress(ctx context.Context, id int64, progress int) error {
 	query := `UPDATE jobs SET progress = ? WHERE id = ?`
 
-	_, err := r.db.db.ExecContext(ctx, query, progress, id)
+	_, err := r.q.ExecContext(ctx, query, progress, id)
 	if err != nil {
 		return fmt.Errorf("failed to update job progress: %w", err)
 	}
@@ -563,18 +660,157 @@ func (r *SQLiteRepository) UpdateJobProgress(ctx context.Context, id int64, prog
 	return nil
 }
 
+// ForceCompleteJob marks a stuck pending or in-progress job completed and
+// clears its PID, for recovering from a worker that died without reporting
+func (r *SQLiteRepository) ForceCompleteJob(ctx context.Context, id int64) error {
+	return r.forceJobStatus(ctx, id, model.JobStatusCompleted, "")
+}
+
+// ForceFailJob marks a stuck pending or in-progress job failed with reason and
+// clears its PID, so the stage can be retried
+func (r *SQLiteRepository) ForceFailJob(ctx context.Context, id int64, reason string) error {
+	return r.forceJobStatus(ctx, id, model.JobStatusFailed, reason)
+}
+
+// forceJobStatus sets a terminal status on an active job, returning
+// ErrJobNotActive if the job finished on its own in the meantime
+func (r *SQLiteRepository) forceJobStatus(ctx context.Context, id int64, status model.JobStatus, errorMsg string) error {
+	query := `
+		UPDATE jobs
+		SET status = ?, error_message = ?, pid = NULL, completed_at = ?
+		WHERE id = ? AND status IN (?, ?)
+	`
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	res, err := r.q.ExecContext(ctx, query, status, errorMsg, now, id,
+		model.JobStatusPending, model.JobStatusInProgress)
+	if err != nil {
+		return fmt.Errorf("failed to force job status: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to force job status: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("job %d: %w", id, ErrJobNotActive)
+	}
+
+	return nil
+}
+
+// SupersedeJob records that the failed job id was replaced by the retry job
+// supersededBy, returning ErrJobNotRetryable if the job isn't failed or was
+// already retried
+func (r *SQLiteRepository) SupersedeJob(ctx context.Context, id, supersededBy int64) error {
+	query := `
+		UPDATE jobs
+		SET superseded_by = ?
+		WHERE id = ? AND status = ? AND superseded_by IS NULL
+	`
+
+	res, err := r.q.ExecContext(ctx, query, supersededBy, id, model.JobStatusFailed)
+	if err != nil {
+		return fmt.Errorf("failed to supersede job: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to supersede job: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("job %d: %w", id, ErrJobNotRetryable)
+	}
+	return nil
+}
+
+// ReopenJob sets the failed job id back to pending so it can be dispatched
+// again, returning ErrJobNotRetryable if the job isn't failed or was already
+// retried
+func (r *SQLiteRepository) ReopenJob(ctx context.Context, id int64) error {
+	query := `
+		UPDATE jobs
+		SET status = ?, error_message = '', pid = NULL, completed_at = NULL
+		WHERE id = ? AND status = ? AND superseded_by IS NULL
+	`
+
+	res, err := r.q.ExecContext(ctx, query, model.JobStatusPending, id, model.JobStatusFailed)
+	if err != nil {
+		return fmt.Errorf("failed to reopen job: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to reopen job: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("job %d: %w", id, ErrJobNotRetryable)
+	}
+	return nil
+}
+
+// itemFullLogEvents is how many of each job's most recent log events
+// GetItemFull includes
+const itemFullLogEvents = 100
+
+// GetItemFull gathers the item with its seasons, jobs, job options, transcode
+// files and recent log events into one record, or returns nil if the item
+// doesn't exist
+func (r *SQLiteRepository) GetItemFull(ctx context.Context, itemID int64) (*model.ItemRecord, error) {
+	var record *model.ItemRecord
+	err := r.WithTx(ctx, func(tx Repository) error {
+		item, err := tx.GetMediaItem(ctx, itemID)
+		if err != nil || item == nil {
+			return err
+		}
+		if item.Seasons, err = tx.ListSeasonsForItem(ctx, itemID); err != nil {
+			return err
+		}
+
+		rec := &model.ItemRecord{Item: *item, JobOptions: make(map[int64]map[string]interface{})}
+		if rec.Jobs, err = tx.ListJobsForMedia(ctx, itemID); err != nil {
+			return err
+		}
+		for _, job := range rec.Jobs {
+			opts, err := tx.GetJobOptions(ctx, job.ID)
+			if err != nil {
+				return err
+			}
+			if len(opts) > 0 {
+				rec.JobOptions[job.ID] = opts
+			}
+
+			files, err := tx.ListTranscodeFiles(ctx, job.ID)
+			if err != nil {
+				return err
+			}
+			rec.TranscodeFiles = append(rec.TranscodeFiles, files...)
+
+			events, err := tx.ListLogEvents(ctx, job.ID, itemFullLogEvents)
+			if err != nil {
+				return err
+			}
+			rec.LogEvents = append(rec.LogEvents, events...)
+		}
+
+		record = rec
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
 // ListJobsForMedia lists all jobs for a media item
 func (r *SQLiteRepository) ListJobsForMedia(ctx context.Context, mediaItemID int64) ([]model.Job, error) {
 	query := `
 		SELECT id, media_item_id, season_id, stage, status, disc, worker_id, pid,
 		       input_dir, output_dir, log_path, error_message, progress,
-		       started_at, completed_at, created_at
+		       superseded_by, started_at, completed_at, created_at
 		FROM jobs
 		WHERE media_item_id = ?
 		ORDER BY created_at ASC
 	`
 
-	rows, err := r.db.db.QueryContext(ctx, query, mediaItemID)
+	rows, err := r.q.QueryContext(ctx, query, mediaItemID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list jobs: %w", err)
 	}
@@ -583,8 +819,7 @@ func (r *SQLiteRepository) ListJobsForMedia(ctx context.Context, mediaItemID int
 	var jobs []model.Job
 	for rows.Next() {
 		var job model.Job
-		var stageStr string
-		var seasonID, disc sql.NullInt64
+		var seasonID, disc, supersededBy sql.NullInt64
 		var workerID, inputDir, outputDir, logPath, errorMessage sql.NullString
 		var pid sql.NullInt64
 		var startedAt, completedAt, createdAt sql.NullString
@@ -593,7 +828,7 @@ func (r *SQLiteRepository) ListJobsForMedia(ctx context.Context, mediaItemID int
 			&job.ID,
 			&job.MediaItemID,
 			&seasonID,
-			&stageStr,
+			&job.Stage,
 			&job.Status,
 			&disc,
 			&workerID,
@@ -603,6 +838,7 @@ func (r *SQLiteRepository) ListJobsForMedia(ctx context.Context, mediaItemID int
 			&logPath,
 			&errorMessage,
 			&job.Progress,
+			&supersededBy,
 			&startedAt,
 			&completedAt,
 			&createdAt,
@@ -611,9 +847,6 @@ func (r *SQLiteRepository) ListJobsForMedia(ctx context.Context, mediaItemID int
 			return nil, fmt.Errorf("failed to scan job: %w", err)
 		}
 
-		// Parse stage
-		job.Stage = parseStage(stageStr)
-
 		// Handle nullable fields
 		if seasonID.Valid {
 			s := seasonID.Int64
@@ -641,6 +874,10 @@ func (r *SQLiteRepository) ListJobsForMedia(ctx context.Context, mediaItemID int
 		if errorMessage.Valid {
 			job.ErrorMessage = errorMessage.String
 		}
+		if supersededBy.Valid {
+			id := supersededBy.Int64
+			job.SupersededBy = &id
+		}
 		if startedAt.Valid {
 			t, err := time.Parse(time.RFC3339, startedAt.String)
 			if err == nil {
@@ -670,6 +907,108 @@ func (r *SQLiteRepository) ListJobsForMedia(ctx context.Context, mediaItemID int
 	return jobs, nil
 }
 
+// GetJobStats returns job counts grouped by stage and status, plus total transcode output bytes
+func (r *SQLiteRepository) GetJobStats(ctx context.Context) (model.JobStats, error) {
+	stats := model.JobStats{
+		ByStage: make(map[model.Stage]map[model.JobStatus]int),
+	}
+
+	query := `
+		SELECT stage, status, COUNT(*)
+		FROM jobs
+		GROUP BY stage, status
+	`
+
+	rows, err := r.q.QueryContext(ctx, query)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get job stats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stage model.Stage
+		var status model.JobStatus
+		var count int
+
+		if err := rows.Scan(&stage, &status, &count); err != nil {
+			return stats, fmt.Errorf("failed to scan job stats: %w", err)
+		}
+
+		if stats.ByStage[stage] == nil {
+			stats.ByStage[stage] = make(map[model.JobStatus]int)
+		}
+		stats.ByStage[stage][status] = count
+		stats.Total += count
+	}
+
+	if err := rows.Err(); err != nil {
+		return stats, fmt.Errorf("error iterating job stats: %w", err)
+	}
+
+	err = r.q.QueryRowContext(ctx, `SELECT COALESCE(SUM(output_size), 0) FROM transcode_files`).Scan(&stats.BytesProcessed)
+	if err != nil {
+		return stats, fmt.Errorf("failed to sum transcode output: %w", err)
+	}
+
+	return stats, nil
+}
+
+// CanStartStage reports whether the stage before stage has a completed job for
+// the item (and season, when seasonID is set). When it can't start, the reason
+// names the missing prerequisite.
+func (r *SQLiteRepository) CanStartStage(ctx context.Context, itemID int64, stage model.Stage, seasonID *int64) (bool, string) {
+	prev, ok := stage.PreviousStage()
+	if !ok {
+		return true, ""
+	}
+
+	query := `
+		SELECT COUNT(*) FROM jobs
+		WHERE media_item_id = ? AND stage = ? AND status = 'completed'
+		  AND (? IS NULL OR season_id = ?)
+	`
+	var count int
+	if err := r.q.QueryRowContext(ctx, query, itemID, prev, seasonID, seasonID).Scan(&count); err != nil {
+		return false, fmt.Sprintf("failed to check %s prerequisites: %v", stage, err)
+	}
+	if count > 0 {
+		return true, ""
+	}
+
+	if seasonID != nil {
+		return false, fmt.Sprintf("cannot start %s: no completed %s job for this season", stage, prev)
+	}
+	return false, fmt.Sprintf("cannot start %s: no completed %s job for this item", stage, prev)
+}
+
+// DeleteJobsBefore deletes finished jobs created before cutoff and returns the number removed.
+// Jobs belonging to items that are not yet completed are never deleted, since later
+// stages look up earlier job output directories. Pending and in-progress jobs are
+// always kept. If onlyCompleted is true, failed jobs are kept as well.
+func (r *SQLiteRepository) DeleteJobsBefore(ctx context.Context, cutoff time.Time, onlyCompleted bool) (int64, error) {
+	query := `
+		DELETE FROM jobs
+		WHERE created_at < ?
+		  AND media_item_id IN (SELECT id FROM media_items WHERE status = 'completed')
+	`
+	if onlyCompleted {
+		query += " AND status = 'completed'"
+	} else {
+		query += " AND status IN ('completed', 'failed')"
+	}
+
+	result, err := r.q.ExecContext(ctx, query, cutoff.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete jobs: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get deleted job count: %w", err)
+	}
+	return deleted, nil
+}
+
 // CreateLogEvent creates a new log event
 func (r *SQLiteRepository) CreateLogEvent(ctx context.Context, event *model.LogEvent) error {
 	query := `
@@ -679,7 +1018,7 @@ func (r *SQLiteRepository) CreateLogEvent(ctx context.Context, event *model.LogE
 
 	now := time.Now().UTC().Format(time.RFC3339)
 
-	result, err := r.db.db.ExecContext(ctx, query,
+	result, err := r.q.ExecContext(ctx, query,
 		event.JobID,
 		event.Level,
 		event.Message,
@@ -713,7 +1052,7 @@ func (r *SQLiteRepository) ListLogEvents(ctx context.Context, jobID int64, limit
 		args = append(args, limit)
 	}
 
-	rows, err := r.db.db.QueryContext(ctx, query, args...)
+	rows, err := r.q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list log events: %w", err)
 	}
@@ -750,18 +1089,35 @@ func (r *SQLiteRepository) ListLogEvents(ctx context.Context, jobID int64, limit
 	return events, nil
 }
 
+// DeleteLogEventsBefore deletes log events older than cutoff and returns the number removed
+func (r *SQLiteRepository) DeleteLogEventsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `DELETE FROM log_events WHERE timestamp < ?`
+
+	result, err := r.q.ExecContext(ctx, query, cutoff.UTC().Format(time.RFC3339))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete log events: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get deleted log event count: %w", err)
+	}
+	return deleted, nil
+}
+
 // GetDiscProgress gets progress for all discs of a TV show
 func (r *SQLiteRepository) GetDiscProgress(ctx context.Context, mediaItemID int64) ([]model.DiscProgress, error) {
 	query := `
-		SELECT disc, status, id
+		SELECT disc, status, id, season_id
 		FROM jobs
 		WHERE media_item_id = ?
 		  AND stage = 'rip'
 		  AND disc IS NOT NULL
+		  AND superseded_by IS NULL
 		ORDER BY disc ASC
 	`
 
-	rows, err := r.db.db.QueryContext(ctx, query, mediaItemID)
+	rows, err := r.q.QueryContext(ctx, query, mediaItemID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get disc progress: %w", err)
 	}
@@ -771,13 +1127,17 @@ func (r *SQLiteRepository) GetDiscProgress(ctx context.Context, mediaItemID int6
 	for rows.Next() {
 		var p model.DiscProgress
 		var disc int64
+		var seasonID sql.NullInt64
 
-		err := rows.Scan(&disc, &p.Status, &p.JobID)
+		err := rows.Scan(&disc, &p.Status, &p.JobID, &seasonID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan disc progress: %w", err)
 		}
 
 		p.Disc = int(disc)
+		if seasonID.Valid {
+			p.SeasonID = &seasonID.Int64
+		}
 		progress = append(progress, p)
 	}
 
@@ -788,36 +1148,24 @@ func (r *SQLiteRepository) GetDiscProgress(ctx context.Context, mediaItemID int6
 	return progress, nil
 }
 
-// parseStage converts a stage string to Stage enum
-func parseStage(s string) model.Stage {
-	switch s {
-	case "rip":
-		return model.StageRip
-	case "organize":
-		return model.StageOrganize
-	case "remux":
-		return model.StageRemux
-	case "transcode":
-		return model.StageTranscode
-	case "publish":
-		return model.StagePublish
-	default:
-		return model.StageRip
-	}
-}
-
 // CreateSeason creates a new season
 func (r *SQLiteRepository) CreateSeason(ctx context.Context, season *model.Season) error {
+	if !season.StageStatus.IsValid() {
+		return fmt.Errorf("invalid stage status %q", season.StageStatus)
+	}
+
 	query := `
-		INSERT INTO seasons (item_id, number, current_stage, stage_status, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO seasons (item_id, number, current_stage, stage_status, expected_episodes, expected_discs, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 	`
 	now := time.Now().UTC().Format(time.RFC3339)
-	result, err := r.db.db.ExecContext(ctx, query,
+	result, err := r.q.ExecContext(ctx, query,
 		season.ItemID,
 		season.Number,
-		season.CurrentStage.String(),
+		season.CurrentStage,
 		season.StageStatus,
+		season.ExpectedEpisodes,
+		season.ExpectedDiscs,
 		now,
 		now,
 	)
@@ -832,23 +1180,65 @@ func (r *SQLiteRepository) CreateSeason(ctx context.Context, season *model.Seaso
 	return nil
 }
 
+// CreateSeasons creates the given season numbers for a TV show in one
+// transaction, skipping numbers that already exist. Returns the seasons created.
+func (r *SQLiteRepository) CreateSeasons(ctx context.Context, itemID int64, numbers []int) ([]model.Season, error) {
+	var created []model.Season
+	err := r.WithTx(ctx, func(tx Repository) error {
+		for _, number := range numbers {
+			existing, err := tx.GetSeasonByNumber(ctx, itemID, number)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				continue
+			}
+			season := model.Season{
+				ItemID:       itemID,
+				Number:       number,
+				CurrentStage: model.StageRip,
+				StageStatus:  model.StatusPending,
+			}
+			if err := tx.CreateSeason(ctx, &season); err != nil {
+				return fmt.Errorf("failed to create season %d: %w", number, err)
+			}
+			created = append(created, season)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
 // GetSeason retrieves a season by ID
 func (r *SQLiteRepository) GetSeason(ctx context.Context, id int64) (*model.Season, error) {
+	return r.getSeason(ctx, "id = ?", id)
+}
+
+// GetSeasonByNumber retrieves a TV show's season by its season number
+func (r *SQLiteRepository) GetSeasonByNumber(ctx context.Context, itemID int64, number int) (*model.Season, error) {
+	return r.getSeason(ctx, "item_id = ? AND number = ?", itemID, number)
+}
+
+// getSeason retrieves the season matching where, or nil if there is none
+func (r *SQLiteRepository) getSeason(ctx context.Context, where string, args ...any) (*model.Season, error) {
 	query := `
-		SELECT id, item_id, number, current_stage, stage_status, created_at, updated_at
+		SELECT id, item_id, number, current_stage, stage_status, expected_episodes, expected_discs, created_at, updated_at
 		FROM seasons
-		WHERE id = ?
-	`
+		WHERE ` + where
 	var season model.Season
-	var stageStr, statusStr string
 	var createdAt, updatedAt string
 
-	err := r.db.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.q.QueryRowContext(ctx, query, args...).Scan(
 		&season.ID,
 		&season.ItemID,
 		&season.Number,
-		&stageStr,
-		&statusStr,
+		&season.CurrentStage,
+		&season.StageStatus,
+		&season.ExpectedEpisodes,
+		&season.ExpectedDiscs,
 		&createdAt,
 		&updatedAt,
 	)
@@ -859,8 +1249,6 @@ func (r *SQLiteRepository) GetSeason(ctx context.Context, id int64) (*model.Seas
 		return nil, fmt.Errorf("failed to get season: %w", err)
 	}
 
-	season.CurrentStage = parseStage(stageStr)
-	season.StageStatus = model.Status(statusStr)
 	season.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 	season.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 
@@ -870,12 +1258,12 @@ func (r *SQLiteRepository) GetSeason(ctx context.Context, id int64) (*model.Seas
 // ListSeasonsForItem lists all seasons for a TV show item
 func (r *SQLiteRepository) ListSeasonsForItem(ctx context.Context, itemID int64) ([]model.Season, error) {
 	query := `
-		SELECT id, item_id, number, current_stage, stage_status, created_at, updated_at
+		SELECT id, item_id, number, current_stage, stage_status, expected_episodes, expected_discs, created_at, updated_at
 		FROM seasons
 		WHERE item_id = ?
 		ORDER BY number ASC
 	`
-	rows, err := r.db.db.QueryContext(ctx, query, itemID)
+	rows, err := r.q.QueryContext(ctx, query, itemID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list seasons: %w", err)
 	}
@@ -884,15 +1272,16 @@ func (r *SQLiteRepository) ListSeasonsForItem(ctx context.Context, itemID int64)
 	var seasons []model.Season
 	for rows.Next() {
 		var season model.Season
-		var stageStr, statusStr string
 		var createdAt, updatedAt string
 
 		err := rows.Scan(
 			&season.ID,
 			&season.ItemID,
 			&season.Number,
-			&stageStr,
-			&statusStr,
+			&season.CurrentStage,
+			&season.StageStatus,
+			&season.ExpectedEpisodes,
+			&season.ExpectedDiscs,
 			&createdAt,
 			&updatedAt,
 		)
@@ -900,9 +1289,7 @@ func (r *SQLiteRepository) ListSeasonsForItem(ctx context.Context, itemID int64)
 			return nil, fmt.Errorf("failed to scan season: %w", err)
 		}
 
-		season.CurrentStage = parseStage(stageStr)
-		season.StageStatus = model.Status(statusStr)
-		season.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+			season.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
 		season.UpdatedAt, _ = time.Parse(time.RFC3339, updatedAt)
 
 		seasons = append(seasons, season)
@@ -913,14 +1300,18 @@ func (r *SQLiteRepository) ListSeasonsForItem(ctx context.Context, itemID int64)
 
 // UpdateSeason updates a season
 func (r *SQLiteRepository) UpdateSeason(ctx context.Context, season *model.Season) error {
+	if !season.StageStatus.IsValid() {
+		return fmt.Errorf("invalid stage status %q", season.StageStatus)
+	}
+
 	query := `
 		UPDATE seasons
 		SET current_stage = ?, stage_status = ?, updated_at = ?
 		WHERE id = ?
 	`
 	now := time.Now().UTC().Format(time.RFC3339)
-	_, err := r.db.db.ExecContext(ctx, query,
-		season.CurrentStage.String(),
+	_, err := r.q.ExecContext(ctx, query,
+		season.CurrentStage,
 		season.StageStatus,
 		now,
 		season.ID,
@@ -931,15 +1322,52 @@ func (r *SQLiteRepository) UpdateSeason(ctx context.Context, season *model.Seaso
 	return nil
 }
 
+// UpdateSeasonExpectedEpisodes sets the number of episodes a season should have (0 = unknown)
+func (r *SQLiteRepository) UpdateSeasonExpectedEpisodes(ctx context.Context, id int64, expected int) error {
+	if expected < 0 {
+		return fmt.Errorf("expected episodes must not be negative, got %d", expected)
+	}
+	query := `UPDATE seasons SET expected_episodes = ?, updated_at = ? WHERE id = ?`
+	now := time.Now().UTC().Format(time.RFC3339)
+	result, err := r.q.ExecContext(ctx, query, expected, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to update expected episodes: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("season %d not found", id)
+	}
+	return nil
+}
+
+// UpdateSeasonExpectedDiscs sets the number of discs a season spans (0 = unknown)
+func (r *SQLiteRepository) UpdateSeasonExpectedDiscs(ctx context.Context, id int64, expected int) error {
+	if expected < 0 {
+		return fmt.Errorf("expected discs must not be negative, got %d", expected)
+	}
+	query := `UPDATE seasons SET expected_discs = ?, updated_at = ? WHERE id = ?`
+	now := time.Now().UTC().Format(time.RFC3339)
+	result, err := r.q.ExecContext(ctx, query, expected, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to update expected discs: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("season %d not found", id)
+	}
+	return nil
+}
+
 // UpdateSeasonStage updates a season's stage and status
 func (r *SQLiteRepository) UpdateSeasonStage(ctx context.Context, id int64, stage model.Stage, status model.Status) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid stage status %q", status)
+	}
 	query := `
 		UPDATE seasons
 		SET current_stage = ?, stage_status = ?, updated_at = ?
 		WHERE id = ?
 	`
 	now := time.Now().UTC().Format(time.RFC3339)
-	_, err := r.db.db.ExecContext(ctx, query, stage.String(), status, now, id)
+	_, err := r.q.ExecContext(ctx, query, stage, status, now, id)
 	if err != nil {
 		return fmt.Errorf("failed to update season stage: %w", err)
 	}
@@ -948,9 +1376,12 @@ func (r *SQLiteRepository) UpdateSeasonStage(ctx context.Context, id int64, stag
 
 // UpdateMediaItemStatus updates an item's overall status
 func (r *SQLiteRepository) UpdateMediaItemStatus(ctx context.Context, id int64, status model.ItemStatus) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid item status %q", status)
+	}
 	query := `UPDATE media_items SET status = ?, updated_at = ? WHERE id = ?`
 	now := time.Now().UTC().Format(time.RFC3339)
-	_, err := r.db.db.ExecContext(ctx, query, status, now, id)
+	_, err := r.q.ExecContext(ctx, query, status, now, id)
 	if err != nil {
 		return fmt.Errorf("failed to update media item status: %w", err)
 	}
@@ -959,24 +1390,86 @@ func (r *SQLiteRepository) UpdateMediaItemStatus(ctx context.Context, id int64,
 
 // UpdateMediaItemStage updates a media item's current stage and stage status
 func (r *SQLiteRepository) UpdateMediaItemStage(ctx context.Context, id int64, stage model.Stage, status model.Status) error {
+	if !status.IsValid() {
+		return fmt.Errorf("invalid stage status %q", status)
+	}
 	query := `UPDATE media_items SET current_stage = ?, stage_status = ?, updated_at = ? WHERE id = ?`
 	now := time.Now().UTC().Format(time.RFC3339)
-	_, err := r.db.db.ExecContext(ctx, query, stage.String(), status, now, id)
+	_, err := r.q.ExecContext(ctx, query, stage, status, now, id)
 	if err != nil {
 		return fmt.Errorf("failed to update media item stage: %w", err)
 	}
 	return nil
 }
 
+// UpdateMediaItemName renames a media item. It returns an error wrapping
+// ErrDuplicateName if another item of the same type already uses safeName.
+// Output directories written under the old safe name are not moved.
+func (r *SQLiteRepository) UpdateMediaItemName(ctx context.Context, id int64, name, safeName string) error {
+	var conflicts int
+	err := r.q.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM media_items
+		WHERE safe_name = ? AND id != ? AND type = (SELECT type FROM media_items WHERE id = ?)
+	`, safeName, id, id).Scan(&conflicts)
+	if err != nil {
+		return fmt.Errorf("failed to check media item name: %w", err)
+	}
+	if conflicts > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateName, safeName)
+	}
+
+	query := `UPDATE media_items SET name = ?, safe_name = ?, updated_at = ? WHERE id = ?`
+	now := time.Now().UTC().Format(time.RFC3339)
+	result, err := r.q.ExecContext(ctx, query, name, safeName, now, id)
+	if err != nil {
+		return fmt.Errorf("failed to update media item name: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("media item %d not found", id)
+	}
+	return nil
+}
+
+// UpdateMediaItemDatabaseID sets the TMDB and TVDB IDs used for publishing.
+// Movies may only carry a TMDB ID and TV shows only a TVDB ID; nil clears the ID.
+func (r *SQLiteRepository) UpdateMediaItemDatabaseID(ctx context.Context, id int64, tmdbID, tvdbID *int) error {
+	for _, dbID := range []*int{tmdbID, tvdbID} {
+		if dbID != nil && *dbID <= 0 {
+			return fmt.Errorf("database ID must be a positive integer, got %d", *dbID)
+		}
+	}
+
+	var itemType model.MediaType
+	err := r.q.QueryRowContext(ctx, `SELECT type FROM media_items WHERE id = ?`, id).Scan(&itemType)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("media item %d not found", id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get media item type: %w", err)
+	}
+	if itemType == model.MediaTypeMovie && tvdbID != nil {
+		return fmt.Errorf("movies use a TMDB ID, not a TVDB ID")
+	}
+	if itemType == model.MediaTypeTV && tmdbID != nil {
+		return fmt.Errorf("TV shows use a TVDB ID, not a TMDB ID")
+	}
+
+	query := `UPDATE media_items SET tmdb_id = ?, tvdb_id = ?, updated_at = ? WHERE id = ?`
+	now := time.Now().UTC().Format(time.RFC3339)
+	if _, err := r.q.ExecContext(ctx, query, tmdbID, tvdbID, now, id); err != nil {
+		return fmt.Errorf("failed to update media item database ID: %w", err)
+	}
+	return nil
+}
+
 // ListActiveItems lists all items (including completed - history filtering will be added later)
 func (r *SQLiteRepository) ListActiveItems(ctx context.Context) ([]model.MediaItem, error) {
-	query := `
-		SELECT id, type, name, safe_name, tmdb_id, tvdb_id, status, current_stage, stage_status, created_at, updated_at
+	query := `SELECT ` + mediaItemColumns + `
 		FROM media_items
 		WHERE status IN ('active', 'not_started')
 		ORDER BY updated_at DESC
 	`
-	rows, err := r.db.db.QueryContext(ctx, query)
+	rows, err := r.q.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list active items: %w", err)
 	}
@@ -984,44 +1477,11 @@ func (r *SQLiteRepository) ListActiveItems(ctx context.Context) ([]model.MediaIt
 
 	var items []model.MediaItem
 	for rows.Next() {
-		var item model.MediaItem
-		var tmdbID, tvdbID sql.NullInt64
-		var stageStr, stageStatusStr sql.NullString
-		var createdAt, updatedAt string
-
-		err := rows.Scan(
-			&item.ID,
-			&item.Type,
-			&item.Name,
-			&item.SafeName,
-			&tmdbID,
-			&tvdbID,
-			&item.ItemStatus,
-			&stageStr,
-			&stageStatusStr,
-			&createdAt,
-			&updatedAt,
-		)
+		item, err := scanMediaItem(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan media item: %w", err)
 		}
-
-		if tmdbID.Valid {
-			id := int(tmdbID.Int64)
-			item.TmdbID = &id
-		}
-		if tvdbID.Valid {
-			id := int(tvdbID.Int64)
-			item.TvdbID = &id
-		}
-		if stageStr.Valid {
-			item.CurrentStage = parseStage(stageStr.String)
-		}
-		if stageStatusStr.Valid {
-			item.StageStatus = model.Status(stageStatusStr.String)
-		}
-
-		items = append(items, item)
+		items = append(items, *item)
 	}
 
 	return items, rows.Err()
@@ -1033,7 +1493,7 @@ func (r *SQLiteRepository) CreateTranscodeFile(ctx context.Context, file *model.
 		INSERT INTO transcode_files (job_id, relative_path, status, input_size, duration_secs)
 		VALUES (?, ?, ?, ?, ?)
 	`
-	result, err := r.db.db.ExecContext(ctx, query,
+	result, err := r.q.ExecContext(ctx, query,
 		file.JobID,
 		file.RelativePath,
 		file.Status,
@@ -1056,7 +1516,8 @@ func (r *SQLiteRepository) CreateTranscodeFile(ctx context.Context, file *model.
 func (r *SQLiteRepository) GetTranscodeFile(ctx context.Context, id int64) (*model.TranscodeFile, error) {
 	query := `
 		SELECT id, job_id, relative_path, status, input_size, output_size,
-		       progress, duration_secs, started_at, completed_at, error_message
+		       progress, duration_secs, started_at, completed_at, error_message,
+		       checksum, checksum_algorithm
 		FROM transcode_files
 		WHERE id = ?
 	`
@@ -1065,7 +1526,7 @@ func (r *SQLiteRepository) GetTranscodeFile(ctx context.Context, id int64) (*mod
 	var outputSize sql.NullInt64
 	var errorMsg sql.NullString
 
-	err := r.db.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.q.QueryRowContext(ctx, query, id).Scan(
 		&file.ID,
 		&file.JobID,
 		&file.RelativePath,
@@ -1077,6 +1538,8 @@ func (r *SQLiteRepository) GetTranscodeFile(ctx context.Context, id int64) (*mod
 		&startedAt,
 		&completedAt,
 		&errorMsg,
+		&file.Checksum,
+		&file.ChecksumAlgorithm,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -1109,12 +1572,13 @@ func (r *SQLiteRepository) GetTranscodeFile(ctx context.Context, id int64) (*mod
 func (r *SQLiteRepository) ListTranscodeFiles(ctx context.Context, jobID int64) ([]model.TranscodeFile, error) {
 	query := `
 		SELECT id, job_id, relative_path, status, input_size, output_size,
-		       progress, duration_secs, started_at, completed_at, error_message
+		       progress, duration_secs, started_at, completed_at, error_message,
+		       checksum, checksum_algorithm
 		FROM transcode_files
 		WHERE job_id = ?
 		ORDER BY relative_path
 	`
-	rows, err := r.db.db.QueryContext(ctx, query, jobID)
+	rows, err := r.q.QueryContext(ctx, query, jobID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list transcode files: %w", err)
 	}
@@ -1139,6 +1603,8 @@ func (r *SQLiteRepository) ListTranscodeFiles(ctx context.Context, jobID int64)
 			&startedAt,
 			&completedAt,
 			&errorMsg,
+			&file.Checksum,
+			&file.ChecksumAlgorithm,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan transcode file: %w", err)
 		}
@@ -1171,7 +1637,8 @@ func (r *SQLiteRepository) UpdateTranscodeFile(ctx context.Context, file *model.
 	query := `
 		UPDATE transcode_files
 		SET status = ?, input_size = ?, output_size = ?, progress = ?,
-		    duration_secs = ?, started_at = ?, completed_at = ?, error_message = ?
+		    duration_secs = ?, started_at = ?, completed_at = ?, error_message = ?,
+		    checksum = ?, checksum_algorithm = ?
 		WHERE id = ?
 	`
 	var startedAt, completedAt *string
@@ -1184,7 +1651,7 @@ func (r *SQLiteRepository) UpdateTranscodeFile(ctx context.Context, file *model.
 		completedAt = &s
 	}
 
-	_, err := r.db.db.ExecContext(ctx, query,
+	_, err := r.q.ExecContext(ctx, query,
 		file.Status,
 		file.InputSize,
 		file.OutputSize,
@@ -1193,6 +1660,8 @@ func (r *SQLiteRepository) UpdateTranscodeFile(ctx context.Context, file *model.
 		startedAt,
 		completedAt,
 		file.ErrorMessage,
+		file.Checksum,
+		file.ChecksumAlgorithm,
 		file.ID,
 	)
 	if err != nil {
@@ -1204,7 +1673,7 @@ func (r *SQLiteRepository) UpdateTranscodeFile(ctx context.Context, file *model.
 // UpdateTranscodeFileProgress updates just the progress percentage
 func (r *SQLiteRepository) UpdateTranscodeFileProgress(ctx context.Context, id int64, progress int) error {
 	query := `UPDATE transcode_files SET progress = ? WHERE id = ?`
-	_, err := r.db.db.ExecContext(ctx, query, progress, id)
+	_, err := r.q.ExecContext(ctx, query, progress, id)
 	if err != nil {
 		return fmt.Errorf("failed to update transcode file progress: %w", err)
 	}
@@ -1229,19 +1698,34 @@ func (r *SQLiteRepository) UpdateTranscodeFileStatus(ctx context.Context, id int
 		args = []interface{}{status, errorMsg, id}
 	}
 
-	_, err := r.db.db.ExecContext(ctx, query, args...)
+	_, err := r.q.ExecContext(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update transcode file status: %w", err)
 	}
 	return nil
 }
 
+// GetTranscodeStats sums input and output sizes of completed files for a job
+func (r *SQLiteRepository) GetTranscodeStats(ctx context.Context, jobID int64) (model.TranscodeStats, error) {
+	query := `
+		SELECT COUNT(*), COALESCE(SUM(input_size), 0), COALESCE(SUM(output_size), 0)
+		FROM transcode_files
+		WHERE job_id = ? AND status = 'completed'
+	`
+	var stats model.TranscodeStats
+	err := r.q.QueryRowContext(ctx, query, jobID).Scan(&stats.Files, &stats.InputBytes, &stats.OutputBytes)
+	if err != nil {
+		return stats, fmt.Errorf("failed to get transcode stats: %w", err)
+	}
+	return stats, nil
+}
+
 // GetJobOptions retrieves the JSON options for a job
 func (r *SQLiteRepository) GetJobOptions(ctx context.Context, jobID int64) (map[string]interface{}, error) {
 	query := `SELECT options FROM jobs WHERE id = ?`
 	var optionsJSON sql.NullString
 
-	err := r.db.db.QueryRowContext(ctx, query, jobID).Scan(&optionsJSON)
+	err := r.q.QueryRowContext(ctx, query, jobID).Scan(&optionsJSON)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -1269,7 +1753,7 @@ func (r *SQLiteRepository) SetJobOptions(ctx context.Context, jobID int64, optio
 	}
 
 	query := `UPDATE jobs SET options = ? WHERE id = ?`
-	_, err = r.db.db.ExecContext(ctx, query, string(optionsJSON), jobID)
+	_, err = r.q.ExecContext(ctx, query, string(optionsJSON), jobID)
 	if err != nil {
 		return fmt.Errorf("failed to set job options: %w", err)
 	}
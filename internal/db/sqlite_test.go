@@ -2,6 +2,9 @@ package db
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -296,6 +299,96 @@ func TestSQLiteRepository_ListMediaItems(t *testing.T) {
 		}
 	})
 
+	t.Run("filter by worker", func(t *testing.T) {
+		for _, job := range []*model.Job{
+			{MediaItemID: movie1.ID, Stage: model.StageRip, Status: model.JobStatusCompleted, WorkerID: "ripper-host"},
+			{MediaItemID: tv1.ID, Stage: model.StageRip, Status: model.JobStatusCompleted, WorkerID: "ripper-host"},
+			{MediaItemID: movie2.ID, Stage: model.StageRip, Status: model.JobStatusCompleted, WorkerID: "other-host"},
+		} {
+			if err := repo.CreateJob(ctx, job); err != nil {
+				t.Fatalf("CreateJob() error = %v", err)
+			}
+		}
+
+		items, err := repo.ListMediaItems(ctx, ListOptions{WorkerID: "ripper-host"})
+		if err != nil {
+			t.Fatalf("ListMediaItems() error = %v", err)
+		}
+		if len(items) != 2 {
+			t.Fatalf("len(items) = %d, want 2", len(items))
+		}
+		for _, item := range items {
+			if item.ID == movie2.ID {
+				t.Errorf("worker filter returned %s, which only ran on other-host", item.Name)
+			}
+		}
+	})
+
+	t.Run("search is case-insensitive", func(t *testing.T) {
+		items, err := repo.ListMediaItems(ctx, ListOptions{Search: "mOVIE"})
+		if err != nil {
+			t.Fatalf("ListMediaItems() error = %v", err)
+		}
+		if len(items) != 2 {
+			t.Errorf("len(items) = %d, want 2", len(items))
+		}
+
+		items, err = repo.ListMediaItems(ctx, ListOptions{Search: "show"})
+		if err != nil {
+			t.Fatalf("ListMediaItems() error = %v", err)
+		}
+		if len(items) != 1 || items[0].ID != tv1.ID {
+			t.Errorf("search %q = %+v, want only Show 1", "show", items)
+		}
+	})
+
+	t.Run("search matches safe name and escapes wildcards", func(t *testing.T) {
+		items, err := repo.ListMediaItems(ctx, ListOptions{Search: "movie_2"})
+		if err != nil {
+			t.Fatalf("ListMediaItems() error = %v", err)
+		}
+		if len(items) != 1 || items[0].ID != movie2.ID {
+			t.Errorf("search %q = %+v, want only Movie 2", "movie_2", items)
+		}
+
+		items, err = repo.ListMediaItems(ctx, ListOptions{Search: "%"})
+		if err != nil {
+			t.Fatalf("ListMediaItems() error = %v", err)
+		}
+		if len(items) != 0 {
+			t.Errorf("search %q returned %d items, want 0", "%", len(items))
+		}
+	})
+
+	t.Run("sort by name", func(t *testing.T) {
+		items, err := repo.ListMediaItems(ctx, ListOptions{SortBy: SortByName})
+		if err != nil {
+			t.Fatalf("ListMediaItems() error = %v", err)
+		}
+		var names []string
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+		if strings.Join(names, ",") != "Movie 1,Movie 2,Show 1" {
+			t.Errorf("ascending names = %v", names)
+		}
+
+		items, err = repo.ListMediaItems(ctx, ListOptions{SortBy: SortByName, SortDesc: true})
+		if err != nil {
+			t.Fatalf("ListMediaItems() error = %v", err)
+		}
+		if len(items) != 3 || items[0].Name != "Show 1" {
+			t.Errorf("descending first item = %+v, want Show 1", items)
+		}
+	})
+
+	t.Run("unknown sort field", func(t *testing.T) {
+		_, err := repo.ListMediaItems(ctx, ListOptions{SortBy: "name; DROP TABLE media_items"})
+		if err == nil {
+			t.Error("expected error for unknown sort field")
+		}
+	})
+
 	t.Run("limit and offset", func(t *testing.T) {
 		items, err := repo.ListMediaItems(ctx, ListOptions{Limit: 2, Offset: 1})
 		if err != nil {
@@ -306,6 +399,34 @@ func TestSQLiteRepository_ListMediaItems(t *testing.T) {
 		}
 	})
 
+	t.Run("count matches list filters", func(t *testing.T) {
+		tvType := model.MediaTypeTV
+		for _, opts := range []ListOptions{
+			{},
+			{Type: &tvType},
+			{Search: "movie"},
+			{Search: "movie", Limit: 1, Offset: 1},
+		} {
+			count, err := repo.CountMediaItems(ctx, opts)
+			if err != nil {
+				t.Fatalf("CountMediaItems(%+v) error = %v", opts, err)
+			}
+			unpaged := opts
+			unpaged.Limit, unpaged.Offset = 0, 0
+			items, err := repo.ListMediaItems(ctx, unpaged)
+			if err != nil {
+				t.Fatalf("ListMediaItems() error = %v", err)
+			}
+			if count != len(items) {
+				t.Errorf("CountMediaItems(%+v) = %d, want %d", opts, count, len(items))
+			}
+		}
+
+		if count, _ := repo.CountMediaItems(ctx, ListOptions{Limit: 1}); count != 3 {
+			t.Errorf("CountMediaItems() = %d, want all 3 items regardless of Limit", count)
+		}
+	})
+
 	t.Run("filter by active only", func(t *testing.T) {
 		// Create jobs for some items
 		// movie1 has an active job
@@ -777,6 +898,118 @@ func TestSQLiteRepository_ListJobsForMedia(t *testing.T) {
 	})
 }
 
+func TestSQLiteRepository_GetItemFull(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	if rec, err := repo.GetItemFull(ctx, 999); err != nil || rec != nil {
+		t.Fatalf("GetItemFull(missing) = %v, %v; want nil, nil", rec, err)
+	}
+
+	item := &model.MediaItem{Type: model.MediaTypeTV, Name: "Show", SafeName: "Show"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	seasons, err := repo.CreateSeasons(ctx, item.ID, []int{1, 2})
+	if err != nil {
+		t.Fatalf("CreateSeasons() error = %v", err)
+	}
+
+	rip := &model.Job{MediaItemID: item.ID, SeasonID: &seasons[0].ID, Stage: model.StageRip, Status: model.JobStatusCompleted}
+	transcode := &model.Job{MediaItemID: item.ID, SeasonID: &seasons[0].ID, Stage: model.StageTranscode, Status: model.JobStatusFailed}
+	for _, job := range []*model.Job{rip, transcode} {
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+	}
+	if err := repo.SetJobOptions(ctx, transcode.ID, map[string]interface{}{"profile": "mobile"}); err != nil {
+		t.Fatalf("SetJobOptions() error = %v", err)
+	}
+	file := &model.TranscodeFile{JobID: transcode.ID, RelativePath: "S01E01.mkv", Status: model.TranscodeFileStatusFailed}
+	if err := repo.CreateTranscodeFile(ctx, file); err != nil {
+		t.Fatalf("CreateTranscodeFile() error = %v", err)
+	}
+	for _, job := range []*model.Job{rip, transcode} {
+		event := &model.LogEvent{JobID: job.ID, Level: "info", Message: "started", Timestamp: time.Now()}
+		if err := repo.CreateLogEvent(ctx, event); err != nil {
+			t.Fatalf("CreateLogEvent() error = %v", err)
+		}
+	}
+
+	rec, err := repo.GetItemFull(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("GetItemFull() error = %v", err)
+	}
+	if rec.Item.ID != item.ID || len(rec.Item.Seasons) != 2 {
+		t.Errorf("Item = %d with %d seasons, want %d with 2", rec.Item.ID, len(rec.Item.Seasons), item.ID)
+	}
+	if len(rec.Jobs) != 2 || rec.Jobs[0].ID != rip.ID {
+		t.Errorf("Jobs = %+v, want rip then transcode", rec.Jobs)
+	}
+	if len(rec.JobOptions) != 1 || rec.JobOptions[transcode.ID]["profile"] != "mobile" {
+		t.Errorf("JobOptions = %v, want only the transcode job's profile", rec.JobOptions)
+	}
+	if len(rec.TranscodeFiles) != 1 || rec.TranscodeFiles[0].RelativePath != "S01E01.mkv" {
+		t.Errorf("TranscodeFiles = %+v, want S01E01.mkv", rec.TranscodeFiles)
+	}
+	if len(rec.LogEvents) != 2 {
+		t.Errorf("LogEvents = %d, want one per job", len(rec.LogEvents))
+	}
+}
+
+func TestSQLiteRepository_GetItemFull_ItemState(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	if err := repo.UpdateMediaItemStage(ctx, item.ID, model.StagePublish, model.StatusCompleted); err != nil {
+		t.Fatalf("UpdateMediaItemStage() error = %v", err)
+	}
+	if err := repo.UpdateMediaItemStatus(ctx, item.ID, model.ItemStatusCompleted); err != nil {
+		t.Fatalf("UpdateMediaItemStatus() error = %v", err)
+	}
+
+	rec, err := repo.GetItemFull(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("GetItemFull() error = %v", err)
+	}
+	got := rec.Item
+	if got.ItemStatus != model.ItemStatusCompleted || got.CurrentStage != model.StagePublish || got.StageStatus != model.StatusCompleted {
+		t.Errorf("GetItemFull() item state = %s, %s/%s; want completed, publish/completed",
+			got.ItemStatus, got.CurrentStage, got.StageStatus)
+	}
+
+	items, err := repo.ListMediaItems(ctx, ListOptions{})
+	if err != nil {
+		t.Fatalf("ListMediaItems() error = %v", err)
+	}
+	if len(items) != 1 || items[0].CurrentStage != model.StagePublish || items[0].StageStatus != model.StatusCompleted {
+		t.Errorf("ListMediaItems() = %+v, want the stored stage", items)
+	}
+	bySafeName, err := repo.GetMediaItemBySafeName(ctx, "Movie", nil)
+	if err != nil {
+		t.Fatalf("GetMediaItemBySafeName() error = %v", err)
+	}
+	if bySafeName.ItemStatus != model.ItemStatusCompleted {
+		t.Errorf("GetMediaItemBySafeName() status = %q, want completed", bySafeName.ItemStatus)
+	}
+}
+
 func TestSQLiteRepository_CreateLogEvent(t *testing.T) {
 	db, err := OpenInMemory()
 	if err != nil {
@@ -1121,6 +1354,195 @@ func TestSQLiteRepository_GetSeason(t *testing.T) {
 	})
 }
 
+func TestSQLiteRepository_GetSeasonByNumber(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	// Two shows that both have a season 2
+	var seasons []*model.Season
+	for _, name := range []string{"Show_A", "Show_B"} {
+		show := &model.MediaItem{Type: model.MediaTypeTV, Name: name, SafeName: name}
+		if err := repo.CreateMediaItem(ctx, show); err != nil {
+			t.Fatalf("CreateMediaItem() error = %v", err)
+		}
+		for _, number := range []int{1, 2} {
+			season := &model.Season{ItemID: show.ID, Number: number, StageStatus: model.StatusPending}
+			if err := repo.CreateSeason(ctx, season); err != nil {
+				t.Fatalf("CreateSeason() error = %v", err)
+			}
+			seasons = append(seasons, season)
+		}
+	}
+	showB, want := seasons[2].ItemID, seasons[3]
+
+	t.Run("existing season", func(t *testing.T) {
+		season, err := repo.GetSeasonByNumber(ctx, showB, 2)
+		if err != nil {
+			t.Fatalf("GetSeasonByNumber() error = %v", err)
+		}
+		if season == nil || season.ID != want.ID || season.ItemID != showB || season.Number != 2 {
+			t.Errorf("GetSeasonByNumber() = %+v, want season %d", season, want.ID)
+		}
+	})
+
+	t.Run("missing season", func(t *testing.T) {
+		season, err := repo.GetSeasonByNumber(ctx, showB, 3)
+		if err != nil {
+			t.Fatalf("GetSeasonByNumber() error = %v", err)
+		}
+		if season != nil {
+			t.Errorf("GetSeasonByNumber() = %+v, want nil", season)
+		}
+	})
+}
+
+func TestSQLiteRepository_CreateSeasons(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Show", SafeName: "Show"}
+	if err := repo.CreateMediaItem(ctx, show); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	existing := &model.Season{ItemID: show.ID, Number: 2, StageStatus: model.StatusPending}
+	if err := repo.CreateSeason(ctx, existing); err != nil {
+		t.Fatalf("CreateSeason() error = %v", err)
+	}
+
+	created, err := repo.CreateSeasons(ctx, show.ID, []int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("CreateSeasons() error = %v", err)
+	}
+	if len(created) != 2 || created[0].Number != 1 || created[1].Number != 3 {
+		t.Fatalf("CreateSeasons() = %+v, want seasons 1 and 3", created)
+	}
+	for _, s := range created {
+		if s.ID == 0 || s.ItemID != show.ID || s.CurrentStage != model.StageRip || s.StageStatus != model.StatusPending {
+			t.Errorf("created season = %+v, want pending rip season of show %d", s, show.ID)
+		}
+	}
+
+	seasons, err := repo.ListSeasonsForItem(ctx, show.ID)
+	if err != nil {
+		t.Fatalf("ListSeasonsForItem() error = %v", err)
+	}
+	if len(seasons) != 3 {
+		t.Errorf("got %d seasons, want 3", len(seasons))
+	}
+
+	// Running again creates nothing
+	created, err = repo.CreateSeasons(ctx, show.ID, []int{1, 2, 3})
+	if err != nil {
+		t.Fatalf("CreateSeasons() error = %v", err)
+	}
+	if len(created) != 0 {
+		t.Errorf("CreateSeasons() created %d seasons on rerun, want 0", len(created))
+	}
+}
+
+func TestSQLiteRepository_UpdateSeasonExpectedEpisodes(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Test Show", SafeName: "Test_Show"}
+	if err := repo.CreateMediaItem(ctx, show); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	season := &model.Season{ItemID: show.ID, Number: 1, StageStatus: model.StatusPending}
+	if err := repo.CreateSeason(ctx, season); err != nil {
+		t.Fatalf("CreateSeason() error = %v", err)
+	}
+
+	if err := repo.UpdateSeasonExpectedEpisodes(ctx, season.ID, 13); err != nil {
+		t.Fatalf("UpdateSeasonExpectedEpisodes() error = %v", err)
+	}
+	got, err := repo.GetSeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetSeason() error = %v", err)
+	}
+	if got.ExpectedEpisodes != 13 {
+		t.Errorf("ExpectedEpisodes = %d, want 13", got.ExpectedEpisodes)
+	}
+
+	seasons, err := repo.ListSeasonsForItem(ctx, show.ID)
+	if err != nil {
+		t.Fatalf("ListSeasonsForItem() error = %v", err)
+	}
+	if len(seasons) != 1 || seasons[0].ExpectedEpisodes != 13 {
+		t.Errorf("ListSeasonsForItem() = %+v, want ExpectedEpisodes 13", seasons)
+	}
+
+	if err := repo.UpdateSeasonExpectedEpisodes(ctx, season.ID, -1); err == nil {
+		t.Error("expected error for negative count")
+	}
+	if err := repo.UpdateSeasonExpectedEpisodes(ctx, 99999, 10); err == nil {
+		t.Error("expected error for nonexistent season")
+	}
+}
+
+func TestSQLiteRepository_UpdateSeasonExpectedDiscs(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Test Show", SafeName: "Test_Show"}
+	if err := repo.CreateMediaItem(ctx, show); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	season := &model.Season{ItemID: show.ID, Number: 1, StageStatus: model.StatusPending, ExpectedDiscs: 3}
+	if err := repo.CreateSeason(ctx, season); err != nil {
+		t.Fatalf("CreateSeason() error = %v", err)
+	}
+	got, err := repo.GetSeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetSeason() error = %v", err)
+	}
+	if got.ExpectedDiscs != 3 {
+		t.Errorf("ExpectedDiscs after create = %d, want 3", got.ExpectedDiscs)
+	}
+
+	if err := repo.UpdateSeasonExpectedDiscs(ctx, season.ID, 5); err != nil {
+		t.Fatalf("UpdateSeasonExpectedDiscs() error = %v", err)
+	}
+	seasons, err := repo.ListSeasonsForItem(ctx, show.ID)
+	if err != nil {
+		t.Fatalf("ListSeasonsForItem() error = %v", err)
+	}
+	if len(seasons) != 1 || seasons[0].ExpectedDiscs != 5 {
+		t.Errorf("ListSeasonsForItem() = %+v, want ExpectedDiscs 5", seasons)
+	}
+
+	if err := repo.UpdateSeasonExpectedDiscs(ctx, season.ID, -1); err == nil {
+		t.Error("expected error for negative count")
+	}
+	if err := repo.UpdateSeasonExpectedDiscs(ctx, 99999, 2); err == nil {
+		t.Error("expected error for nonexistent season")
+	}
+}
+
 func TestSQLiteRepository_ListSeasonsForItem(t *testing.T) {
 	db, err := OpenInMemory()
 	if err != nil {
@@ -1346,6 +1768,7 @@ func TestSQLiteRepository_TranscodeFiles(t *testing.T) {
 	file.Progress = 100
 	now := time.Now()
 	file.CompletedAt = &now
+	file.Checksum, file.ChecksumAlgorithm = "9f86d081", "sha256"
 	if err := repo.UpdateTranscodeFile(ctx, file); err != nil {
 		t.Fatalf("UpdateTranscodeFile failed: %v", err)
 	}
@@ -1353,6 +1776,24 @@ func TestSQLiteRepository_TranscodeFiles(t *testing.T) {
 	if got.OutputSize != 500*1024*1024 {
 		t.Errorf("OutputSize = %d, want %d", got.OutputSize, 500*1024*1024)
 	}
+	if got.Checksum != "9f86d081" || got.ChecksumAlgorithm != "sha256" {
+		t.Errorf("Checksum = %s:%s, want sha256:9f86d081", got.ChecksumAlgorithm, got.Checksum)
+	}
+
+	// Test GetTranscodeStats (only completed files are counted)
+	stats, err := repo.GetTranscodeStats(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetTranscodeStats failed: %v", err)
+	}
+	if stats.Files != 1 {
+		t.Errorf("Files = %d, want 1", stats.Files)
+	}
+	if stats.InputBytes != 1024*1024*1024 {
+		t.Errorf("InputBytes = %d, want %d", stats.InputBytes, 1024*1024*1024)
+	}
+	if stats.OutputBytes != 500*1024*1024 {
+		t.Errorf("OutputBytes = %d, want %d", stats.OutputBytes, 500*1024*1024)
+	}
 }
 
 func TestSQLiteRepository_JobOptions(t *testing.T) {
@@ -1499,3 +1940,730 @@ func TestSQLiteRepository_MediaItemDatabaseIDs(t *testing.T) {
 		}
 	})
 }
+
+func TestSQLiteRepository_UpdateMediaItemName(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	items := []*model.MediaItem{
+		{Type: model.MediaTypeMovie, Name: "The Matirx", SafeName: "The_Matirx"},
+		{Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception"},
+		{Type: model.MediaTypeTV, Name: "The Matrix", SafeName: "The_Matrix"},
+	}
+	for _, item := range items {
+		if err := repo.CreateMediaItem(ctx, item); err != nil {
+			t.Fatalf("CreateMediaItem() error = %v", err)
+		}
+	}
+	typo := items[0]
+
+	t.Run("renames item", func(t *testing.T) {
+		// A TV show with the same name lives in a different staging tree
+		if err := repo.UpdateMediaItemName(ctx, typo.ID, "The Matrix", "The_Matrix"); err != nil {
+			t.Fatalf("UpdateMediaItemName() error = %v", err)
+		}
+
+		loaded, err := repo.GetMediaItem(ctx, typo.ID)
+		if err != nil {
+			t.Fatalf("GetMediaItem() error = %v", err)
+		}
+		if loaded.Name != "The Matrix" || loaded.SafeName != "The_Matrix" {
+			t.Errorf("got name=%q safeName=%q", loaded.Name, loaded.SafeName)
+		}
+	})
+
+	t.Run("rejects duplicate name", func(t *testing.T) {
+		err := repo.UpdateMediaItemName(ctx, typo.ID, "Inception", "Inception")
+		if !errors.Is(err, ErrDuplicateName) {
+			t.Errorf("UpdateMediaItemName() error = %v, want ErrDuplicateName", err)
+		}
+	})
+
+	t.Run("keeping the same name is allowed", func(t *testing.T) {
+		if err := repo.UpdateMediaItemName(ctx, typo.ID, "The Matrix", "The_Matrix"); err != nil {
+			t.Errorf("UpdateMediaItemName() error = %v", err)
+		}
+	})
+
+	t.Run("missing item", func(t *testing.T) {
+		if err := repo.UpdateMediaItemName(ctx, 9999, "Gone", "Gone"); err == nil {
+			t.Error("UpdateMediaItemName() error = nil, want error")
+		}
+	})
+}
+
+func TestSQLiteRepository_UpdateMediaItemDatabaseID(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Show", SafeName: "Show"}
+	for _, item := range []*model.MediaItem{movie, show} {
+		if err := repo.CreateMediaItem(ctx, item); err != nil {
+			t.Fatalf("CreateMediaItem() error = %v", err)
+		}
+	}
+
+	intPtr := func(v int) *int { return &v }
+
+	t.Run("sets movie tmdb_id", func(t *testing.T) {
+		if err := repo.UpdateMediaItemDatabaseID(ctx, movie.ID, intPtr(603), nil); err != nil {
+			t.Fatalf("UpdateMediaItemDatabaseID() error = %v", err)
+		}
+		loaded, err := repo.GetMediaItem(ctx, movie.ID)
+		if err != nil {
+			t.Fatalf("GetMediaItem() error = %v", err)
+		}
+		if loaded.DatabaseID() != 603 {
+			t.Errorf("DatabaseID() = %d, want 603", loaded.DatabaseID())
+		}
+	})
+
+	t.Run("sets show tvdb_id", func(t *testing.T) {
+		if err := repo.UpdateMediaItemDatabaseID(ctx, show.ID, nil, intPtr(81189)); err != nil {
+			t.Fatalf("UpdateMediaItemDatabaseID() error = %v", err)
+		}
+		loaded, err := repo.GetMediaItem(ctx, show.ID)
+		if err != nil {
+			t.Fatalf("GetMediaItem() error = %v", err)
+		}
+		if loaded.TvdbID == nil || *loaded.TvdbID != 81189 {
+			t.Errorf("TvdbID = %v, want 81189", loaded.TvdbID)
+		}
+	})
+
+	errorCases := []struct {
+		name   string
+		id     int64
+		tmdbID *int
+		tvdbID *int
+	}{
+		{"tvdb on movie", movie.ID, nil, intPtr(1)},
+		{"tmdb on show", show.ID, intPtr(1), nil},
+		{"non-positive id", movie.ID, intPtr(0), nil},
+		{"missing item", 9999, intPtr(1), nil},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := repo.UpdateMediaItemDatabaseID(ctx, tc.id, tc.tmdbID, tc.tvdbID); err == nil {
+				t.Error("UpdateMediaItemDatabaseID() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestSQLiteRepository_GetJobStats(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	t.Run("empty database", func(t *testing.T) {
+		stats, err := repo.GetJobStats(ctx)
+		if err != nil {
+			t.Fatalf("GetJobStats() error = %v", err)
+		}
+		if stats.Total != 0 {
+			t.Errorf("Total = %d, want 0", stats.Total)
+		}
+		if stats.BytesProcessed != 0 {
+			t.Errorf("BytesProcessed = %d, want 0", stats.BytesProcessed)
+		}
+	})
+
+	item := &model.MediaItem{
+		Type:     model.MediaTypeMovie,
+		Name:     "Test Movie",
+		SafeName: "Test_Movie",
+	}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+
+	jobs := []struct {
+		stage  model.Stage
+		status model.JobStatus
+	}{
+		{model.StageRip, model.JobStatusFailed},
+		{model.StageRip, model.JobStatusCompleted},
+		{model.StageRemux, model.JobStatusCompleted},
+		{model.StageTranscode, model.JobStatusInProgress},
+	}
+	var transcodeJobID int64
+	for _, j := range jobs {
+		job := &model.Job{MediaItemID: item.ID, Stage: j.stage, Status: j.status}
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+		if j.stage == model.StageTranscode {
+			transcodeJobID = job.ID
+		}
+	}
+
+	for i, size := range []int64{1000, 2500} {
+		file := &model.TranscodeFile{
+			JobID:        transcodeJobID,
+			RelativePath: fmt.Sprintf("_main/%d.mkv", i),
+			Status:       model.TranscodeFileStatusPending,
+			InputSize:    size * 2,
+		}
+		if err := repo.CreateTranscodeFile(ctx, file); err != nil {
+			t.Fatalf("CreateTranscodeFile() error = %v", err)
+		}
+		file.Status = model.TranscodeFileStatusCompleted
+		file.OutputSize = size
+		if err := repo.UpdateTranscodeFile(ctx, file); err != nil {
+			t.Fatalf("UpdateTranscodeFile() error = %v", err)
+		}
+	}
+
+	t.Run("grouped counts", func(t *testing.T) {
+		stats, err := repo.GetJobStats(ctx)
+		if err != nil {
+			t.Fatalf("GetJobStats() error = %v", err)
+		}
+		if stats.Total != 4 {
+			t.Errorf("Total = %d, want 4", stats.Total)
+		}
+		if got := stats.Count(model.StageRip, model.JobStatusFailed); got != 1 {
+			t.Errorf("Count(rip, failed) = %d, want 1", got)
+		}
+		if got := stats.Count(model.StageRip, model.JobStatusCompleted); got != 1 {
+			t.Errorf("Count(rip, completed) = %d, want 1", got)
+		}
+		if got := stats.CountByStatus(model.JobStatusCompleted); got != 2 {
+			t.Errorf("CountByStatus(completed) = %d, want 2", got)
+		}
+		if got := stats.Count(model.StagePublish, model.JobStatusCompleted); got != 0 {
+			t.Errorf("Count(publish, completed) = %d, want 0", got)
+		}
+		if stats.BytesProcessed != 3500 {
+			t.Errorf("BytesProcessed = %d, want 3500", stats.BytesProcessed)
+		}
+	})
+}
+
+func TestSQLiteRepository_DeleteJobsBefore(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	done := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Done", SafeName: "Done"}
+	active := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Active", SafeName: "Active"}
+	for _, item := range []*model.MediaItem{done, active} {
+		if err := repo.CreateMediaItem(ctx, item); err != nil {
+			t.Fatalf("CreateMediaItem() error = %v", err)
+		}
+	}
+	if err := repo.UpdateMediaItemStatus(ctx, done.ID, model.ItemStatusCompleted); err != nil {
+		t.Fatalf("UpdateMediaItemStatus() error = %v", err)
+	}
+	if err := repo.UpdateMediaItemStatus(ctx, active.ID, model.ItemStatusActive); err != nil {
+		t.Fatalf("UpdateMediaItemStatus() error = %v", err)
+	}
+
+	createJobs := func(itemID int64, statuses ...model.JobStatus) {
+		for _, status := range statuses {
+			job := &model.Job{MediaItemID: itemID, Stage: model.StageRip, Status: status}
+			if err := repo.CreateJob(ctx, job); err != nil {
+				t.Fatalf("CreateJob() error = %v", err)
+			}
+		}
+	}
+	createJobs(done.ID, model.JobStatusCompleted, model.JobStatusFailed, model.JobStatusCompleted)
+	createJobs(active.ID, model.JobStatusCompleted, model.JobStatusFailed)
+
+	t.Run("cutoff in the past deletes nothing", func(t *testing.T) {
+		deleted, err := repo.DeleteJobsBefore(ctx, time.Now().Add(-time.Hour), false)
+		if err != nil {
+			t.Fatalf("DeleteJobsBefore() error = %v", err)
+		}
+		if deleted != 0 {
+			t.Errorf("deleted = %d, want 0", deleted)
+		}
+	})
+
+	t.Run("only completed keeps failed jobs", func(t *testing.T) {
+		deleted, err := repo.DeleteJobsBefore(ctx, time.Now().Add(time.Hour), true)
+		if err != nil {
+			t.Fatalf("DeleteJobsBefore() error = %v", err)
+		}
+		if deleted != 2 {
+			t.Errorf("deleted = %d, want 2", deleted)
+		}
+	})
+
+	t.Run("deletes failed jobs but never active items", func(t *testing.T) {
+		deleted, err := repo.DeleteJobsBefore(ctx, time.Now().Add(time.Hour), false)
+		if err != nil {
+			t.Fatalf("DeleteJobsBefore() error = %v", err)
+		}
+		if deleted != 1 {
+			t.Errorf("deleted = %d, want 1", deleted)
+		}
+
+		remaining, err := repo.ListJobsForMedia(ctx, done.ID)
+		if err != nil {
+			t.Fatalf("ListJobsForMedia() error = %v", err)
+		}
+		if len(remaining) != 0 {
+			t.Errorf("completed item has %d jobs, want 0", len(remaining))
+		}
+
+		remaining, err = repo.ListJobsForMedia(ctx, active.ID)
+		if err != nil {
+			t.Fatalf("ListJobsForMedia() error = %v", err)
+		}
+		if len(remaining) != 2 {
+			t.Errorf("active item has %d jobs, want 2", len(remaining))
+		}
+	})
+}
+
+func TestSQLiteRepository_DeleteLogEventsBefore(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Test Movie", SafeName: "Test_Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	job := &model.Job{MediaItemID: item.ID, Stage: model.StageRip, Status: model.JobStatusInProgress}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		event := &model.LogEvent{JobID: job.ID, Level: "info", Message: fmt.Sprintf("line %d", i)}
+		if err := repo.CreateLogEvent(ctx, event); err != nil {
+			t.Fatalf("CreateLogEvent() error = %v", err)
+		}
+	}
+
+	deleted, err := repo.DeleteLogEventsBefore(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("DeleteLogEventsBefore() error = %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("deleted = %d, want 0", deleted)
+	}
+
+	deleted, err = repo.DeleteLogEventsBefore(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("DeleteLogEventsBefore() error = %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("deleted = %d, want 3", deleted)
+	}
+}
+
+func TestSQLiteRepository_WithTx(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Test Movie", SafeName: "Test_Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		wantErr := errors.New("stage update failed")
+		err := repo.WithTx(ctx, func(tx Repository) error {
+			job := &model.Job{MediaItemID: item.ID, Stage: model.StageOrganize, Status: model.JobStatusCompleted}
+			if err := tx.CreateJob(ctx, job); err != nil {
+				return err
+			}
+			return wantErr
+		})
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("WithTx() error = %v, want %v", err, wantErr)
+		}
+
+		jobs, err := repo.ListJobsForMedia(ctx, item.ID)
+		if err != nil {
+			t.Fatalf("ListJobsForMedia() error = %v", err)
+		}
+		if len(jobs) != 0 {
+			t.Errorf("got %d jobs after rollback, want 0", len(jobs))
+		}
+	})
+
+	t.Run("commits on success", func(t *testing.T) {
+		err := repo.WithTx(ctx, func(tx Repository) error {
+			job := &model.Job{MediaItemID: item.ID, Stage: model.StageOrganize, Status: model.JobStatusCompleted}
+			if err := tx.CreateJob(ctx, job); err != nil {
+				return err
+			}
+			// Nested calls join the outer transaction
+			return tx.WithTx(ctx, func(tx Repository) error {
+				return tx.UpdateMediaItemStage(ctx, item.ID, model.StageOrganize, model.StatusCompleted)
+			})
+		})
+		if err != nil {
+			t.Fatalf("WithTx() error = %v", err)
+		}
+
+		jobs, _ := repo.ListJobsForMedia(ctx, item.ID)
+		if len(jobs) != 1 {
+			t.Errorf("got %d jobs, want 1", len(jobs))
+		}
+		items, _ := repo.ListActiveItems(ctx)
+		if len(items) != 1 || items[0].CurrentStage != model.StageOrganize || items[0].StageStatus != model.StatusCompleted {
+			t.Errorf("stage not committed: %+v", items)
+		}
+	})
+}
+
+func TestSQLiteRepository_ForceJobStatus(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	newJob := func(stage model.Stage) *model.Job {
+		job := &model.Job{MediaItemID: item.ID, Stage: stage, Status: model.JobStatusInProgress, PID: 1234}
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+		return job
+	}
+
+	ripJob := newJob(model.StageRip)
+	if err := repo.ForceCompleteJob(ctx, ripJob.ID); err != nil {
+		t.Fatalf("ForceCompleteJob() error = %v", err)
+	}
+	got, err := repo.GetJob(ctx, ripJob.ID)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if got.Status != model.JobStatusCompleted || got.PID != 0 || got.CompletedAt == nil {
+		t.Errorf("after ForceCompleteJob() job = %+v, want completed with PID cleared", got)
+	}
+
+	remuxJob := newJob(model.StageRemux)
+	if err := repo.ForceFailJob(ctx, remuxJob.ID, "worker died"); err != nil {
+		t.Fatalf("ForceFailJob() error = %v", err)
+	}
+	got, err = repo.GetJob(ctx, remuxJob.ID)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if got.Status != model.JobStatusFailed || got.ErrorMessage != "worker died" || got.PID != 0 {
+		t.Errorf("after ForceFailJob() job = %+v, want failed with reason and PID cleared", got)
+	}
+
+	// Finished jobs are left alone
+	if err := repo.ForceFailJob(ctx, ripJob.ID, "late"); !errors.Is(err, ErrJobNotActive) {
+		t.Errorf("ForceFailJob() on completed job error = %v, want ErrJobNotActive", err)
+	}
+	got, err = repo.GetJob(ctx, ripJob.ID)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if got.Status != model.JobStatusCompleted {
+		t.Errorf("completed job status = %s after rejected force, want completed", got.Status)
+	}
+}
+
+func TestSQLiteRepository_SupersedeJob(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	failed := &model.Job{MediaItemID: item.ID, Stage: model.StageRemux, Status: model.JobStatusFailed}
+	retry := &model.Job{MediaItemID: item.ID, Stage: model.StageRemux, Status: model.JobStatusPending}
+	for _, job := range []*model.Job{failed, retry} {
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+	}
+
+	if err := repo.SupersedeJob(ctx, failed.ID, retry.ID); err != nil {
+		t.Fatalf("SupersedeJob() error = %v", err)
+	}
+	got, err := repo.GetJob(ctx, failed.ID)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if got.SupersededBy == nil || *got.SupersededBy != retry.ID || got.Status != model.JobStatusFailed {
+		t.Errorf("superseded job = %+v, want failed and superseded by %d", got, retry.ID)
+	}
+	jobs, err := repo.ListJobsForMedia(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("ListJobsForMedia() error = %v", err)
+	}
+	if jobs[0].SupersededBy == nil || jobs[1].SupersededBy != nil {
+		t.Errorf("ListJobsForMedia() superseded_by = %v, %v", jobs[0].SupersededBy, jobs[1].SupersededBy)
+	}
+
+	// A job can only be retried once, and only if it failed
+	if err := repo.SupersedeJob(ctx, failed.ID, retry.ID); !errors.Is(err, ErrJobNotRetryable) {
+		t.Errorf("second SupersedeJob() error = %v, want ErrJobNotRetryable", err)
+	}
+	if err := repo.SupersedeJob(ctx, retry.ID, failed.ID); !errors.Is(err, ErrJobNotRetryable) {
+		t.Errorf("SupersedeJob() on pending job error = %v, want ErrJobNotRetryable", err)
+	}
+}
+
+func TestSQLiteRepository_ReopenJob(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	job := &model.Job{MediaItemID: item.ID, Stage: model.StageTranscode, Status: model.JobStatusPending}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	// Only failed jobs can be reopened
+	if err := repo.ReopenJob(ctx, job.ID); !errors.Is(err, ErrJobNotRetryable) {
+		t.Errorf("ReopenJob() on pending job error = %v, want ErrJobNotRetryable", err)
+	}
+
+	if err := repo.UpdateJobStatus(ctx, job.ID, model.JobStatusFailed, "1 of 3 files failed"); err != nil {
+		t.Fatalf("UpdateJobStatus() error = %v", err)
+	}
+	if err := repo.ReopenJob(ctx, job.ID); err != nil {
+		t.Fatalf("ReopenJob() error = %v", err)
+	}
+	got, err := repo.GetJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if got.Status != model.JobStatusPending || got.ErrorMessage != "" || got.CompletedAt != nil {
+		t.Errorf("reopened job = %+v, want pending without error or completion time", got)
+	}
+}
+
+func TestSQLiteRepository_InvalidStatus(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Test Movie", SafeName: "Test_Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	season := &model.Season{ItemID: item.ID, Number: 1, CurrentStage: model.StageRip, StageStatus: model.StatusPending}
+	if err := repo.CreateSeason(ctx, season); err != nil {
+		t.Fatalf("CreateSeason() error = %v", err)
+	}
+	job := &model.Job{MediaItemID: item.ID, Stage: model.StageRip, Status: model.JobStatusPending}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	if err := repo.UpdateJobStatus(ctx, job.ID, "complete", ""); err == nil || !strings.Contains(err.Error(), `invalid job status "complete"`) {
+		t.Errorf("UpdateJobStatus() error = %v, want invalid job status", err)
+	}
+	if err := repo.UpdateMediaItemStage(ctx, item.ID, model.StageRip, "complete"); err == nil {
+		t.Error("UpdateMediaItemStage() with an invalid status should fail")
+	}
+	if err := repo.UpdateSeasonStage(ctx, season.ID, model.StageRip, "complete"); err == nil {
+		t.Error("UpdateSeasonStage() with an invalid status should fail")
+	}
+	if err := repo.CreateJob(ctx, &model.Job{MediaItemID: item.ID, Stage: model.StageRip, Status: "complete"}); err == nil {
+		t.Error("CreateJob() with an invalid status should fail")
+	}
+	if err := repo.UpdateJob(ctx, &model.Job{ID: job.ID, MediaItemID: item.ID, Stage: model.StageRip, Status: "complete"}); err == nil {
+		t.Error("UpdateJob() with an invalid status should fail")
+	}
+	if err := repo.CreateSeason(ctx, &model.Season{ItemID: item.ID, Number: 2, StageStatus: "complete"}); err == nil {
+		t.Error("CreateSeason() with an invalid status should fail")
+	}
+	if err := repo.UpdateMediaItemStatus(ctx, item.ID, "done"); err == nil {
+		t.Error("UpdateMediaItemStatus() with an invalid status should fail")
+	}
+	if err := repo.CreateMediaItem(ctx, &model.MediaItem{Type: model.MediaTypeMovie, Name: "Other", SafeName: "Other", ItemStatus: "done"}); err == nil {
+		t.Error("CreateMediaItem() with an invalid item status should fail")
+	}
+
+	got, err := repo.GetJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if got.Status != model.JobStatusPending {
+		t.Errorf("job status = %s after rejected update, want pending", got.Status)
+	}
+
+	// A corrupted row that slipped past the CHECK constraint fails to read
+	if _, err := db.db.Exec(`PRAGMA ignore_check_constraints = ON`); err != nil {
+		t.Fatalf("pragma error = %v", err)
+	}
+	if _, err := db.db.Exec(`UPDATE jobs SET status = 'complete' WHERE id = ?`, job.ID); err != nil {
+		t.Fatalf("corrupting status: %v", err)
+	}
+	if _, err := repo.GetJob(ctx, job.ID); err == nil || !strings.Contains(err.Error(), `unknown job status "complete"`) {
+		t.Errorf("GetJob() error = %v, want unknown job status", err)
+	}
+}
+
+func TestSQLiteRepository_InvalidStage(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Test Movie", SafeName: "Test_Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+
+	t.Run("write rejects unknown stage", func(t *testing.T) {
+		job := &model.Job{MediaItemID: item.ID, Stage: model.Stage(42), Status: model.JobStatusPending}
+		if err := repo.CreateJob(ctx, job); err == nil {
+			t.Error("CreateJob() with an invalid stage should fail")
+		}
+	})
+
+	t.Run("read rejects unknown stage", func(t *testing.T) {
+		job := &model.Job{MediaItemID: item.ID, Stage: model.StageRemux, Status: model.JobStatusPending}
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+
+		// Simulate a corrupted row that slipped past the CHECK constraint
+		if _, err := db.db.Exec(`PRAGMA ignore_check_constraints = ON`); err != nil {
+			t.Fatalf("pragma error = %v", err)
+		}
+		if _, err := db.db.Exec(`UPDATE jobs SET stage = 'encode' WHERE id = ?`, job.ID); err != nil {
+			t.Fatalf("corrupting stage: %v", err)
+		}
+
+		got, err := repo.GetJob(ctx, job.ID)
+		if err == nil {
+			t.Fatalf("GetJob() = %+v, want scan error instead of a default stage", got)
+		}
+		if !strings.Contains(err.Error(), `unknown stage "encode"`) {
+			t.Errorf("GetJob() error = %v, want unknown stage", err)
+		}
+	})
+}
+
+func TestSQLiteRepository_CanStartStage(t *testing.T) {
+	db, err := OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer db.Close()
+
+	repo := NewSQLiteRepository(db)
+	ctx := context.Background()
+
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Test Movie", SafeName: "Test_Movie"}
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Test Show", SafeName: "Test_Show"}
+	for _, item := range []*model.MediaItem{movie, show} {
+		if err := repo.CreateMediaItem(ctx, item); err != nil {
+			t.Fatalf("CreateMediaItem() error = %v", err)
+		}
+	}
+	s1 := &model.Season{ItemID: show.ID, Number: 1, StageStatus: model.StatusPending}
+	s2 := &model.Season{ItemID: show.ID, Number: 2, StageStatus: model.StatusPending}
+	for _, season := range []*model.Season{s1, s2} {
+		if err := repo.CreateSeason(ctx, season); err != nil {
+			t.Fatalf("CreateSeason() error = %v", err)
+		}
+	}
+
+	jobs := []*model.Job{
+		{MediaItemID: movie.ID, Stage: model.StageRemux, Status: model.JobStatusCompleted},
+		{MediaItemID: movie.ID, Stage: model.StageTranscode, Status: model.JobStatusFailed},
+		{MediaItemID: show.ID, SeasonID: &s1.ID, Stage: model.StageOrganize, Status: model.JobStatusCompleted},
+	}
+	for _, job := range jobs {
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		itemID   int64
+		stage    model.Stage
+		seasonID *int64
+		want     bool
+		reason   string
+	}{
+		{"rip has no prerequisite", movie.ID, model.StageRip, nil, true, ""},
+		{"transcode after remux", movie.ID, model.StageTranscode, nil, true, ""},
+		{"failed transcode blocks publish", movie.ID, model.StagePublish, nil, false, "cannot start publish: no completed transcode job for this item"},
+		{"organize without rip", movie.ID, model.StageOrganize, nil, false, "cannot start organize: no completed rip job for this item"},
+		{"season organized", show.ID, model.StageRemux, &s1.ID, true, ""},
+		{"other season not organized", show.ID, model.StageRemux, &s2.ID, false, "cannot start remux: no completed organize job for this season"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, reason := repo.CanStartStage(ctx, tt.itemID, tt.stage, tt.seasonID)
+			if ok != tt.want || reason != tt.reason {
+				t.Errorf("CanStartStage() = %v, %q; want %v, %q", ok, reason, tt.want, tt.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+// Package dispatch starts stage binaries for pipeline jobs, either locally
+// or on a remote host over SSH.
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// reachabilityTimeout bounds how long CheckTarget waits for SSH to connect
+const reachabilityTimeout = 10 * time.Second
+
+// SSH dispatch waits for the remote shell to launch the binary in the
+// background, retrying connection failures
+const (
+	dispatchTimeout  = 30 * time.Second // Per attempt
+	dispatchAttempts = 3
+)
+
+// dispatchBackoff is the wait before the first SSH dispatch retry, doubled
+// after each further failure (a variable for testing)
+var dispatchBackoff = 2 * time.Second
+
+// sshOptions make ssh fail instead of prompting, and give up on hosts that
+// don't answer
+var sshOptions = []string{"-o", "BatchMode=yes", "-o", "ConnectTimeout=5"}
+
+// CommandRunner executes commands
+type CommandRunner interface {
+	// Start starts a command without waiting for it to finish
+	Start(name string, args []string) error
+	// Run runs a command to completion, returning its combined output
+	Run(ctx context.Context, name string, args []string) (string, error)
+}
+
+// defaultCommandRunner runs real processes
+type defaultCommandRunner struct{}
+
+func (r *defaultCommandRunner) Start(name string, args []string) error {
+	return exec.Command(name, args...).Start()
+}
+
+func (r *defaultCommandRunner) Run(ctx context.Context, name string, args []string) (string, error) {
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	return string(output), err
+}
+
+// Dispatcher launches the binary for a job's stage
+type Dispatcher struct {
+	cfg    *config.Config
+	binDir string        // Directory searched for sibling binaries
+	runner CommandRunner // Injectable for testing
+}
+
+// NewDispatcher creates a Dispatcher using cfg for database and remote paths.
+// Local binaries are looked up next to the running executable, falling back to PATH.
+func NewDispatcher(cfg *config.Config) *Dispatcher {
+	var binDir string
+	if exe, err := os.Executable(); err == nil {
+		binDir = filepath.Dir(exe)
+	}
+	return &Dispatcher{
+		cfg:    cfg,
+		binDir: binDir,
+		runner: &defaultCommandRunner{},
+	}
+}
+
+// SetCommandRunner allows injecting a custom command runner (for testing)
+func (d *Dispatcher) SetCommandRunner(runner CommandRunner) {
+	d.runner = runner
+}
+
+// CheckTarget verifies an SSH dispatch target accepts non-interactive logins.
+// An empty target (local execution) is always reachable.
+func (d *Dispatcher) CheckTarget(ctx context.Context, target string) error {
+	if target == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, reachabilityTimeout)
+	defer cancel()
+
+	args := append(append([]string{}, sshOptions...), target, "true")
+	if output, err := d.runner.Run(ctx, "ssh", args); err != nil {
+		if msg := strings.TrimSpace(output); msg != "" {
+			return fmt.Errorf("dispatch target %s unreachable: %s", target, msg)
+		}
+		return fmt.Errorf("dispatch target %s unreachable: %w", target, err)
+	}
+	return nil
+}
+
+// Dispatch starts the stage binary for job. An empty target runs it locally;
+// otherwise it is run on target via SSH, assuming the binary is in the remote PATH,
+// with MEDIA_BASE and the database path taken from the target's remote config.
+// SSH dispatch returns once the remote shell has started the binary in the
+// background, so an unreachable host or a missing binary is reported here.
+func (d *Dispatcher) Dispatch(ctx context.Context, job *model.Job, target string) error {
+	binaryName := BinaryName(job.Stage)
+
+	if target == "" {
+		args := d.jobArgs(job, d.cfg.DatabasePath())
+		if file := d.cfg.ConfigFile(); file != "" {
+			// Run the job against the same config as the dispatcher
+			args = append(args, "-config", file)
+		}
+		if err := d.runner.Start(d.resolveLocal(binaryName), args); err != nil {
+			return fmt.Errorf("failed to start %s: %w", binaryName, err)
+		}
+		return nil
+	}
+
+	// ssh joins its arguments into a remote shell command, so quote each word
+	remote := []string{
+		"MEDIA_BASE=" + shellQuote(d.cfg.RemoteMediaBase(target)),
+		"nohup", binaryName,
+	}
+	for _, arg := range d.jobArgs(job, d.cfg.RemoteDatabasePath(target)) {
+		remote = append(remote, shellQuote(arg))
+	}
+	command := fmt.Sprintf("command -v %s >/dev/null || { echo '%s not found in PATH' >&2; exit 127; }; %s </dev/null >/dev/null 2>&1 &",
+		binaryName, binaryName, strings.Join(remote, " "))
+
+	sshArgs := append(append([]string{}, sshOptions...), target, command)
+	if err := d.runSSH(ctx, sshArgs); err != nil {
+		return fmt.Errorf("failed to SSH dispatch %s to %s: %w", binaryName, target, err)
+	}
+	return nil
+}
+
+// runSSH runs ssh with args, retrying with backoff while ssh can't connect
+// to the host. Nothing else is retried: once connected the remote command may
+// have started, and running it twice would launch the job twice.
+func (d *Dispatcher) runSSH(ctx context.Context, args []string) error {
+	backoff := dispatchBackoff
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, dispatchTimeout)
+		output, err := d.runner.Run(attemptCtx, "ssh", args)
+		timedOut := attemptCtx.Err() != nil
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		retry := !timedOut && sshConnectFailed(err, output)
+		if msg := strings.TrimSpace(output); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		if timedOut {
+			err = fmt.Errorf("timed out after %s: %w", dispatchTimeout, err)
+		}
+		if ctx.Err() != nil || attempt == dispatchAttempts || !retry {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// sshConnectFailures are ssh messages for a connection that failed before
+// any remote command could run
+var sshConnectFailures = []string{
+	"ssh: connect to host",
+	"ssh: Could not resolve hostname",
+	"Connection timed out during banner exchange",
+	"kex_exchange_identification:",
+}
+
+// sshConnectFailed reports whether ssh exited because it couldn't connect to
+// the host, which proves the remote command never ran. ssh exits 255 for
+// those, but also when an established connection drops, so the output is
+// checked as well.
+func sshConnectFailed(err error, output string) bool {
+	if errors.Is(err, exec.ErrNotFound) {
+		return false
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() != 255 {
+		return false
+	}
+	for _, msg := range sshConnectFailures {
+		if strings.Contains(output, msg) {
+			return true
+		}
+	}
+	return false
+}
+
+// BinaryName returns the name of the binary that executes a stage
+func BinaryName(stage model.Stage) string {
+	if stage == model.StageRip {
+		return "ripper"
+	}
+	return stage.String()
+}
+
+// jobArgs builds the common stage binary arguments, passing on the
+// configured log level
+func (d *Dispatcher) jobArgs(job *model.Job, dbPath string) []string {
+	args := []string{
+		"-job-id", fmt.Sprintf("%d", job.ID),
+		"-db", dbPath,
+	}
+	if level := d.cfg.Logging.Level; level != "" {
+		args = append(args, "-log-level", level)
+	}
+	return args
+}
+
+// resolveLocal returns the sibling binary path if it exists, otherwise the bare name for PATH lookup
+func (d *Dispatcher) resolveLocal(binaryName string) string {
+	if d.binDir == "" {
+		return binaryName
+	}
+	siblingPath := filepath.Join(d.binDir, binaryName)
+	if _, err := os.Stat(siblingPath); err == nil {
+		return siblingPath
+	}
+	return binaryName
+}
+
+// shellQuote quotes s for a POSIX shell if it contains anything beyond safe characters
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@", r))
+	}) == -1 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,327 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// fakeRunner records commands instead of running them
+type fakeRunner struct {
+	name   string
+	args   []string
+	err    error
+	output string
+	errs   []error // Returned by the first Run calls, before err
+	runs   int
+}
+
+func (r *fakeRunner) Start(name string, args []string) error {
+	r.name = name
+	r.args = args
+	return r.err
+}
+
+func (r *fakeRunner) Run(ctx context.Context, name string, args []string) (string, error) {
+	r.name = name
+	r.args = args
+	r.runs++
+	if r.runs <= len(r.errs) {
+		return r.output, r.errs[r.runs-1]
+	}
+	return r.output, r.err
+}
+
+func newTestDispatcher(t *testing.T, cfg *config.Config) (*Dispatcher, *fakeRunner) {
+	t.Helper()
+	t.Setenv("MEDIA_BASE", "/mnt/media")
+	d := NewDispatcher(cfg)
+	d.binDir = t.TempDir()
+	runner := &fakeRunner{}
+	d.SetCommandRunner(runner)
+	return d, runner
+}
+
+func TestDispatcher_Dispatch_Local(t *testing.T) {
+	d, runner := newTestDispatcher(t, &config.Config{})
+
+	job := &model.Job{ID: 42, Stage: model.StageRemux}
+	if err := d.Dispatch(context.Background(), job, ""); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	if runner.name != "remux" {
+		t.Errorf("name = %q, want %q", runner.name, "remux")
+	}
+	want := "-job-id 42 -db /mnt/media/pipeline/pipeline.db"
+	if got := strings.Join(runner.args, " "); got != want {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+}
+
+func TestDispatcher_Dispatch_LogLevel(t *testing.T) {
+	d, runner := newTestDispatcher(t, &config.Config{Logging: config.LoggingConfig{Level: "debug"}})
+
+	job := &model.Job{ID: 42, Stage: model.StageTranscode}
+	if err := d.Dispatch(context.Background(), job, ""); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	want := "-job-id 42 -db /mnt/media/pipeline/pipeline.db -log-level debug"
+	if got := strings.Join(runner.args, " "); got != want {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+}
+
+func TestDispatcher_Dispatch_LocalConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "staging.yaml")
+	if err := os.WriteFile(path, []byte("staging_base: /mnt/test/staging\nlibrary_base: /mnt/test/library\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadFromFlag(path)
+	if err != nil {
+		t.Fatalf("LoadFromFlag() error = %v", err)
+	}
+	d, runner := newTestDispatcher(t, cfg)
+
+	job := &model.Job{ID: 42, Stage: model.StageRemux}
+	if err := d.Dispatch(context.Background(), job, ""); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	want := "-job-id 42 -db /mnt/media/pipeline/pipeline.db -config " + path
+	if got := strings.Join(runner.args, " "); got != want {
+		t.Errorf("args = %q, want %q", got, want)
+	}
+}
+
+func TestDispatcher_Dispatch_LocalSibling(t *testing.T) {
+	d, runner := newTestDispatcher(t, &config.Config{})
+
+	sibling := filepath.Join(d.binDir, "transcode")
+	if err := os.WriteFile(sibling, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	job := &model.Job{ID: 1, Stage: model.StageTranscode}
+	if err := d.Dispatch(context.Background(), job, ""); err != nil {
+		t.Fatalf("Dispatch() error = %v", err)
+	}
+
+	if runner.name != sibling {
+		t.Errorf("name = %q, want sibling %q", runner.name, sibling)
+	}
+}
+
+func TestDispatcher_Dispatch_SSH(t *testing.T) {
+	tests := []struct {
+		name    string
+		remotes map[string]config.RemoteConfig
+		want    string
+	}{
+		{
+			name: "shared filesystem defaults",
+			want: "MEDIA_BASE=/mnt/media nohup ripper -job-id 7 -db /mnt/media/pipeline/pipeline.db",
+		},
+		{
+			name: "remote media base",
+			remotes: map[string]config.RemoteConfig{
+				"ripper-host": {MediaBase: "/srv/media"},
+			},
+			want: "MEDIA_BASE=/srv/media nohup ripper -job-id 7 -db /srv/media/pipeline/pipeline.db",
+		},
+		{
+			name: "remote db path",
+			remotes: map[string]config.RemoteConfig{
+				"ripper-host": {MediaBase: "/srv/media", DBPath: "/net/db/pipeline.db"},
+			},
+			want: "MEDIA_BASE=/srv/media nohup ripper -job-id 7 -db /net/db/pipeline.db",
+		},
+		{
+			name: "paths with spaces are quoted",
+			remotes: map[string]config.RemoteConfig{
+				"ripper-host": {MediaBase: "/srv/my media"},
+			},
+			want: "MEDIA_BASE='/srv/my media' nohup ripper -job-id 7 -db '/srv/my media/pipeline/pipeline.db'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, runner := newTestDispatcher(t, &config.Config{Remotes: tt.remotes})
+
+			job := &model.Job{ID: 7, Stage: model.StageRip}
+			if err := d.Dispatch(context.Background(), job, "ripper-host"); err != nil {
+				t.Fatalf("Dispatch() error = %v", err)
+			}
+
+			if runner.name != "ssh" {
+				t.Errorf("name = %q, want %q", runner.name, "ssh")
+			}
+			n := len(runner.args)
+			if n < 2 || runner.args[n-2] != "ripper-host" {
+				t.Fatalf("args = %q, want [... ripper-host <command>]", runner.args)
+			}
+			// The binary is started in the background, after checking it exists
+			want := "command -v ripper >/dev/null || { echo 'ripper not found in PATH' >&2; exit 127; }; " +
+				tt.want + " </dev/null >/dev/null 2>&1 &"
+			if runner.args[n-1] != want {
+				t.Errorf("remote command = %q, want %q", runner.args[n-1], want)
+			}
+		})
+	}
+}
+
+func TestDispatcher_Dispatch_Error(t *testing.T) {
+	d, runner := newTestDispatcher(t, &config.Config{})
+	runner.err = errors.New("exec: not found")
+
+	job := &model.Job{ID: 3, Stage: model.StagePublish}
+	err := d.Dispatch(context.Background(), job, "")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !errors.Is(err, runner.err) {
+		t.Errorf("error should wrap runner error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "publish") {
+		t.Errorf("error should name the binary, got %v", err)
+	}
+}
+
+func TestDispatcher_Dispatch_SSHRetry(t *testing.T) {
+	dispatchBackoff = time.Millisecond
+	t.Cleanup(func() { dispatchBackoff = 2 * time.Second })
+
+	job := &model.Job{ID: 7, Stage: model.StageRip}
+
+	t.Run("retries until connected", func(t *testing.T) {
+		d, runner := newTestDispatcher(t, &config.Config{})
+		runner.errs = []error{errors.New("exit status 255")}
+		runner.output = "ssh: connect to host ripper-host port 22: Connection refused\n"
+
+		if err := d.Dispatch(context.Background(), job, "ripper-host"); err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+		if runner.runs != 2 {
+			t.Errorf("ssh ran %d times, want 2", runner.runs)
+		}
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		d, runner := newTestDispatcher(t, &config.Config{})
+		runner.err = errors.New("exit status 255")
+		runner.output = "ssh: connect to host ripper-host port 22: No route to host\n"
+
+		err := d.Dispatch(context.Background(), job, "ripper-host")
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if runner.runs != dispatchAttempts {
+			t.Errorf("ssh ran %d times, want %d", runner.runs, dispatchAttempts)
+		}
+		if !strings.Contains(err.Error(), "ripper-host") || !strings.Contains(err.Error(), "No route to host") {
+			t.Errorf("error should name the host and include ssh output, got %v", err)
+		}
+	})
+
+	t.Run("dropped connection is not retried", func(t *testing.T) {
+		d, runner := newTestDispatcher(t, &config.Config{})
+		runner.err = exitError(t, 255)
+		runner.output = "Connection to ripper-host closed by remote host.\n"
+
+		if err := d.Dispatch(context.Background(), job, "ripper-host"); err == nil {
+			t.Fatal("expected error")
+		}
+		if runner.runs != 1 {
+			t.Errorf("ssh ran %d times, want 1, the job may already be running", runner.runs)
+		}
+	})
+
+	t.Run("missing remote binary is not retried", func(t *testing.T) {
+		d, runner := newTestDispatcher(t, &config.Config{})
+		runner.err = exitError(t, 127)
+		runner.output = "ripper not found in PATH\n"
+
+		err := d.Dispatch(context.Background(), job, "ripper-host")
+		if err == nil || !strings.Contains(err.Error(), "ripper not found in PATH") {
+			t.Errorf("Dispatch() error = %v, want the remote error", err)
+		}
+		if runner.runs != 1 {
+			t.Errorf("ssh ran %d times, want 1", runner.runs)
+		}
+	})
+}
+
+// exitError returns the *exec.ExitError of a process exiting with code
+func exitError(t *testing.T, code int) error {
+	t.Helper()
+	err := exec.Command("sh", "-c", fmt.Sprintf("exit %d", code)).Run()
+	if err == nil {
+		t.Fatalf("exit %d succeeded", code)
+	}
+	return err
+}
+
+func TestDispatcher_CheckTarget(t *testing.T) {
+	t.Run("local is always reachable", func(t *testing.T) {
+		d, runner := newTestDispatcher(t, &config.Config{})
+		runner.err = errors.New("should not run")
+		if err := d.CheckTarget(context.Background(), ""); err != nil {
+			t.Errorf("CheckTarget(\"\") error = %v", err)
+		}
+		if runner.name != "" {
+			t.Errorf("ran %q for local target", runner.name)
+		}
+	})
+
+	t.Run("reachable", func(t *testing.T) {
+		d, runner := newTestDispatcher(t, &config.Config{})
+		if err := d.CheckTarget(context.Background(), "ripper-host"); err != nil {
+			t.Errorf("CheckTarget() error = %v", err)
+		}
+		if runner.name != "ssh" || runner.args[len(runner.args)-2] != "ripper-host" {
+			t.Errorf("ran %s %q", runner.name, runner.args)
+		}
+	})
+
+	t.Run("unreachable", func(t *testing.T) {
+		d, runner := newTestDispatcher(t, &config.Config{})
+		runner.err = errors.New("exit status 255")
+		runner.output = "ssh: connect to host ripper-host port 22: Connection refused\n"
+
+		err := d.CheckTarget(context.Background(), "ripper-host")
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if !strings.Contains(err.Error(), "Connection refused") {
+			t.Errorf("error should include ssh output, got %v", err)
+		}
+	})
+}
+
+func TestBinaryName(t *testing.T) {
+	tests := []struct {
+		stage model.Stage
+		want  string
+	}{
+		{model.StageRip, "ripper"},
+		{model.StageRemux, "remux"},
+		{model.StageTranscode, "transcode"},
+		{model.StagePublish, "publish"},
+	}
+	for _, tt := range tests {
+		if got := BinaryName(tt.stage); got != tt.want {
+			t.Errorf("BinaryName(%v) = %q, want %q", tt.stage, got, tt.want)
+		}
+	}
+}
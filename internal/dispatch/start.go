@@ -0,0 +1,164 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// ErrJobActive is returned when a stage is started while a job for it is
+// already pending or in progress
+var ErrJobActive = errors.New("job already running")
+
+// ErrStageNotReady is returned when a stage is started before the stage
+// ahead of it has completed
+var ErrStageNotReady = errors.New("previous stage not completed")
+
+// FailReason prefixes the error recorded on jobs that never launched
+const FailReason = "dispatch failed: "
+
+// CreateJob creates job unless an active job already exists for the same
+// item and stage (and season, for TV) or the previous stage hasn't completed.
+// If markStage is set, the item or season is moved to the job's stage, in
+// progress. The checks and insert run in one transaction, so the TUI and the
+// API starting the same stage can't both pass.
+func CreateJob(ctx context.Context, repo db.Repository, job *model.Job, markStage bool) error {
+	return CreateJobWithOptions(ctx, repo, job, markStage, nil)
+}
+
+// CreateJobWithOptions is CreateJob that also sets the job's options in the
+// same transaction, so a job is never left pending without them
+func CreateJobWithOptions(ctx context.Context, repo db.Repository, job *model.Job, markStage bool, opts map[string]interface{}) error {
+	return repo.WithTx(ctx, func(tx db.Repository) error {
+		active, err := FindActiveJob(ctx, tx, job)
+		if err != nil {
+			return fmt.Errorf("failed to check for active jobs: %w", err)
+		}
+		if active != nil {
+			return fmt.Errorf("%w: %s job %d is %s", ErrJobActive, active.Stage, active.ID, active.Status)
+		}
+		if ok, reason := tx.CanStartStage(ctx, job.MediaItemID, job.Stage, job.SeasonID); !ok {
+			return fmt.Errorf("%w: %s", ErrStageNotReady, reason)
+		}
+
+		if err := tx.CreateJob(ctx, job); err != nil {
+			return fmt.Errorf("failed to create job: %w", err)
+		}
+		if len(opts) > 0 {
+			if err := tx.SetJobOptions(ctx, job.ID, opts); err != nil {
+				return fmt.Errorf("failed to set job options: %w", err)
+			}
+		}
+		if !markStage {
+			return nil
+		}
+		if job.SeasonID != nil {
+			return tx.UpdateSeasonStage(ctx, *job.SeasonID, job.Stage, model.StatusInProgress)
+		}
+		return tx.UpdateMediaItemStage(ctx, job.MediaItemID, job.Stage, model.StatusInProgress)
+	})
+}
+
+// FindActiveJob returns the pending or in-progress job that job would
+// duplicate, or nil. Season jobs match on season regardless of disc, since a
+// season's discs are ripped one at a time.
+func FindActiveJob(ctx context.Context, repo db.Repository, job *model.Job) (*model.Job, error) {
+	if job.SeasonID == nil {
+		return repo.GetActiveJobForStage(ctx, job.MediaItemID, job.Stage, job.Disc)
+	}
+
+	jobs, err := repo.ListJobsForMedia(ctx, job.MediaItemID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range jobs {
+		j := &jobs[i]
+		if j.Stage == job.Stage && j.IsActive() && j.SeasonID != nil && *j.SeasonID == *job.SeasonID {
+			return j, nil
+		}
+	}
+	return nil, nil
+}
+
+// Launch dispatches a created job to target. A job that can't be launched is
+// marked failed, so it doesn't sit pending with nothing running it. Once the
+// job exists, launching it and recording the outcome aren't cut short by ctx
+// being cancelled, e.g. by an HTTP client going away.
+func (d *Dispatcher) Launch(ctx context.Context, repo db.Repository, job *model.Job, target string) error {
+	ctx = context.WithoutCancel(ctx)
+	err := d.Dispatch(ctx, job, target)
+	if err == nil {
+		return nil
+	}
+	if failErr := FinishJob(ctx, repo, *job, model.JobStatusFailed, FailReason+err.Error()); failErr != nil {
+		return errors.Join(err, fmt.Errorf("failed to mark job %d failed: %w", job.ID, failErr))
+	}
+	return err
+}
+
+// FinishJob forces job to status and moves its movie or season to match, so
+// the stage can be retried or the next one started
+func FinishJob(ctx context.Context, repo db.Repository, job model.Job, status model.JobStatus, reason string) error {
+	return repo.WithTx(ctx, func(tx db.Repository) error {
+		var err error
+		if status == model.JobStatusCompleted {
+			err = tx.ForceCompleteJob(ctx, job.ID)
+		} else {
+			err = tx.ForceFailJob(ctx, job.ID, reason)
+		}
+		if err != nil {
+			return err
+		}
+		return SetStageStatus(ctx, tx, &job, status.StageStatus())
+	})
+}
+
+// SetStageStatus moves the job's movie or season to status at the job's
+// stage. A season's rip spans several disc jobs and is finished by hand, so
+// season rips are left alone.
+func SetStageStatus(ctx context.Context, repo db.Repository, job *model.Job, status model.Status) error {
+	if job.SeasonID == nil {
+		return repo.UpdateMediaItemStage(ctx, job.MediaItemID, job.Stage, status)
+	}
+	if job.Stage == model.StageRip {
+		return nil
+	}
+	return repo.UpdateSeasonStage(ctx, *job.SeasonID, job.Stage, status)
+}
+
+// NextRipDisc returns the disc number for the next rip of a season, or of a
+// movie when seasonID is nil, after the discs among jobs. A movie's first
+// disc has no number.
+func NextRipDisc(jobs []model.Job, seasonID *int64) *int {
+	disc := 1
+	ripped := false
+	for _, j := range jobs {
+		if j.Stage != model.StageRip {
+			continue
+		}
+		if seasonID == nil {
+			if j.SeasonID != nil {
+				continue
+			}
+		} else if j.SeasonID == nil || *j.SeasonID != *seasonID {
+			continue
+		}
+		if j.Status == model.JobStatusCompleted {
+			ripped = true
+		}
+		if j.Disc != nil && *j.Disc >= disc {
+			disc = *j.Disc + 1
+		}
+	}
+	if seasonID == nil {
+		if !ripped {
+			return nil
+		}
+		// A movie's unnumbered first disc counts as disc 1
+		disc = max(disc, 2)
+	}
+	return &disc
+}
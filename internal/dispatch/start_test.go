@@ -0,0 +1,209 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestCreateJob(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception"}
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Show", SafeName: "Show"}
+	for _, item := range []*model.MediaItem{movie, show} {
+		if err := repo.CreateMediaItem(ctx, item); err != nil {
+			t.Fatalf("CreateMediaItem() error = %v", err)
+		}
+	}
+	s1 := &model.Season{ItemID: show.ID, Number: 1, StageStatus: model.StatusPending}
+	s2 := &model.Season{ItemID: show.ID, Number: 2, StageStatus: model.StatusPending}
+	for _, season := range []*model.Season{s1, s2} {
+		if err := repo.CreateSeason(ctx, season); err != nil {
+			t.Fatalf("CreateSeason() error = %v", err)
+		}
+	}
+
+	t.Run("movie stage", func(t *testing.T) {
+		early := &model.Job{MediaItemID: movie.ID, Stage: model.StageRemux, Status: model.JobStatusPending}
+		if err := CreateJob(ctx, repo, early, false); !errors.Is(err, ErrStageNotReady) || !strings.Contains(err.Error(), "no completed organize job") {
+			t.Fatalf("CreateJob() before organize error = %v, want missing organize", err)
+		}
+
+		organized := &model.Job{MediaItemID: movie.ID, Stage: model.StageOrganize, Status: model.JobStatusCompleted}
+		if err := repo.CreateJob(ctx, organized); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+
+		first := &model.Job{MediaItemID: movie.ID, Stage: model.StageRemux, Status: model.JobStatusPending}
+		if err := CreateJob(ctx, repo, first, false); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+		dup := &model.Job{MediaItemID: movie.ID, Stage: model.StageRemux, Status: model.JobStatusPending}
+		if err := CreateJob(ctx, repo, dup, false); !errors.Is(err, ErrJobActive) {
+			t.Fatalf("CreateJob() error = %v, want ErrJobActive", err)
+		}
+
+		// Once the first job finishes the stage can be started again
+		if err := repo.UpdateJobStatus(ctx, first.ID, model.JobStatusFailed, "boom"); err != nil {
+			t.Fatalf("UpdateJobStatus() error = %v", err)
+		}
+		if err := CreateJob(ctx, repo, dup, false); err != nil {
+			t.Errorf("CreateJob() after failure error = %v", err)
+		}
+	})
+
+	t.Run("season rip ignores disc", func(t *testing.T) {
+		disc1, disc2 := 1, 2
+		first := &model.Job{MediaItemID: show.ID, SeasonID: &s1.ID, Stage: model.StageRip, Status: model.JobStatusInProgress, Disc: &disc1}
+		if err := CreateJob(ctx, repo, first, false); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+		next := &model.Job{MediaItemID: show.ID, SeasonID: &s1.ID, Stage: model.StageRip, Status: model.JobStatusPending, Disc: &disc2}
+		if err := CreateJob(ctx, repo, next, false); !errors.Is(err, ErrJobActive) {
+			t.Fatalf("CreateJob() error = %v, want ErrJobActive", err)
+		}
+
+		// Another season of the same show is independent
+		other := &model.Job{MediaItemID: show.ID, SeasonID: &s2.ID, Stage: model.StageRip, Status: model.JobStatusPending, Disc: &disc1}
+		if err := CreateJob(ctx, repo, other, false); err != nil {
+			t.Errorf("CreateJob() for other season error = %v", err)
+		}
+	})
+}
+
+func TestLaunch_FailureMarksJobFailed(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+	d, runner := newTestDispatcher(t, &config.Config{})
+	runner.err = errors.New("exec: ripper: not found")
+
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception"}
+	if err := repo.CreateMediaItem(ctx, movie); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	job := &model.Job{MediaItemID: movie.ID, Stage: model.StageRip, Status: model.JobStatusPending}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	if err := d.Launch(ctx, repo, job, ""); err == nil {
+		t.Fatal("Launch() should return the dispatch error")
+	}
+
+	got, err := repo.GetJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if got.Status != model.JobStatusFailed || !strings.HasPrefix(got.ErrorMessage, FailReason) {
+		t.Errorf("job = %s %q, want failed with the dispatch error", got.Status, got.ErrorMessage)
+	}
+}
+
+func TestCreateJob_MarkStage(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception"}
+	if err := repo.CreateMediaItem(ctx, movie); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+
+	job := &model.Job{MediaItemID: movie.ID, Stage: model.StageRip, Status: model.JobStatusPending}
+	if err := CreateJob(ctx, repo, job, true); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	got, err := repo.GetMediaItem(ctx, movie.ID)
+	if err != nil {
+		t.Fatalf("GetMediaItem() error = %v", err)
+	}
+	if got.CurrentStage != model.StageRip || got.StageStatus != model.StatusInProgress {
+		t.Errorf("item stage = %s %s, want rip in_progress", got.CurrentStage, got.StageStatus)
+	}
+}
+
+func TestCreateJobWithOptions(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception"}
+	if err := repo.CreateMediaItem(ctx, movie); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+
+	job := &model.Job{MediaItemID: movie.ID, Stage: model.StageRip, Status: model.JobStatusPending}
+	opts := map[string]interface{}{"profile": "archive"}
+	if err := CreateJobWithOptions(ctx, repo, job, true, opts); err != nil {
+		t.Fatalf("CreateJobWithOptions() error = %v", err)
+	}
+
+	got, err := repo.GetJobOptions(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetJobOptions() error = %v", err)
+	}
+	if got["profile"] != "archive" {
+		t.Errorf("job options = %v, want profile archive", got)
+	}
+}
+
+func TestNextRipDisc(t *testing.T) {
+	seasonID := int64(3)
+	disc := func(n int) *int { return &n }
+	rip := func(status model.JobStatus, season *int64, n *int) model.Job {
+		return model.Job{Stage: model.StageRip, Status: status, SeasonID: season, Disc: n}
+	}
+
+	tests := []struct {
+		name     string
+		jobs     []model.Job
+		seasonID *int64
+		want     *int
+	}{
+		{"first season disc", nil, &seasonID, disc(1)},
+		{"after season discs", []model.Job{rip(model.JobStatusCompleted, &seasonID, disc(1)), rip(model.JobStatusFailed, &seasonID, disc(2))}, &seasonID, disc(3)},
+		{"first movie disc", nil, nil, nil},
+		{"movie retried after a failure", []model.Job{rip(model.JobStatusFailed, nil, nil)}, nil, nil},
+		{"second movie disc", []model.Job{rip(model.JobStatusCompleted, nil, nil)}, nil, disc(2)},
+		{"third movie disc", []model.Job{rip(model.JobStatusCompleted, nil, nil), rip(model.JobStatusCompleted, nil, disc(2))}, nil, disc(3)},
+		{"movie ignores season discs", []model.Job{rip(model.JobStatusCompleted, &seasonID, disc(4))}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NextRipDisc(tt.jobs, tt.seasonID)
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Errorf("NextRipDisc() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// InputDir validates a directory given on the command line and returns its
+// absolute path, so it can be recorded on the job
+func InputDir(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid input directory %s: %w", path, err)
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", fmt.Errorf("input directory %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("input directory %s is not a directory", path)
+	}
+	return abs, nil
+}
+
+// StageOutputDir checks that the output directory a previous stage's job
+// recorded is still there, so a stage whose input was deleted or moved
+// fails before it starts rather than partway through
+func StageOutputDir(stage, path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("input directory from %s job no longer exists: %s", stage, path)
+	}
+	if err != nil {
+		return fmt.Errorf("input directory from %s job: %w", stage, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("input directory from %s job is not a directory: %s", stage, path)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInputDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "movie.mkv")
+	os.WriteFile(file, []byte("data"), 0644)
+
+	got, err := InputDir(dir + "/./")
+	if err != nil {
+		t.Fatalf("InputDir() error = %v", err)
+	}
+	if got != dir {
+		t.Errorf("InputDir() = %q, want %q", got, dir)
+	}
+
+	if _, err := InputDir(filepath.Join(dir, "missing")); err == nil {
+		t.Error("InputDir(missing) expected error")
+	}
+	if _, err := InputDir(file); err == nil {
+		t.Error("InputDir(file) expected error")
+	}
+}
+
+func TestStageOutputDir(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "movie.mkv")
+	os.WriteFile(file, []byte("data"), 0644)
+
+	if err := StageOutputDir("remux", dir); err != nil {
+		t.Errorf("StageOutputDir() error = %v", err)
+	}
+
+	missing := filepath.Join(dir, "missing")
+	want := "input directory from remux job no longer exists: " + missing
+	if err := StageOutputDir("remux", missing); err == nil || err.Error() != want {
+		t.Errorf("StageOutputDir(missing) error = %v, want %q", err, want)
+	}
+	if err := StageOutputDir("remux", file); err == nil {
+		t.Error("StageOutputDir(file) expected error")
+	}
+}
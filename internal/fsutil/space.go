@@ -0,0 +1,97 @@
+// Package fsutil provides filesystem helpers shared by the stage binaries.
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// ErrInsufficientSpace is returned when a filesystem lacks room for a stage's output
+var ErrInsufficientSpace = errors.New("insufficient space")
+
+// FreeSpace returns the bytes available to unprivileged users on the filesystem
+// holding path. If path does not exist yet, its nearest existing parent is used.
+func FreeSpace(path string) (int64, error) {
+	dir, err := existingAncestor(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, fmt.Errorf("failed to stat filesystem for %s: %w", dir, err)
+	}
+	return int64(stat.Bavail) * int64(stat.Bsize), nil
+}
+
+// CheckFreeSpace returns an error wrapping ErrInsufficientSpace if the filesystem
+// holding path has less than needed bytes available
+func CheckFreeSpace(path string, needed int64) error {
+	available, err := FreeSpace(path)
+	if err != nil {
+		return err
+	}
+	if available < needed {
+		return fmt.Errorf("%w on %s: need %s, have %s",
+			ErrInsufficientSpace, path, FormatBytes(needed), FormatBytes(available))
+	}
+	return nil
+}
+
+// DirSize returns the total size of regular files under path
+func DirSize(path string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure %s: %w", path, err)
+	}
+	return total, nil
+}
+
+// FormatBytes formats a byte count for humans, e.g. "4.2 GB"
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// existingAncestor walks up from path to the first directory that exists
+func existingAncestor(path string) (string, error) {
+	dir := filepath.Clean(path)
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir, nil
+		} else if !os.IsNotExist(err) {
+			return "", fmt.Errorf("failed to stat %s: %w", dir, err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no existing parent directory for %s", path)
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,82 @@
+package fsutil
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFreeSpace_MissingPathUsesParent(t *testing.T) {
+	dir := t.TempDir()
+
+	want, err := FreeSpace(dir)
+	if err != nil {
+		t.Fatalf("FreeSpace() error = %v", err)
+	}
+	if want <= 0 {
+		t.Fatalf("FreeSpace() = %d, want > 0", want)
+	}
+
+	got, err := FreeSpace(filepath.Join(dir, "not", "created", "yet"))
+	if err != nil {
+		t.Fatalf("FreeSpace(missing) error = %v", err)
+	}
+	if got <= 0 {
+		t.Errorf("FreeSpace(missing) = %d, want > 0", got)
+	}
+}
+
+func TestCheckFreeSpace(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := CheckFreeSpace(dir, 1); err != nil {
+		t.Errorf("CheckFreeSpace(1 byte) error = %v", err)
+	}
+
+	err := CheckFreeSpace(dir, math.MaxInt64)
+	if !errors.Is(err, ErrInsufficientSpace) {
+		t.Fatalf("CheckFreeSpace(huge) error = %v, want ErrInsufficientSpace", err)
+	}
+	if !strings.Contains(err.Error(), "need ") || !strings.Contains(err.Error(), "have ") {
+		t.Errorf("error should state need and have, got %q", err)
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "_main"), 0755)
+	os.WriteFile(filepath.Join(dir, "_main", "a.mkv"), make([]byte, 1000), 0644)
+	os.WriteFile(filepath.Join(dir, "b.mkv"), make([]byte, 234), 0644)
+
+	size, err := DirSize(dir)
+	if err != nil {
+		t.Fatalf("DirSize() error = %v", err)
+	}
+	if size != 1234 {
+		t.Errorf("DirSize() = %d, want 1234", size)
+	}
+
+	if _, err := DirSize(filepath.Join(dir, "missing")); err == nil {
+		t.Error("DirSize(missing) expected error")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{512, "512 B"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{42 * 1024 * 1024 * 1024 / 10, "4.2 GB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.n); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package logging
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
 	"time"
 )
@@ -36,6 +38,49 @@ func (l Level) String() string {
 	}
 }
 
+// ParseLevel converts a -log-level value to the minimum level logged,
+// treating "" as info. Only error, info and debug are accepted.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "", "info":
+		return LevelInfo, nil
+	case "debug":
+		return LevelDebug, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q (valid: error, info, debug)", s)
+	}
+}
+
+// Format selects how log entries are written
+type Format string
+
+const (
+	FormatText Format = "text" // "2006-01-02 15:04:05 [INFO] msg"
+	FormatJSON Format = "json" // One JSON object per line
+)
+
+// ParseFormat validates a format name, treating "" as text
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unknown log format %q (valid: text, json)", s)
+	}
+}
+
+// jsonEntry is a single JSON log line
+type jsonEntry struct {
+	TS    string `json:"ts"`
+	Level string `json:"level"`
+	JobID int64  `json:"job_id"`
+	Msg   string `json:"msg"`
+}
+
 // Logger provides multi-destination logging with level filtering
 type Logger struct {
 	mu         sync.Mutex
@@ -43,6 +88,8 @@ type Logger struct {
 	file       io.Writer
 	fileCloser io.Closer
 	minLevel   Level
+	fileFormat Format
+	jobID      int64
 
 	// For DB event logging
 	eventFn func(level, msg string)
@@ -50,11 +97,22 @@ type Logger struct {
 
 // Options configures a Logger instance
 type Options struct {
-	Stdout     io.Writer                // nil = no stdout
-	File       io.Writer                // nil = no file
-	FileCloser io.Closer                // Optional closer for File (for cleanup)
-	MinLevel   Level                    // Minimum level to log
-	EventFn    func(level, msg string)  // Called for significant events
+	Stdout     io.Writer               // nil = no stdout
+	File       io.Writer               // nil = no file
+	FileCloser io.Closer               // Optional closer for File (for cleanup)
+	MinLevel   Level                   // Minimum level to log
+	EventFn    func(level, msg string) // Called for significant events
+	FileFormat Format                  // Format for File ("" = text)
+	JobID      int64                   // Included in JSON entries
+}
+
+// JobOptions configures the log file written by NewForJob
+type JobOptions struct {
+	JobID      int64  // Included in JSON entries
+	Format     Format // File format; the console mirror is always text
+	Level      string // Minimum level: "error", "info" or "debug" ("" = info)
+	MaxBytes   int64  // Rotate the file once it reaches this size (0 = never)
+	MaxBackups int    // Rotated files to keep as job.log.1 .. job.log.N
 }
 
 // New creates a new Logger with the given options
@@ -65,11 +123,18 @@ func New(opts Options) *Logger {
 		fileCloser: opts.FileCloser,
 		minLevel:   opts.MinLevel,
 		eventFn:    opts.EventFn,
+		fileFormat: opts.FileFormat,
+		jobID:      opts.JobID,
 	}
 }
 
 // NewForJob creates a logger configured for a job execution
-func NewForJob(logPath string, stdout bool, eventFn func(level, msg string)) (*Logger, error) {
+func NewForJob(logPath string, stdout bool, eventFn func(level, msg string), jobOpts JobOptions) (*Logger, error) {
+	minLevel, err := ParseLevel(jobOpts.Level)
+	if err != nil {
+		return nil, err
+	}
+
 	var stdoutWriter io.Writer
 	if stdout {
 		stdoutWriter = os.Stdout
@@ -78,9 +143,9 @@ func NewForJob(logPath string, stdout bool, eventFn func(level, msg string)) (*L
 	var fileWriter io.Writer
 	var fileCloser io.Closer
 	if logPath != "" {
-		f, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		f, err := openRotatingFile(logPath, jobOpts.MaxBytes, jobOpts.MaxBackups)
 		if err != nil {
-			return nil, fmt.Errorf("failed to open log file: %w", err)
+			return nil, err
 		}
 		fileWriter = f
 		fileCloser = f
@@ -90,8 +155,10 @@ func NewForJob(logPath string, stdout bool, eventFn func(level, msg string)) (*L
 		Stdout:     stdoutWriter,
 		File:       fileWriter,
 		FileCloser: fileCloser,
-		MinLevel:   LevelInfo,
+		MinLevel:   minLevel,
 		EventFn:    eventFn,
+		FileFormat: jobOpts.Format,
+		JobID:      jobOpts.JobID,
 	}), nil
 }
 
@@ -101,24 +168,44 @@ func (l *Logger) log(level Level, msg string, args ...any) {
 		return
 	}
 
-	formatted := fmt.Sprintf(msg, args...)
-	line := fmt.Sprintf("%s [%s] %s\n",
-		time.Now().Format("2006-01-02 15:04:05"),
-		level.String(),
-		formatted,
-	)
+	l.write(level, fmt.Sprintf(msg, args...))
+}
+
+// write emits one entry to each destination in that destination's format
+func (l *Logger) write(level Level, msg string) {
+	now := time.Now()
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
 	if l.stdout != nil {
-		l.stdout.Write([]byte(line))
+		l.stdout.Write(l.formatEntry(FormatText, now, level, msg))
 	}
 	if l.file != nil {
-		l.file.Write([]byte(line))
+		l.file.Write(l.formatEntry(l.fileFormat, now, level, msg))
 	}
 }
 
+// formatEntry renders a single log line, including the trailing newline
+func (l *Logger) formatEntry(format Format, now time.Time, level Level, msg string) []byte {
+	if format == FormatJSON {
+		line, err := json.Marshal(jsonEntry{
+			TS:    now.UTC().Format(time.RFC3339Nano),
+			Level: strings.ToLower(level.String()),
+			JobID: l.jobID,
+			Msg:   msg,
+		})
+		if err == nil {
+			return append(line, '\n')
+		}
+	}
+	return []byte(fmt.Sprintf("%s [%s] %s\n",
+		now.Format("2006-01-02 15:04:05"),
+		level.String(),
+		msg,
+	))
+}
+
 // Debug logs a debug message
 func (l *Logger) Debug(msg string, args ...any) {
 	l.log(LevelDebug, msg, args...)
@@ -146,21 +233,8 @@ func (l *Logger) Error(msg string, args ...any) {
 
 // Event logs a significant event to file AND DB (if configured)
 func (l *Logger) Event(level Level, msg string) {
-	// Format the message directly since Event doesn't take variadic args
-	line := fmt.Sprintf("%s [%s] %s\n",
-		time.Now().Format("2006-01-02 15:04:05"),
-		level.String(),
-		msg,
-	)
-
-	l.mu.Lock()
-	if l.stdout != nil {
-		l.stdout.Write([]byte(line))
-	}
-	if l.file != nil {
-		l.file.Write([]byte(line))
-	}
-	l.mu.Unlock()
+	// Write the message directly since Event doesn't take variadic args
+	l.write(level, msg)
 
 	if l.eventFn != nil {
 		l.eventFn(level.String(), msg)
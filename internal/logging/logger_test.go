@@ -2,10 +2,12 @@ package logging
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestLogger_WritesToMultipleDestinations(t *testing.T) {
@@ -240,7 +242,7 @@ func TestLogger_Close(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, "test.log")
 
-	logger, err := NewForJob(logPath, false, nil)
+	logger, err := NewForJob(logPath, false, nil, JobOptions{})
 	if err != nil {
 		t.Fatalf("NewForJob failed: %v", err)
 	}
@@ -279,7 +281,7 @@ func TestNewForJob_WithStdout(t *testing.T) {
 	tmpDir := t.TempDir()
 	logPath := filepath.Join(tmpDir, "test.log")
 
-	logger, err := NewForJob(logPath, true, nil)
+	logger, err := NewForJob(logPath, true, nil, JobOptions{})
 	if err != nil {
 		t.Fatalf("NewForJob failed: %v", err)
 	}
@@ -292,7 +294,7 @@ func TestNewForJob_WithStdout(t *testing.T) {
 }
 
 func TestNewForJob_WithoutFile(t *testing.T) {
-	logger, err := NewForJob("", true, nil)
+	logger, err := NewForJob("", true, nil, JobOptions{})
 	if err != nil {
 		t.Fatalf("NewForJob failed: %v", err)
 	}
@@ -306,18 +308,63 @@ func TestNewForJob_WithoutFile(t *testing.T) {
 
 func TestNewForJob_InvalidPath(t *testing.T) {
 	// Try to create a log file in a non-existent directory
-	_, err := NewForJob("/nonexistent/dir/test.log", false, nil)
+	_, err := NewForJob("/nonexistent/dir/test.log", false, nil, JobOptions{})
 	if err == nil {
 		t.Error("expected error for invalid path")
 	}
 }
 
+func TestNewForJob_Level(t *testing.T) {
+	tests := []struct {
+		level     string
+		wantDebug bool
+		wantInfo  bool
+	}{
+		{"", false, true},
+		{"debug", true, true},
+		{"error", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), "job.log")
+			logger, err := NewForJob(logPath, false, nil, JobOptions{Level: tt.level})
+			if err != nil {
+				t.Fatalf("NewForJob failed: %v", err)
+			}
+			logger.Debug("debug line")
+			logger.Info("info line")
+			logger.Error("error line")
+			logger.Close()
+
+			data, err := os.ReadFile(logPath)
+			if err != nil {
+				t.Fatalf("ReadFile: %v", err)
+			}
+			out := string(data)
+			if got := strings.Contains(out, "debug line"); got != tt.wantDebug {
+				t.Errorf("debug logged = %v, want %v", got, tt.wantDebug)
+			}
+			if got := strings.Contains(out, "info line"); got != tt.wantInfo {
+				t.Errorf("info logged = %v, want %v", got, tt.wantInfo)
+			}
+			if !strings.Contains(out, "error line") {
+				t.Error("errors should always be logged")
+			}
+		})
+	}
+
+	if _, err := NewForJob("", false, nil, JobOptions{Level: "loud"}); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
+
 func TestNewForJob_WithEventFn(t *testing.T) {
 	var eventCalls []string
 
 	logger, err := NewForJob("", true, func(level, msg string) {
 		eventCalls = append(eventCalls, level+":"+msg)
-	})
+	}, JobOptions{})
 	if err != nil {
 		t.Fatalf("NewForJob failed: %v", err)
 	}
@@ -329,3 +376,94 @@ func TestNewForJob_WithEventFn(t *testing.T) {
 		t.Errorf("expected 1 event call, got %d", len(eventCalls))
 	}
 }
+
+func TestLogger_JSONFormat(t *testing.T) {
+	var stdout, file bytes.Buffer
+
+	logger := New(Options{
+		Stdout:     &stdout,
+		File:       &file,
+		MinLevel:   LevelInfo,
+		FileFormat: FormatJSON,
+		JobID:      42,
+	})
+
+	logger.Info("copied %d files", 3)
+	logger.Error("disk \"full\"\nretrying")
+	logger.Event(LevelWarn, "low space")
+
+	lines := strings.Split(strings.TrimSuffix(file.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d JSON lines, want 3:\n%s", len(lines), file.String())
+	}
+
+	want := []struct {
+		level string
+		msg   string
+	}{
+		{"info", "copied 3 files"},
+		{"error", "disk \"full\"\nretrying"},
+		{"warn", "low space"},
+	}
+	for i, line := range lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("line %d is not valid JSON: %v\n%s", i, err, line)
+		}
+		if entry["level"] != want[i].level || entry["msg"] != want[i].msg {
+			t.Errorf("line %d = %v, want level=%q msg=%q", i, entry, want[i].level, want[i].msg)
+		}
+		if entry["job_id"] != float64(42) {
+			t.Errorf("line %d job_id = %v, want 42", i, entry["job_id"])
+		}
+		if _, err := time.Parse(time.RFC3339Nano, entry["ts"].(string)); err != nil {
+			t.Errorf("line %d ts = %v: %v", i, entry["ts"], err)
+		}
+	}
+
+	// The console mirror stays human-readable
+	if !strings.Contains(stdout.String(), "[INFO] copied 3 files") {
+		t.Errorf("stdout should stay text, got %q", stdout.String())
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Format
+		wantErr bool
+	}{
+		{"", FormatText, false},
+		{"text", FormatText, false},
+		{"json", FormatJSON, false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Level
+		wantErr bool
+	}{
+		{"", LevelInfo, false},
+		{"info", LevelInfo, false},
+		{"DEBUG", LevelDebug, false},
+		{"error", LevelError, false},
+		{"warn", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
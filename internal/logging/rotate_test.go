@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewForJob_RotatesAtMaxBytes(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "job.log")
+
+	logger, err := NewForJob(logPath, false, nil, JobOptions{MaxBytes: 200, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("NewForJob failed: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		logger.Info("progress line %02d padded to a reasonable length", i)
+	}
+	logger.Close()
+
+	for _, name := range []string{"job.log", "job.log.1", "job.log.2"} {
+		info, err := os.Stat(filepath.Join(filepath.Dir(logPath), name))
+		if err != nil {
+			t.Fatalf("expected %s: %v", name, err)
+		}
+		if info.Size() > 200 {
+			t.Errorf("%s is %d bytes, want <= 200", name, info.Size())
+		}
+	}
+	if _, err := os.Stat(logPath + ".3"); !os.IsNotExist(err) {
+		t.Errorf("job.log.3 should not exist with MaxBackups=2, stat err = %v", err)
+	}
+
+	// The newest line is in the live file
+	data, _ := os.ReadFile(logPath)
+	if !strings.Contains(string(data), "progress line 19") {
+		t.Errorf("job.log should contain the last line, got:\n%s", data)
+	}
+}
+
+func TestNewForJob_NoBackupsTruncates(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "job.log")
+
+	logger, err := NewForJob(logPath, false, nil, JobOptions{MaxBytes: 100})
+	if err != nil {
+		t.Fatalf("NewForJob failed: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		logger.Info("line %d with some padding text", i)
+	}
+	logger.Close()
+
+	if _, err := os.Stat(logPath + ".1"); !os.IsNotExist(err) {
+		t.Errorf("no backup expected with MaxBackups=0, stat err = %v", err)
+	}
+	info, err := os.Stat(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > 100 {
+		t.Errorf("job.log is %d bytes, want <= 100", info.Size())
+	}
+}
+
+func TestNewForJob_UnlimitedByDefault(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "job.log")
+
+	logger, err := NewForJob(logPath, false, nil, JobOptions{})
+	if err != nil {
+		t.Fatalf("NewForJob failed: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		logger.Info("line %d", i)
+	}
+	logger.Close()
+
+	if _, err := os.Stat(logPath + ".1"); !os.IsNotExist(err) {
+		t.Errorf("no rotation expected without MaxBytes, stat err = %v", err)
+	}
+}
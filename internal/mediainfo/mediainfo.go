@@ -0,0 +1,242 @@
+// Package mediainfo reads the container and stream layout of media files
+// with ffprobe.
+package mediainfo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Stream types as reported by ffprobe's codec_type
+const (
+	StreamVideo    = "video"
+	StreamAudio    = "audio"
+	StreamSubtitle = "subtitle"
+)
+
+// MediaInfo describes a media file's container and streams
+type MediaInfo struct {
+	Format   Format
+	Streams  []Stream
+	Chapters []Chapter
+}
+
+// Format describes the container
+type Format struct {
+	Name     string  // e.g. "matroska,webm"
+	Duration float64 // Seconds, 0 if unknown
+	Size     int64   // Bytes
+	BitRate  int64   // Bits per second
+}
+
+// Stream describes a single stream in the file
+type Stream struct {
+	Index         int
+	Type          string // StreamVideo, StreamAudio, StreamSubtitle or another ffprobe codec_type
+	Codec         string
+	Language      string // "und" when the stream has no language tag
+	Title         string
+	Channels      int    // Audio only
+	ChannelLayout string // Audio only, e.g. "5.1(side)"
+	Width         int    // Video only
+	Height        int    // Video only
+	Default       bool
+	Forced        bool
+}
+
+// Chapter is a chapter marker
+type Chapter struct {
+	Start float64 // Seconds
+	End   float64 // Seconds
+	Title string
+}
+
+// Video returns the video streams in file order
+func (m *MediaInfo) Video() []Stream {
+	return m.streamsOfType(StreamVideo)
+}
+
+// Audio returns the audio streams in file order
+func (m *MediaInfo) Audio() []Stream {
+	return m.streamsOfType(StreamAudio)
+}
+
+// Subtitles returns the subtitle streams in file order
+func (m *MediaInfo) Subtitles() []Stream {
+	return m.streamsOfType(StreamSubtitle)
+}
+
+func (m *MediaInfo) streamsOfType(kind string) []Stream {
+	var streams []Stream
+	for _, s := range m.Streams {
+		if s.Type == kind {
+			streams = append(streams, s)
+		}
+	}
+	return streams
+}
+
+// ffprobeJSON represents the JSON output from ffprobe -show_streams -show_format -show_chapters
+type ffprobeJSON struct {
+	Streams []struct {
+		Index         int    `json:"index"`
+		CodecType     string `json:"codec_type"`
+		CodecName     string `json:"codec_name"`
+		Channels      int    `json:"channels"`
+		ChannelLayout string `json:"channel_layout"`
+		Width         int    `json:"width"`
+		Height        int    `json:"height"`
+		Disposition   struct {
+			Default int `json:"default"`
+			Forced  int `json:"forced"`
+		} `json:"disposition"`
+		Tags struct {
+			Language string `json:"language"`
+			Title    string `json:"title"`
+		} `json:"tags"`
+	} `json:"streams"`
+	Chapters []struct {
+		StartTime string `json:"start_time"`
+		EndTime   string `json:"end_time"`
+		Tags      struct {
+			Title string `json:"title"`
+		} `json:"tags"`
+	} `json:"chapters"`
+	Format struct {
+		FormatName string `json:"format_name"`
+		Duration   string `json:"duration"`
+		Size       string `json:"size"`
+		BitRate    string `json:"bit_rate"`
+	} `json:"format"`
+}
+
+// Probe runs ffprobe from PATH on path
+func Probe(path string) (*MediaInfo, error) {
+	return ProbeWith("", path)
+}
+
+// ProbeWith runs the given ffprobe binary on path.
+// If ffprobePath is empty, uses "ffprobe" from PATH.
+func ProbeWith(ffprobePath, path string) (*MediaInfo, error) {
+	if ffprobePath == "" {
+		ffprobePath = "ffprobe"
+	}
+	cmd := exec.Command(ffprobePath,
+		"-v", "error",
+		"-print_format", "json",
+		"-show_streams",
+		"-show_format",
+		"-show_chapters",
+		path,
+	)
+
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("ffprobe failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("ffprobe failed: %w", err)
+	}
+
+	return Parse(output)
+}
+
+// Parse parses ffprobe JSON output into a MediaInfo. Fields ffprobe leaves
+// out or reports as "N/A" are left zero.
+func Parse(jsonData []byte) (*MediaInfo, error) {
+	var data ffprobeJSON
+	if err := json.Unmarshal(jsonData, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse ffprobe output: %w", err)
+	}
+
+	info := &MediaInfo{
+		Format: Format{
+			Name:     data.Format.FormatName,
+			Duration: parseFloat(data.Format.Duration),
+			Size:     parseInt(data.Format.Size),
+			BitRate:  parseInt(data.Format.BitRate),
+		},
+	}
+
+	for _, s := range data.Streams {
+		lang := s.Tags.Language
+		if lang == "" {
+			lang = "und"
+		}
+		info.Streams = append(info.Streams, Stream{
+			Index:         s.Index,
+			Type:          s.CodecType,
+			Codec:         s.CodecName,
+			Language:      lang,
+			Title:         s.Tags.Title,
+			Channels:      s.Channels,
+			ChannelLayout: s.ChannelLayout,
+			Width:         s.Width,
+			Height:        s.Height,
+			Default:       s.Disposition.Default == 1,
+			Forced:        s.Disposition.Forced == 1,
+		})
+	}
+	for _, c := range data.Chapters {
+		info.Chapters = append(info.Chapters, Chapter{
+			Start: parseFloat(c.StartTime),
+			End:   parseFloat(c.EndTime),
+			Title: c.Tags.Title,
+		})
+	}
+
+	return info, nil
+}
+
+func parseFloat(s string) float64 {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+func parseInt(s string) int64 {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// String describes the stream on one line, e.g.
+// `#1 audio truehd eng 8ch (7.1) "Surround" [default]`
+func (s Stream) String() string {
+	parts := []string{fmt.Sprintf("#%d", s.Index), s.Type, s.Codec}
+	switch s.Type {
+	case StreamVideo:
+		if s.Width > 0 && s.Height > 0 {
+			parts = append(parts, fmt.Sprintf("%dx%d", s.Width, s.Height))
+		}
+	case StreamAudio:
+		parts = append(parts, s.Language)
+		if s.Channels > 0 {
+			parts = append(parts, fmt.Sprintf("%dch", s.Channels))
+		}
+		if s.ChannelLayout != "" {
+			parts = append(parts, "("+s.ChannelLayout+")")
+		}
+	case StreamSubtitle:
+		parts = append(parts, s.Language)
+	}
+	if s.Title != "" {
+		parts = append(parts, strconv.Quote(s.Title))
+	}
+	if s.Default {
+		parts = append(parts, "[default]")
+	}
+	if s.Forced {
+		parts = append(parts, "[forced]")
+	}
+	return strings.Join(parts, " ")
+}
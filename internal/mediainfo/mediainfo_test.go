@@ -0,0 +1,115 @@
+package mediainfo
+
+import (
+	"os/exec"
+	"testing"
+)
+
+const sampleJSON = `{
+	"streams": [
+		{"index": 0, "codec_type": "video", "codec_name": "hevc", "width": 1920, "height": 1080,
+		 "disposition": {"default": 1, "forced": 0}},
+		{"index": 1, "codec_type": "audio", "codec_name": "truehd", "channels": 8, "channel_layout": "7.1",
+		 "disposition": {"default": 1, "forced": 0}, "tags": {"language": "eng", "title": "Surround 7.1"}},
+		{"index": 2, "codec_type": "audio", "codec_name": "ac3", "channels": 6},
+		{"index": 3, "codec_type": "subtitle", "codec_name": "hdmv_pgs_subtitle",
+		 "disposition": {"default": 0, "forced": 1}, "tags": {"language": "bul"}},
+		{"index": 4, "codec_type": "attachment", "codec_name": "ttf"}
+	],
+	"chapters": [
+		{"start_time": "0.000000", "end_time": "312.500000", "tags": {"title": "Chapter 01"}},
+		{"start_time": "312.500000", "end_time": "5430.250000"}
+	],
+	"format": {"format_name": "matroska,webm", "duration": "5430.250000", "size": "24000000000", "bit_rate": "N/A"}
+}`
+
+func TestParse(t *testing.T) {
+	info, err := Parse([]byte(sampleJSON))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if info.Format.Name != "matroska,webm" {
+		t.Errorf("Format.Name = %q", info.Format.Name)
+	}
+	if info.Format.Duration != 5430.25 {
+		t.Errorf("Format.Duration = %v, want 5430.25", info.Format.Duration)
+	}
+	if info.Format.Size != 24000000000 {
+		t.Errorf("Format.Size = %d", info.Format.Size)
+	}
+	if info.Format.BitRate != 0 {
+		t.Errorf("Format.BitRate = %d, want 0 for N/A", info.Format.BitRate)
+	}
+
+	if len(info.Streams) != 5 {
+		t.Fatalf("got %d streams, want 5", len(info.Streams))
+	}
+	if len(info.Video()) != 1 || len(info.Audio()) != 2 || len(info.Subtitles()) != 1 {
+		t.Fatalf("got %d video, %d audio, %d subs, want 1, 2, 1",
+			len(info.Video()), len(info.Audio()), len(info.Subtitles()))
+	}
+
+	video := info.Video()[0]
+	if video.Width != 1920 || video.Height != 1080 || !video.Default {
+		t.Errorf("video = %+v", video)
+	}
+	audio := info.Audio()
+	if audio[0].Language != "eng" || audio[0].Channels != 8 || audio[0].Title != "Surround 7.1" {
+		t.Errorf("audio[0] = %+v", audio[0])
+	}
+	if audio[1].Language != "und" {
+		t.Errorf("untagged audio language = %q, want und", audio[1].Language)
+	}
+	if sub := info.Subtitles()[0]; !sub.Forced || sub.Default {
+		t.Errorf("subtitle disposition = default %v forced %v, want forced only", sub.Default, sub.Forced)
+	}
+
+	if len(info.Chapters) != 2 {
+		t.Fatalf("got %d chapters, want 2", len(info.Chapters))
+	}
+	if c := info.Chapters[0]; c.Start != 0 || c.End != 312.5 || c.Title != "Chapter 01" {
+		t.Errorf("chapters[0] = %+v", c)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	if _, err := Parse([]byte("not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestStream_String(t *testing.T) {
+	info, err := Parse([]byte(sampleJSON))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []string{
+		"#0 video hevc 1920x1080 [default]",
+		`#1 audio truehd eng 8ch (7.1) "Surround 7.1" [default]`,
+		"#2 audio ac3 und 6ch",
+		"#3 subtitle hdmv_pgs_subtitle bul [forced]",
+		"#4 attachment ttf",
+	}
+	for i, s := range info.Streams {
+		if got := s.String(); got != want[i] {
+			t.Errorf("Streams[%d].String() = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestProbeWith_MissingBinary(t *testing.T) {
+	if _, err := ProbeWith("/nonexistent/ffprobe", "input.mkv"); err == nil {
+		t.Error("expected error for missing ffprobe binary")
+	}
+}
+
+func TestProbe_Integration(t *testing.T) {
+	if _, err := exec.LookPath("ffprobe"); err != nil {
+		t.Skip("ffprobe not available")
+	}
+	if _, err := Probe("/nonexistent/input.mkv"); err == nil {
+		t.Error("expected error probing a missing file")
+	}
+}
@@ -0,0 +1,81 @@
+// Package metadata looks up TMDB and TVDB IDs by title so items can be
+// published without copying IDs by hand.
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrNotConfigured is returned when searching a database without an API key
+var ErrNotConfigured = errors.New("no API key configured")
+
+// Match is a single search result
+type Match struct {
+	ID    int    // TMDB ID for movies, TVDB ID for TV shows
+	Title string // Canonical title
+	Year  int    // Release or first-air year, 0 if unknown
+}
+
+// Client searches the online databases used for publishing
+type Client interface {
+	// SearchMovie searches TMDB for movies matching title
+	SearchMovie(title string) ([]Match, error)
+	// SearchTV searches TVDB for series matching title
+	SearchTV(title string) ([]Match, error)
+}
+
+// HTTPClient implements Client against the TMDB and TVDB web APIs
+type HTTPClient struct {
+	tmdb *tmdbClient
+	tvdb *tvdbClient
+}
+
+// NewClient creates a client for whichever API keys are set.
+// Returns nil if neither key is set, so lookups stay disabled offline.
+func NewClient(tmdbAPIKey, tvdbAPIKey string) *HTTPClient {
+	if tmdbAPIKey == "" && tvdbAPIKey == "" {
+		return nil
+	}
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	c := &HTTPClient{}
+	if tmdbAPIKey != "" {
+		c.tmdb = &tmdbClient{apiKey: tmdbAPIKey, baseURL: defaultTMDBBaseURL, client: httpClient}
+	}
+	if tvdbAPIKey != "" {
+		c.tvdb = &tvdbClient{apiKey: tvdbAPIKey, baseURL: defaultTVDBBaseURL, client: httpClient}
+	}
+	return c
+}
+
+// SearchMovie searches TMDB for movies matching title
+func (c *HTTPClient) SearchMovie(title string) ([]Match, error) {
+	if c == nil || c.tmdb == nil {
+		return nil, fmt.Errorf("tmdb: %w", ErrNotConfigured)
+	}
+	return c.tmdb.searchMovie(title)
+}
+
+// SearchTV searches TVDB for series matching title
+func (c *HTTPClient) SearchTV(title string) ([]Match, error) {
+	if c == nil || c.tvdb == nil {
+		return nil, fmt.Errorf("tvdb: %w", ErrNotConfigured)
+	}
+	return c.tvdb.searchSeries(title)
+}
+
+// parseYear extracts the year from a "2006-01-02" or "2006" date string
+func parseYear(date string) int {
+	if len(date) < 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(date[:4])
+	if err != nil {
+		return 0
+	}
+	return year
+}
@@ -0,0 +1,133 @@
+package metadata
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var _ Client = (*HTTPClient)(nil)
+
+func TestNewClient_NoKeys(t *testing.T) {
+	if c := NewClient("", ""); c != nil {
+		t.Fatal("expected nil client without API keys")
+	}
+
+	c := NewClient("tmdb-key", "")
+	if _, err := c.SearchTV("Lost"); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("SearchTV() error = %v, want ErrNotConfigured", err)
+	}
+}
+
+func TestSearchMovie(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/movie" {
+			t.Errorf("path = %q, want /search/movie", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("api_key"); got != "secret" {
+			t.Errorf("api_key = %q, want secret", got)
+		}
+		if got := r.URL.Query().Get("query"); got != "The Matrix" {
+			t.Errorf("query = %q, want The Matrix", got)
+		}
+		w.Write([]byte(`{"results": [
+			{"id": 603, "title": "The Matrix", "release_date": "1999-03-31"},
+			{"id": 604, "title": "The Matrix Reloaded", "release_date": ""}
+		]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("secret", "")
+	c.tmdb.baseURL = server.URL
+
+	matches, err := c.SearchMovie("The Matrix")
+	if err != nil {
+		t.Fatalf("SearchMovie() error = %v", err)
+	}
+
+	want := []Match{
+		{ID: 603, Title: "The Matrix", Year: 1999},
+		{ID: 604, Title: "The Matrix Reloaded", Year: 0},
+	}
+	if len(matches) != len(want) {
+		t.Fatalf("got %d matches, want %d", len(matches), len(want))
+	}
+	for i := range want {
+		if matches[i] != want[i] {
+			t.Errorf("matches[%d] = %+v, want %+v", i, matches[i], want[i])
+		}
+	}
+}
+
+func TestSearchMovie_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c := NewClient("bad", "")
+	c.tmdb.baseURL = server.URL
+
+	if _, err := c.SearchMovie("The Matrix"); err == nil {
+		t.Error("SearchMovie() error = nil, want error")
+	}
+}
+
+func TestSearchMovie_TransportErrorHidesKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close() // Connections are refused from here on
+
+	c := NewClient("secret-tmdb-key", "")
+	c.tmdb.baseURL = server.URL
+
+	_, err := c.SearchMovie("The Matrix")
+	if err == nil {
+		t.Fatal("SearchMovie() error = nil, want error")
+	}
+	if strings.Contains(err.Error(), "secret-tmdb-key") {
+		t.Errorf("error leaks the API key: %v", err)
+	}
+}
+
+func TestSearchTV(t *testing.T) {
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			logins++
+			w.Write([]byte(`{"data": {"token": "tok"}}`))
+		case "/search":
+			if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+				t.Errorf("Authorization = %q, want Bearer tok", got)
+			}
+			if got := r.URL.Query().Get("type"); got != "series" {
+				t.Errorf("type = %q, want series", got)
+			}
+			w.Write([]byte(`{"data": [
+				{"tvdb_id": "73739", "name": "Lost", "year": "2004"},
+				{"tvdb_id": "not-a-number", "name": "Broken"}
+			]}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("", "secret")
+	c.tvdb.baseURL = server.URL
+
+	for i := 0; i < 2; i++ {
+		matches, err := c.SearchTV("Lost")
+		if err != nil {
+			t.Fatalf("SearchTV() error = %v", err)
+		}
+		if len(matches) != 1 || matches[0] != (Match{ID: 73739, Title: "Lost", Year: 2004}) {
+			t.Errorf("matches = %+v", matches)
+		}
+	}
+	if logins != 1 {
+		t.Errorf("logins = %d, want token to be reused", logins)
+	}
+}
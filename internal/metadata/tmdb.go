@@ -0,0 +1,64 @@
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const defaultTMDBBaseURL = "https://api.themoviedb.org/3"
+
+// tmdbClient searches The Movie Database (v3 API, api_key auth)
+type tmdbClient struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+// tmdbSearchResponse is the subset of /search/movie we use
+type tmdbSearchResponse struct {
+	Results []struct {
+		ID          int    `json:"id"`
+		Title       string `json:"title"`
+		ReleaseDate string `json:"release_date"`
+	} `json:"results"`
+}
+
+// searchMovie queries /search/movie and returns matches in TMDB's relevance order
+func (c *tmdbClient) searchMovie(title string) ([]Match, error) {
+	params := url.Values{}
+	params.Set("api_key", c.apiKey)
+	params.Set("query", title)
+
+	resp, err := c.client.Get(c.baseURL + "/search/movie?" + params.Encode())
+	if err != nil {
+		// The URL carries the API key, so only keep the underlying cause
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return nil, fmt.Errorf("failed to search tmdb: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tmdb search failed: %s", resp.Status)
+	}
+
+	var body tmdbSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode tmdb response: %w", err)
+	}
+
+	matches := make([]Match, 0, len(body.Results))
+	for _, r := range body.Results {
+		matches = append(matches, Match{
+			ID:    r.ID,
+			Title: r.Title,
+			Year:  parseYear(r.ReleaseDate),
+		})
+	}
+	return matches, nil
+}
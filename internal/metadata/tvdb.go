@@ -0,0 +1,128 @@
+package metadata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+const defaultTVDBBaseURL = "https://api4.thetvdb.com/v4"
+
+// tvdbClient searches TheTVDB (v4 API, bearer token from /login)
+type tvdbClient struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+
+	mu    sync.Mutex
+	token string
+}
+
+// tvdbLoginResponse is the /login payload
+type tvdbLoginResponse struct {
+	Data struct {
+		Token string `json:"token"`
+	} `json:"data"`
+}
+
+// tvdbSearchResponse is the subset of /search we use
+type tvdbSearchResponse struct {
+	Data []struct {
+		TVDBID string `json:"tvdb_id"`
+		Name   string `json:"name"`
+		Year   string `json:"year"`
+	} `json:"data"`
+}
+
+// searchSeries queries /search restricted to series
+func (c *tvdbClient) searchSeries(title string) ([]Match, error) {
+	token, err := c.login()
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("query", title)
+	params.Set("type", "series")
+
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tvdb request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tvdb: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		// Tokens last a month; drop it so the next search logs in again
+		c.mu.Lock()
+		c.token = ""
+		c.mu.Unlock()
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tvdb search failed: %s", resp.Status)
+	}
+
+	var body tvdbSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode tvdb response: %w", err)
+	}
+
+	matches := make([]Match, 0, len(body.Data))
+	for _, r := range body.Data {
+		id, err := strconv.Atoi(r.TVDBID)
+		if err != nil {
+			continue
+		}
+		matches = append(matches, Match{
+			ID:    id,
+			Title: r.Name,
+			Year:  parseYear(r.Year),
+		})
+	}
+	return matches, nil
+}
+
+// login exchanges the API key for a bearer token, reusing a cached one
+func (c *tvdbClient) login() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" {
+		return c.token, nil
+	}
+
+	payload, err := json.Marshal(map[string]string{"apikey": c.apiKey})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode tvdb login: %w", err)
+	}
+
+	resp, err := c.client.Post(c.baseURL+"/login", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("failed to log in to tvdb: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("tvdb login failed: %s", resp.Status)
+	}
+
+	var body tvdbLoginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode tvdb login: %w", err)
+	}
+	if body.Data.Token == "" {
+		return "", fmt.Errorf("tvdb login returned no token")
+	}
+
+	c.token = body.Data.Token
+	return c.token, nil
+}
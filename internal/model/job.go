@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"os"
+	"time"
+)
 
 // JobStatus represents the current state of a job
 type JobStatus string
@@ -12,6 +16,55 @@ const (
 	JobStatusFailed     JobStatus = "failed"
 )
 
+func (s JobStatus) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is one of the known job statuses
+func (s JobStatus) IsValid() bool {
+	switch s {
+	case JobStatusPending, JobStatusInProgress, JobStatusCompleted, JobStatusFailed:
+		return true
+	}
+	return false
+}
+
+// StageStatus returns the stage status matching a job's status
+func (s JobStatus) StageStatus() Status {
+	switch s {
+	case JobStatusCompleted:
+		return StatusCompleted
+	case JobStatusInProgress:
+		return StatusInProgress
+	case JobStatusFailed:
+		return StatusFailed
+	default:
+		return StatusPending
+	}
+}
+
+// ParseJobStatus converts a status name ("pending", "completed", ...) to a JobStatus
+func ParseJobStatus(name string) (JobStatus, error) {
+	if s := JobStatus(name); s.IsValid() {
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown job status %q", name)
+}
+
+// Scan implements sql.Scanner, rejecting unknown job statuses
+func (s *JobStatus) Scan(src any) error {
+	name, err := scanName(src, "JobStatus")
+	if err != nil {
+		return err
+	}
+	status, err := ParseJobStatus(name)
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
+
 // Job represents a single stage execution attempt
 type Job struct {
 	ID           int64
@@ -26,7 +79,8 @@ type Job struct {
 	OutputDir    string
 	LogPath      string
 	ErrorMessage string
-	Progress     int // 0-100 percentage
+	Progress     int    // 0-100 percentage
+	SupersededBy *int64 // Retry job that replaced this failed one
 	StartedAt    *time.Time
 	CompletedAt  *time.Time
 	CreatedAt    time.Time
@@ -37,11 +91,23 @@ func (j *Job) IsActive() bool {
 	return j.Status == JobStatusPending || j.Status == JobStatusInProgress
 }
 
-// Duration returns the job duration, or zero if not completed
+// SetWorker records this host and process as the ones running the job, so
+// it can be told apart from jobs on other dispatch targets and checked for
+// a crashed worker
+func (j *Job) SetWorker() {
+	j.WorkerID, _ = os.Hostname()
+	j.PID = os.Getpid()
+}
+
+// Duration returns how long the job ran: CompletedAt - StartedAt once finished,
+// the time elapsed so far if still running, or zero if not started
 func (j *Job) Duration() time.Duration {
-	if j.StartedAt == nil || j.CompletedAt == nil {
+	if j.StartedAt == nil {
 		return 0
 	}
+	if j.CompletedAt == nil {
+		return time.Since(*j.StartedAt)
+	}
 	return j.CompletedAt.Sub(*j.StartedAt)
 }
 
@@ -56,7 +122,32 @@ type LogEvent struct {
 
 // DiscProgress tracks rip status for a TV disc
 type DiscProgress struct {
-	Disc   int
-	Status JobStatus
-	JobID  int64
+	Disc     int
+	Status   JobStatus
+	JobID    int64
+	SeasonID *int64 // nil for rips not tied to a season
+}
+
+// JobStats holds aggregate job counts for dashboards
+type JobStats struct {
+	ByStage        map[Stage]map[JobStatus]int // stage -> status -> count
+	Total          int                         // Total number of jobs
+	BytesProcessed int64                       // Sum of transcode output sizes
+}
+
+// Count returns the number of jobs for a stage and status
+func (s *JobStats) Count(stage Stage, status JobStatus) int {
+	if s.ByStage == nil {
+		return 0
+	}
+	return s.ByStage[stage][status]
+}
+
+// CountByStatus returns the number of jobs with a status across all stages
+func (s *JobStats) CountByStatus(status JobStatus) int {
+	total := 0
+	for _, counts := range s.ByStage {
+		total += counts[status]
+	}
+	return total
 }
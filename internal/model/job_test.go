@@ -1,6 +1,7 @@
 package model
 
 import (
+	"os"
 	"testing"
 	"time"
 )
@@ -58,7 +59,75 @@ func TestJob_Duration_InProgress(t *testing.T) {
 	}
 
 	duration := job.Duration()
-	if duration != 0 {
-		t.Errorf("Duration() = %v, want 0 for in-progress job", duration)
+	if duration < time.Hour || duration > time.Hour+time.Minute {
+		t.Errorf("Duration() = %v, want ~1 hour elapsed for in-progress job", duration)
+	}
+}
+
+func TestJob_SetWorker(t *testing.T) {
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skipf("no hostname: %v", err)
+	}
+
+	var job Job
+	job.SetWorker()
+	if job.WorkerID != host || job.PID != os.Getpid() {
+		t.Errorf("SetWorker() = %q pid %d, want %q pid %d", job.WorkerID, job.PID, host, os.Getpid())
+	}
+}
+
+func TestParseJobStatus(t *testing.T) {
+	for _, s := range []JobStatus{JobStatusPending, JobStatusInProgress, JobStatusCompleted, JobStatusFailed} {
+		got, err := ParseJobStatus(s.String())
+		if err != nil || got != s {
+			t.Errorf("ParseJobStatus(%q) = %v, %v; want %v", s.String(), got, err, s)
+		}
+	}
+	for _, name := range []string{"complete", "", "skipped"} {
+		if _, err := ParseJobStatus(name); err == nil {
+			t.Errorf("ParseJobStatus(%q) should fail", name)
+		}
+		if JobStatus(name).IsValid() {
+			t.Errorf("JobStatus(%q).IsValid() = true", name)
+		}
+	}
+}
+
+func TestJobStatus_StageStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		jobStatus      JobStatus
+		expectedStatus Status
+	}{
+		{
+			name:           "JobStatusCompleted maps to StatusCompleted",
+			jobStatus:      JobStatusCompleted,
+			expectedStatus: StatusCompleted,
+		},
+		{
+			name:           "JobStatusInProgress maps to StatusInProgress",
+			jobStatus:      JobStatusInProgress,
+			expectedStatus: StatusInProgress,
+		},
+		{
+			name:           "JobStatusFailed maps to StatusFailed",
+			jobStatus:      JobStatusFailed,
+			expectedStatus: StatusFailed,
+		},
+		{
+			name:           "JobStatusPending maps to StatusPending",
+			jobStatus:      JobStatusPending,
+			expectedStatus: StatusPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.jobStatus.StageStatus()
+			if result != tt.expectedStatus {
+				t.Errorf("%v.StageStatus() = %v, want %v", tt.jobStatus, result, tt.expectedStatus)
+			}
+		})
 	}
 }
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"database/sql/driver"
 	"fmt"
 	"time"
 )
@@ -33,6 +34,38 @@ func (s Stage) String() string {
 	}
 }
 
+// ParseStage converts a stage name ("rip", "remux", ...) to a Stage
+func ParseStage(name string) (Stage, error) {
+	for s := StageRip; s <= StagePublish; s++ {
+		if s.String() == name {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown stage %q", name)
+}
+
+// Scan implements sql.Scanner, rejecting unknown stage names
+func (s *Stage) Scan(src any) error {
+	name, err := scanName(src, "Stage")
+	if err != nil {
+		return err
+	}
+	stage, err := ParseStage(name)
+	if err != nil {
+		return err
+	}
+	*s = stage
+	return nil
+}
+
+// Value implements driver.Valuer, storing the stage by name
+func (s Stage) Value() (driver.Value, error) {
+	if s < StageRip || s > StagePublish {
+		return nil, fmt.Errorf("invalid stage %d", int(s))
+	}
+	return s.String(), nil
+}
+
 func (s Stage) DisplayName() string {
 	switch s {
 	case StageRip:
@@ -65,6 +98,17 @@ func (s Stage) NextStage() Stage {
 	}
 }
 
+// PreviousStage returns the stage that must complete before s can start,
+// or false for rip, which has no prerequisite
+func (s Stage) PreviousStage() (Stage, bool) {
+	if s <= StageRip || s > StagePublish {
+		return 0, false
+	}
+	return s - 1, true
+}
+
+// NextAction describes what an item needs once s has completed, e.g. "needs
+// remux", or "done" after publish
 func (s Stage) NextAction() string {
 	switch s {
 	case StageRip:
@@ -76,7 +120,7 @@ func (s Stage) NextAction() string {
 	case StageTranscode:
 		return "needs publish"
 	case StagePublish:
-		return "complete"
+		return "done"
 	default:
 		return "unknown"
 	}
@@ -92,6 +136,53 @@ const (
 	StatusFailed     Status = "failed"
 )
 
+func (s Status) String() string {
+	return string(s)
+}
+
+// IsValid reports whether s is one of the known stage statuses
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusPending, StatusInProgress, StatusCompleted, StatusFailed:
+		return true
+	}
+	return false
+}
+
+// ParseStatus converts a status name ("pending", "completed", ...) to a Status
+func ParseStatus(name string) (Status, error) {
+	if s := Status(name); s.IsValid() {
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown status %q", name)
+}
+
+// Scan implements sql.Scanner, rejecting unknown statuses
+func (s *Status) Scan(src any) error {
+	name, err := scanName(src, "Status")
+	if err != nil {
+		return err
+	}
+	status, err := ParseStatus(name)
+	if err != nil {
+		return err
+	}
+	*s = status
+	return nil
+}
+
+// scanName returns the text column src for scanning into typ
+func scanName(src any, typ string) (string, error) {
+	switch v := src.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	default:
+		return "", fmt.Errorf("cannot scan %T into %s", src, typ)
+	}
+}
+
 // MediaType distinguishes movies from TV shows
 type MediaType string
 
@@ -109,6 +200,23 @@ const (
 	ItemStatusCompleted  ItemStatus = "completed"
 )
 
+// IsValid reports whether s is one of the known item statuses
+func (s ItemStatus) IsValid() bool {
+	switch s {
+	case ItemStatusNotStarted, ItemStatusActive, ItemStatusCompleted:
+		return true
+	}
+	return false
+}
+
+// RipMode selects what the rip stage writes for an item's discs
+type RipMode string
+
+const (
+	RipModeTitles RipMode = "titles" // One MKV per title, for the rest of the pipeline (default)
+	RipModeBackup RipMode = "backup" // Decrypted copy of the whole disc, for archiving
+)
+
 // StageInfo contains metadata about a specific pipeline stage for an item
 type StageInfo struct {
 	Stage       Stage
@@ -125,6 +233,8 @@ type MediaItem struct {
 	Type     MediaType // "movie" or "tv"
 	Name     string    // Human-readable name like "The Lion King"
 	SafeName string    // Filesystem-safe name like "The_Lion_King"
+	Edition  string    // Optional edition like "Extended", part of SafeName
+	RipMode  RipMode   // How discs are ripped; backups skip the later stages
 
 	// Database IDs for FileBot matching
 	TmdbID *int // TheMovieDB ID (for movies)
@@ -140,6 +250,9 @@ type MediaItem struct {
 	// For TV Shows: seasons contain the pipeline state
 	Seasons []Season // Populated for TV shows
 
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
 	// Legacy fields (to be removed after migration)
 	Season  *int        // DEPRECATED: Season number for TV
 	Stages  []StageInfo // DEPRECATED: History of stages
@@ -166,6 +279,49 @@ func (m *MediaItem) DatabaseID() int {
 	return 0
 }
 
+// DisplayPath returns where the item landed in the library: the output
+// directory of its most recent completed publish job among jobs, or "" if it
+// hasn't been published. jobs are expected oldest first, as the repository
+// lists them.
+func (m *MediaItem) DisplayPath(jobs []Job) string {
+	for i := len(jobs) - 1; i >= 0; i-- {
+		j := jobs[i]
+		if j.MediaItemID == m.ID && j.Stage == StagePublish && j.Status == JobStatusCompleted && j.OutputDir != "" {
+			return j.OutputDir
+		}
+	}
+	return ""
+}
+
+// JobStage returns the stage a movie is at: that of the latest of jobs, with
+// the job's outcome, unless the stored stage was overridden since. jobs are
+// expected oldest first.
+func (m *MediaItem) JobStage(jobs []Job) (Stage, Status) {
+	if len(jobs) == 0 || m.StageOverridden(jobs[len(jobs)-1]) {
+		return m.CurrentStage, m.StageStatus
+	}
+	latest := jobs[len(jobs)-1]
+	return latest.Stage, latest.Status.StageStatus()
+}
+
+// StageOverridden reports whether a movie's stored stage was written after
+// its latest job last changed, as the stage override menu does. An
+// in-progress stage never counts, since stage binaries don't reset it when
+// a job fails.
+func (m *MediaItem) StageOverridden(latest Job) bool {
+	if m.StageStatus == StatusInProgress {
+		return false
+	}
+	changed := latest.CreatedAt
+	if latest.StartedAt != nil && latest.StartedAt.After(changed) {
+		changed = *latest.StartedAt
+	}
+	if latest.CompletedAt != nil && latest.CompletedAt.After(changed) {
+		changed = *latest.CompletedAt
+	}
+	return m.UpdatedAt.After(changed)
+}
+
 // IsReadyForNextStage returns true if the item has completed its current stage
 func (m *MediaItem) IsReadyForNextStage() bool {
 	return m.Status == StatusCompleted && m.Current != StagePublish
@@ -191,6 +347,16 @@ func (m *MediaItem) GetStageInfo(stage Stage) *StageInfo {
 	return nil
 }
 
+// ItemRecord is everything the pipeline knows about one media item, as
+// exported for bug reports or moving an item to another database
+type ItemRecord struct {
+	Item           MediaItem                        // Seasons populated for TV shows
+	Jobs           []Job                            // Oldest first
+	JobOptions     map[int64]map[string]interface{} // job ID -> options, for jobs that have any
+	TranscodeFiles []TranscodeFile                  // Files of all transcode jobs
+	LogEvents      []LogEvent                       // Most recent events of each job
+}
+
 // PipelineState holds the complete state of the media pipeline
 type PipelineState struct {
 	Items     []MediaItem
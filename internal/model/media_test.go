@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 )
 
 func TestMediaItem_UniqueKey(t *testing.T) {
@@ -36,6 +37,62 @@ func TestMediaItem_UniqueKey(t *testing.T) {
 	}
 }
 
+func TestMediaItem_DisplayPath(t *testing.T) {
+	item := MediaItem{ID: 1}
+	jobs := []Job{
+		{MediaItemID: 1, Stage: StagePublish, Status: JobStatusCompleted, OutputDir: "/library/movies/Old"},
+		{MediaItemID: 1, Stage: StagePublish, Status: JobStatusCompleted, OutputDir: "/library/movies/New"},
+		{MediaItemID: 1, Stage: StagePublish, Status: JobStatusFailed, OutputDir: "/library/movies/Broken"},
+		{MediaItemID: 1, Stage: StageTranscode, Status: JobStatusCompleted, OutputDir: "/staging/4-transcoded"},
+		{MediaItemID: 2, Stage: StagePublish, Status: JobStatusCompleted, OutputDir: "/library/movies/Other"},
+	}
+
+	if got := item.DisplayPath(jobs); got != "/library/movies/New" {
+		t.Errorf("DisplayPath() = %q, want %q", got, "/library/movies/New")
+	}
+	if got := item.DisplayPath(jobs[2:4]); got != "" {
+		t.Errorf("DisplayPath(unpublished) = %q, want empty", got)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	for _, s := range []Status{StatusPending, StatusInProgress, StatusCompleted, StatusFailed} {
+		got, err := ParseStatus(s.String())
+		if err != nil || got != s {
+			t.Errorf("ParseStatus(%q) = %v, %v; want %v", s.String(), got, err, s)
+		}
+	}
+	for _, name := range []string{"complete", "", "COMPLETED"} {
+		if _, err := ParseStatus(name); err == nil {
+			t.Errorf("ParseStatus(%q) should fail", name)
+		}
+		if Status(name).IsValid() {
+			t.Errorf("Status(%q).IsValid() = true", name)
+		}
+	}
+
+	var s Status
+	if err := s.Scan([]byte("in_progress")); err != nil || s != StatusInProgress {
+		t.Errorf("Scan() = %v, %v; want in_progress", s, err)
+	}
+	if err := s.Scan("complete"); err == nil {
+		t.Error("Scan() of an unknown status should fail")
+	}
+}
+
+func TestItemStatus_IsValid(t *testing.T) {
+	for _, s := range []ItemStatus{ItemStatusNotStarted, ItemStatusActive, ItemStatusCompleted} {
+		if !s.IsValid() {
+			t.Errorf("%q.IsValid() = false", s)
+		}
+	}
+	for _, name := range []string{"done", "", "pending"} {
+		if ItemStatus(name).IsValid() {
+			t.Errorf("ItemStatus(%q).IsValid() = true", name)
+		}
+	}
+}
+
 func TestMediaItem_IsReadyForNextStage(t *testing.T) {
 	tests := []struct {
 		name string
@@ -146,3 +203,53 @@ func TestMediaItem_DatabaseID(t *testing.T) {
 }
 
 func ptr[T any](v T) *T { return &v }
+
+func TestMediaItem_StageOverridden(t *testing.T) {
+	jobTime := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	completed := jobTime.Add(time.Hour)
+	latest := Job{Stage: StageTranscode, Status: JobStatusCompleted, CreatedAt: jobTime, CompletedAt: &completed}
+
+	tests := []struct {
+		name    string
+		status  Status
+		updated time.Time
+		want    bool
+	}{
+		{"written after the job finished", StatusCompleted, completed.Add(time.Minute), true},
+		{"written while the job ran", StatusCompleted, jobTime.Add(time.Minute), false},
+		{"in progress never overrides", StatusInProgress, completed.Add(time.Minute), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &MediaItem{CurrentStage: StageRemux, StageStatus: tt.status, UpdatedAt: tt.updated}
+			if got := item.StageOverridden(latest); got != tt.want {
+				t.Errorf("StageOverridden() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMediaItem_JobStage(t *testing.T) {
+	jobTime := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	jobs := []Job{
+		{Stage: StageRip, Status: JobStatusCompleted, CreatedAt: jobTime},
+		{Stage: StageRemux, Status: JobStatusFailed, CreatedAt: jobTime.Add(time.Hour)},
+	}
+
+	item := &MediaItem{CurrentStage: StageRip, StageStatus: StatusPending}
+	if stage, status := item.JobStage(nil); stage != StageRip || status != StatusPending {
+		t.Errorf("JobStage(no jobs) = %s %s, want the stored rip pending", stage, status)
+	}
+
+	item.UpdatedAt = jobTime
+	if stage, status := item.JobStage(jobs); stage != StageRemux || status != StatusFailed {
+		t.Errorf("JobStage() = %s %s, want the latest job's remux failed", stage, status)
+	}
+
+	// Overridden to skip the failed remux
+	item.CurrentStage, item.StageStatus = StageRemux, StatusCompleted
+	item.UpdatedAt = jobTime.Add(2 * time.Hour)
+	if stage, status := item.JobStage(jobs); stage != StageRemux || status != StatusCompleted {
+		t.Errorf("JobStage(overridden) = %s %s, want the stored remux completed", stage, status)
+	}
+}
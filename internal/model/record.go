@@ -0,0 +1,120 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// An ItemRecord's JSON names its stages ("remux" rather than 2), so exported
+// records are readable and don't depend on the order of the Stage constants.
+// The record* types shadow each Stage field with its name.
+
+type recordJSON struct {
+	Item           recordItem
+	Jobs           []recordJob
+	JobOptions     map[int64]map[string]interface{}
+	TranscodeFiles []TranscodeFile
+	LogEvents      []LogEvent
+}
+
+type recordItem struct {
+	MediaItem
+	CurrentStage string
+	Seasons      []recordSeason
+	Stages       []recordStageInfo
+	Current      string
+}
+
+type recordSeason struct {
+	Season
+	CurrentStage string
+}
+
+type recordStageInfo struct {
+	StageInfo
+	Stage string
+}
+
+type recordJob struct {
+	Job
+	Stage string
+}
+
+// MarshalJSON encodes the record with stages by name
+func (r ItemRecord) MarshalJSON() ([]byte, error) {
+	out := recordJSON{
+		Item: recordItem{
+			MediaItem:    r.Item,
+			CurrentStage: r.Item.CurrentStage.String(),
+			Current:      r.Item.Current.String(),
+		},
+		JobOptions:     r.JobOptions,
+		TranscodeFiles: r.TranscodeFiles,
+		LogEvents:      r.LogEvents,
+	}
+	for _, s := range r.Item.Seasons {
+		out.Item.Seasons = append(out.Item.Seasons, recordSeason{Season: s, CurrentStage: s.CurrentStage.String()})
+	}
+	for _, s := range r.Item.Stages {
+		out.Item.Stages = append(out.Item.Stages, recordStageInfo{StageInfo: s, Stage: s.Stage.String()})
+	}
+	for _, j := range r.Jobs {
+		out.Jobs = append(out.Jobs, recordJob{Job: j, Stage: j.Stage.String()})
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a record written by MarshalJSON, rejecting unknown
+// stage names
+func (r *ItemRecord) UnmarshalJSON(data []byte) error {
+	var in recordJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	var err error
+	rec := ItemRecord{
+		Item:           in.Item.MediaItem,
+		JobOptions:     in.JobOptions,
+		TranscodeFiles: in.TranscodeFiles,
+		LogEvents:      in.LogEvents,
+	}
+	if rec.Item.CurrentStage, err = parseRecordStage(in.Item.CurrentStage); err != nil {
+		return err
+	}
+	if rec.Item.Current, err = parseRecordStage(in.Item.Current); err != nil {
+		return err
+	}
+	rec.Item.Seasons = nil
+	for _, s := range in.Item.Seasons {
+		if s.Season.CurrentStage, err = parseRecordStage(s.CurrentStage); err != nil {
+			return fmt.Errorf("season %d: %w", s.Number, err)
+		}
+		rec.Item.Seasons = append(rec.Item.Seasons, s.Season)
+	}
+	rec.Item.Stages = nil
+	for _, s := range in.Item.Stages {
+		if s.StageInfo.Stage, err = parseRecordStage(s.Stage); err != nil {
+			return err
+		}
+		rec.Item.Stages = append(rec.Item.Stages, s.StageInfo)
+	}
+	for _, j := range in.Jobs {
+		if j.Job.Stage, err = parseRecordStage(j.Stage); err != nil {
+			return fmt.Errorf("job %d: %w", j.ID, err)
+		}
+		rec.Jobs = append(rec.Jobs, j.Job)
+	}
+
+	*r = rec
+	return nil
+}
+
+// parseRecordStage parses a stage name from a record; a missing name is the
+// zero Stage, as it would be in the struct
+func parseRecordStage(name string) (Stage, error) {
+	if name == "" {
+		return 0, nil
+	}
+	return ParseStage(name)
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestItemRecord_JSON(t *testing.T) {
+	record := ItemRecord{
+		Item: MediaItem{
+			ID:      1,
+			Type:    MediaTypeTV,
+			Name:    "Show",
+			Seasons: []Season{{ID: 2, Number: 1, CurrentStage: StageTranscode, StageStatus: StatusInProgress}},
+		},
+		Jobs: []Job{{ID: 3, MediaItemID: 1, Stage: StageRemux, Status: JobStatusCompleted}},
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	for _, want := range []string{`"CurrentStage":"transcode"`, `"Stage":"remux"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Marshal() missing %s:\n%s", want, data)
+		}
+	}
+
+	var got ItemRecord
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got.Item.Name != "Show" || len(got.Item.Seasons) != 1 || got.Item.Seasons[0].CurrentStage != StageTranscode {
+		t.Errorf("Unmarshal() item = %+v", got.Item)
+	}
+	if len(got.Jobs) != 1 || got.Jobs[0].Stage != StageRemux || got.Jobs[0].Status != JobStatusCompleted {
+		t.Errorf("Unmarshal() jobs = %+v", got.Jobs)
+	}
+
+	bogus := strings.Replace(string(data), `"Stage":"remux"`, `"Stage":"bogus"`, 1)
+	if err := json.Unmarshal([]byte(bogus), &got); err == nil {
+		t.Error("Unmarshal() of an unknown stage should fail")
+	}
+}
+
+func TestStage_JSONIsNumeric(t *testing.T) {
+	// Only ItemRecord names stages; elsewhere Stage keeps its default encoding
+	data, err := json.Marshal(Job{Stage: StageRemux})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"Stage":2`) {
+		t.Errorf("Marshal() = %s", data)
+	}
+}
@@ -4,13 +4,15 @@ import "time"
 
 // Season represents a TV show season that moves through the pipeline
 type Season struct {
-	ID           int64
-	ItemID       int64     // Foreign key to Item (TV show)
-	Number       int       // Season number (1, 2, 3...)
-	CurrentStage Stage     // Current pipeline stage
-	StageStatus  Status    // Status of current stage
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID               int64
+	ItemID           int64  // Foreign key to Item (TV show)
+	Number           int    // Season number (1, 2, 3...)
+	CurrentStage     Stage  // Current pipeline stage
+	StageStatus      Status // Status of current stage
+	ExpectedEpisodes int    // Episodes the season should have, 0 if unknown
+	ExpectedDiscs    int    // Discs the season spans, 0 if unknown
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
 // IsReadyForNextStage returns true if the season has completed its current stage
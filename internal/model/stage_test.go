@@ -1,6 +1,8 @@
 package model
 
-import "testing"
+import (
+	"testing"
+)
 
 func TestStage_String(t *testing.T) {
 	tests := []struct {
@@ -20,38 +22,83 @@ func TestStage_String(t *testing.T) {
 	}
 }
 
-func TestStage_DisplayName(t *testing.T) {
+func TestStage_Progression(t *testing.T) {
 	tests := []struct {
-		stage Stage
-		want  string
+		stage       Stage
+		displayName string
+		nextStage   Stage
+		nextAction  string
 	}{
-		{StageRip, "1-Ripped"},
-		{StageOrganize, "2-Organized"},
-		{StageRemux, "3-Remuxed"},
-		{StageTranscode, "4-Transcoded"},
-		{StagePublish, "Library"},
+		{StageRip, "1-Ripped", StageOrganize, "needs organize"},
+		{StageOrganize, "2-Organized", StageRemux, "needs remux"},
+		{StageRemux, "3-Remuxed", StageTranscode, "needs transcode"},
+		{StageTranscode, "4-Transcoded", StagePublish, "needs publish"},
+		{StagePublish, "Library", StagePublish, "done"}, // terminal
+		{Stage(42), "Unknown", StagePublish, "unknown"},
 	}
 	for _, tt := range tests {
-		if got := tt.stage.DisplayName(); got != tt.want {
-			t.Errorf("Stage(%d).DisplayName() = %q, want %q", tt.stage, got, tt.want)
+		if got := tt.stage.DisplayName(); got != tt.displayName {
+			t.Errorf("Stage(%d).DisplayName() = %q, want %q", tt.stage, got, tt.displayName)
+		}
+		if got := tt.stage.NextStage(); got != tt.nextStage {
+			t.Errorf("Stage(%d).NextStage() = %d, want %d", tt.stage, got, tt.nextStage)
+		}
+		if got := tt.stage.NextAction(); got != tt.nextAction {
+			t.Errorf("Stage(%d).NextAction() = %q, want %q", tt.stage, got, tt.nextAction)
 		}
 	}
 }
 
-func TestStage_NextStage(t *testing.T) {
-	tests := []struct {
-		stage Stage
-		want  Stage
-	}{
-		{StageRip, StageOrganize},
-		{StageOrganize, StageRemux},
-		{StageRemux, StageTranscode},
-		{StageTranscode, StagePublish},
-		{StagePublish, StagePublish}, // terminal
+func TestParseStage(t *testing.T) {
+	for s := StageRip; s <= StagePublish; s++ {
+		got, err := ParseStage(s.String())
+		if err != nil || got != s {
+			t.Errorf("ParseStage(%q) = %v, %v; want %v", s.String(), got, err, s)
+		}
 	}
-	for _, tt := range tests {
-		if got := tt.stage.NextStage(); got != tt.want {
-			t.Errorf("Stage(%d).NextStage() = %d, want %d", tt.stage, got, tt.want)
+	if _, err := ParseStage("encode"); err == nil {
+		t.Error("ParseStage(\"encode\") should fail")
+	}
+}
+
+func TestStage_Scan(t *testing.T) {
+	var s Stage
+	if err := s.Scan("transcode"); err != nil || s != StageTranscode {
+		t.Errorf("Scan(\"transcode\") = %v, stage %v", err, s)
+	}
+	if err := s.Scan([]byte("remux")); err != nil || s != StageRemux {
+		t.Errorf("Scan([]byte(\"remux\")) = %v, stage %v", err, s)
+	}
+
+	s = StagePublish
+	for _, src := range []any{"bogus", "", int64(2), nil} {
+		if err := s.Scan(src); err == nil {
+			t.Errorf("Scan(%#v) should fail", src)
+		}
+	}
+	if s != StagePublish {
+		t.Errorf("failed Scan changed stage to %v", s)
+	}
+}
+
+func TestStage_Value(t *testing.T) {
+	v, err := StageRemux.Value()
+	if err != nil || v != "remux" {
+		t.Errorf("Value() = %v, %v; want \"remux\"", v, err)
+	}
+	if _, err := Stage(42).Value(); err == nil {
+		t.Error("Value() of an invalid stage should fail")
+	}
+}
+
+func TestStage_PreviousStage(t *testing.T) {
+	if _, ok := StageRip.PreviousStage(); ok {
+		t.Error("StageRip.PreviousStage() should report no prerequisite")
+	}
+	for s := StageOrganize; s <= StagePublish; s++ {
+		prev, ok := s.PreviousStage()
+		if !ok || prev.NextStage() != s {
+			t.Errorf("%v.PreviousStage() = %v, %v", s, prev, ok)
 		}
 	}
 }
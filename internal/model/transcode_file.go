@@ -26,6 +26,11 @@ type TranscodeFile struct {
 	StartedAt    *time.Time
 	CompletedAt  *time.Time
 	ErrorMessage string
+
+	// Digest of the output file, set when it completes, so copies of it
+	// can be verified without hashing the source again
+	Checksum          string
+	ChecksumAlgorithm string // e.g. "sha256"; empty if there is no checksum
 }
 
 // SizeSaved returns bytes saved (input - output)
@@ -40,3 +45,27 @@ func (f *TranscodeFile) CompressionRatio() float64 {
 	}
 	return float64(f.OutputSize) / float64(f.InputSize)
 }
+
+// TranscodeStats aggregates sizes across the completed files of a transcode job
+type TranscodeStats struct {
+	Files       int   // Number of completed files
+	InputBytes  int64 // Total input size
+	OutputBytes int64 // Total output size
+}
+
+// CompressionRatio returns the output/input ratio (lower is better compression)
+func (s *TranscodeStats) CompressionRatio() float64 {
+	if s.InputBytes == 0 {
+		return 0
+	}
+	return float64(s.OutputBytes) / float64(s.InputBytes)
+}
+
+// SavedPercent returns the percentage of space saved, at most 100. It is
+// negative when the output came out larger than the input.
+func (s *TranscodeStats) SavedPercent() float64 {
+	if s.InputBytes == 0 {
+		return 0
+	}
+	return (1 - s.CompressionRatio()) * 100
+}
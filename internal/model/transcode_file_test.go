@@ -0,0 +1,44 @@
+package model
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTranscodeStats_SavedPercent(t *testing.T) {
+	tests := []struct {
+		name      string
+		stats     TranscodeStats
+		wantRatio float64
+		wantSaved float64
+	}{
+		{
+			name:      "no input",
+			stats:     TranscodeStats{},
+			wantRatio: 0,
+			wantSaved: 0,
+		},
+		{
+			name:      "half size",
+			stats:     TranscodeStats{InputBytes: 1000, OutputBytes: 500},
+			wantRatio: 0.5,
+			wantSaved: 50,
+		},
+		{
+			name:      "larger output",
+			stats:     TranscodeStats{InputBytes: 1000, OutputBytes: 1100},
+			wantRatio: 1.1,
+			wantSaved: -10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.stats.CompressionRatio(); math.Abs(got-tt.wantRatio) > 0.0001 {
+				t.Errorf("CompressionRatio() = %v, want %v", got, tt.wantRatio)
+			}
+			if got := tt.stats.SavedPercent(); math.Abs(got-tt.wantSaved) > 0.0001 {
+				t.Errorf("SavedPercent() = %v, want %v", got, tt.wantSaved)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package organize
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Destinations a Move can send a file to, relative to the rip directory
+const (
+	DestMain   = "_main"
+	DestExtras = "_extras/other"
+	DestKeep   = "" // Leave the file where it is for a manual decision
+)
+
+// extrasMaxFraction is the share of the main feature's length below which a
+// title is taken for an extra; longer ones are often alternate cuts or
+// duplicate playlists, so they are left for the user
+const extrasMaxFraction = 0.5
+
+// FileInfo describes a ripped title for Classify
+type FileInfo struct {
+	Name         string  // File name in the rip directory
+	Size         int64   // Bytes
+	DurationSecs float64 // 0 when it couldn't be probed
+}
+
+// Move is one step of a suggested organization
+type Move struct {
+	Name string
+	Dest string // DestMain, DestExtras or DestKeep
+}
+
+// Suggestion is a proposed plan for sorting a movie rip, in the order of
+// the files given to Classify
+type Suggestion struct {
+	Moves []Move
+}
+
+// Classify guesses which of a movie rip's titles is the main feature and
+// which are extras. The longest title is the feature; titles under half its
+// length are extras and the rest are left in place. Sizes stand in for
+// durations when any title couldn't be probed.
+func Classify(files []FileInfo) Suggestion {
+	if len(files) == 0 {
+		return Suggestion{}
+	}
+
+	byDuration := true
+	for _, f := range files {
+		if f.DurationSecs <= 0 {
+			byDuration = false
+			break
+		}
+	}
+	length := func(f FileInfo) float64 {
+		if byDuration {
+			return f.DurationSecs
+		}
+		return float64(f.Size)
+	}
+
+	main := 0
+	for i, f := range files {
+		if length(f) > length(files[main]) {
+			main = i
+		}
+	}
+
+	s := Suggestion{Moves: make([]Move, len(files))}
+	for i, f := range files {
+		dest := DestKeep
+		switch {
+		case i == main:
+			dest = DestMain
+		case length(f) < length(files[main])*extrasMaxFraction:
+			dest = DestExtras
+		}
+		s.Moves[i] = Move{Name: f.Name, Dest: dest}
+	}
+	return s
+}
+
+// Apply moves the files of s within dir, creating the destination
+// directories. It stops at the first failure and never overwrites a file.
+func (s Suggestion) Apply(dir string) error {
+	for _, m := range s.Moves {
+		if m.Dest == DestKeep {
+			continue
+		}
+		destDir := filepath.Join(dir, filepath.FromSlash(m.Dest))
+		if err := os.MkdirAll(destDir, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", m.Dest, err)
+		}
+		dest := filepath.Join(destDir, m.Name)
+		if _, err := os.Stat(dest); err == nil {
+			return fmt.Errorf("%s/%s already exists", m.Dest, m.Name)
+		}
+		if err := os.Rename(filepath.Join(dir, m.Name), dest); err != nil {
+			return fmt.Errorf("failed to move %s: %w", m.Name, err)
+		}
+	}
+	return nil
+}
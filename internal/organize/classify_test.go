@@ -0,0 +1,87 @@
+package organize
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []FileInfo
+		want  []string // Destination per file
+	}{
+		{
+			name: "longest title is the feature",
+			files: []FileInfo{
+				{Name: "title_t00.mkv", Size: 900, DurationSecs: 600},
+				{Name: "title_t01.mkv", Size: 20000, DurationSecs: 7200},
+				{Name: "title_t02.mkv", Size: 30000, DurationSecs: 6900}, // Alternate cut
+				{Name: "title_t03.mkv", Size: 500, DurationSecs: 120},
+			},
+			want: []string{DestExtras, DestMain, DestKeep, DestExtras},
+		},
+		{
+			name: "sizes when a title couldn't be probed",
+			files: []FileInfo{
+				{Name: "title_t00.mkv", Size: 30000, DurationSecs: 7200},
+				{Name: "title_t01.mkv", Size: 900},
+			},
+			want: []string{DestMain, DestExtras},
+		},
+		{
+			name:  "single title",
+			files: []FileInfo{{Name: "title_t00.mkv", Size: 30000}},
+			want:  []string{DestMain},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Classify(tt.files)
+			if len(got.Moves) != len(tt.want) {
+				t.Fatalf("Classify() = %+v, want %d moves", got, len(tt.want))
+			}
+			for i, m := range got.Moves {
+				if m.Name != tt.files[i].Name || m.Dest != tt.want[i] {
+					t.Errorf("move %d = %+v, want %s to %q", i, m, tt.files[i].Name, tt.want[i])
+				}
+			}
+		})
+	}
+
+	if got := Classify(nil); len(got.Moves) != 0 {
+		t.Errorf("Classify(nil) = %+v, want no moves", got)
+	}
+}
+
+func TestSuggestion_Apply(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"feature.mkv", "extra.mkv", "other.mkv"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := Suggestion{Moves: []Move{
+		{Name: "feature.mkv", Dest: DestMain},
+		{Name: "extra.mkv", Dest: DestExtras},
+		{Name: "other.mkv", Dest: DestKeep},
+	}}
+	if err := s.Apply(dir); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	for _, path := range []string{"_main/feature.mkv", "_extras/other/extra.mkv", "other.mkv"} {
+		if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+			t.Errorf("expected %s: %v", path, err)
+		}
+	}
+
+	// Never overwrites an existing file
+	if err := os.WriteFile(filepath.Join(dir, "feature.mkv"), []byte("again"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := (Suggestion{Moves: []Move{{Name: "feature.mkv", Dest: DestMain}}}).Apply(dir); err == nil {
+		t.Error("expected an error moving over an existing file")
+	}
+}
@@ -0,0 +1,83 @@
+package organize
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// ApplyEpisodeOffset renames the episode files in discPath/_episodes so
+// their numbers include offset, e.g. 01.mkv becomes 03.mkv with an offset
+// of 2. Later stages take episode numbers from the file names, so this is
+// what makes an offset stick. Nothing is renamed if any new name is taken
+// or would go below episode 1.
+func ApplyEpisodeOffset(discPath string, offset int) error {
+	if offset == 0 {
+		return nil
+	}
+	dir := filepath.Join(discPath, "_episodes")
+	files, err := filepath.Glob(filepath.Join(dir, "*.mkv"))
+	if err != nil {
+		return err
+	}
+
+	type rename struct {
+		first    int
+		from, to string
+	}
+	var renames []rename
+	taken := make(map[string]bool)
+	for _, file := range files {
+		taken[filepath.Base(file)] = true
+	}
+	for _, file := range files {
+		name := filepath.Base(file)
+		m := episodePattern.FindStringSubmatchIndex(name)
+		if m == nil {
+			continue
+		}
+		first, _ := strconv.Atoi(name[m[2]:m[3]])
+		if first+offset < 1 {
+			return fmt.Errorf("%s: episode %d with offset %d is below 1", name, first, offset)
+		}
+		newName := shiftEpisode(name, m[2], m[3], offset)
+		if m[4] >= 0 {
+			newName = shiftEpisode(newName, m[4]+len(newName)-len(name), m[5]+len(newName)-len(name), offset)
+		}
+		renames = append(renames, rename{first: first, from: name, to: newName})
+	}
+
+	// Rename away from the direction of the shift, so each new name is
+	// free by the time it is used
+	sort.Slice(renames, func(i, j int) bool {
+		if offset > 0 {
+			return renames[i].first > renames[j].first
+		}
+		return renames[i].first < renames[j].first
+	})
+	moving := make(map[string]bool)
+	for _, r := range renames {
+		moving[r.from] = true
+	}
+	for _, r := range renames {
+		if taken[r.to] && !moving[r.to] {
+			return fmt.Errorf("cannot renumber %s: %s already exists", r.from, r.to)
+		}
+	}
+
+	for _, r := range renames {
+		if err := os.Rename(filepath.Join(dir, r.from), filepath.Join(dir, r.to)); err != nil {
+			return fmt.Errorf("failed to rename %s: %w", r.from, err)
+		}
+	}
+	return nil
+}
+
+// shiftEpisode adds offset to the episode number at name[start:end],
+// keeping at least its zero padding
+func shiftEpisode(name string, start, end, offset int) string {
+	n, _ := strconv.Atoi(name[start:end])
+	return name[:start] + fmt.Sprintf("%0*d", end-start, n+offset) + name[end:]
+}
@@ -0,0 +1,67 @@
+package organize
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func writeEpisodes(t *testing.T, names ...string) string {
+	t.Helper()
+	disc := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(disc, "_episodes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(disc, "_episodes", name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return disc
+}
+
+func listEpisodes(t *testing.T, disc string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(disc, "_episodes"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	return names
+}
+
+func TestApplyEpisodeOffset(t *testing.T) {
+	disc := writeEpisodes(t, "01.mkv", "02_Pilot_Part_2.mkv", "03-04.mkv", "notes.txt")
+
+	if err := ApplyEpisodeOffset(disc, 2); err != nil {
+		t.Fatalf("ApplyEpisodeOffset() error = %v", err)
+	}
+
+	want := []string{"03.mkv", "04_Pilot_Part_2.mkv", "05-06.mkv", "notes.txt"}
+	if got := listEpisodes(t, disc); !slices.Equal(got, want) {
+		t.Errorf("episodes = %v, want %v", got, want)
+	}
+	// The contents moved with the names
+	if data, _ := os.ReadFile(filepath.Join(disc, "_episodes", "03.mkv")); string(data) != "01.mkv" {
+		t.Errorf("03.mkv holds %q, want the old 01.mkv", data)
+	}
+}
+
+func TestApplyEpisodeOffset_Refusals(t *testing.T) {
+	// Shifting down would turn 01.mkv into episode 0; nothing is renamed
+	disc := writeEpisodes(t, "01.mkv", "02.mkv")
+	if err := ApplyEpisodeOffset(disc, -1); err == nil {
+		t.Error("expected an error for an offset below episode 1")
+	}
+	if got := listEpisodes(t, disc); !slices.Equal(got, []string{"01.mkv", "02.mkv"}) {
+		t.Errorf("episodes = %v, want nothing renamed", got)
+	}
+
+	if err := ApplyEpisodeOffset(writeEpisodes(t), 3); err != nil {
+		t.Errorf("empty disc error = %v", err)
+	}
+}
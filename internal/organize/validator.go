@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 )
 
 // ValidationResult holds the result of validating an organization directory
@@ -16,8 +17,26 @@ type ValidationResult struct {
 	Warnings []string
 }
 
+// EpisodeOffsetOption is the rip job option holding the number added to
+// that disc's episode numbers, so each disc can be numbered from 01. It
+// only lasts until organize completes and ApplyEpisodeOffset renames the files.
+const EpisodeOffsetOption = "episode_offset"
+
+// EpisodeOffset reads the episode offset from a rip job's options, or 0
+func EpisodeOffset(opts map[string]interface{}) int {
+	// Options round-trip through JSON, so numbers come back as float64
+	if n, ok := opts[EpisodeOffsetOption].(float64); ok {
+		return int(n)
+	}
+	return 0
+}
+
 // Validator validates that media has been organized correctly
-type Validator struct{}
+type Validator struct {
+	// EpisodeOffsets maps a disc path to the number added to its episode
+	// numbers when validating a season
+	EpisodeOffsets map[string]int
+}
 
 // ValidateMovie validates that a movie directory is properly organized
 func (v *Validator) ValidateMovie(outputDir string) ValidationResult {
@@ -120,8 +139,10 @@ func (v *Validator) ValidateTVDisc(discDir string) ValidationResult {
 	return result
 }
 
-// ValidateTVSeason validates a multi-disc TV season by checking each disc
-func (v *Validator) ValidateTVSeason(discPaths []string) ValidationResult {
+// ValidateTVSeason validates a multi-disc TV season by checking each disc.
+// If expected is positive, a season whose highest episode falls short of it
+// is invalid, which usually means a disc was never ripped.
+func (v *Validator) ValidateTVSeason(discPaths []string, expected int) ValidationResult {
 	result := ValidationResult{Valid: true}
 
 	if len(discPaths) == 0 {
@@ -130,8 +151,8 @@ func (v *Validator) ValidateTVSeason(discPaths []string) ValidationResult {
 		return result
 	}
 
-	// Validate each disc and collect all episodes
-	allEpisodes := make(map[int]bool)
+	// Validate each disc and collect all episodes, with the discs holding each
+	allEpisodes := make(map[int][]string)
 
 	for _, discPath := range discPaths {
 		discName := filepath.Base(discPath)
@@ -147,13 +168,12 @@ func (v *Validator) ValidateTVSeason(discPaths []string) ValidationResult {
 		// Collect episode numbers from this disc
 		episodesDir := filepath.Join(discPath, "_episodes")
 		files, _ := filepath.Glob(filepath.Join(episodesDir, "*.mkv"))
+		offset := v.EpisodeOffsets[discPath]
 		for _, ep := range v.parseEpisodeNumbers(files) {
-			allEpisodes[ep] = true
+			allEpisodes[ep+offset] = append(allEpisodes[ep+offset], discName)
 		}
 	}
 
-	// Check for duplicate episodes across discs (warning, not error)
-	// Check for gaps in the combined episode list
 	if len(allEpisodes) > 0 {
 		var episodes []int
 		for ep := range allEpisodes {
@@ -161,16 +181,62 @@ func (v *Validator) ValidateTVSeason(discPaths []string) ValidationResult {
 		}
 		sort.Ints(episodes)
 
+		// Check for duplicate episodes across discs (warning, not error).
+		// Usually the episodes were numbered per disc instead of across the
+		// season, and they would collide at publish.
+		for _, ep := range episodes {
+			if discs := allEpisodes[ep]; len(discs) > 1 {
+				result.Warnings = append(result.Warnings,
+					fmt.Sprintf("episode %d appears on more than one disc: %s", ep, strings.Join(discs, ", ")))
+			}
+		}
+
+		// Check for gaps in the combined episode list
+
 		if gaps := v.findGaps(episodes); len(gaps) > 0 {
 			for _, gap := range gaps {
 				result.Warnings = append(result.Warnings, fmt.Sprintf("missing episode %d across all discs", gap))
 			}
 		}
+
+		if expected > 0 {
+			highest := episodes[len(episodes)-1]
+			switch {
+			case highest < expected:
+				result.Valid = false
+				result.Errors = append(result.Errors,
+					fmt.Sprintf("expected %d episodes but the highest is %d (missing a disc?)", expected, highest))
+			case highest > expected:
+				result.Warnings = append(result.Warnings,
+					fmt.Sprintf("found episode %d but only %d expected", highest, expected))
+			}
+		}
 	}
 
 	return result
 }
 
+// AutoNumber returns the episode offset for each disc that continues the
+// numbering from the disc before it. A disc numbered from 01 gets the
+// previous disc's highest episode as its offset; a disc already numbered
+// past it gets none. Discs without episodes are skipped.
+func AutoNumber(discPaths []string) map[string]int {
+	v := &Validator{}
+	offsets := make(map[string]int)
+	highest := 0
+	for _, discPath := range discPaths {
+		files, _ := filepath.Glob(filepath.Join(discPath, "_episodes", "*.mkv"))
+		episodes := v.parseEpisodeNumbers(files)
+		if len(episodes) == 0 {
+			continue
+		}
+		offset := max(0, highest-episodes[0]+1)
+		offsets[discPath] = offset
+		highest = episodes[len(episodes)-1] + offset
+	}
+	return offsets
+}
+
 // checkRootEmpty verifies the root directory only contains underscore-prefixed directories and .rip state
 func (v *Validator) checkRootEmpty(dir string) []string {
 	var errors []string
@@ -1,8 +1,10 @@
 package organize
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -220,6 +222,157 @@ func TestValidator_ValidateTV(t *testing.T) {
 	}
 }
 
+func TestValidator_ValidateTVSeason(t *testing.T) {
+	// Two discs holding episodes 1-4
+	root := t.TempDir()
+	var discPaths []string
+	for disc, episodes := range [][]string{{"01.mkv", "02.mkv"}, {"03.mkv", "04.mkv"}} {
+		discDir := filepath.Join(root, fmt.Sprintf("Disc%d", disc+1))
+		os.MkdirAll(filepath.Join(discDir, "_episodes"), 0755)
+		for _, ep := range episodes {
+			os.WriteFile(filepath.Join(discDir, "_episodes", ep), []byte{}, 0644)
+		}
+		discPaths = append(discPaths, discDir)
+	}
+
+	tests := []struct {
+		name        string
+		expected    int
+		wantOK      bool
+		wantErr     string
+		wantWarning string
+	}{
+		{name: "no expectation", expected: 0, wantOK: true},
+		{name: "matches expectation", expected: 4, wantOK: true},
+		{name: "final episodes missing", expected: 6, wantOK: false, wantErr: "expected 6 episodes but the highest is 4"},
+		{name: "more than expected", expected: 3, wantOK: true, wantWarning: "found episode 4 but only 3 expected"},
+	}
+
+	v := &Validator{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := v.ValidateTVSeason(discPaths, tt.expected)
+			if result.Valid != tt.wantOK {
+				t.Errorf("Valid = %v, want %v (errors: %v)", result.Valid, tt.wantOK, result.Errors)
+			}
+			if tt.wantErr != "" && !slices.ContainsFunc(result.Errors, func(e string) bool { return containsSubstring(e, tt.wantErr) }) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, result.Errors)
+			}
+			if tt.wantWarning != "" && !slices.ContainsFunc(result.Warnings, func(w string) bool { return containsSubstring(w, tt.wantWarning) }) {
+				t.Errorf("expected warning containing %q, got %v", tt.wantWarning, result.Warnings)
+			}
+		})
+	}
+}
+
+func TestValidator_ValidateTVSeason_DuplicateEpisodes(t *testing.T) {
+	// Episodes numbered per disc instead of across the season
+	root := t.TempDir()
+	var discPaths []string
+	for disc := 1; disc <= 3; disc++ {
+		discDir := filepath.Join(root, fmt.Sprintf("Disc%d", disc))
+		os.MkdirAll(filepath.Join(discDir, "_episodes"), 0755)
+		episodes := []string{"01.mkv", "02.mkv"}
+		if disc == 3 {
+			episodes = []string{"03.mkv"}
+		}
+		for _, ep := range episodes {
+			os.WriteFile(filepath.Join(discDir, "_episodes", ep), []byte{}, 0644)
+		}
+		discPaths = append(discPaths, discDir)
+	}
+
+	v := &Validator{}
+	result := v.ValidateTVSeason(discPaths, 0)
+
+	want := []string{
+		"episode 1 appears on more than one disc: Disc1, Disc2",
+		"episode 2 appears on more than one disc: Disc1, Disc2",
+	}
+	if !slices.Equal(result.Warnings, want) {
+		t.Errorf("Warnings = %v, want %v", result.Warnings, want)
+	}
+	if !result.Valid {
+		t.Errorf("duplicates should only warn, got errors: %v", result.Errors)
+	}
+}
+
+// makeDiscs creates one disc directory per entry under root, each holding
+// the given episode files
+func makeDiscs(t *testing.T, discs ...[]string) []string {
+	t.Helper()
+	root := t.TempDir()
+	var discPaths []string
+	for i, episodes := range discs {
+		discDir := filepath.Join(root, fmt.Sprintf("Disc%d", i+1))
+		os.MkdirAll(filepath.Join(discDir, "_episodes"), 0755)
+		for _, ep := range episodes {
+			os.WriteFile(filepath.Join(discDir, "_episodes", ep), []byte{}, 0644)
+		}
+		discPaths = append(discPaths, discDir)
+	}
+	return discPaths
+}
+
+func TestValidator_ValidateTVSeason_EpisodeOffsets(t *testing.T) {
+	// Numbered per disc, with disc 2 starting at episode 5
+	discPaths := makeDiscs(t, []string{"01.mkv", "02.mkv", "03.mkv", "04.mkv"}, []string{"01.mkv", "02.mkv"})
+
+	v := &Validator{EpisodeOffsets: map[string]int{discPaths[1]: 4}}
+	result := v.ValidateTVSeason(discPaths, 6)
+	if !result.Valid {
+		t.Errorf("Valid = false, errors: %v", result.Errors)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("Warnings = %v, want none", result.Warnings)
+	}
+}
+
+func TestAutoNumber(t *testing.T) {
+	tests := []struct {
+		name  string
+		discs [][]string
+		want  []int
+	}{
+		{
+			name:  "numbered per disc",
+			discs: [][]string{{"01.mkv", "02.mkv", "03.mkv"}, {"01.mkv", "02.mkv"}, {"01.mkv"}},
+			want:  []int{0, 3, 5},
+		},
+		{
+			name:  "already continuous",
+			discs: [][]string{{"01.mkv", "02.mkv"}, {"03.mkv", "04.mkv"}},
+			want:  []int{0, 0},
+		},
+		{
+			name:  "mixed",
+			discs: [][]string{{"01.mkv", "02.mkv"}, {"03.mkv", "04.mkv"}, {"01-02.mkv"}},
+			want:  []int{0, 0, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			discPaths := makeDiscs(t, tt.discs...)
+			offsets := AutoNumber(discPaths)
+			for i, want := range tt.want {
+				if got := offsets[discPaths[i]]; got != want {
+					t.Errorf("disc %d offset = %d, want %d", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEpisodeOffset(t *testing.T) {
+	if got := EpisodeOffset(map[string]interface{}{EpisodeOffsetOption: float64(4)}); got != 4 {
+		t.Errorf("EpisodeOffset() = %d, want 4", got)
+	}
+	if got := EpisodeOffset(nil); got != 0 {
+		t.Errorf("EpisodeOffset(nil) = %d, want 0", got)
+	}
+}
+
 func TestValidator_ParseEpisodeNumbers(t *testing.T) {
 	tests := []struct {
 		filename string
@@ -0,0 +1,139 @@
+// Package progress streams live job progress to external consumers.
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultWebhookInterval is the minimum time between posts for a job
+const DefaultWebhookInterval = 5 * time.Second
+
+// webhookQueue is how many updates can wait to be posted before new ones
+// are dropped
+const webhookQueue = 16
+
+// Update is the JSON payload posted to the webhook
+type Update struct {
+	JobID     int64     `json:"job_id"`
+	Stage     string    `json:"stage"`
+	Percent   int       `json:"percent"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WebhookSink posts throttled progress updates to an HTTP endpoint.
+// It satisfies both ripper.ProgressSink and transcode.ProgressSink.
+// Delivery is best-effort: updates are posted in the background, and
+// failed posts or updates arriving while the queue is full are dropped, so a
+// slow or down dashboard never holds up the job. A nil *WebhookSink discards
+// all updates.
+type WebhookSink struct {
+	url      string
+	stage    string
+	interval time.Duration
+	client   *http.Client
+	updates  chan Update
+	done     chan struct{}
+
+	mu       sync.Mutex
+	closed   bool
+	lastSent map[int64]time.Time
+	lastPct  map[int64]int
+	now      func() time.Time
+}
+
+// NewWebhookSink creates a sink that posts updates for the given stage to url.
+// Returns nil if url is empty, so callers can pass the config value straight through.
+// Call Close once the job is done to deliver the remaining updates.
+func NewWebhookSink(url, stage string) *WebhookSink {
+	if url == "" {
+		return nil
+	}
+	s := &WebhookSink{
+		url:      url,
+		stage:    stage,
+		interval: DefaultWebhookInterval,
+		client:   &http.Client{Timeout: 2 * time.Second},
+		updates:  make(chan Update, webhookQueue),
+		done:     make(chan struct{}),
+		lastSent: make(map[int64]time.Time),
+		lastPct:  make(map[int64]int),
+		now:      time.Now,
+	}
+	go s.deliver()
+	return s
+}
+
+// Close stops accepting updates and waits for the queued ones to be posted
+func (s *WebhookSink) Close() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.updates)
+	}
+	s.mu.Unlock()
+	<-s.done
+}
+
+// Progress records a progress update, posting it if the throttle interval
+// has elapsed. Reaching 100% is always posted.
+func (s *WebhookSink) Progress(jobID int64, percent int) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	now := s.now()
+	last, seen := s.lastSent[jobID]
+	if percent == s.lastPct[jobID] && seen {
+		return
+	}
+	if seen && percent < 100 && now.Sub(last) < s.interval {
+		return
+	}
+
+	select {
+	case s.updates <- Update{
+		JobID:     jobID,
+		Stage:     s.stage,
+		Percent:   percent,
+		Timestamp: now.UTC(),
+	}:
+		s.lastSent[jobID] = now
+		s.lastPct[jobID] = percent
+	default:
+		// The queue is full; a later update will report newer progress
+	}
+}
+
+// deliver posts queued updates in order until the sink is closed
+func (s *WebhookSink) deliver() {
+	defer close(s.done)
+	for update := range s.updates {
+		s.post(update)
+	}
+}
+
+// post sends a single update, ignoring any error
+func (s *WebhookSink) post(update Update) {
+	body, err := json.Marshal(update)
+	if err != nil {
+		return
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
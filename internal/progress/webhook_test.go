@@ -0,0 +1,115 @@
+package progress
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cuivienor/media-pipeline/internal/ripper"
+	"github.com/cuivienor/media-pipeline/internal/transcode"
+)
+
+// WebhookSink must be usable by both stages that report progress
+var (
+	_ ripper.ProgressSink    = (*WebhookSink)(nil)
+	_ transcode.ProgressSink = (*WebhookSink)(nil)
+)
+
+func TestNewWebhookSink_EmptyURL(t *testing.T) {
+	sink := NewWebhookSink("", "rip")
+	if sink != nil {
+		t.Fatal("expected nil sink for empty URL")
+	}
+
+	// Must not panic
+	sink.Progress(1, 50)
+	sink.Close()
+}
+
+func TestWebhookSink_Progress(t *testing.T) {
+	var mu sync.Mutex
+	var received []Update
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var u Update
+		if err := json.NewDecoder(r.Body).Decode(&u); err != nil {
+			t.Errorf("failed to decode payload: %v", err)
+		}
+		mu.Lock()
+		received = append(received, u)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, "transcode")
+	clock := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time { return clock }
+
+	sink.Progress(7, 1) // sent (first update)
+	sink.Progress(7, 2) // throttled
+	sink.Progress(7, 2) // duplicate
+	clock = clock.Add(DefaultWebhookInterval)
+	sink.Progress(7, 40)  // sent (interval elapsed)
+	sink.Progress(7, 100) // sent (completion bypasses throttle)
+	sink.Progress(8, 5)   // sent (different job)
+	sink.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	want := []struct {
+		jobID   int64
+		percent int
+	}{{7, 1}, {7, 40}, {7, 100}, {8, 5}}
+
+	if len(received) != len(want) {
+		t.Fatalf("received %d updates, want %d: %+v", len(received), len(want), received)
+	}
+	for i, w := range want {
+		if received[i].JobID != w.jobID || received[i].Percent != w.percent {
+			t.Errorf("update %d = job %d %d%%, want job %d %d%%",
+				i, received[i].JobID, received[i].Percent, w.jobID, w.percent)
+		}
+		if received[i].Stage != "transcode" {
+			t.Errorf("update %d stage = %q, want %q", i, received[i].Stage, "transcode")
+		}
+	}
+}
+
+func TestWebhookSink_ServerDown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	// Failed posts are dropped without panicking or blocking the caller
+	sink := NewWebhookSink(url, "rip")
+	sink.Progress(1, 100)
+	sink.Close()
+}
+
+func TestWebhookSink_SlowServerDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL, "rip")
+	clock := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	sink.now = func() time.Time { return clock }
+
+	start := time.Now()
+	for percent := 1; percent <= 100; percent++ {
+		clock = clock.Add(DefaultWebhookInterval)
+		sink.Progress(1, percent)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Progress blocked for %s on a slow server", elapsed)
+	}
+
+	close(release)
+	sink.Close()
+}
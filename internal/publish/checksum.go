@@ -0,0 +1,74 @@
+package publish
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/cuivienor/media-pipeline/internal/transcode"
+)
+
+// filebotCopyPattern matches a file FileBot copied: [COPY] from [src] to [dst]
+var filebotCopyPattern = regexp.MustCompile(`\[COPY\] from \[([^\]]+)\] to \[([^\]]+)\]`)
+
+// parseFilebotCopies returns the destination of each file FileBot copied,
+// keyed by its source
+func parseFilebotCopies(output string) map[string]string {
+	copies := make(map[string]string)
+	for _, m := range filebotCopyPattern.FindAllStringSubmatch(output, -1) {
+		copies[m[1]] = m[2]
+	}
+	return copies
+}
+
+// transcodeChecksums returns the checksums the transcode job recorded in
+// inputDir's manifest, keyed by absolute file path. Input without a manifest,
+// e.g. published straight from a remux, has none.
+func transcodeChecksums(inputDir string) (map[string]string, error) {
+	m, err := transcode.ReadManifest(inputDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	absDir, err := filepath.Abs(inputDir)
+	if err != nil {
+		return nil, err
+	}
+	sums := make(map[string]string)
+	for _, e := range m.Files {
+		if e.Checksum != "" && e.ChecksumAlgorithm == transcode.ChecksumAlgorithm {
+			sums[filepath.Join(absDir, filepath.FromSlash(e.Path))] = e.Checksum
+		}
+	}
+	return sums, nil
+}
+
+// verifyChecksum checks dst, a copy of src, against the checksum recorded
+// for src. Files without one are taken as they are. A copy that doesn't
+// match is removed, so a retry copies it again.
+func verifyChecksum(sums map[string]string, src, dst string) error {
+	abs, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	want, ok := sums[abs]
+	if !ok {
+		return nil
+	}
+
+	got, err := transcode.FileChecksum(dst)
+	if err != nil {
+		return fmt.Errorf("failed to checksum %s: %w", dst, err)
+	}
+	if got != want {
+		os.Remove(dst)
+		return fmt.Errorf("%s does not match its transcoded source: %s %s, want %s", dst, transcode.ChecksumAlgorithm, got, want)
+	}
+	return nil
+}
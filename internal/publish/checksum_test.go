@@ -0,0 +1,72 @@
+package publish
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/transcode"
+)
+
+func TestParseFilebotCopies(t *testing.T) {
+	output := `Rename episodes using [TheTVDB]
+[COPY] from [/input/_main/01.mkv] to [/library/tv/Show/Season 01/Show - S01E01.mkv]
+[COPY] from [/input/_main/02.mkv] to [/library/tv/Show/Season 01/Show - S01E02.mkv]
+Processed 2 files`
+
+	copies := parseFilebotCopies(output)
+	if len(copies) != 2 || copies["/input/_main/02.mkv"] != "/library/tv/Show/Season 01/Show - S01E02.mkv" {
+		t.Errorf("parseFilebotCopies() = %v", copies)
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	inputDir := t.TempDir()
+	libDir := t.TempDir()
+	src := filepath.Join(inputDir, "_main", "movie.mkv")
+	os.MkdirAll(filepath.Dir(src), 0755)
+	os.WriteFile(src, []byte("transcoded"), 0644)
+
+	sum, err := transcode.FileChecksum(src)
+	if err != nil {
+		t.Fatalf("FileChecksum() error = %v", err)
+	}
+	err = transcode.WriteManifest(inputDir, &transcode.Manifest{Files: []transcode.ManifestEntry{
+		{Path: "_main/movie.mkv", Checksum: sum, ChecksumAlgorithm: transcode.ChecksumAlgorithm},
+	}})
+	if err != nil {
+		t.Fatalf("WriteManifest() error = %v", err)
+	}
+
+	sums, err := transcodeChecksums(inputDir)
+	if err != nil {
+		t.Fatalf("transcodeChecksums() error = %v", err)
+	}
+
+	good := filepath.Join(libDir, "good.mkv")
+	os.WriteFile(good, []byte("transcoded"), 0644)
+	if err := verifyChecksum(sums, src, good); err != nil {
+		t.Errorf("verifyChecksum() of a matching copy error = %v", err)
+	}
+
+	bad := filepath.Join(libDir, "bad.mkv")
+	os.WriteFile(bad, []byte("transcodeX"), 0644)
+	if err := verifyChecksum(sums, src, bad); err == nil {
+		t.Error("verifyChecksum() of a corrupt copy should fail")
+	}
+	if _, err := os.Stat(bad); !os.IsNotExist(err) {
+		t.Error("corrupt copy should be removed")
+	}
+
+	// Files the manifest doesn't cover aren't checked
+	if err := verifyChecksum(sums, filepath.Join(inputDir, "_main", "other.mkv"), bad); err != nil {
+		t.Errorf("verifyChecksum() without a recorded checksum error = %v", err)
+	}
+}
+
+func TestTranscodeChecksums_NoManifest(t *testing.T) {
+	sums, err := transcodeChecksums(t.TempDir())
+	if err != nil || sums != nil {
+		t.Errorf("transcodeChecksums() = %v, %v; want nil, nil", sums, err)
+	}
+}
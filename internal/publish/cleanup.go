@@ -0,0 +1,85 @@
+package publish
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// stagingStages are the stages whose outputs are only intermediate copies
+// once the media is in the library
+var stagingStages = map[model.Stage]bool{
+	model.StageRip:       true,
+	model.StageRemux:     true,
+	model.StageTranscode: true,
+}
+
+// StagingDirs returns the output directories of the completed rip, remux and
+// transcode jobs in jobs. When seasonID is set only that season's jobs count.
+func StagingDirs(jobs []model.Job, seasonID *int64) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, j := range jobs {
+		if !stagingStages[j.Stage] || j.Status != model.JobStatusCompleted || j.OutputDir == "" {
+			continue
+		}
+		if seasonID != nil && (j.SeasonID == nil || *j.SeasonID != *seasonID) {
+			continue
+		}
+		dir := filepath.Clean(j.OutputDir)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// CleanupStaging removes dirs, skipping with a warning any that are not
+// inside stagingBase so the rest are still cleaned up.
+// With dryRun set it only logs what would be removed.
+// Returns the directories removed (or that would be removed).
+func (p *Publisher) CleanupStaging(dirs []string, stagingBase string, dryRun bool) ([]string, error) {
+	base := filepath.Clean(stagingBase)
+	var removed []string
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(base, dir)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			p.logWarn("Not removing %s: not inside staging base %s", dir, base)
+			continue
+		}
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+
+		if dryRun {
+			p.logInfo("Would remove staging directory: %s", dir)
+			removed = append(removed, dir)
+			continue
+		}
+
+		p.logInfo("Removing staging directory: %s", dir)
+		if err := os.RemoveAll(dir); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", dir, err)
+		}
+		removed = append(removed, dir)
+	}
+	return removed, nil
+}
+
+// logInfo logs to the publisher's logger if one is set
+func (p *Publisher) logInfo(msg string, args ...any) {
+	if p.logger != nil {
+		p.logger.Info(msg, args...)
+	}
+}
+
+// logWarn logs a warning to the publisher's logger if one is set
+func (p *Publisher) logWarn(msg string, args ...any) {
+	if p.logger != nil {
+		p.logger.Warn(msg, args...)
+	}
+}
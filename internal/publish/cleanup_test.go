@@ -0,0 +1,97 @@
+package publish
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestStagingDirs(t *testing.T) {
+	s1, s2 := int64(1), int64(2)
+	jobs := []model.Job{
+		{Stage: model.StageRip, Status: model.JobStatusCompleted, SeasonID: &s1, OutputDir: "/staging/1-ripped/tv/Show/S01/Disc1"},
+		{Stage: model.StageRip, Status: model.JobStatusCompleted, SeasonID: &s1, OutputDir: "/staging/1-ripped/tv/Show/S01/Disc2"},
+		{Stage: model.StageRip, Status: model.JobStatusFailed, SeasonID: &s1, OutputDir: "/staging/1-ripped/tv/Show/S01/Disc3"},
+		{Stage: model.StageRip, Status: model.JobStatusCompleted, SeasonID: &s2, OutputDir: "/staging/1-ripped/tv/Show/S02/Disc1"},
+		{Stage: model.StageRemux, Status: model.JobStatusCompleted, SeasonID: &s1, OutputDir: "/staging/2-remuxed/tv/Show/S01"},
+		{Stage: model.StageTranscode, Status: model.JobStatusCompleted, SeasonID: &s1, OutputDir: "/staging/3-transcoded/tv/Show/S01"},
+		{Stage: model.StageTranscode, Status: model.JobStatusCompleted, SeasonID: &s1, OutputDir: "/staging/3-transcoded/tv/Show/S01/"},
+		{Stage: model.StagePublish, Status: model.JobStatusCompleted, SeasonID: &s1, OutputDir: "/library/tv/Show/Season 01"},
+	}
+
+	got := StagingDirs(jobs, &s1)
+	want := []string{
+		"/staging/1-ripped/tv/Show/S01/Disc1",
+		"/staging/1-ripped/tv/Show/S01/Disc2",
+		"/staging/2-remuxed/tv/Show/S01",
+		"/staging/3-transcoded/tv/Show/S01",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StagingDirs() = %v, want %v", got, want)
+	}
+
+	if got := StagingDirs(jobs, nil); len(got) != 5 {
+		t.Errorf("StagingDirs(nil) = %v, want all 5 completed staging dirs", got)
+	}
+}
+
+func TestPublisher_CleanupStaging(t *testing.T) {
+	staging := t.TempDir()
+	ripped := filepath.Join(staging, "1-ripped", "movies", "Movie")
+	transcoded := filepath.Join(staging, "3-transcoded", "movies", "Movie")
+	for _, dir := range []string{ripped, transcoded} {
+		os.MkdirAll(dir, 0755)
+		os.WriteFile(filepath.Join(dir, "title.mkv"), []byte("data"), 0644)
+	}
+	missing := filepath.Join(staging, "2-remuxed", "movies", "Movie")
+	dirs := []string{ripped, missing, transcoded}
+
+	pub := NewPublisher(nil, nil, PublishOptions{})
+
+	t.Run("dry run removes nothing", func(t *testing.T) {
+		removed, err := pub.CleanupStaging(dirs, staging, true)
+		if err != nil {
+			t.Fatalf("CleanupStaging() error = %v", err)
+		}
+		if len(removed) != 2 {
+			t.Errorf("removed = %v, want the 2 existing dirs", removed)
+		}
+		for _, dir := range []string{ripped, transcoded} {
+			if _, err := os.Stat(dir); err != nil {
+				t.Errorf("%s removed during dry run", dir)
+			}
+		}
+	})
+
+	t.Run("skips paths outside staging", func(t *testing.T) {
+		outside := t.TempDir()
+		removed, err := pub.CleanupStaging([]string{outside, staging}, staging, false)
+		if err != nil || len(removed) != 0 {
+			t.Errorf("CleanupStaging() = %v, %v; want nothing removed", removed, err)
+		}
+		for _, dir := range []string{outside, staging} {
+			if _, err := os.Stat(dir); err != nil {
+				t.Errorf("%s outside staging was removed", dir)
+			}
+		}
+	})
+
+	t.Run("removes directories", func(t *testing.T) {
+		// A directory outside staging first doesn't stop the rest
+		removed, err := pub.CleanupStaging(append([]string{t.TempDir()}, dirs...), staging, false)
+		if err != nil {
+			t.Fatalf("CleanupStaging() error = %v", err)
+		}
+		if len(removed) != 2 {
+			t.Errorf("removed = %v, want 2 dirs", removed)
+		}
+		for _, dir := range []string{ripped, transcoded} {
+			if _, err := os.Stat(dir); !os.IsNotExist(err) {
+				t.Errorf("%s still exists", dir)
+			}
+		}
+	})
+}
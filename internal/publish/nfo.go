@@ -0,0 +1,118 @@
+package publish
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// nfoUniqueID is a Kodi <uniqueid> element
+type nfoUniqueID struct {
+	Type    string `xml:"type,attr"`
+	Default bool   `xml:"default,attr"`
+	Value   int    `xml:",chardata"`
+}
+
+// movieNFO is a minimal Kodi/Jellyfin movie.nfo. The IDs are left out when
+// the item has none, so the media server looks the movie up itself.
+type movieNFO struct {
+	XMLName  xml.Name     `xml:"movie"`
+	Title    string       `xml:"title"`
+	UniqueID *nfoUniqueID `xml:"uniqueid,omitempty"`
+	TmdbID   int          `xml:"tmdbid,omitempty"`
+}
+
+// tvShowNFO is a minimal Kodi/Jellyfin tvshow.nfo, without IDs when the item
+// has none
+type tvShowNFO struct {
+	XMLName  xml.Name     `xml:"tvshow"`
+	Title    string       `xml:"title"`
+	UniqueID *nfoUniqueID `xml:"uniqueid,omitempty"`
+	TvdbID   int          `xml:"tvdbid,omitempty"`
+}
+
+// buildNFO returns the .nfo file name and XML content for an item
+func buildNFO(item *model.MediaItem) (string, []byte, error) {
+	var name string
+	var doc any
+	id := item.DatabaseID()
+	uniqueID := func(kind string) *nfoUniqueID {
+		if id == 0 {
+			return nil
+		}
+		return &nfoUniqueID{Type: kind, Default: true, Value: id}
+	}
+
+	switch item.Type {
+	case model.MediaTypeMovie:
+		name = "movie.nfo"
+		doc = movieNFO{
+			Title:    item.Name,
+			UniqueID: uniqueID("tmdb"),
+			TmdbID:   id,
+		}
+	case model.MediaTypeTV:
+		name = "tvshow.nfo"
+		doc = tvShowNFO{
+			Title:    item.Name,
+			UniqueID: uniqueID("tvdb"),
+			TvdbID:   id,
+		}
+	default:
+		return "", nil, fmt.Errorf("no nfo format for media type %q", item.Type)
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to encode nfo: %w", err)
+	}
+	content := []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes" ?>` + "\n")
+	content = append(content, body...)
+	content = append(content, '\n')
+	return name, content, nil
+}
+
+// nfoDir returns the directory the item's .nfo belongs in. Movies keep it
+// next to the video; tvshow.nfo goes in the show folder, which is the first
+// directory under the TV library (libraryDest is a season folder).
+func (p *Publisher) nfoDir(item *model.MediaItem, libraryDest string) string {
+	if item.Type != model.MediaTypeTV {
+		return libraryDest
+	}
+	rel, err := filepath.Rel(p.opts.LibraryTV, libraryDest)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return filepath.Dir(libraryDest)
+	}
+	return filepath.Join(p.opts.LibraryTV, strings.Split(rel, string(filepath.Separator))[0])
+}
+
+// writeNFO writes the item's .nfo sidecar, leaving an existing one alone so
+// edits made in the media server survive a re-publish. It returns the path
+// written, or "" if the file already existed.
+func (p *Publisher) writeNFO(item *model.MediaItem, libraryDest string) (string, error) {
+	name, content, err := buildNFO(item)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(p.nfoDir(item, libraryDest), name)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if os.IsExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", name, err)
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return path, nil
+}
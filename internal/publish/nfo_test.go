@@ -0,0 +1,119 @@
+package publish
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestBuildNFO(t *testing.T) {
+	tmdbID := 603
+	name, content, err := buildNFO(&model.MediaItem{Type: model.MediaTypeMovie, Name: "The Matrix & Co", TmdbID: &tmdbID})
+	if err != nil {
+		t.Fatalf("buildNFO() error = %v", err)
+	}
+	if name != "movie.nfo" {
+		t.Errorf("name = %q, want movie.nfo", name)
+	}
+	for _, want := range []string{
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes" ?>`,
+		"<movie>",
+		"<title>The Matrix &amp; Co</title>",
+		`<uniqueid type="tmdb" default="true">603</uniqueid>`,
+		"<tmdbid>603</tmdbid>",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("movie.nfo missing %q:\n%s", want, content)
+		}
+	}
+
+	tvdbID := 81189
+	name, content, err = buildNFO(&model.MediaItem{Type: model.MediaTypeTV, Name: "Breaking Bad", TvdbID: &tvdbID})
+	if err != nil {
+		t.Fatalf("buildNFO() error = %v", err)
+	}
+	if name != "tvshow.nfo" || !strings.Contains(string(content), `<uniqueid type="tvdb" default="true">81189</uniqueid>`) {
+		t.Errorf("tvshow.nfo = %s:\n%s", name, content)
+	}
+}
+
+func TestBuildNFO_NoDatabaseID(t *testing.T) {
+	_, content, err := buildNFO(&model.MediaItem{Type: model.MediaTypeMovie, Name: "Home Movies"})
+	if err != nil {
+		t.Fatalf("buildNFO() error = %v", err)
+	}
+	for _, unwanted := range []string{"<uniqueid", "<tmdbid>"} {
+		if strings.Contains(string(content), unwanted) {
+			t.Errorf("movie.nfo contains %q:\n%s", unwanted, content)
+		}
+	}
+}
+
+func TestPublisher_Publish_WritesNFO(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	libraryTV := filepath.Join(tmpDir, "library", "tv")
+	os.MkdirAll(filepath.Join(inputDir, "_main"), 0755)
+	os.WriteFile(filepath.Join(inputDir, "_main", "episode1.mkv"), []byte("test content"), 0644)
+
+	tvdbID := 67890
+	item := &model.MediaItem{Type: model.MediaTypeTV, Name: "Test Show", SafeName: "Test_Show", TvdbID: &tvdbID}
+
+	pub := NewPublisher(nil, nil, PublishOptions{
+		LibraryMovies: filepath.Join(tmpDir, "library", "movies"),
+		LibraryTV:     libraryTV,
+		WriteNFO:      true,
+	})
+	pub.SetFilebotRunner(&mockTVFilebotRunner{})
+
+	if _, err := pub.Publish(context.Background(), item, inputDir); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+
+	// tvshow.nfo belongs in the show folder, not the season folder
+	nfoPath := filepath.Join(libraryTV, "Test Show", "tvshow.nfo")
+	data, err := os.ReadFile(nfoPath)
+	if err != nil {
+		t.Fatalf("tvshow.nfo not written: %v", err)
+	}
+	if !strings.Contains(string(data), "<tvdbid>67890</tvdbid>") {
+		t.Errorf("tvshow.nfo content:\n%s", data)
+	}
+
+	// A second publish leaves an edited nfo alone
+	os.WriteFile(nfoPath, []byte("edited"), 0644)
+	if _, err := pub.Publish(context.Background(), item, inputDir); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+	if data, _ := os.ReadFile(nfoPath); string(data) != "edited" {
+		t.Errorf("existing nfo overwritten: %s", data)
+	}
+}
+
+func TestPublisher_Publish_NFODisabled(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	os.MkdirAll(filepath.Join(inputDir, "_main"), 0755)
+	os.WriteFile(filepath.Join(inputDir, "_main", "movie.mkv"), []byte("test content"), 0644)
+
+	tmdbID := 12345
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Test Movie", SafeName: "Test_Movie", TmdbID: &tmdbID}
+
+	pub := NewPublisher(nil, nil, PublishOptions{
+		LibraryMovies: filepath.Join(tmpDir, "library", "movies"),
+		LibraryTV:     filepath.Join(tmpDir, "library", "tv"),
+	})
+	mock := &mockFilebotRunner{}
+	pub.SetFilebotRunner(mock)
+
+	if _, err := pub.Publish(context.Background(), item, inputDir); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(mock.destDir, "movie.nfo")); !os.IsNotExist(err) {
+		t.Errorf("movie.nfo written without WriteNFO, stat err = %v", err)
+	}
+}
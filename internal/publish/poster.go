@@ -0,0 +1,77 @@
+package publish
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// posterName is the file name media servers pick up as local artwork
+const posterName = "poster.jpg"
+
+// FFmpegRunner executes ffmpeg commands
+type FFmpegRunner interface {
+	Run(args []string) (string, error)
+}
+
+// defaultFFmpegRunner runs the configured ffmpeg binary
+type defaultFFmpegRunner struct {
+	path string
+}
+
+func (r *defaultFFmpegRunner) Run(args []string) (string, error) {
+	cmd := exec.Command(r.path, args...)
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+// SetFFmpegRunner allows injecting a custom ffmpeg runner (for testing)
+func (p *Publisher) SetFFmpegRunner(runner FFmpegRunner) {
+	p.ffmpeg = runner
+}
+
+// buildPosterArgs constructs ffmpeg arguments to grab a single frame
+func buildPosterArgs(video, timestamp, dest string) []string {
+	return []string{
+		"-hide_banner", "-loglevel", "error",
+		"-ss", timestamp,
+		"-i", video,
+		"-frames:v", "1",
+		"-q:v", "2",
+		"-n",
+		dest,
+	}
+}
+
+// writePoster extracts a frame from the first video in libraryDest as
+// poster.jpg, next to the item's .nfo. An existing poster is left alone, as
+// it is either scraped artwork or one chosen by hand. It returns the path
+// written, or "" if a poster already existed.
+func (p *Publisher) writePoster(item *model.MediaItem, libraryDest string) (string, error) {
+	dest := filepath.Join(p.nfoDir(item, libraryDest), posterName)
+	if _, err := os.Stat(dest); err == nil {
+		return "", nil
+	}
+
+	videos, err := filepath.Glob(filepath.Join(libraryDest, "*.mkv"))
+	if err != nil {
+		return "", fmt.Errorf("failed to glob destination: %w", err)
+	}
+	if len(videos) == 0 {
+		return "", fmt.Errorf("no video in %s to take a poster from", libraryDest)
+	}
+
+	args := buildPosterArgs(videos[0], p.opts.PosterTimestamp, dest)
+	if output, err := p.ffmpeg.Run(args); err != nil {
+		os.Remove(dest)
+		return "", fmt.Errorf("ffmpeg failed: %w\nOutput: %s", err, output)
+	}
+	// ffmpeg exits cleanly when the timestamp is past the end of the video
+	if _, err := os.Stat(dest); err != nil {
+		return "", fmt.Errorf("ffmpeg wrote no frame at %s (is the video shorter?)", p.opts.PosterTimestamp)
+	}
+	return dest, nil
+}
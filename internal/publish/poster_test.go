@@ -0,0 +1,122 @@
+package publish
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// mockFFmpegRunner records calls and writes a fake frame to the output path
+type mockFFmpegRunner struct {
+	calls  [][]string
+	noFile bool
+	err    error
+}
+
+func (m *mockFFmpegRunner) Run(args []string) (string, error) {
+	m.calls = append(m.calls, args)
+	if m.err != nil {
+		return "boom", m.err
+	}
+	if !m.noFile {
+		os.WriteFile(args[len(args)-1], []byte("jpeg"), 0644)
+	}
+	return "", nil
+}
+
+func TestBuildPosterArgs(t *testing.T) {
+	got := buildPosterArgs("/lib/Movie.mkv", "00:02:00", "/lib/poster.jpg")
+	want := []string{
+		"-hide_banner", "-loglevel", "error",
+		"-ss", "00:02:00",
+		"-i", "/lib/Movie.mkv",
+		"-frames:v", "1",
+		"-q:v", "2",
+		"-n",
+		"/lib/poster.jpg",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildPosterArgs() =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestPublisher_Publish_GeneratesPoster(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	os.MkdirAll(filepath.Join(inputDir, "_main"), 0755)
+	os.WriteFile(filepath.Join(inputDir, "_main", "movie.mkv"), []byte("test content"), 0644)
+
+	tmdbID := 12345
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Test Movie", SafeName: "Test_Movie", TmdbID: &tmdbID}
+
+	pub := NewPublisher(nil, nil, PublishOptions{
+		LibraryMovies:   filepath.Join(tmpDir, "library", "movies"),
+		LibraryTV:       filepath.Join(tmpDir, "library", "tv"),
+		GeneratePoster:  true,
+		PosterTimestamp: "330",
+	})
+	filebot := &mockFilebotRunner{}
+	pub.SetFilebotRunner(filebot)
+	ffmpeg := &mockFFmpegRunner{}
+	pub.SetFFmpegRunner(ffmpeg)
+
+	if _, err := pub.Publish(context.Background(), item, inputDir); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+
+	poster := filepath.Join(filebot.destDir, "poster.jpg")
+	if _, err := os.Stat(poster); err != nil {
+		t.Fatalf("poster.jpg not written: %v", err)
+	}
+	if len(ffmpeg.calls) != 1 {
+		t.Fatalf("ffmpeg called %d times, want 1", len(ffmpeg.calls))
+	}
+	want := buildPosterArgs(filepath.Join(filebot.destDir, "movie.mkv"), "330", poster)
+	if !reflect.DeepEqual(ffmpeg.calls[0], want) {
+		t.Errorf("ffmpeg args = %v, want %v", ffmpeg.calls[0], want)
+	}
+
+	// An existing poster is kept and ffmpeg isn't run again
+	if _, err := pub.Publish(context.Background(), item, inputDir); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+	if len(ffmpeg.calls) != 1 {
+		t.Errorf("ffmpeg ran again with a poster in place")
+	}
+}
+
+func TestPublisher_Publish_PosterFailureIsNotFatal(t *testing.T) {
+	for _, ffmpeg := range []*mockFFmpegRunner{
+		{err: fmt.Errorf("exit status 1")},
+		{noFile: true},
+	} {
+		tmpDir := t.TempDir()
+		inputDir := filepath.Join(tmpDir, "input")
+		os.MkdirAll(filepath.Join(inputDir, "_main"), 0755)
+		os.WriteFile(filepath.Join(inputDir, "_main", "movie.mkv"), []byte("test content"), 0644)
+
+		tmdbID := 12345
+		item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Test Movie", SafeName: "Test_Movie", TmdbID: &tmdbID}
+
+		pub := NewPublisher(nil, nil, PublishOptions{
+			LibraryMovies:  filepath.Join(tmpDir, "library", "movies"),
+			LibraryTV:      filepath.Join(tmpDir, "library", "tv"),
+			GeneratePoster: true,
+		})
+		filebot := &mockFilebotRunner{}
+		pub.SetFilebotRunner(filebot)
+		pub.SetFFmpegRunner(ffmpeg)
+
+		if _, err := pub.Publish(context.Background(), item, inputDir); err != nil {
+			t.Fatalf("Publish error: %v", err)
+		}
+		if _, err := pub.writePoster(item, filebot.destDir); err == nil {
+			t.Errorf("writePoster() with %+v: expected error", ffmpeg)
+		}
+	}
+}
@@ -2,15 +2,21 @@ package publish
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/cuivienor/media-pipeline/internal/config"
 	"github.com/cuivienor/media-pipeline/internal/db"
 	"github.com/cuivienor/media-pipeline/internal/logging"
+	"github.com/cuivienor/media-pipeline/internal/mediainfo"
 	"github.com/cuivienor/media-pipeline/internal/model"
 )
 
@@ -40,10 +46,40 @@ var extrasTypes = []string{
 	"other",
 }
 
+// DefaultCopyBufferSize is the buffer used for copying extras, large enough
+// to keep network filesystems busy
+const DefaultCopyBufferSize = 1 << 20
+
+// DefaultMin4KWidth is the video width from which a movie counts as 4K, below
+// 3840 so scope films cropped to e.g. 3836x1604 still do
+const DefaultMin4KWidth = 3200
+
 // PublishOptions configures the publisher
 type PublishOptions struct {
 	LibraryMovies string // Destination for movies
 	LibraryTV     string // Destination for TV shows
+
+	// LibraryMovies4K receives movies whose video is at least Min4KWidth
+	// (default DefaultMin4KWidth) pixels wide, as measured with FFprobePath.
+	// Empty publishes every movie to LibraryMovies.
+	LibraryMovies4K string
+	Min4KWidth      int
+	FFprobePath     string // default "ffprobe"
+
+	MovieFormat string // FileBot format for movies (default config.DefaultMovieFormat)
+	TVFormat    string // FileBot format for TV shows (default config.DefaultTVFormat)
+	WriteNFO    bool   // Write movie.nfo/tvshow.nfo sidecars for media servers
+
+	GeneratePoster  bool   // Extract a frame as poster.jpg when the item has none
+	PosterTimestamp string // Where to take the poster frame (default config.DefaultPosterTimestamp)
+	FFmpegPath      string // ffmpeg binary used for the poster (default "ffmpeg")
+
+	// CopyRateLimit caps the copy of extras in bytes per second (0 = unlimited).
+	// Main content is copied by FileBot and isn't throttled.
+	CopyRateLimit int64
+
+	CopyBufferSize int  // Buffer for copying extras (default DefaultCopyBufferSize)
+	PreservePerms  bool // Give copied extras their source's permissions instead of the umask default
 }
 
 // ExtraDir represents an extras directory found in the input
@@ -59,15 +95,45 @@ type Publisher struct {
 	logger  *logging.Logger
 	opts    PublishOptions
 	filebot FilebotRunner // Injectable for testing
+	ffmpeg  FFmpegRunner  // Injectable for testing
+
+	// probe reads a file's streams; replaced in tests to avoid ffprobe
+	probe func(path string) (*mediainfo.MediaInfo, error)
 }
 
 // NewPublisher creates a new Publisher
+// Empty format strings fall back to config.DefaultMovieFormat and config.DefaultTVFormat
+// An empty PosterTimestamp falls back to config.DefaultPosterTimestamp
+// A zero CopyBufferSize falls back to DefaultCopyBufferSize
+// A zero Min4KWidth falls back to DefaultMin4KWidth
 func NewPublisher(repo db.Repository, logger *logging.Logger, opts PublishOptions) *Publisher {
+	if opts.MovieFormat == "" {
+		opts.MovieFormat = config.DefaultMovieFormat
+	}
+	if opts.TVFormat == "" {
+		opts.TVFormat = config.DefaultTVFormat
+	}
+	if opts.PosterTimestamp == "" {
+		opts.PosterTimestamp = config.DefaultPosterTimestamp
+	}
+	if opts.FFmpegPath == "" {
+		opts.FFmpegPath = "ffmpeg"
+	}
+	if opts.CopyBufferSize <= 0 {
+		opts.CopyBufferSize = DefaultCopyBufferSize
+	}
+	if opts.Min4KWidth <= 0 {
+		opts.Min4KWidth = DefaultMin4KWidth
+	}
 	return &Publisher{
 		repo:    repo,
 		logger:  logger,
 		opts:    opts,
 		filebot: &defaultFilebotRunner{},
+		ffmpeg:  &defaultFFmpegRunner{path: opts.FFmpegPath},
+		probe: func(path string) (*mediainfo.MediaInfo, error) {
+			return mediainfo.ProbeWith(opts.FFprobePath, path)
+		},
 	}
 }
 
@@ -76,31 +142,79 @@ func (p *Publisher) SetFilebotRunner(runner FilebotRunner) {
 	p.filebot = runner
 }
 
-// buildFilebotArgs constructs FileBot CLI arguments
-func (p *Publisher) buildFilebotArgs(inputDir string, mediaType string, dbID int) []string {
-	var db, output, format string
+// Library returns the library an item is published to. With a 4K movies
+// library configured, a movie's transcoded files in inputDir/_main are
+// probed to choose between it and the movies library.
+func (p *Publisher) Library(item *model.MediaItem, inputDir string) (string, error) {
+	if item.Type == model.MediaTypeTV {
+		return p.opts.LibraryTV, nil
+	}
+	if p.opts.LibraryMovies4K == "" {
+		return p.opts.LibraryMovies, nil
+	}
+
+	width, err := p.videoWidth(filepath.Join(inputDir, "_main"))
+	if err != nil {
+		return "", fmt.Errorf("failed to detect resolution: %w", err)
+	}
+	if width >= p.opts.Min4KWidth {
+		if p.logger != nil {
+			p.logger.Info("Video is %d pixels wide, publishing to the 4K library", width)
+		}
+		return p.opts.LibraryMovies4K, nil
+	}
+	return p.opts.LibraryMovies, nil
+}
+
+// videoWidth returns the width of the widest video stream in dir's MKV files
+func (p *Publisher) videoWidth(dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.mkv"))
+	if err != nil {
+		return 0, err
+	}
+	if len(files) == 0 {
+		return 0, fmt.Errorf("no MKV files in %s", dir)
+	}
+
+	width := 0
+	for _, f := range files {
+		info, err := p.probe(f)
+		if err != nil {
+			return 0, err
+		}
+		for _, s := range info.Video() {
+			width = max(width, s.Width)
+		}
+	}
+	return width, nil
+}
 
+// buildFilebotArgs constructs FileBot CLI arguments copying into library
+func (p *Publisher) buildFilebotArgs(inputDir string, mediaType string, dbID int, library string) []string {
+	db := "TheTVDB"
 	if mediaType == "movie" {
 		db = "TheMovieDB"
-		output = p.opts.LibraryMovies
-		format = "{n} ({y})/{n} ({y})"
-	} else {
-		db = "TheTVDB"
-		output = p.opts.LibraryTV
-		format = "{n}/Season {s.pad(2)}/{n} - {s00e00} - {t}"
 	}
 
 	return []string{
 		"-rename", inputDir,
 		"--db", db,
 		"--q", fmt.Sprintf("%d", dbID),
-		"--output", output,
-		"--format", format,
+		"--output", library,
+		"--format", p.formatFor(mediaType),
 		"-non-strict",
 		"--action", "copy",
 	}
 }
 
+// formatFor returns the FileBot format string for a media type
+func (p *Publisher) formatFor(mediaType string) string {
+	if mediaType == "movie" {
+		return p.opts.MovieFormat
+	}
+	return p.opts.TVFormat
+}
+
 // findExtras scans for Jellyfin-compatible extras in _extras/<type>/
 func (p *Publisher) findExtras(inputDir string) []ExtraDir {
 	var extras []ExtraDir
@@ -141,8 +255,40 @@ func parseFilebotDestination(output string) string {
 	return ""
 }
 
-// copyExtras copies extras directories to the library destination
-func (p *Publisher) copyExtras(extras []ExtraDir, libraryDest string) (int, error) {
+// Errors for known FileBot failure modes
+var (
+	ErrNoFiles = errors.New("filebot found no files to process")
+	ErrNoMatch = errors.New("filebot could not identify the media (check the TMDB/TVDB ID)")
+	ErrLicense = errors.New("filebot license is missing or invalid")
+	ErrFetch   = errors.New("filebot could not reach the metadata service (retry the publish)")
+)
+
+// filebotErrorPatterns maps FileBot output fragments to typed errors.
+// License problems are checked first since they prevent any other processing,
+// then fetch failures, which FileBot follows with its no-match messages.
+var filebotErrorPatterns = []struct {
+	pattern *regexp.Regexp
+	err     error
+}{
+	{regexp.MustCompile(`(?i)license (error|expired|required|invalid)|invalid license|requires a valid license`), ErrLicense},
+	{regexp.MustCompile(`(?i)failed to fetch`), ErrFetch},
+	{regexp.MustCompile(`(?i)no files selected for (rename|processing)`), ErrNoFiles},
+	{regexp.MustCompile(`(?i)failed to identify|unable to identify|no (episode|movie) information`), ErrNoMatch},
+}
+
+// parseFilebotError maps FileBot output to a typed error, or nil if no known failure is found
+func parseFilebotError(output string) error {
+	for _, p := range filebotErrorPatterns {
+		if p.pattern.MatchString(output) {
+			return p.err
+		}
+	}
+	return nil
+}
+
+// copyExtras copies extras directories to the library destination, checking
+// each copy against sums
+func (p *Publisher) copyExtras(extras []ExtraDir, libraryDest string, sums map[string]string) (int, error) {
 	copied := 0
 
 	for _, extra := range extras {
@@ -153,32 +299,72 @@ func (p *Publisher) copyExtras(extras []ExtraDir, libraryDest string) (int, erro
 
 		for _, srcFile := range extra.Files {
 			dstFile := filepath.Join(destDir, filepath.Base(srcFile))
-			if err := copyFile(srcFile, dstFile); err != nil {
+			start := time.Now()
+			written, err := copyFile(srcFile, dstFile, p.opts.CopyBufferSize, p.opts.CopyRateLimit, p.opts.PreservePerms)
+			if err != nil {
 				return copied, fmt.Errorf("failed to copy %s: %w", srcFile, err)
 			}
+			if err := verifyChecksum(sums, srcFile, dstFile); err != nil {
+				return copied, err
+			}
 			copied++
+			if p.logger != nil {
+				elapsed := time.Since(start)
+				p.logger.Info("Copied %s/%s: %dMB in %s (%.1f MB/s)", extra.Type, filepath.Base(srcFile),
+					written/(1024*1024), elapsed.Round(time.Millisecond), float64(written)/(1024*1024)/elapsed.Seconds())
+			}
 		}
 	}
 
 	return copied, nil
 }
 
-// copyFile copies a single file
-func copyFile(src, dst string) error {
+// copyFile copies a single file through a bufSize buffer, at most rateLimit
+// bytes per second if it is positive, and returns the bytes written. The copy
+// keeps the source's modification time, so media servers sort it by when it
+// was made, and with preservePerms its permissions. A copy that comes up
+// short, including one that filled the disk, is removed.
+func copyFile(src, dst string, bufSize int, rateLimit int64, preservePerms bool) (int64, error) {
 	srcF, err := os.Open(src)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer srcF.Close()
 
+	info, err := srcF.Stat()
+	if err != nil {
+		return 0, err
+	}
+
 	dstF, err := os.Create(dst)
 	if err != nil {
-		return err
+		return 0, err
 	}
-	defer dstF.Close()
 
-	_, err = dstF.ReadFrom(srcF)
-	return err
+	// Hide ReadFrom/WriteTo so io.CopyBuffer uses buf
+	buf := make([]byte, max(bufSize, 1))
+	written, err := io.CopyBuffer(struct{ io.Writer }{dstF}, struct{ io.Reader }{newRateLimitedReader(srcF, rateLimit)}, buf)
+	// Network filesystems may only report write errors on close
+	if closeErr := dstF.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && written != info.Size() {
+		err = fmt.Errorf("short copy: wrote %d of %d bytes", written, info.Size())
+	}
+	if errors.Is(err, syscall.ENOSPC) {
+		err = fmt.Errorf("destination disk is full after %d of %d bytes: %w", written, info.Size(), err)
+	}
+	if err == nil {
+		err = os.Chtimes(dst, time.Time{}, info.ModTime())
+	}
+	if err == nil && preservePerms {
+		err = os.Chmod(dst, info.Mode().Perm())
+	}
+	if err != nil {
+		os.Remove(dst)
+		return written, err
+	}
+	return written, nil
 }
 
 // verifyFiles checks that files exist in the destination directory
@@ -222,29 +408,53 @@ func (p *Publisher) Publish(ctx context.Context, item *model.MediaItem, inputDir
 
 	mediaType := string(item.Type)
 
+	library, err := p.Library(item, inputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Copies are checked against the checksums taken when transcoding
+	sums, err := transcodeChecksums(inputDir)
+	if err != nil && p.logger != nil {
+		p.logger.Warn("Copies won't be checksummed: %v", err)
+	}
+
 	// Transcode outputs to _main/ subdirectory - use that for FileBot
 	mainDir := filepath.Join(inputDir, "_main")
 
 	// Run FileBot on main content
-	args := p.buildFilebotArgs(mainDir, mediaType, dbID)
+	args := p.buildFilebotArgs(mainDir, mediaType, dbID, library)
 	if p.logger != nil {
 		p.logger.Info("Running FileBot: filebot %s", strings.Join(args, " "))
 	}
 
 	output, err := p.runFilebot(args)
 	if err != nil {
+		if fbErr := parseFilebotError(output); fbErr != nil {
+			return nil, fmt.Errorf("filebot failed: %w\nOutput: %s", fbErr, output)
+		}
 		return nil, fmt.Errorf("filebot failed: %w\nOutput: %s", err, output)
 	}
 
 	// Parse destination from output
 	libraryDest := parseFilebotDestination(output)
 	if libraryDest == "" {
+		// FileBot may exit cleanly without copying anything
+		if fbErr := parseFilebotError(output); fbErr != nil {
+			return nil, fmt.Errorf("filebot failed: %w\nOutput: %s", fbErr, output)
+		}
 		return nil, fmt.Errorf("failed to determine library destination from FileBot output")
 	}
 
 	// Count main files copied
 	mainCount := strings.Count(output, "[COPY]")
 
+	for src, dst := range parseFilebotCopies(output) {
+		if err := verifyChecksum(sums, src, dst); err != nil {
+			return nil, fmt.Errorf("verification failed: %w", err)
+		}
+	}
+
 	// Find and copy extras
 	extras := p.findExtras(inputDir)
 	extrasCount := 0
@@ -252,7 +462,7 @@ func (p *Publisher) Publish(ctx context.Context, item *model.MediaItem, inputDir
 		if p.logger != nil {
 			p.logger.Info("Found %d extras directories", len(extras))
 		}
-		extrasCount, err = p.copyExtras(extras, libraryDest)
+		extrasCount, err = p.copyExtras(extras, libraryDest, sums)
 		if err != nil {
 			return nil, fmt.Errorf("failed to copy extras: %w", err)
 		}
@@ -263,6 +473,30 @@ func (p *Publisher) Publish(ctx context.Context, item *model.MediaItem, inputDir
 		return nil, fmt.Errorf("verification failed: %w", err)
 	}
 
+	// The media is already in place, so a missing sidecar is only a warning
+	if p.opts.WriteNFO {
+		nfoPath, err := p.writeNFO(item, libraryDest)
+		if p.logger != nil {
+			switch {
+			case err != nil:
+				p.logger.Warn("Failed to write nfo: %v", err)
+			case nfoPath != "":
+				p.logger.Info("Wrote %s", nfoPath)
+			}
+		}
+	}
+	if p.opts.GeneratePoster {
+		posterPath, err := p.writePoster(item, libraryDest)
+		if p.logger != nil {
+			switch {
+			case err != nil:
+				p.logger.Warn("Failed to generate poster: %v", err)
+			case posterPath != "":
+				p.logger.Info("Wrote %s", posterPath)
+			}
+		}
+	}
+
 	return &PublishResult{
 		LibraryPath:   libraryDest,
 		MainFiles:     mainCount,
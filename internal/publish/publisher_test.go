@@ -2,13 +2,17 @@ package publish
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/cuivienor/media-pipeline/internal/config"
 	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/mediainfo"
 	"github.com/cuivienor/media-pipeline/internal/model"
 )
 
@@ -37,7 +41,7 @@ func TestPublisher_BuildFilebotArgs_Movie(t *testing.T) {
 		LibraryMovies: "/mnt/media/library/movies",
 	})
 
-	args := p.buildFilebotArgs("/input/dir", "movie", 12345)
+	args := p.buildFilebotArgs("/input/dir", "movie", 12345, p.opts.LibraryMovies)
 
 	expected := []string{
 		"-rename", "/input/dir",
@@ -59,7 +63,7 @@ func TestPublisher_BuildFilebotArgs_TV(t *testing.T) {
 		LibraryTV: "/mnt/media/library/tv",
 	})
 
-	args := p.buildFilebotArgs("/input/dir", "tv", 67890)
+	args := p.buildFilebotArgs("/input/dir", "tv", 67890, p.opts.LibraryTV)
 
 	expected := []string{
 		"-rename", "/input/dir",
@@ -76,6 +80,33 @@ func TestPublisher_BuildFilebotArgs_TV(t *testing.T) {
 	}
 }
 
+func TestPublisher_BuildFilebotArgs_CustomFormat(t *testing.T) {
+	p := NewPublisher(nil, nil, PublishOptions{
+		LibraryMovies: "/mnt/media/library/movies",
+		MovieFormat:   "{n} ({y}) {edition}/{n}",
+	})
+
+	args := p.buildFilebotArgs("/input/dir", "movie", 12345, p.opts.LibraryMovies)
+
+	var format string
+	for i, arg := range args {
+		if arg == "--format" && i+1 < len(args) {
+			format = args[i+1]
+		}
+	}
+	if format != "{n} ({y}) {edition}/{n}" {
+		t.Errorf("--format = %q, want custom movie format", format)
+	}
+}
+
+func TestNewPublisher_EmptyFormatUsesDefault(t *testing.T) {
+	p := NewPublisher(nil, nil, PublishOptions{})
+
+	if p.formatFor("movie") != config.DefaultMovieFormat {
+		t.Errorf("formatFor(movie) = %q, want config.DefaultMovieFormat", p.formatFor("movie"))
+	}
+}
+
 func TestPublisher_FindExtras(t *testing.T) {
 	// Create temp directory structure matching transcode output (_extras/<type>/)
 	dir := t.TempDir()
@@ -105,6 +136,111 @@ func TestPublisher_RunFilebot_ParsesOutput(t *testing.T) {
 	}
 }
 
+func TestParseFilebotError(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   error
+	}{
+		{
+			name:   "no files selected",
+			output: "Rename movies using [TheMovieDB]\nNo files selected for rename\nFailure (°_°)",
+			want:   ErrNoFiles,
+		},
+		{
+			name:   "no files selected for processing",
+			output: "Exception: No files selected for processing",
+			want:   ErrNoFiles,
+		},
+		{
+			name:   "failed to identify",
+			output: "Rename episodes using [TheTVDB]\nFailed to identify or process any files",
+			want:   ErrNoMatch,
+		},
+		{
+			name:   "metadata fetch failed",
+			output: "Rename movies using [TheMovieDB]\nFailed to fetch resource: Connection timed out\nFailed to identify or process any files",
+			want:   ErrFetch,
+		},
+		{
+			name:   "license error",
+			output: "License Error: UNREGISTERED\nFileBot requires a valid license",
+			want:   ErrLicense,
+		},
+		{
+			name:   "unknown failure",
+			output: "java.lang.OutOfMemoryError",
+			want:   nil,
+		},
+		{
+			name:   "successful output",
+			output: "[COPY] from [/input/Movie.mkv] to [/library/movies/Movie (2024)/Movie (2024).mkv]",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseFilebotError(tt.output); got != tt.want {
+				t.Errorf("parseFilebotError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCopyFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.mkv")
+	data := []byte("larger than the buffer")
+	if err := os.WriteFile(src, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(src, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(dir, "dst.mkv")
+	written, err := copyFile(src, dst, 4, 0, false)
+	if err != nil {
+		t.Fatalf("copyFile() error = %v", err)
+	}
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != int64(len(data)) || string(got) != string(data) {
+		t.Errorf("copyFile() wrote %d bytes %q, want %q", written, got, data)
+	}
+	info, err := os.Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(mtime) {
+		t.Errorf("copy mtime = %s, want the source's %s", info.ModTime(), mtime)
+	}
+	if info.Mode().Perm() == 0600 {
+		t.Error("copy kept the source permissions without preservePerms")
+	}
+
+	kept := filepath.Join(dir, "kept.mkv")
+	if _, err := copyFile(src, kept, 4, 0, true); err != nil {
+		t.Fatalf("copyFile() error = %v", err)
+	}
+	if info, err := os.Stat(kept); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("copy with preservePerms = %v, %v, want mode 0600", info, err)
+	}
+
+	// A failed copy doesn't leave a partial file behind
+	failed := filepath.Join(dir, "failed.mkv")
+	if _, err := copyFile(dir, failed, 4, 0, false); err == nil {
+		t.Error("copyFile() of a directory should fail")
+	}
+	if _, err := os.Stat(failed); !os.IsNotExist(err) {
+		t.Errorf("partial copy left behind: %v", err)
+	}
+}
+
 func TestPublisher_CopyExtras(t *testing.T) {
 	srcDir := t.TempDir()
 	dstDir := t.TempDir()
@@ -120,7 +256,7 @@ func TestPublisher_CopyExtras(t *testing.T) {
 		Files: []string{filepath.Join(srcDir, "featurettes", "making_of.mkv")},
 	}}
 
-	copied, err := p.copyExtras(extras, dstDir)
+	copied, err := p.copyExtras(extras, dstDir, nil)
 	if err != nil {
 		t.Errorf("copyExtras failed: %v", err)
 	}
@@ -174,6 +310,98 @@ func TestPublisher_Publish_RequiresDatabaseID(t *testing.T) {
 	}
 }
 
+// failingFilebotRunner returns fixed output and error
+type failingFilebotRunner struct {
+	output string
+	err    error
+}
+
+func (m *failingFilebotRunner) Run(args []string) (string, error) {
+	return m.output, m.err
+}
+
+func TestPublisher_Publish_TypedFilebotError(t *testing.T) {
+	tmdbID := 12345
+	item := &MediaItem{
+		Type:     MediaTypeMovie,
+		Name:     "Test Movie",
+		SafeName: "Test_Movie",
+		TmdbID:   &tmdbID,
+	}
+
+	p := NewPublisher(nil, nil, PublishOptions{LibraryMovies: t.TempDir()})
+	p.SetFilebotRunner(&failingFilebotRunner{
+		output: "Failed to identify or process any files",
+		err:    fmt.Errorf("exit status 1"),
+	})
+
+	_, err := p.Publish(context.Background(), item, t.TempDir())
+	if !errors.Is(err, ErrNoMatch) {
+		t.Errorf("expected ErrNoMatch, got: %v", err)
+	}
+}
+
+func TestPublisher_Library(t *testing.T) {
+	inputDir := t.TempDir()
+	os.MkdirAll(filepath.Join(inputDir, "_main"), 0755)
+	os.WriteFile(filepath.Join(inputDir, "_main", "movie.mkv"), []byte("test"), 0644)
+
+	movie := &MediaItem{Type: MediaTypeMovie, Name: "Test Movie"}
+	show := &MediaItem{Type: MediaTypeTV, Name: "Test Show"}
+
+	tests := []struct {
+		name      string
+		item      *MediaItem
+		library4K string
+		width     int
+		want      string
+	}{
+		{"no 4K library", movie, "", 3840, "/movies"},
+		{"UHD movie", movie, "/movies-4k", 3840, "/movies-4k"},
+		{"cropped UHD movie", movie, "/movies-4k", 3836, "/movies-4k"},
+		{"HD movie", movie, "/movies-4k", 1920, "/movies"},
+		{"TV show", show, "/movies-4k", 3840, "/tv"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPublisher(nil, nil, PublishOptions{
+				LibraryMovies:   "/movies",
+				LibraryTV:       "/tv",
+				LibraryMovies4K: tt.library4K,
+			})
+			p.probe = func(path string) (*mediainfo.MediaInfo, error) {
+				return &mediainfo.MediaInfo{Streams: []mediainfo.Stream{
+					{Type: mediainfo.StreamVideo, Width: tt.width},
+				}}, nil
+			}
+
+			got, err := p.Library(tt.item, inputDir)
+			if err != nil {
+				t.Fatalf("Library() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Library() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublisher_Library_ProbeFailure(t *testing.T) {
+	inputDir := t.TempDir()
+	os.MkdirAll(filepath.Join(inputDir, "_main"), 0755)
+	os.WriteFile(filepath.Join(inputDir, "_main", "movie.mkv"), []byte("test"), 0644)
+
+	p := NewPublisher(nil, nil, PublishOptions{LibraryMovies: "/movies", LibraryMovies4K: "/movies-4k"})
+	p.probe = func(path string) (*mediainfo.MediaInfo, error) {
+		return nil, errors.New("ffprobe not found")
+	}
+
+	// Guessing would put the movie in the wrong library, so it must fail
+	if _, err := p.Library(&MediaItem{Type: MediaTypeMovie}, inputDir); err == nil {
+		t.Error("expected error when the resolution can't be detected")
+	}
+}
+
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && containsAt(s, substr))
 }
@@ -224,7 +452,7 @@ func (m *mockFilebotRunner) Run(args []string) (string, error) {
 	var output string
 	for _, src := range files {
 		dst := filepath.Join(destDir, filepath.Base(src))
-		if err := copyFile(src, dst); err != nil {
+		if _, err := copyFile(src, dst, DefaultCopyBufferSize, 0, false); err != nil {
 			return "", err
 		}
 		output += fmt.Sprintf("[COPY] from [%s] to [%s]\n", src, dst)
@@ -270,7 +498,7 @@ func (m *mockTVFilebotRunner) Run(args []string) (string, error) {
 	for i, src := range files {
 		episodeNum := i + 1
 		dst := filepath.Join(destDir, fmt.Sprintf("Test Show - S01E%02d - Episode.mkv", episodeNum))
-		if err := copyFile(src, dst); err != nil {
+		if _, err := copyFile(src, dst, DefaultCopyBufferSize, 0, false); err != nil {
 			return "", err
 		}
 		output += fmt.Sprintf("[COPY] from [%s] to [%s]\n", src, dst)
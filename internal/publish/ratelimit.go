@@ -0,0 +1,56 @@
+package publish
+
+import (
+	"io"
+	"time"
+)
+
+// rateLimitedReader throttles reads from r to rate bytes per second with a
+// token bucket holding up to one second of tokens
+type rateLimitedReader struct {
+	r      io.Reader
+	rate   float64   // Bytes per second
+	tokens float64   // Bytes that may be read without waiting; negative is debt
+	last   time.Time // When tokens were last refilled
+
+	sleep func(time.Duration) // time.Sleep, injectable for testing
+	now   func() time.Time    // time.Now, injectable for testing
+}
+
+// newRateLimitedReader wraps r to read at most rate bytes per second.
+// A rate of zero or less returns r unchanged.
+func newRateLimitedReader(r io.Reader, rate int64) io.Reader {
+	if rate <= 0 {
+		return r
+	}
+	return &rateLimitedReader{
+		r:      r,
+		rate:   float64(rate),
+		tokens: float64(rate),
+		last:   time.Now(),
+		sleep:  time.Sleep,
+		now:    time.Now,
+	}
+}
+
+// Read reads at most one bucket's worth, then waits off any debt so the
+// average rate stays under the limit
+func (l *rateLimitedReader) Read(p []byte) (int, error) {
+	if burst := int(l.rate); len(p) > burst {
+		p = p[:burst]
+	}
+	n, err := l.r.Read(p)
+
+	now := l.now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+
+	l.tokens -= float64(n)
+	if l.tokens < 0 {
+		l.sleep(time.Duration(-l.tokens / l.rate * float64(time.Second)))
+	}
+	return n, err
+}
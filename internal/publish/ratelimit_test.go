@@ -0,0 +1,35 @@
+package publish
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestRateLimitedReader(t *testing.T) {
+	src := bytes.NewReader(make([]byte, 3000))
+	if r := newRateLimitedReader(src, 0); r != io.Reader(src) {
+		t.Error("a zero rate should not wrap the reader")
+	}
+
+	// The clock only moves while the reader sleeps
+	clock := time.Unix(0, 0)
+	var slept time.Duration
+	r := newRateLimitedReader(src, 1000).(*rateLimitedReader)
+	r.last = clock
+	r.now = func() time.Time { return clock }
+	r.sleep = func(d time.Duration) {
+		slept += d
+		clock = clock.Add(d)
+	}
+
+	n, err := io.Copy(io.Discard, r)
+	if err != nil || n != 3000 {
+		t.Fatalf("io.Copy() = %d, %v, want 3000 bytes", n, err)
+	}
+	// The first second's worth is the initial burst
+	if slept != 2*time.Second {
+		t.Errorf("slept %s, want 2s for 3000 bytes at 1000 bytes/s", slept)
+	}
+}
@@ -0,0 +1,102 @@
+package remux
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Manifest location inside a remux output directory
+const (
+	ManifestDir  = ".remux"
+	ManifestName = "manifest.json"
+)
+
+// LanguagesOption is the remux job option recording the languages the job
+// kept, so a later config change can be told apart from the job's choice
+const LanguagesOption = "languages"
+
+// Manifest records the track languages of a remux job's output, as a
+// provenance trail for its track selection
+type Manifest struct {
+	JobID          int64           `json:"job_id"`
+	CompletedAt    time.Time       `json:"completed_at"`
+	RequestedLangs []string        `json:"requested_langs"`
+	Files          []ManifestEntry `json:"files"`
+}
+
+// ManifestEntry describes the languages of one remuxed file
+type ManifestEntry struct {
+	Path          string   `json:"path"` // Relative to the output directory
+	FoundLangs    []string `json:"found_langs"`
+	AudioLangs    []string `json:"audio_langs"`
+	SubtitleLangs []string `json:"subtitle_langs"`
+	MissingLangs  []string `json:"missing_langs,omitempty"`
+	TracksRemoved int      `json:"tracks_removed"`
+	AudioFallback bool     `json:"audio_fallback,omitempty"`
+}
+
+// ManifestPath returns the manifest path for a remux output directory
+func ManifestPath(outputDir string) string {
+	return filepath.Join(outputDir, ManifestDir, ManifestName)
+}
+
+// BuildManifest lists the languages requested, found and kept for each of
+// results, with paths relative to outputDir
+func BuildManifest(jobID int64, outputDir string, languages []string, results []RemuxResult) *Manifest {
+	m := &Manifest{
+		JobID:          jobID,
+		CompletedAt:    time.Now().UTC(),
+		RequestedLangs: languages,
+		Files:          []ManifestEntry{},
+	}
+	for _, r := range results {
+		path, err := filepath.Rel(outputDir, r.OutputPath)
+		if err != nil {
+			path = r.OutputPath
+		}
+		m.Files = append(m.Files, ManifestEntry{
+			Path:          filepath.ToSlash(path),
+			FoundLangs:    r.FoundLangs,
+			AudioLangs:    r.OutputAudioLangs,
+			SubtitleLangs: r.OutputSubtitleLangs,
+			MissingLangs:  r.MissingLanguages(languages),
+			TracksRemoved: r.TracksRemoved,
+			AudioFallback: r.AudioFallback,
+		})
+	}
+	return m
+}
+
+// WriteManifest writes m to outputDir/.remux/manifest.json
+func WriteManifest(outputDir string, m *Manifest) error {
+	path := ManifestPath(outputDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadManifest reads the manifest from a remux output directory
+func ReadManifest(outputDir string) (*Manifest, error) {
+	data, err := os.ReadFile(ManifestPath(outputDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return &m, nil
+}
@@ -1,9 +1,11 @@
 package remux
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"sort"
 	"strings"
 )
 
@@ -14,6 +16,7 @@ type Track struct {
 	Codec    string
 	Language string
 	Title    string
+	Channels int // Audio only, 0 if unknown
 	Forced   bool
 	Default  bool
 }
@@ -23,6 +26,7 @@ type TrackInfo struct {
 	Video     []Track
 	Audio     []Track
 	Subtitles []Track
+	Chapters  int // Chapter entries across all editions
 }
 
 // mkvmergeJSON represents the JSON output from mkvmerge -J
@@ -35,12 +39,16 @@ type mkvmergeJSON struct {
 		Type       string `json:"type"`
 		Codec      string `json:"codec"`
 		Properties struct {
-			Language     string `json:"language"`
-			TrackName    string `json:"track_name"`
-			ForcedTrack  bool   `json:"forced_track"`
-			DefaultTrack bool   `json:"default_track"`
+			Language      string `json:"language"`
+			TrackName     string `json:"track_name"`
+			AudioChannels int    `json:"audio_channels"`
+			ForcedTrack   bool   `json:"forced_track"`
+			DefaultTrack  bool   `json:"default_track"`
 		} `json:"properties"`
 	} `json:"tracks"`
+	Chapters []struct {
+		NumEntries int `json:"num_entries"`
+	} `json:"chapters"`
 }
 
 // GetTrackInfo runs mkvmerge -J on a file and returns parsed track info
@@ -69,6 +77,7 @@ func ParseTrackInfo(jsonData []byte) (*TrackInfo, error) {
 			Codec:    t.Codec,
 			Language: t.Properties.Language,
 			Title:    t.Properties.TrackName,
+			Channels: t.Properties.AudioChannels,
 			Forced:   t.Properties.ForcedTrack,
 			Default:  t.Properties.DefaultTrack,
 		}
@@ -82,6 +91,9 @@ func ParseTrackInfo(jsonData []byte) (*TrackInfo, error) {
 			info.Subtitles = append(info.Subtitles, track)
 		}
 	}
+	for _, c := range data.Chapters {
+		info.Chapters += c.NumEntries
+	}
 
 	return info, nil
 }
@@ -95,7 +107,8 @@ func FilterTracks(info *TrackInfo, languages []string) *TrackInfo {
 	}
 
 	filtered := &TrackInfo{
-		Video: append([]Track{}, info.Video...), // Keep all video tracks
+		Video:    append([]Track{}, info.Video...), // Keep all video tracks
+		Chapters: info.Chapters,
 	}
 
 	for _, track := range info.Audio {
@@ -113,9 +126,63 @@ func FilterTracks(info *TrackInfo, languages []string) *TrackInfo {
 	return filtered
 }
 
+// KeepFirstAudio adds info's first audio track to filtered when filtering
+// left it without audio. It reports whether a track was added.
+func KeepFirstAudio(filtered, info *TrackInfo) bool {
+	if len(filtered.Audio) > 0 || len(info.Audio) == 0 {
+		return false
+	}
+	filtered.Audio = []Track{info.Audio[0]}
+	return true
+}
+
+// MkvmergeOptions adjusts what mkvmerge copies besides the selected tracks
+type MkvmergeOptions struct {
+	NoChapters       bool   // Drop the input's chapters
+	ClearTrackTitles bool   // Replace track titles with NormalizeTrackTitle
+	Title            string // Segment title for the output, if set
+}
+
+// CapTracksPerLanguage keeps at most max tracks of each language, in their
+// original order, and returns them with the number dropped. The tracks with
+// the most channels are kept, earlier tracks winning ties, so for subtitles
+// it keeps the first ones. A max of 0 keeps everything.
+func CapTracksPerLanguage(tracks []Track, max int) ([]Track, int) {
+	if max <= 0 {
+		return tracks, 0
+	}
+
+	byLang := make(map[string][]int) // language -> indexes into tracks
+	for i, t := range tracks {
+		lang := strings.ToLower(t.Language)
+		byLang[lang] = append(byLang[lang], i)
+	}
+
+	keep := make(map[int]bool)
+	for _, idxs := range byLang {
+		sort.SliceStable(idxs, func(a, b int) bool {
+			return tracks[idxs[a]].Channels > tracks[idxs[b]].Channels
+		})
+		for _, i := range idxs[:min(max, len(idxs))] {
+			keep[i] = true
+		}
+	}
+
+	var kept []Track
+	for i, t := range tracks {
+		if keep[i] {
+			kept = append(kept, t)
+		}
+	}
+	return kept, len(tracks) - len(kept)
+}
+
 // BuildMkvmergeArgs builds mkvmerge command arguments for remuxing with filtered tracks
-func BuildMkvmergeArgs(inputPath, outputPath string, tracks *TrackInfo) []string {
+func BuildMkvmergeArgs(inputPath, outputPath string, tracks *TrackInfo, opts MkvmergeOptions) []string {
 	args := []string{"-o", outputPath}
+	if opts.Title != "" {
+		args = append(args, "--title", opts.Title)
+	}
 
 	// Build track selection arguments
 	// Video: always keep all
@@ -151,14 +218,41 @@ func BuildMkvmergeArgs(inputPath, outputPath string, tracks *TrackInfo) []string
 		args = append(args, "--no-subtitles")
 	}
 
+	if opts.NoChapters {
+		args = append(args, "--no-chapters")
+	}
+
+	if opts.ClearTrackTitles {
+		for _, group := range [][]Track{tracks.Video, tracks.Audio, tracks.Subtitles} {
+			for _, t := range group {
+				args = append(args, "--track-name", fmt.Sprintf("%d:%s", t.ID, NormalizeTrackTitle(t)))
+			}
+		}
+	}
+
 	args = append(args, inputPath)
 	return args
 }
 
-// RunMkvmerge executes mkvmerge with the given arguments
-func RunMkvmerge(args []string) error {
-	cmd := exec.Command("mkvmerge", args...)
+// NormalizeTrackTitle returns the title a track keeps when titles are
+// cleared. Disc titles like "Surround 5.1" only repeat what players already
+// show, so they are dropped; commentary tracks are the exception, as the
+// title is the only thing telling them apart from the main audio.
+func NormalizeTrackTitle(t Track) string {
+	if t.Type == "audio" && strings.Contains(strings.ToLower(t.Title), "commentary") {
+		return "Commentary"
+	}
+	return ""
+}
+
+// RunMkvmerge executes mkvmerge with the given arguments.
+// mkvmerge is killed if ctx is cancelled.
+func RunMkvmerge(ctx context.Context, args []string) error {
+	cmd := exec.CommandContext(ctx, "mkvmerge", args...)
 	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
 	if err != nil {
 		return fmt.Errorf("mkvmerge failed: %w\nOutput: %s", err, string(output))
 	}
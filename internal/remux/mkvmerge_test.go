@@ -1,6 +1,7 @@
 package remux
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -10,13 +11,14 @@ func TestParseTrackInfo(t *testing.T) {
 		"container": {"type": "Matroska"},
 		"tracks": [
 			{"id": 0, "type": "video", "codec": "HEVC", "properties": {}},
-			{"id": 1, "type": "audio", "codec": "AAC", "properties": {"language": "eng", "track_name": "English"}},
+			{"id": 1, "type": "audio", "codec": "AAC", "properties": {"language": "eng", "track_name": "English", "audio_channels": 6}},
 			{"id": 2, "type": "audio", "codec": "AAC", "properties": {"language": "bul", "track_name": "Bulgarian"}},
 			{"id": 3, "type": "audio", "codec": "AAC", "properties": {"language": "fra", "track_name": "French"}},
 			{"id": 4, "type": "subtitles", "codec": "SubRip/SRT", "properties": {"language": "eng", "track_name": "English"}},
 			{"id": 5, "type": "subtitles", "codec": "SubRip/SRT", "properties": {"language": "eng", "track_name": "English (Forced)", "forced_track": true}},
 			{"id": 6, "type": "subtitles", "codec": "SubRip/SRT", "properties": {"language": "bul", "track_name": "Bulgarian"}}
-		]
+		],
+		"chapters": [{"num_entries": 12}, {"num_entries": 3}]
 	}`
 
 	info, err := ParseTrackInfo([]byte(jsonOutput))
@@ -41,11 +43,21 @@ func TestParseTrackInfo(t *testing.T) {
 	if info.Audio[1].Language != "bul" {
 		t.Errorf("Audio[1].Language = %q, want bul", info.Audio[1].Language)
 	}
+	if info.Audio[0].Channels != 6 {
+		t.Errorf("Audio[0].Channels = %d, want 6", info.Audio[0].Channels)
+	}
 
 	// Check forced subtitle flag
 	if !info.Subtitles[1].Forced {
 		t.Error("Subtitles[1].Forced = false, want true")
 	}
+
+	if info.Chapters != 15 {
+		t.Errorf("Chapters = %d, want 15 across both editions", info.Chapters)
+	}
+	if filtered := FilterTracks(info, []string{"eng"}); filtered.Chapters != 15 {
+		t.Errorf("FilterTracks() Chapters = %d, want 15", filtered.Chapters)
+	}
 }
 
 func TestFilterTracks(t *testing.T) {
@@ -125,6 +137,94 @@ func TestFilterTracks_EmptyLanguages(t *testing.T) {
 	}
 }
 
+func TestKeepFirstAudio(t *testing.T) {
+	info := &TrackInfo{
+		Video: []Track{{ID: 0, Type: "video"}},
+		Audio: []Track{
+			{ID: 1, Type: "audio", Language: "jpn", Title: "Japanese"},
+			{ID: 2, Type: "audio", Language: "fra", Title: "French"},
+		},
+	}
+
+	filtered := FilterTracks(info, []string{"eng"})
+	if !KeepFirstAudio(filtered, info) {
+		t.Fatal("KeepFirstAudio() = false, want true when no audio matched")
+	}
+	if len(filtered.Audio) != 1 || filtered.Audio[0].ID != 1 {
+		t.Errorf("Filtered audio = %+v, want only track 1", filtered.Audio)
+	}
+
+	// Nothing to add once a language matched
+	filtered = FilterTracks(info, []string{"fra"})
+	if KeepFirstAudio(filtered, info) {
+		t.Error("KeepFirstAudio() = true, want false when audio matched")
+	}
+	if len(filtered.Audio) != 1 || filtered.Audio[0].ID != 2 {
+		t.Errorf("Filtered audio = %+v, want only track 2", filtered.Audio)
+	}
+
+	// A file without audio stays without audio
+	noAudio := &TrackInfo{Video: info.Video}
+	filtered = FilterTracks(noAudio, []string{"eng"})
+	if KeepFirstAudio(filtered, noAudio) {
+		t.Error("KeepFirstAudio() = true for a file without audio")
+	}
+}
+
+func TestCapTracksPerLanguage(t *testing.T) {
+	audio := []Track{
+		{ID: 1, Type: "audio", Language: "eng", Channels: 2},
+		{ID: 2, Type: "audio", Language: "bul", Channels: 2},
+		{ID: 3, Type: "audio", Language: "eng", Channels: 6},
+		{ID: 4, Type: "audio", Language: "ENG", Channels: 6},
+		{ID: 5, Type: "audio", Language: "eng", Channels: 8},
+	}
+
+	tests := []struct {
+		name        string
+		max         int
+		wantIDs     []int
+		wantDropped int
+	}{
+		{name: "no cap", max: 0, wantIDs: []int{1, 2, 3, 4, 5}},
+		{name: "most channels kept", max: 1, wantIDs: []int{2, 5}, wantDropped: 3},
+		{name: "earlier track wins a tie", max: 2, wantIDs: []int{2, 3, 5}, wantDropped: 2},
+		{name: "cap above count", max: 5, wantIDs: []int{1, 2, 3, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, dropped := CapTracksPerLanguage(audio, tt.max)
+			var ids []int
+			for _, tr := range kept {
+				ids = append(ids, tr.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("kept IDs = %v, want %v", ids, tt.wantIDs)
+			}
+			if dropped != tt.wantDropped {
+				t.Errorf("dropped = %d, want %d", dropped, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestCapTracksPerLanguage_SubtitlesKeepFirst(t *testing.T) {
+	subs := []Track{
+		{ID: 4, Type: "subtitles", Language: "eng"},
+		{ID: 5, Type: "subtitles", Language: "eng", Forced: true},
+		{ID: 6, Type: "subtitles", Language: "eng"},
+	}
+
+	kept, dropped := CapTracksPerLanguage(subs, 2)
+	if len(kept) != 2 || kept[0].ID != 4 || kept[1].ID != 5 {
+		t.Errorf("kept = %+v, want tracks 4 and 5", kept)
+	}
+	if dropped != 1 {
+		t.Errorf("dropped = %d, want 1", dropped)
+	}
+}
+
 func TestBuildMkvmergeArgs(t *testing.T) {
 	tracks := &TrackInfo{
 		Video: []Track{
@@ -140,7 +240,7 @@ func TestBuildMkvmergeArgs(t *testing.T) {
 		},
 	}
 
-	args := BuildMkvmergeArgs("/input/file.mkv", "/output/file.mkv", tracks)
+	args := BuildMkvmergeArgs("/input/file.mkv", "/output/file.mkv", tracks, MkvmergeOptions{})
 
 	// Check output path
 	if args[0] != "-o" || args[1] != "/output/file.mkv" {
@@ -196,7 +296,7 @@ func TestBuildMkvmergeArgs_NoTracks(t *testing.T) {
 		Subtitles: []Track{},
 	}
 
-	args := BuildMkvmergeArgs("/input/file.mkv", "/output/file.mkv", tracks)
+	args := BuildMkvmergeArgs("/input/file.mkv", "/output/file.mkv", tracks, MkvmergeOptions{})
 
 	// Should have --no-audio and --no-subtitles
 	hasNoAudio := false
@@ -217,3 +317,67 @@ func TestBuildMkvmergeArgs_NoTracks(t *testing.T) {
 		t.Error("Expected --no-subtitles when no subtitle tracks")
 	}
 }
+
+func TestBuildMkvmergeArgs_Chapters(t *testing.T) {
+	tracks := &TrackInfo{Video: []Track{{ID: 0, Type: "video"}}, Chapters: 12}
+
+	hasNoChapters := func(args []string) bool {
+		for _, arg := range args {
+			if arg == "--no-chapters" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if args := BuildMkvmergeArgs("/input/file.mkv", "/output/file.mkv", tracks, MkvmergeOptions{}); hasNoChapters(args) {
+		t.Errorf("keeping chapters: unexpected --no-chapters in %v", args)
+	}
+	args := BuildMkvmergeArgs("/input/file.mkv", "/output/file.mkv", tracks, MkvmergeOptions{NoChapters: true})
+	if !hasNoChapters(args) {
+		t.Errorf("stripping chapters: expected --no-chapters in %v", args)
+	}
+	// Options only apply to the input file that follows them
+	if args[len(args)-1] != "/input/file.mkv" {
+		t.Errorf("Expected input path at end, got %s", args[len(args)-1])
+	}
+}
+
+func TestBuildMkvmergeArgs_Titles(t *testing.T) {
+	tracks := &TrackInfo{
+		Video: []Track{{ID: 0, Type: "video", Title: "Segment"}},
+		Audio: []Track{
+			{ID: 1, Type: "audio", Language: "eng", Title: "Surround 5.1"},
+			{ID: 2, Type: "audio", Language: "eng", Title: "Director's Commentary"},
+		},
+		Subtitles: []Track{{ID: 4, Type: "subtitles", Language: "eng"}},
+	}
+
+	args := BuildMkvmergeArgs("/input/file.mkv", "/output/file.mkv", tracks, MkvmergeOptions{
+		ClearTrackTitles: true,
+		Title:            "The Matrix",
+	})
+
+	want := []string{
+		"-o", "/output/file.mkv",
+		"--title", "The Matrix",
+		"--video-tracks", "0",
+		"--audio-tracks", "1,2",
+		"--subtitle-tracks", "4",
+		"--track-name", "0:",
+		"--track-name", "1:",
+		"--track-name", "2:Commentary",
+		"--track-name", "4:",
+		"/input/file.mkv",
+	}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("args =\n%v\nwant\n%v", args, want)
+	}
+
+	// Without the options titles are left as mkvmerge copies them
+	for _, arg := range BuildMkvmergeArgs("/input/file.mkv", "/output/file.mkv", tracks, MkvmergeOptions{}) {
+		if arg == "--track-name" || arg == "--title" {
+			t.Errorf("unexpected %s without title options", arg)
+		}
+	}
+}
@@ -5,17 +5,72 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 )
 
 // Remuxer handles MKV file remuxing with track filtering
 type Remuxer struct {
-	languages []string
+	languages      []string
+	concurrency    int
+	keepFirstAudio bool
+	keepChapters   bool
+	clearTitles    bool
+	fileTitle      string
+	maxAudio       int // per language, 0 for no cap
+	maxSubs        int // per language, 0 for no cap
+
+	// remuxFile processes one file; replaced in tests to avoid mkvmerge
+	remuxFile func(ctx context.Context, inputPath, outputPath string) (*RemuxResult, error)
 }
 
 // NewRemuxer creates a new Remuxer with the specified language filters
 func NewRemuxer(languages []string) *Remuxer {
-	return &Remuxer{languages: languages}
+	r := &Remuxer{languages: languages, concurrency: 1, keepChapters: true}
+	r.remuxFile = r.RemuxFile
+	return r
+}
+
+// SetConcurrency sets how many files RemuxDirectory processes at once.
+// Values below 1 are treated as 1.
+func (r *Remuxer) SetConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	r.concurrency = n
+}
+
+// SetFallbackKeepFirstAudio makes RemuxFile keep the first audio track of a
+// file that has no audio in the configured languages, instead of dropping
+// all of its audio
+func (r *Remuxer) SetFallbackKeepFirstAudio(keep bool) {
+	r.keepFirstAudio = keep
+}
+
+// SetKeepChapters sets whether RemuxFile copies the input's chapters (the
+// default) or strips them
+func (r *Remuxer) SetKeepChapters(keep bool) {
+	r.keepChapters = keep
+}
+
+// SetTrackCaps limits how many audio and subtitle tracks RemuxFile keeps per
+// language after filtering. 0 means no cap.
+func (r *Remuxer) SetTrackCaps(maxAudio, maxSubs int) {
+	r.maxAudio = maxAudio
+	r.maxSubs = maxSubs
+}
+
+// SetClearTrackTitles makes RemuxFile drop the disc's track titles, keeping
+// only those NormalizeTrackTitle considers useful
+func (r *Remuxer) SetClearTrackTitles(clear bool) {
+	r.clearTitles = clear
+}
+
+// SetFileTitle sets the title written to each output file, e.g. the item
+// name. An empty title keeps the input's.
+func (r *Remuxer) SetFileTitle(title string) {
+	r.fileTitle = title
 }
 
 // RemuxResult contains statistics about a remux operation
@@ -25,6 +80,19 @@ type RemuxResult struct {
 	InputTracks   TrackCounts
 	OutputTracks  TrackCounts
 	TracksRemoved int
+	CappedTracks  int        // Of TracksRemoved, those over the per-language caps
+	Kept          *TrackInfo // Tracks selected for the output file
+	AudioFallback bool       // No audio matched the languages, so the first track was kept
+
+	// Languages of the kept tracks, in track order without repeats
+	OutputAudioLangs    []string
+	OutputSubtitleLangs []string
+
+	// Languages the remuxer was asked to keep, and those the input's audio
+	// and subtitle tracks are in, so a dropped language can be told apart
+	// from one the source never had
+	RequestedLangs []string
+	FoundLangs     []string
 }
 
 // TrackCounts holds counts by track type
@@ -34,8 +102,48 @@ type TrackCounts struct {
 	Subtitles int
 }
 
+// MissingLanguages returns the languages from languages that no kept audio or
+// subtitle track is in, e.g. because the disc tagged its English track "und"
+func (r *RemuxResult) MissingLanguages(languages []string) []string {
+	var missing []string
+	for _, lang := range languages {
+		lang = strings.ToLower(lang)
+		if !slices.Contains(r.OutputAudioLangs, lang) && !slices.Contains(r.OutputSubtitleLangs, lang) {
+			missing = append(missing, lang)
+		}
+	}
+	return missing
+}
+
+// distinctLanguages returns the lowercased languages of tracks in order,
+// without repeats
+func distinctLanguages(tracks []Track) []string {
+	var langs []string
+	for _, lang := range trackLanguages(tracks) {
+		if !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+	return langs
+}
+
+// requestedLanguages returns the configured languages lowercased, as track
+// languages are compared
+func (r *Remuxer) requestedLanguages() []string {
+	langs := make([]string, len(r.languages))
+	for i, lang := range r.languages {
+		langs[i] = strings.ToLower(lang)
+	}
+	return langs
+}
+
 // RemuxFile remuxes a single MKV file, filtering tracks by language
+// If ctx is cancelled, mkvmerge is killed and the partial output removed.
 func (r *Remuxer) RemuxFile(ctx context.Context, inputPath, outputPath string) (*RemuxResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	// Get track info from input
 	inputInfo, err := GetTrackInfo(inputPath)
 	if err != nil {
@@ -44,6 +152,13 @@ func (r *Remuxer) RemuxFile(ctx context.Context, inputPath, outputPath string) (
 
 	// Filter tracks
 	filteredInfo := FilterTracks(inputInfo, r.languages)
+	var cappedAudio, cappedSubs int
+	filteredInfo.Audio, cappedAudio = CapTracksPerLanguage(filteredInfo.Audio, r.maxAudio)
+	filteredInfo.Subtitles, cappedSubs = CapTracksPerLanguage(filteredInfo.Subtitles, r.maxSubs)
+	audioFallback := r.keepFirstAudio && KeepFirstAudio(filteredInfo, inputInfo)
+	if !r.keepChapters {
+		filteredInfo.Chapters = 0
+	}
 
 	// Ensure output directory exists
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
@@ -51,8 +166,15 @@ func (r *Remuxer) RemuxFile(ctx context.Context, inputPath, outputPath string) (
 	}
 
 	// Build and run mkvmerge
-	args := BuildMkvmergeArgs(inputPath, outputPath, filteredInfo)
-	if err := RunMkvmerge(args); err != nil {
+	args := BuildMkvmergeArgs(inputPath, outputPath, filteredInfo, MkvmergeOptions{
+		NoChapters:       !r.keepChapters,
+		ClearTrackTitles: r.clearTitles,
+		Title:            r.fileTitle,
+	})
+	if err := RunMkvmerge(ctx, args); err != nil {
+		if ctx.Err() != nil {
+			os.Remove(outputPath)
+		}
 		return nil, err
 	}
 
@@ -74,15 +196,22 @@ func (r *Remuxer) RemuxFile(ctx context.Context, inputPath, outputPath string) (
 		OutputTracks: outputCounts,
 		TracksRemoved: (inputCounts.Audio - outputCounts.Audio) +
 			(inputCounts.Subtitles - outputCounts.Subtitles),
+		CappedTracks:        cappedAudio + cappedSubs,
+		Kept:                filteredInfo,
+		AudioFallback:       audioFallback,
+		OutputAudioLangs:    distinctLanguages(filteredInfo.Audio),
+		OutputSubtitleLangs: distinctLanguages(filteredInfo.Subtitles),
+		RequestedLangs:      r.requestedLanguages(),
+		FoundLangs:          distinctLanguages(append(slices.Clone(inputInfo.Audio), inputInfo.Subtitles...)),
 	}, nil
 }
 
 // RemuxDirectory remuxes all MKV files in a directory
 // For movies: remuxes _main/*.mkv files
 // For TV: remuxes _episodes/*.mkv files, preserving episode names
+// Files are processed by up to SetConcurrency workers; results are returned
+// in directory order. After the first failure no new files are started.
 func (r *Remuxer) RemuxDirectory(ctx context.Context, inputDir, outputDir string, isTV bool) ([]RemuxResult, error) {
-	var results []RemuxResult
-
 	// Determine input subdirectory
 	var srcDir string
 	if isTV {
@@ -97,6 +226,7 @@ func (r *Remuxer) RemuxDirectory(ctx context.Context, inputDir, outputDir string
 		return nil, fmt.Errorf("failed to read directory %s: %w", srcDir, err)
 	}
 
+	var names []string
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
@@ -104,24 +234,69 @@ func (r *Remuxer) RemuxDirectory(ctx context.Context, inputDir, outputDir string
 		if !strings.HasSuffix(strings.ToLower(entry.Name()), ".mkv") {
 			continue
 		}
+		names = append(names, entry.Name())
+	}
 
-		inputPath := filepath.Join(srcDir, entry.Name())
+	// Each worker writes only its own result slot, so no locking is needed.
+	// Only the first failure is kept: the files it cancels fail after it.
+	fileResults := make([]*RemuxResult, len(names))
+	var firstErr error
+	var failOnce sync.Once
 
-		// Determine output path
-		var outputPath string
-		if isTV {
-			// TV: preserve episode naming in _episodes
-			outputPath = filepath.Join(outputDir, "_episodes", entry.Name())
-		} else {
-			// Movie: single file in _main
-			outputPath = filepath.Join(outputDir, "_main", entry.Name())
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sem := make(chan struct{}, r.concurrency)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		sem <- struct{}{}
+		if workCtx.Err() != nil {
+			<-sem
+			break
 		}
 
-		result, err := r.RemuxFile(ctx, inputPath, outputPath)
-		if err != nil {
-			return results, fmt.Errorf("failed to remux %s: %w", entry.Name(), err)
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			inputPath := filepath.Join(srcDir, name)
+
+			// Determine output path
+			var outputPath string
+			if isTV {
+				// TV: preserve episode naming in _episodes
+				outputPath = filepath.Join(outputDir, "_episodes", name)
+			} else {
+				// Movie: single file in _main
+				outputPath = filepath.Join(outputDir, "_main", name)
+			}
+
+			result, err := r.remuxFile(workCtx, inputPath, outputPath)
+			if err != nil {
+				failOnce.Do(func() {
+					firstErr = fmt.Errorf("failed to remux %s: %w", name, err)
+					cancel()
+				})
+				return
+			}
+			fileResults[i] = result
+		}(i, name)
+	}
+	wg.Wait()
+
+	var results []RemuxResult
+	for i := range names {
+		if fileResults[i] != nil {
+			results = append(results, *fileResults[i])
 		}
-		results = append(results, *result)
+	}
+	// Cancellation takes precedence over the errors it caused in workers
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	if firstErr != nil {
+		return results, firstErr
 	}
 
 	// Also copy extras if present
@@ -2,10 +2,14 @@ package remux
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewRemuxer(t *testing.T) {
@@ -75,6 +79,71 @@ func TestRemuxer_RemuxFile(t *testing.T) {
 	if result.OutputTracks.Audio == 0 {
 		t.Error("Expected audio tracks in output")
 	}
+	if len(result.OutputAudioLangs) == 0 {
+		t.Error("Expected output audio languages")
+	}
+	if strings.Join(result.RequestedLangs, ",") != "eng,bul" {
+		t.Errorf("RequestedLangs = %v, want [eng bul]", result.RequestedLangs)
+	}
+	if len(result.FoundLangs) == 0 {
+		t.Error("Expected input languages")
+	}
+}
+
+func TestDistinctLanguages(t *testing.T) {
+	tracks := []Track{{Language: "eng"}, {Language: "BUL"}, {Language: "eng"}, {Language: "und"}}
+	got := distinctLanguages(tracks)
+	if strings.Join(got, ",") != "eng,bul,und" {
+		t.Errorf("distinctLanguages() = %v, want [eng bul und]", got)
+	}
+}
+
+func TestRemuxResult_MissingLanguages(t *testing.T) {
+	r := &RemuxResult{
+		OutputAudioLangs:    []string{"bul"},
+		OutputSubtitleLangs: []string{"eng", "bul"},
+	}
+	if got := r.MissingLanguages([]string{"ENG", "bul"}); len(got) != 0 {
+		t.Errorf("MissingLanguages() = %v, want none (eng has subtitles)", got)
+	}
+	if got := r.MissingLanguages([]string{"eng", "jpn"}); strings.Join(got, ",") != "jpn" {
+		t.Errorf("MissingLanguages() = %v, want [jpn]", got)
+	}
+}
+
+func TestManifest(t *testing.T) {
+	outputDir := t.TempDir()
+	results := []RemuxResult{{
+		OutputPath:          filepath.Join(outputDir, "_episodes", "S01E01.mkv"),
+		TracksRemoved:       3,
+		RequestedLangs:      []string{"eng", "bul"},
+		FoundLangs:          []string{"eng", "fre"},
+		OutputAudioLangs:    []string{"eng"},
+		OutputSubtitleLangs: []string{"eng"},
+	}}
+
+	if err := WriteManifest(outputDir, BuildManifest(7, outputDir, []string{"eng", "bul"}, results)); err != nil {
+		t.Fatalf("WriteManifest() error = %v", err)
+	}
+	m, err := ReadManifest(outputDir)
+	if err != nil {
+		t.Fatalf("ReadManifest() error = %v", err)
+	}
+
+	if m.JobID != 7 || strings.Join(m.RequestedLangs, ",") != "eng,bul" || len(m.Files) != 1 {
+		t.Fatalf("manifest = %+v", m)
+	}
+	f := m.Files[0]
+	if f.Path != "_episodes/S01E01.mkv" {
+		t.Errorf("Path = %q, want relative to the output directory", f.Path)
+	}
+	// bul was requested but the source never had it
+	if strings.Join(f.FoundLangs, ",") != "eng,fre" || strings.Join(f.MissingLangs, ",") != "bul" {
+		t.Errorf("FoundLangs = %v, MissingLangs = %v, want [eng fre] and [bul]", f.FoundLangs, f.MissingLangs)
+	}
+	if f.TracksRemoved != 3 {
+		t.Errorf("TracksRemoved = %d, want 3", f.TracksRemoved)
+	}
 }
 
 func TestRemuxer_RemuxDirectory_Movies(t *testing.T) {
@@ -293,3 +362,156 @@ func generateSimpleTestMKV(outputPath string) error {
 	_ = output // Ignore output for now
 	return nil
 }
+
+func TestRemuxer_RemuxDirectory_Concurrency(t *testing.T) {
+	inputDir := t.TempDir()
+	outputDir := t.TempDir()
+
+	episodesDir := filepath.Join(inputDir, "_episodes")
+	if err := os.MkdirAll(episodesDir, 0755); err != nil {
+		t.Fatalf("Failed to create _episodes dir: %v", err)
+	}
+	names := []string{"S01E01.mkv", "S01E02.mkv", "S01E03.mkv", "S01E04.mkv", "S01E05.mkv"}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(episodesDir, name), nil, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	delays := make(map[string]time.Duration)
+	for i, name := range names {
+		delays[name] = time.Duration(len(names)-i) * 10 * time.Millisecond
+	}
+
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+
+	remuxer := NewRemuxer([]string{"eng"})
+	remuxer.SetConcurrency(3)
+	remuxer.remuxFile = func(ctx context.Context, inputPath, outputPath string) (*RemuxResult, error) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+
+		// Earlier files take longer so they finish out of order
+		time.Sleep(delays[filepath.Base(inputPath)])
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return &RemuxResult{InputPath: inputPath, OutputPath: outputPath}, nil
+	}
+
+	results, err := remuxer.RemuxDirectory(context.Background(), inputDir, outputDir, true)
+	if err != nil {
+		t.Fatalf("RemuxDirectory() error = %v", err)
+	}
+
+	if len(results) != len(names) {
+		t.Fatalf("got %d results, want %d", len(results), len(names))
+	}
+	for i, r := range results {
+		if filepath.Base(r.InputPath) != names[i] {
+			t.Errorf("results[%d] = %s, want %s", i, filepath.Base(r.InputPath), names[i])
+		}
+	}
+	if maxInFlight > 3 {
+		t.Errorf("max in flight = %d, want at most 3", maxInFlight)
+	}
+	if maxInFlight < 2 {
+		t.Errorf("max in flight = %d, want files processed concurrently", maxInFlight)
+	}
+}
+
+func TestRemuxer_RemuxDirectory_StopsAfterFailure(t *testing.T) {
+	inputDir := t.TempDir()
+
+	mainDir := filepath.Join(inputDir, "_main")
+	if err := os.MkdirAll(mainDir, 0755); err != nil {
+		t.Fatalf("Failed to create _main dir: %v", err)
+	}
+	for _, name := range []string{"a.mkv", "b.mkv", "c.mkv"} {
+		if err := os.WriteFile(filepath.Join(mainDir, name), nil, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	var calls []string
+	remuxer := NewRemuxer([]string{"eng"})
+	remuxer.remuxFile = func(ctx context.Context, inputPath, outputPath string) (*RemuxResult, error) {
+		calls = append(calls, filepath.Base(inputPath))
+		if filepath.Base(inputPath) == "b.mkv" {
+			return nil, errors.New("mkvmerge failed")
+		}
+		return &RemuxResult{InputPath: inputPath, OutputPath: outputPath}, nil
+	}
+
+	results, err := remuxer.RemuxDirectory(context.Background(), inputDir, t.TempDir(), false)
+	if err == nil || !strings.Contains(err.Error(), "b.mkv") {
+		t.Fatalf("RemuxDirectory() error = %v, want failure for b.mkv", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("got %d results, want 1", len(results))
+	}
+	if len(calls) != 2 {
+		t.Errorf("processed %v, want to stop after b.mkv", calls)
+	}
+}
+
+func TestRemuxer_RemuxDirectory_ReportsFailingFile(t *testing.T) {
+	inputDir := t.TempDir()
+
+	mainDir := filepath.Join(inputDir, "_main")
+	if err := os.MkdirAll(mainDir, 0755); err != nil {
+		t.Fatalf("Failed to create _main dir: %v", err)
+	}
+	for _, name := range []string{"a.mkv", "b.mkv"} {
+		if err := os.WriteFile(filepath.Join(mainDir, name), nil, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	remuxer := NewRemuxer([]string{"eng"})
+	remuxer.SetConcurrency(2)
+	remuxer.remuxFile = func(ctx context.Context, inputPath, outputPath string) (*RemuxResult, error) {
+		if filepath.Base(inputPath) == "b.mkv" {
+			return nil, errors.New("mkvmerge failed")
+		}
+		// a.mkv is still running when b.mkv fails, and is cancelled by it
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	_, err := remuxer.RemuxDirectory(context.Background(), inputDir, t.TempDir(), false)
+	if err == nil || !strings.Contains(err.Error(), "b.mkv") || errors.Is(err, context.Canceled) {
+		t.Fatalf("RemuxDirectory() error = %v, want failure for b.mkv", err)
+	}
+}
+
+func TestRemuxer_RemuxDirectory_Cancelled(t *testing.T) {
+	inputDir := t.TempDir()
+
+	mainDir := filepath.Join(inputDir, "_main")
+	if err := os.MkdirAll(mainDir, 0755); err != nil {
+		t.Fatalf("Failed to create _main dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(mainDir, "a.mkv"), nil, 0644); err != nil {
+		t.Fatalf("Failed to write a.mkv: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	remuxer := NewRemuxer([]string{"eng"})
+	remuxer.remuxFile = func(ctx context.Context, inputPath, outputPath string) (*RemuxResult, error) {
+		t.Error("no file should be processed after cancellation")
+		return nil, nil
+	}
+
+	if _, err := remuxer.RemuxDirectory(ctx, inputDir, t.TempDir(), false); !errors.Is(err, context.Canceled) {
+		t.Errorf("RemuxDirectory() error = %v, want context.Canceled", err)
+	}
+}
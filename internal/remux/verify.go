@@ -0,0 +1,115 @@
+package remux
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/cuivienor/media-pipeline/internal/mediainfo"
+)
+
+// ProbeTracks runs ffprobe on a file and returns its tracks. This is
+// independent of mkvmerge, so it can confirm what mkvmerge actually wrote.
+// If ffprobePath is empty, uses "ffprobe" from PATH.
+func ProbeTracks(ffprobePath, path string) (*TrackInfo, error) {
+	info, err := mediainfo.ProbeWith(ffprobePath, path)
+	if err != nil {
+		return nil, err
+	}
+	return tracksFromMediaInfo(info), nil
+}
+
+// ParseFFprobeStreams parses ffprobe -show_streams JSON output into TrackInfo.
+// Streams without a language tag are reported as "und", matching mkvmerge.
+func ParseFFprobeStreams(jsonData []byte) (*TrackInfo, error) {
+	info, err := mediainfo.Parse(jsonData)
+	if err != nil {
+		return nil, err
+	}
+	return tracksFromMediaInfo(info), nil
+}
+
+// tracksFromMediaInfo converts probed streams into mkvmerge-style tracks
+func tracksFromMediaInfo(mi *mediainfo.MediaInfo) *TrackInfo {
+	info := &TrackInfo{Chapters: len(mi.Chapters)}
+	for _, s := range mi.Streams {
+		track := Track{
+			ID:       s.Index,
+			Codec:    s.Codec,
+			Language: s.Language,
+			Title:    s.Title,
+			Forced:   s.Forced,
+		}
+
+		switch s.Type {
+		case mediainfo.StreamVideo:
+			track.Type = "video"
+			info.Video = append(info.Video, track)
+		case mediainfo.StreamAudio:
+			track.Type = "audio"
+			info.Audio = append(info.Audio, track)
+		case mediainfo.StreamSubtitle:
+			track.Type = "subtitles"
+			info.Subtitles = append(info.Subtitles, track)
+		}
+	}
+	return info
+}
+
+// CompareTracks returns an error describing every difference between the
+// audio and subtitle tracks that were intended and those actually present,
+// and whether chapters were kept or stripped as intended. Chapter counts
+// aren't compared, as ffprobe only reports the default edition's.
+func CompareTracks(want, got *TrackInfo) error {
+	var errs []error
+	if (want.Chapters > 0) != (got.Chapters > 0) {
+		errs = append(errs, fmt.Errorf("chapters: want %d, got %d", want.Chapters, got.Chapters))
+	}
+	if err := compareTrackLanguages("audio", want.Audio, got.Audio); err != nil {
+		errs = append(errs, err)
+	}
+	if err := compareTrackLanguages("subtitle", want.Subtitles, got.Subtitles); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// compareTrackLanguages compares track counts and languages in order
+func compareTrackLanguages(kind string, want, got []Track) error {
+	wantLangs := trackLanguages(want)
+	gotLangs := trackLanguages(got)
+	if strings.Join(wantLangs, ",") == strings.Join(gotLangs, ",") {
+		return nil
+	}
+	return fmt.Errorf("%s tracks: want %d %v, got %d %v", kind, len(want), wantLangs, len(got), gotLangs)
+}
+
+// trackLanguages returns the lowercased language of each track
+func trackLanguages(tracks []Track) []string {
+	langs := make([]string, len(tracks))
+	for i, t := range tracks {
+		langs[i] = strings.ToLower(t.Language)
+	}
+	return langs
+}
+
+// VerifyResults re-probes each remuxed file with ffprobe and checks that its
+// audio and subtitle tracks match the selection that was passed to mkvmerge
+func VerifyResults(ffprobePath string, results []RemuxResult) error {
+	var errs []error
+	for _, r := range results {
+		if r.Kept == nil {
+			continue
+		}
+		actual, err := ProbeTracks(ffprobePath, r.OutputPath)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(r.OutputPath), err))
+			continue
+		}
+		if err := CompareTracks(r.Kept, actual); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(r.OutputPath), err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+package remux
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFFprobeStreams(t *testing.T) {
+	jsonData := `{
+		"streams": [
+			{"index": 0, "codec_type": "video", "codec_name": "h264"},
+			{"index": 1, "codec_type": "audio", "codec_name": "truehd", "tags": {"language": "eng", "title": "Surround 7.1"}},
+			{"index": 2, "codec_type": "audio", "codec_name": "ac3"},
+			{"index": 3, "codec_type": "subtitle", "codec_name": "hdmv_pgs_subtitle", "tags": {"language": "bul"}},
+			{"index": 4, "codec_type": "attachment", "codec_name": "ttf"}
+		]
+	}`
+
+	info, err := ParseFFprobeStreams([]byte(jsonData))
+	if err != nil {
+		t.Fatalf("ParseFFprobeStreams() error = %v", err)
+	}
+
+	if len(info.Video) != 1 || len(info.Audio) != 2 || len(info.Subtitles) != 1 {
+		t.Fatalf("got %d video, %d audio, %d subs, want 1, 2, 1", len(info.Video), len(info.Audio), len(info.Subtitles))
+	}
+	if info.Audio[0].Language != "eng" || info.Audio[0].Title != "Surround 7.1" {
+		t.Errorf("Audio[0] = %+v", info.Audio[0])
+	}
+	if info.Audio[1].Language != "und" {
+		t.Errorf("untagged audio language = %q, want und", info.Audio[1].Language)
+	}
+	if info.Subtitles[0].Type != "subtitles" {
+		t.Errorf("subtitle type = %q, want subtitles", info.Subtitles[0].Type)
+	}
+}
+
+func TestParseFFprobeStreams_Invalid(t *testing.T) {
+	if _, err := ParseFFprobeStreams([]byte("not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestCompareTracks(t *testing.T) {
+	want := &TrackInfo{
+		Audio:     []Track{{Language: "eng"}, {Language: "bul"}},
+		Subtitles: []Track{{Language: "eng"}},
+	}
+
+	tests := []struct {
+		name    string
+		got     *TrackInfo
+		wantErr []string
+	}{
+		{
+			name: "match ignores case",
+			got: &TrackInfo{
+				Audio:     []Track{{Language: "ENG"}, {Language: "bul"}},
+				Subtitles: []Track{{Language: "eng"}},
+			},
+		},
+		{
+			name: "missing audio track",
+			got: &TrackInfo{
+				Audio:     []Track{{Language: "eng"}},
+				Subtitles: []Track{{Language: "eng"}},
+			},
+			wantErr: []string{"audio tracks: want 2 [eng bul], got 1 [eng]"},
+		},
+		{
+			name: "wrong subtitle language and extra audio",
+			got: &TrackInfo{
+				Audio:     []Track{{Language: "eng"}, {Language: "bul"}, {Language: "jpn"}},
+				Subtitles: []Track{{Language: "fre"}},
+			},
+			wantErr: []string{"audio tracks", "subtitle tracks: want 1 [eng], got 1 [fre]"},
+		},
+		{
+			name: "chapters left in",
+			got: &TrackInfo{
+				Audio:     []Track{{Language: "eng"}, {Language: "bul"}},
+				Subtitles: []Track{{Language: "eng"}},
+				Chapters:  8,
+			},
+			wantErr: []string{"chapters: want 0, got 8"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CompareTracks(want, tt.got)
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Errorf("CompareTracks() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("CompareTracks() error = nil, want error")
+			}
+			for _, w := range tt.wantErr {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("error %q does not contain %q", err, w)
+				}
+			}
+		})
+	}
+}
+
+func TestCompareTracks_Chapters(t *testing.T) {
+	want := &TrackInfo{Chapters: 15}
+
+	if err := CompareTracks(want, &TrackInfo{Chapters: 12}); err != nil {
+		t.Errorf("CompareTracks() error = %v, want nil when chapters survived", err)
+	}
+	err := CompareTracks(want, &TrackInfo{})
+	if err == nil || !strings.Contains(err.Error(), "chapters: want 15, got 0") {
+		t.Errorf("CompareTracks() error = %v, want lost chapters reported", err)
+	}
+}
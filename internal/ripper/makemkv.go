@@ -3,12 +3,76 @@ package ripper
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os/exec"
+	"regexp"
 	"strconv"
 	"strings"
 )
 
+// ErrMakeMKVLicense indicates makemkvcon refused to run because its
+// registration or beta key has expired
+var ErrMakeMKVLicense = errors.New("makemkv license expired or invalid")
+
+var (
+	// makemkvVersionRegex matches the version in the startup message, e.g. "MakeMKV v1.17.7 linux(x64-release) started"
+	makemkvVersionRegex = regexp.MustCompile(`MakeMKV v(\d+(?:\.\d+)*)`)
+	// makemkvLicenseRegex matches messages makemkvcon prints when it will not rip
+	makemkvLicenseRegex = regexp.MustCompile(`(?i)evaluation period has expired|version is too old|registration key.*(invalid|expired)|key (has )?expired`)
+)
+
+// CheckMakeMKV runs makemkvcon without scanning any disc and returns its version.
+// It returns an error wrapping ErrMakeMKVLicense if the license has expired,
+// which otherwise causes rips to silently produce no output.
+// If path is empty, uses "makemkvcon" from PATH.
+func CheckMakeMKV(path string) (string, error) {
+	if path == "" {
+		path = "makemkvcon"
+	}
+
+	// disc:9999 never exists, so makemkvcon only prints its startup messages
+	cmd := exec.Command(path, "-r", "--noscan", "info", "disc:9999")
+	output, runErr := cmd.CombinedOutput()
+
+	version, err := parseMakeMKVCheck(string(output))
+	if err != nil {
+		return version, err
+	}
+	if version == "" {
+		if runErr != nil {
+			return "", fmt.Errorf("failed to run makemkvcon: %w", runErr)
+		}
+		return "", fmt.Errorf("could not determine makemkvcon version")
+	}
+	return version, nil
+}
+
+// parseMakeMKVCheck extracts the version and license state from makemkvcon robot output
+func parseMakeMKVCheck(output string) (string, error) {
+	var version string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "MSG:") {
+			continue
+		}
+		if version == "" {
+			if m := makemkvVersionRegex.FindStringSubmatch(line); m != nil {
+				version = m[1]
+			}
+		}
+		if makemkvLicenseRegex.MatchString(line) {
+			parts := splitCSV(strings.TrimPrefix(line, "MSG:"))
+			msg := line
+			if len(parts) >= 4 {
+				msg = unquote(parts[3])
+			}
+			return version, fmt.Errorf("%w: %s", ErrMakeMKVLicense, msg)
+		}
+	}
+	return version, nil
+}
+
 // DefaultMakeMKVRunner executes makemkvcon commands
 type DefaultMakeMKVRunner struct {
 	makemkvconPath string
@@ -56,6 +120,64 @@ func (r *DefaultMakeMKVRunner) GetDiscInfo(ctx context.Context, discPath string)
 	return parser.GetDiscInfo(), nil
 }
 
+// driveStateInserted is the state makemkvcon's DRV lines give a drive with
+// a disc in it
+const driveStateInserted = 2
+
+// DriveReady reports whether the drive at discPath ("disc:N", "dev:/dev/sr0"
+// or "/dev/sr0") has a disc in it. It only lists the drives, without scanning
+// the disc's titles like GetDiscInfo, so it is cheap enough to poll.
+func (r *DefaultMakeMKVRunner) DriveReady(ctx context.Context, discPath string) (bool, error) {
+	// disc:9999 never exists, so makemkvcon lists the drives and stops
+	output, runErr := r.execCommand(ctx, r.makemkvconPath, "-r", "info", "disc:9999").Output()
+
+	ready, found := parseDriveState(string(output), discPath)
+	if !found {
+		if runErr != nil {
+			return false, fmt.Errorf("makemkvcon failed: %w", runErr)
+		}
+		return false, fmt.Errorf("drive %s not found", discPath)
+	}
+	return ready, nil
+}
+
+// parseDriveState finds the DRV line for discPath in makemkvcon robot output,
+// e.g. DRV:0,2,999,12,"BD-RE drive","DISC_LABEL","/dev/sr0", and reports
+// whether that drive has a disc in it
+func parseDriveState(output, discPath string) (ready, found bool) {
+	index := -1
+	device := ""
+	switch {
+	case strings.HasPrefix(discPath, "disc:"):
+		n, err := strconv.Atoi(strings.TrimPrefix(discPath, "disc:"))
+		if err != nil {
+			return false, false
+		}
+		index = n
+	case strings.HasPrefix(discPath, "dev:"):
+		device = strings.TrimPrefix(discPath, "dev:")
+	default:
+		device = discPath
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "DRV:") {
+			continue
+		}
+		parts := splitCSV(strings.TrimPrefix(line, "DRV:"))
+		if len(parts) < 2 {
+			continue
+		}
+		n, _ := strconv.Atoi(parts[0])
+		state, _ := strconv.Atoi(parts[1])
+		if (index >= 0 && n == index) || (device != "" && len(parts) >= 7 && unquote(parts[6]) == device) {
+			return state == driveStateInserted, true
+		}
+	}
+	return false, false
+}
+
 // RipTitles rips specified titles from a disc
 // If titleIndices is nil or empty, rips all titles
 func (r *DefaultMakeMKVRunner) RipTitles(ctx context.Context, discPath, outputDir string, titleIndices []int, onLine LineCallback, onProgress ProgressCallback) error {
@@ -63,6 +185,9 @@ func (r *DefaultMakeMKVRunner) RipTitles(ctx context.Context, discPath, outputDi
 	// Otherwise, use "all" to rip everything
 	if len(titleIndices) > 0 {
 		for _, idx := range titleIndices {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			if err := r.ripTitle(ctx, discPath, outputDir, idx, onLine, onProgress); err != nil {
 				return err
 			}
@@ -75,39 +200,23 @@ func (r *DefaultMakeMKVRunner) RipTitles(ctx context.Context, discPath, outputDi
 
 // ripTitle rips a single title
 func (r *DefaultMakeMKVRunner) ripTitle(ctx context.Context, discPath, outputDir string, titleIdx int, onLine LineCallback, onProgress ProgressCallback) error {
-	args := []string{"-r", "--noscan", "mkv", discPath, strconv.Itoa(titleIdx), outputDir}
-
-	cmd := r.execCommand(ctx, r.makemkvconPath, args...)
-
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return fmt.Errorf("failed to create stdout pipe: %w", err)
-	}
-
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start makemkvcon: %w", err)
-	}
-
-	scanner := bufio.NewScanner(stdout)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if onLine != nil {
-			onLine(line)
-		}
-		r.handleProgressLine(line, onProgress)
-	}
-
-	if err := cmd.Wait(); err != nil {
-		return fmt.Errorf("makemkvcon failed: %w", err)
-	}
-
-	return nil
+	args := []string{"-r", "--noscan", "mkv", makeMKVSource(discPath), strconv.Itoa(titleIdx), outputDir}
+	return r.run(ctx, args, onLine, onProgress)
 }
 
 // ripAllTitles rips all titles from a disc
 func (r *DefaultMakeMKVRunner) ripAllTitles(ctx context.Context, discPath, outputDir string, onLine LineCallback, onProgress ProgressCallback) error {
-	args := r.buildMkvArgs(discPath, outputDir, nil)
+	return r.run(ctx, r.buildMkvArgs(discPath, outputDir, nil), onLine, onProgress)
+}
 
+// Backup writes a decrypted copy of the whole disc into outputDir
+func (r *DefaultMakeMKVRunner) Backup(ctx context.Context, discPath, outputDir string, onLine LineCallback, onProgress ProgressCallback) error {
+	return r.run(ctx, r.buildBackupArgs(discPath, outputDir), onLine, onProgress)
+}
+
+// run runs makemkvcon with args, passing each output line to onLine and
+// progress updates to onProgress
+func (r *DefaultMakeMKVRunner) run(ctx context.Context, args []string, onLine LineCallback, onProgress ProgressCallback) error {
 	cmd := r.execCommand(ctx, r.makemkvconPath, args...)
 
 	stdout, err := cmd.StdoutPipe()
@@ -158,12 +267,12 @@ func (r *DefaultMakeMKVRunner) handleProgressLine(line string, callback Progress
 
 // buildInfoArgs builds command line arguments for info command
 func (r *DefaultMakeMKVRunner) buildInfoArgs(discPath string) []string {
-	return []string{"-r", "--noscan", "info", discPath}
+	return []string{"-r", "--noscan", "info", makeMKVSource(discPath)}
 }
 
 // buildMkvArgs builds command line arguments for mkv command
 func (r *DefaultMakeMKVRunner) buildMkvArgs(discPath, outputDir string, titleIndices []int) []string {
-	args := []string{"-r", "--noscan", "mkv", discPath}
+	args := []string{"-r", "--noscan", "mkv", makeMKVSource(discPath)}
 
 	if len(titleIndices) == 0 {
 		args = append(args, "all")
@@ -180,5 +289,10 @@ func (r *DefaultMakeMKVRunner) buildMkvArgs(discPath, outputDir string, titleInd
 	return args
 }
 
+// buildBackupArgs builds command line arguments for backup command
+func (r *DefaultMakeMKVRunner) buildBackupArgs(discPath, outputDir string) []string {
+	return []string{"-r", "--noscan", "backup", "--decrypt", makeMKVSource(discPath), outputDir}
+}
+
 // Ensure DefaultMakeMKVRunner implements MakeMKVRunner interface
 var _ MakeMKVRunner = (*DefaultMakeMKVRunner)(nil)
@@ -2,6 +2,7 @@ package ripper
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -102,6 +103,38 @@ func TestDefaultMakeMKVRunner_BuildMkvArgs_AllTitles(t *testing.T) {
 	}
 }
 
+func TestDefaultMakeMKVRunner_BuildBackupArgs(t *testing.T) {
+	runner := NewMakeMKVRunner("")
+
+	args := runner.buildBackupArgs("disc:0", "/output")
+
+	expected := []string{"-r", "--noscan", "backup", "--decrypt", "disc:0", "/output"}
+	if !stringSliceEqual(args, expected) {
+		t.Errorf("buildBackupArgs = %v, want %v", args, expected)
+	}
+}
+
+func TestDefaultMakeMKVRunner_BuildArgs_ImageSources(t *testing.T) {
+	runner := NewMakeMKVRunner("")
+	dir := t.TempDir()
+	iso := filepath.Join(dir, "Movie.iso")
+	if err := os.WriteFile(iso, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	args := runner.buildInfoArgs(iso)
+	expected := []string{"-r", "--noscan", "info", "iso:" + iso}
+	if !stringSliceEqual(args, expected) {
+		t.Errorf("buildInfoArgs = %v, want %v", args, expected)
+	}
+
+	args = runner.buildMkvArgs(dir, "/output", nil)
+	expected = []string{"-r", "--noscan", "mkv", "file:" + dir, "all", "/output"}
+	if !stringSliceEqual(args, expected) {
+		t.Errorf("buildMkvArgs = %v, want %v", args, expected)
+	}
+}
+
 func TestDefaultMakeMKVRunner_BuildMkvArgs_SpecificTitles(t *testing.T) {
 	runner := NewMakeMKVRunner("")
 
@@ -203,3 +236,119 @@ func stringSliceEqual(a, b []string) bool {
 	}
 	return true
 }
+
+func TestParseMakeMKVCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		wantVersion string
+		wantLicense bool
+	}{
+		{
+			name:        "valid license",
+			output:      `MSG:1005,0,1,"MakeMKV v1.17.7 linux(x64-release) started","%1 started","MakeMKV v1.17.7 linux(x64-release)"` + "\n" + `DRV:0,256,999,0,"","",""`,
+			wantVersion: "1.17.7",
+		},
+		{
+			name:        "mock output",
+			output:      `MSG:1005,0,0,"MakeMKV v1.17.6 (mock) started","MakeMKV v1.17.6 (mock) started"`,
+			wantVersion: "1.17.6",
+		},
+		{
+			name: "expired beta",
+			output: `MSG:1005,0,1,"MakeMKV v1.17.5 linux(x64-release) started","%1 started","MakeMKV v1.17.5 linux(x64-release)"` + "\n" +
+				`MSG:5021,260,1,"This application version is too old. Please download the latest version at http://www.makemkv.com/ or enter a registration key to continue using the current version.","%1","x"`,
+			wantVersion: "1.17.5",
+			wantLicense: true,
+		},
+		{
+			name:        "evaluation expired",
+			output:      `MSG:5095,0,0,"Evaluation period has expired. Please purchase an activation key.","x"`,
+			wantLicense: true,
+		},
+		{
+			name:   "no output",
+			output: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := parseMakeMKVCheck(tt.output)
+			if version != tt.wantVersion {
+				t.Errorf("version = %q, want %q", version, tt.wantVersion)
+			}
+			if tt.wantLicense {
+				if !errors.Is(err, ErrMakeMKVLicense) {
+					t.Errorf("err = %v, want ErrMakeMKVLicense", err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseDriveState(t *testing.T) {
+	output := `MSG:1005,0,1,"MakeMKV v1.17.7 linux(x64-release) started","%1 started","MakeMKV v1.17.7 linux(x64-release)"
+DRV:0,2,999,12,"BD-RE HL-DT-ST BD-RE WH16NS60","THE_SHOW_S1D2","/dev/sr0"
+DRV:1,0,999,0,"DVD+R-DL ASUS","","/dev/sr1"
+DRV:2,256,999,0,"","",""`
+
+	tests := []struct {
+		discPath  string
+		wantReady bool
+		wantFound bool
+	}{
+		{"disc:0", true, true},
+		{"disc:1", false, true},
+		{"dev:/dev/sr0", true, true},
+		{"/dev/sr1", false, true},
+		{"disc:5", false, false},
+		{"/dev/sr9", false, false},
+	}
+
+	for _, tt := range tests {
+		ready, found := parseDriveState(output, tt.discPath)
+		if ready != tt.wantReady || found != tt.wantFound {
+			t.Errorf("parseDriveState(%q) = %v, %v; want %v, %v", tt.discPath, ready, found, tt.wantReady, tt.wantFound)
+		}
+	}
+}
+
+func TestDefaultMakeMKVRunner_DriveReady(t *testing.T) {
+	var gotArgs []string
+	runner := &DefaultMakeMKVRunner{
+		execCommand: func(ctx context.Context, name string, args ...string) *exec.Cmd {
+			gotArgs = args
+			return exec.CommandContext(ctx, "echo", `DRV:0,1,999,0,"BD-RE drive","","/dev/sr0"`)
+		},
+	}
+
+	ready, err := runner.DriveReady(context.Background(), "disc:0")
+	if err != nil || ready {
+		t.Errorf("DriveReady() = %v, %v; want false (tray open)", ready, err)
+	}
+	if strings.Join(gotArgs, " ") != "-r info disc:9999" {
+		t.Errorf("args = %v, want a drive listing without a title scan", gotArgs)
+	}
+
+	if _, err := runner.DriveReady(context.Background(), "disc:3"); err == nil {
+		t.Error("DriveReady() of a missing drive should fail")
+	}
+}
+
+func TestCheckMakeMKV_WithMock(t *testing.T) {
+	mockPath := findMockMakeMKV()
+	if mockPath == "" {
+		t.Skip("mock-makemkv not found, skipping integration test")
+	}
+
+	version, err := CheckMakeMKV(mockPath)
+	if err != nil {
+		t.Fatalf("CheckMakeMKV failed: %v", err)
+	}
+	if version == "" {
+		t.Error("Expected version from mock")
+	}
+}
@@ -0,0 +1,156 @@
+package ripper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ripStateFile is where per-title progress is recorded inside a rip directory
+const ripStateFile = ".rip/titles.json"
+
+// ripState records which titles of a disc have finished ripping, so an
+// interrupted rip can resume with the titles still missing
+type ripState struct {
+	DiscName  string        `json:"disc_name"`
+	DiscID    string        `json:"disc_id"`
+	Titles    int           `json:"titles"`
+	Completed []rippedTitle `json:"completed"`
+	byIndex   map[int][]ripFile
+}
+
+// rippedTitle lists the files MakeMKV wrote for one title
+type rippedTitle struct {
+	Index int       `json:"index"`
+	Files []ripFile `json:"files"`
+}
+
+// ripFile is one MKV file with the size it had when its title finished
+type ripFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+// newRipState starts an empty state for a disc
+func newRipState(info *DiscInfo) *ripState {
+	return &ripState{
+		DiscName: info.Name,
+		DiscID:   info.ID,
+		Titles:   len(info.Titles),
+		byIndex:  make(map[int][]ripFile),
+	}
+}
+
+// loadRipState reads the state from dir, returning nil if there is none
+func loadRipState(dir string) (*ripState, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ripStateFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rip state: %w", err)
+	}
+
+	var st ripState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("failed to parse rip state: %w", err)
+	}
+	st.byIndex = make(map[int][]ripFile)
+	for _, t := range st.Completed {
+		st.byIndex[t.Index] = t.Files
+	}
+	return &st, nil
+}
+
+// save writes the state into dir
+func (s *ripState) save(dir string) error {
+	path := filepath.Join(dir, ripStateFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create rip state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode rip state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write rip state: %w", err)
+	}
+	return nil
+}
+
+// matches reports whether the state was recorded for the same disc
+func (s *ripState) matches(info *DiscInfo) bool {
+	return s.DiscName == info.Name && s.DiscID == info.ID && s.Titles == len(info.Titles)
+}
+
+// complete reports whether title idx finished and its files are still intact in dir
+func (s *ripState) complete(dir string, idx int) bool {
+	files, ok := s.byIndex[idx]
+	if !ok || len(files) == 0 {
+		return false
+	}
+	for _, f := range files {
+		info, err := os.Stat(filepath.Join(dir, f.Name))
+		if err != nil || info.Size() == 0 || info.Size() != f.Size {
+			return false
+		}
+	}
+	return true
+}
+
+// markComplete records the files title idx produced
+func (s *ripState) markComplete(idx int, files []ripFile) {
+	s.byIndex[idx] = files
+	s.Completed = s.Completed[:0]
+	for i, f := range s.byIndex {
+		s.Completed = append(s.Completed, rippedTitle{Index: i, Files: f})
+	}
+	sort.Slice(s.Completed, func(a, b int) bool { return s.Completed[a].Index < s.Completed[b].Index })
+}
+
+// owns reports whether name belongs to a completed title
+func (s *ripState) owns(name string) bool {
+	for _, files := range s.byIndex {
+		for _, f := range files {
+			if f.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mkvFiles returns the sizes of the MKV files directly in dir
+func mkvFiles(dir string) (map[string]int64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]int64)
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(strings.ToLower(e.Name()), ".mkv") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		files[e.Name()] = info.Size()
+	}
+	return files, nil
+}
+
+// newFiles returns the files in after that are not in before
+func newFiles(before, after map[string]int64) []ripFile {
+	var files []ripFile
+	for name, size := range after {
+		if _, ok := before[name]; !ok {
+			files = append(files, ripFile{Name: name, Size: size})
+		}
+	}
+	sort.Slice(files, func(a, b int) bool { return files[a].Name < files[b].Name })
+	return files
+}
@@ -51,17 +51,61 @@ func (r *Ripper) Rip(ctx context.Context, req *RipRequest, outputDir string, onL
 
 	r.logger.Info("Output directory: %s", outputDir)
 
-	// Create output directory
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
+	// Don't replace an earlier rip unless asked to
+	if entries, err := os.ReadDir(outputDir); err == nil && len(entries) > 0 && !req.Overwrite {
+		r.logger.Error("Output directory already has a rip: %s", outputDir)
+		return nil, fmt.Errorf("output directory already has a rip: %s (rip with overwrite to replace it)", outputDir)
+	}
+
+	if req.RipMode == model.RipModeBackup {
+		return r.backup(ctx, req, outputDir, result, onLine, onProgress)
+	}
+
+	discInfo := req.DiscInfo
+	if discInfo == nil {
+		info, err := r.runner.GetDiscInfo(ctx, req.DiscPath)
+		if err != nil {
+			r.logger.Error("Failed to read disc: %v", err)
+			return nil, fmt.Errorf("failed to read disc: %w", err)
+		}
+		discInfo = info
+	}
+
+	// Rip into a sibling .partial directory and move it into place only once
+	// everything succeeded, so later stages never see a half-ripped disc.
+	// Titles an earlier attempt at the same disc finished are kept; anything
+	// else left in the .partial directory is discarded.
+	partialDir := PartialDir(outputDir)
+	state, err := loadRipState(partialDir)
+	if err != nil {
+		r.logger.Error("Ignoring unreadable rip state: %v", err)
+	}
+	if state == nil || !state.matches(discInfo) {
+		if err := os.RemoveAll(partialDir); err != nil {
+			r.logger.Error("Failed to remove stale partial directory: %v", err)
+			return nil, fmt.Errorf("failed to remove stale partial directory: %w", err)
+		}
+		state = newRipState(discInfo)
+	}
+	if err := os.MkdirAll(partialDir, 0755); err != nil {
 		r.logger.Error("Failed to create output directory: %v", err)
 		return nil, fmt.Errorf("failed to create output directory: %w", err)
 	}
 
 	// Run ripping
 	r.logger.Info("Starting MakeMKV rip from %s", req.DiscPath)
-	err := r.runner.RipTitles(ctx, req.DiscPath, outputDir, nil, onLine, onProgress)
+	if len(discInfo.Titles) == 0 {
+		// Nothing to track per title, so rip everything in one pass
+		err = r.runner.RipTitles(ctx, req.DiscPath, partialDir, nil, onLine, onProgress)
+	} else {
+		err = r.ripMissingTitles(ctx, req.DiscPath, partialDir, discInfo, state, onLine, onProgress)
+	}
+	if ctx.Err() != nil {
+		// A killed makemkvcon may exit cleanly or with its own error
+		err = ctx.Err()
+	}
 	if err != nil {
-		r.logger.Error("Rip failed: %v", err)
+		r.logger.Error("Rip failed: %v (partial output kept in %s)", err, partialDir)
 		result.Status = model.StatusFailed
 		result.Error = err
 		result.CompletedAt = time.Now()
@@ -70,11 +114,16 @@ func (r *Ripper) Rip(ctx context.Context, req *RipRequest, outputDir string, onL
 
 	r.logger.Info("Rip completed, creating organization scaffolding...")
 	// Create organization scaffolding for manual review
-	if err := CreateOrganizationScaffolding(outputDir, req); err != nil {
+	if err := CreateOrganizationScaffolding(partialDir, req); err != nil {
 		r.logger.Error("Failed to create organization scaffolding: %v", err)
 		return nil, fmt.Errorf("failed to create organization scaffolding: %w", err)
 	}
 
+	if err := replaceDir(partialDir, outputDir); err != nil {
+		r.logger.Error("Failed to move rip into place: %v", err)
+		return nil, fmt.Errorf("failed to move rip into place: %w", err)
+	}
+
 	result.Status = model.StatusCompleted
 	result.CompletedAt = time.Now()
 
@@ -82,12 +131,141 @@ func (r *Ripper) Rip(ctx context.Context, req *RipRequest, outputDir string, onL
 	return result, nil
 }
 
+// backup copies the whole disc, decrypted, to outputDir through a .partial
+// directory like a title rip. A backup isn't resumed and gets no
+// organization scaffolding, as there are no titles to sort.
+func (r *Ripper) backup(ctx context.Context, req *RipRequest, outputDir string, result *RipResult, onLine LineCallback, onProgress ProgressCallback) (*RipResult, error) {
+	partialDir := PartialDir(outputDir)
+	if err := os.RemoveAll(partialDir); err != nil {
+		r.logger.Error("Failed to remove stale partial directory: %v", err)
+		return nil, fmt.Errorf("failed to remove stale partial directory: %w", err)
+	}
+	if err := os.MkdirAll(partialDir, 0755); err != nil {
+		r.logger.Error("Failed to create output directory: %v", err)
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	r.logger.Info("Starting MakeMKV backup from %s", req.DiscPath)
+	err := r.runner.Backup(ctx, req.DiscPath, partialDir, onLine, onProgress)
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		r.logger.Error("Backup failed: %v (partial output kept in %s)", err, partialDir)
+		result.Status = model.StatusFailed
+		result.Error = err
+		result.CompletedAt = time.Now()
+		return result, err
+	}
+
+	if err := replaceDir(partialDir, outputDir); err != nil {
+		r.logger.Error("Failed to move backup into place: %v", err)
+		return nil, fmt.Errorf("failed to move backup into place: %w", err)
+	}
+
+	result.Status = model.StatusCompleted
+	result.CompletedAt = time.Now()
+
+	r.logger.Info("Backup finished successfully in %s", result.Duration())
+	return result, nil
+}
+
+// replaceDir moves partialDir to outputDir, removing whatever was at
+// outputDir first. The earlier rip is only removed once the new one is done.
+func replaceDir(partialDir, outputDir string) error {
+	if err := os.RemoveAll(outputDir); err != nil {
+		return err
+	}
+	return os.Rename(partialDir, outputDir)
+}
+
+// ripMissingTitles rips the titles state doesn't have yet one at a time,
+// recording each in state as it finishes
+func (r *Ripper) ripMissingTitles(ctx context.Context, discPath, partialDir string, info *DiscInfo, state *ripState, onLine LineCallback, onProgress ProgressCallback) error {
+	var pending []int
+	for _, t := range info.Titles {
+		if !state.complete(partialDir, t.Index) {
+			pending = append(pending, t.Index)
+		}
+	}
+	done := len(info.Titles) - len(pending)
+	if done > 0 {
+		r.logger.Info("Resuming: %d of %d titles already ripped", done, len(info.Titles))
+	}
+
+	// Files not belonging to a finished title are from an interrupted attempt
+	existing, err := mkvFiles(partialDir)
+	if err != nil {
+		return fmt.Errorf("failed to list partial directory: %w", err)
+	}
+	for name := range existing {
+		if !state.owns(name) {
+			r.logger.Info("Removing incomplete title file: %s", name)
+			if err := os.Remove(filepath.Join(partialDir, name)); err != nil {
+				return fmt.Errorf("failed to remove incomplete title file: %w", err)
+			}
+		}
+	}
+
+	total := len(info.Titles)
+	for i, idx := range pending {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		before, err := mkvFiles(partialDir)
+		if err != nil {
+			return fmt.Errorf("failed to list partial directory: %w", err)
+		}
+
+		// Scale each title's 0-100 into its share of the whole disc
+		finished := done + i
+		titleProgress := func(p Progress) {
+			if onProgress == nil {
+				return
+			}
+			p.CurrentTitle = idx
+			p.TotalTitles = total
+			p.Percent = (float64(finished)*100 + p.Percent) / float64(total)
+			onProgress(p)
+		}
+
+		if err := r.runner.RipTitles(ctx, discPath, partialDir, []int{idx}, onLine, titleProgress); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		after, err := mkvFiles(partialDir)
+		if err != nil {
+			return fmt.Errorf("failed to list partial directory: %w", err)
+		}
+		state.markComplete(idx, newFiles(before, after))
+		// The title itself is fine, only resuming would redo it
+		if err := state.save(partialDir); err != nil {
+			r.logger.Error("Failed to record title %d: %v", idx, err)
+		}
+	}
+	return nil
+}
+
+// PartialDir returns the temporary directory a rip is written to before it is
+// moved to outputDir
+func PartialDir(outputDir string) string {
+	return filepath.Clean(outputDir) + ".partial"
+}
+
 // BuildOutputDir builds the output directory path for a rip request
 func (r *Ripper) BuildOutputDir(req *RipRequest) string {
 	safeName := req.SafeName()
 
 	switch req.Type {
 	case MediaTypeMovie:
+		// Later discs of a multi-disc movie get a directory of their own
+		if req.Disc > 1 {
+			safeName = fmt.Sprintf("%s_Disc%d", safeName, req.Disc)
+		}
 		return filepath.Join(r.stagingBase, "1-ripped", "movies", safeName)
 	case MediaTypeTV:
 		season := fmt.Sprintf("S%02d", req.Season)
@@ -3,6 +3,7 @@ package ripper
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -236,11 +237,166 @@ func TestRipper_Rip_CreatesOrganizationScaffolding(t *testing.T) {
 	}
 }
 
+func TestRipper_Rip_MovesPartialIntoPlaceOnSuccess(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mockRunner := &testMakeMKVRunner{}
+	ripper := NewRipper(tmpDir, mockRunner, nil)
+
+	req := &RipRequest{
+		Type:     MediaTypeMovie,
+		Name:     "Test Movie",
+		DiscPath: "disc:0",
+	}
+
+	outputDir := filepath.Join(tmpDir, "1-ripped", "movies", "Test_Movie")
+	if _, err := ripper.Rip(context.Background(), req, outputDir, nil, nil); err != nil {
+		t.Fatalf("Rip failed: %v", err)
+	}
+
+	if mockRunner.ripOutputDir != PartialDir(outputDir) {
+		t.Errorf("MakeMKV wrote to %q, want %q", mockRunner.ripOutputDir, PartialDir(outputDir))
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "title_t00.mkv")); err != nil {
+		t.Errorf("ripped title not in final directory: %v", err)
+	}
+	if _, err := os.Stat(PartialDir(outputDir)); !os.IsNotExist(err) {
+		t.Error("partial directory should not remain after success")
+	}
+}
+
+func TestRipper_Rip_KeepsPartialOnFailure(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mockRunner := &testMakeMKVRunner{ripError: errors.New("read error")}
+	ripper := NewRipper(tmpDir, mockRunner, nil)
+
+	req := &RipRequest{
+		Type:     MediaTypeMovie,
+		Name:     "Test Movie",
+		DiscPath: "disc:0",
+	}
+
+	outputDir := filepath.Join(tmpDir, "1-ripped", "movies", "Test_Movie")
+	if _, err := ripper.Rip(context.Background(), req, outputDir, nil, nil); err == nil {
+		t.Fatal("Expected error from Rip")
+	}
+
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Error("final output directory must not exist after a failed rip")
+	}
+	if _, err := os.Stat(filepath.Join(PartialDir(outputDir), "title_t00.mkv")); err != nil {
+		t.Errorf("partial output should be kept for debugging: %v", err)
+	}
+
+	// A retry discards the stale partial directory and succeeds
+	mockRunner.ripError = nil
+	if _, err := ripper.Rip(context.Background(), req, outputDir, nil, nil); err != nil {
+		t.Fatalf("retry Rip failed: %v", err)
+	}
+	if _, err := os.Stat(outputDir); err != nil {
+		t.Errorf("final output directory missing after retry: %v", err)
+	}
+}
+
+func TestRipper_Rip_Cancelled(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// The runner exits cleanly, as makemkvcon can when killed
+	mockRunner := &testMakeMKVRunner{}
+	ripper := NewRipper(tmpDir, mockRunner, nil)
+
+	req := &RipRequest{
+		Type:     MediaTypeMovie,
+		Name:     "Test Movie",
+		DiscPath: "disc:0",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	outputDir := filepath.Join(tmpDir, "1-ripped", "movies", "Test_Movie")
+	if _, err := ripper.Rip(ctx, req, outputDir, nil, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Rip() error = %v, want context.Canceled", err)
+	}
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		t.Error("a cancelled rip must not be moved into place")
+	}
+}
+
+func TestRipper_Rip_RefusesExistingOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mockRunner := &testMakeMKVRunner{}
+	ripper := NewRipper(tmpDir, mockRunner, nil)
+
+	req := &RipRequest{
+		Type:     MediaTypeMovie,
+		Name:     "Test Movie",
+		DiscPath: "disc:0",
+	}
+
+	outputDir := filepath.Join(tmpDir, "1-ripped", "movies", "Test_Movie")
+	os.MkdirAll(outputDir, 0755)
+	os.WriteFile(filepath.Join(outputDir, "title_t00.mkv"), []byte("earlier rip"), 0644)
+
+	if _, err := ripper.Rip(context.Background(), req, outputDir, nil, nil); err == nil {
+		t.Fatal("Expected error for existing output directory")
+	}
+	if mockRunner.ripTitlesCalled {
+		t.Error("MakeMKV should not run when output already exists")
+	}
+}
+
+func TestRipper_Rip_ExistingOutput(t *testing.T) {
+	tests := []struct {
+		name      string
+		earlier   bool // An earlier rip left a file behind
+		overwrite bool
+	}{
+		{name: "empty directory", earlier: false},
+		{name: "overwrite earlier rip", earlier: true, overwrite: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			mockRunner := &testMakeMKVRunner{}
+			ripper := NewRipper(tmpDir, mockRunner, nil)
+
+			req := &RipRequest{
+				Type:      MediaTypeMovie,
+				Name:      "Test Movie",
+				DiscPath:  "disc:0",
+				Overwrite: tt.overwrite,
+			}
+
+			outputDir := filepath.Join(tmpDir, "1-ripped", "movies", "Test_Movie")
+			os.MkdirAll(outputDir, 0755)
+			if tt.earlier {
+				os.WriteFile(filepath.Join(outputDir, "old.mkv"), []byte("old"), 0644)
+			}
+
+			if _, err := ripper.Rip(context.Background(), req, outputDir, nil, nil); err != nil {
+				t.Fatalf("Rip() error = %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(outputDir, "old.mkv")); !os.IsNotExist(err) {
+				t.Error("the earlier rip should have been replaced")
+			}
+			if _, err := os.Stat(filepath.Join(outputDir, "_main")); err != nil {
+				t.Errorf("new rip not moved into place: %v", err)
+			}
+		})
+	}
+}
+
 // Test helper implementations
 type testMakeMKVRunner struct {
 	discInfo        *DiscInfo
 	ripError        error
 	ripTitlesCalled bool
+	ripOutputDir    string
+	backupCalled    bool
 }
 
 func (m *testMakeMKVRunner) GetDiscInfo(ctx context.Context, discPath string) (*DiscInfo, error) {
@@ -258,5 +414,140 @@ func (m *testMakeMKVRunner) GetDiscInfo(ctx context.Context, discPath string) (*
 
 func (m *testMakeMKVRunner) RipTitles(ctx context.Context, discPath, outputDir string, titleIndices []int, onLine LineCallback, onProgress ProgressCallback) error {
 	m.ripTitlesCalled = true
+	m.ripOutputDir = outputDir
+	// Simulate MakeMKV writing a title before finishing or failing
+	os.WriteFile(filepath.Join(outputDir, "title_t00.mkv"), []byte("mkv"), 0644)
+	return m.ripError
+}
+
+func (m *testMakeMKVRunner) Backup(ctx context.Context, discPath, outputDir string, onLine LineCallback, onProgress ProgressCallback) error {
+	m.backupCalled = true
+	m.ripOutputDir = outputDir
+	// Simulate MakeMKV writing the disc structure
+	os.MkdirAll(filepath.Join(outputDir, "BDMV", "STREAM"), 0755)
+	os.WriteFile(filepath.Join(outputDir, "BDMV", "STREAM", "00000.m2ts"), []byte("m2ts"), 0644)
 	return m.ripError
 }
+
+// titleRunner writes one file per ripped title and can fail on a given title
+type titleRunner struct {
+	info   *DiscInfo
+	failOn int // Title index to fail on after writing part of it, -1 for none
+	ripped []int
+}
+
+func (m *titleRunner) GetDiscInfo(ctx context.Context, discPath string) (*DiscInfo, error) {
+	return m.info, nil
+}
+
+func (m *titleRunner) Backup(ctx context.Context, discPath, outputDir string, onLine LineCallback, onProgress ProgressCallback) error {
+	return errors.New("titleRunner does not back up")
+}
+
+func (m *titleRunner) RipTitles(ctx context.Context, discPath, outputDir string, titleIndices []int, onLine LineCallback, onProgress ProgressCallback) error {
+	for _, idx := range titleIndices {
+		m.ripped = append(m.ripped, idx)
+		path := filepath.Join(outputDir, fmt.Sprintf("title_t%02d.mkv", idx))
+		if idx == m.failOn {
+			os.WriteFile(path, []byte("trunc"), 0644)
+			return errors.New("read error")
+		}
+		os.WriteFile(path, []byte(fmt.Sprintf("title %d complete", idx)), 0644)
+		if onProgress != nil {
+			onProgress(Progress{Percent: 100})
+		}
+	}
+	return nil
+}
+
+func TestRipper_Rip_Backup(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mockRunner := &testMakeMKVRunner{}
+	ripper := NewRipper(tmpDir, mockRunner, nil)
+
+	req := &RipRequest{Type: MediaTypeMovie, Name: "Test Movie", DiscPath: "disc:0", RipMode: model.RipModeBackup}
+	outputDir := filepath.Join(tmpDir, "1-ripped", "movies", "Test_Movie")
+
+	result, err := ripper.Rip(context.Background(), req, outputDir, nil, nil)
+	if err != nil {
+		t.Fatalf("Rip() error = %v", err)
+	}
+	if !result.IsSuccess() {
+		t.Errorf("Status = %s, want completed", result.Status)
+	}
+	if !mockRunner.backupCalled || mockRunner.ripTitlesCalled {
+		t.Errorf("backup called = %v, titles ripped = %v; want only a backup", mockRunner.backupCalled, mockRunner.ripTitlesCalled)
+	}
+	if mockRunner.ripOutputDir != PartialDir(outputDir) {
+		t.Errorf("backup written to %s, want %s", mockRunner.ripOutputDir, PartialDir(outputDir))
+	}
+	if _, err := os.Stat(filepath.Join(outputDir, "BDMV", "STREAM", "00000.m2ts")); err != nil {
+		t.Errorf("backup not moved into place: %v", err)
+	}
+	// There are no titles to organize
+	if _, err := os.Stat(filepath.Join(outputDir, "_main")); !os.IsNotExist(err) {
+		t.Errorf("backup should get no organization scaffolding, stat _main: %v", err)
+	}
+}
+
+func TestRipper_Rip_ResumesFinishedTitles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	info := &DiscInfo{Name: "Test Disc", ID: "TEST_DISC", Titles: []TitleInfo{{Index: 0}, {Index: 1}, {Index: 2}}}
+	runner := &titleRunner{info: info, failOn: 1}
+	ripper := NewRipper(tmpDir, runner, nil)
+
+	req := &RipRequest{Type: MediaTypeMovie, Name: "Test Movie", DiscPath: "disc:0"}
+	outputDir := filepath.Join(tmpDir, "1-ripped", "movies", "Test_Movie")
+
+	if _, err := ripper.Rip(context.Background(), req, outputDir, nil, nil); err == nil {
+		t.Fatal("Expected error from interrupted rip")
+	}
+
+	// The retry skips title 0, redoes the truncated title 1 and finishes
+	runner.failOn = -1
+	runner.ripped = nil
+	var percents []float64
+	onProgress := func(p Progress) { percents = append(percents, p.Percent) }
+	if _, err := ripper.Rip(context.Background(), req, outputDir, nil, onProgress); err != nil {
+		t.Fatalf("resumed Rip failed: %v", err)
+	}
+
+	if fmt.Sprint(runner.ripped) != "[1 2]" {
+		t.Errorf("resumed rip ripped titles %v, want [1 2]", runner.ripped)
+	}
+	data, err := os.ReadFile(filepath.Join(outputDir, "title_t01.mkv"))
+	if err != nil || string(data) != "title 1 complete" {
+		t.Errorf("title 1 = %q, %v; want the re-ripped file", data, err)
+	}
+	if fmt.Sprintf("%.0f", percents) != "[67 100]" {
+		t.Errorf("progress = %.0f, want [67 100] across the whole disc", percents)
+	}
+}
+
+func TestRipper_Rip_DiscardsPartialFromOtherDisc(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	info := &DiscInfo{Name: "Disc One", ID: "DISC_1", Titles: []TitleInfo{{Index: 0}, {Index: 1}}}
+	runner := &titleRunner{info: info, failOn: 1}
+	ripper := NewRipper(tmpDir, runner, nil)
+
+	req := &RipRequest{Type: MediaTypeMovie, Name: "Test Movie", DiscPath: "disc:0"}
+	outputDir := filepath.Join(tmpDir, "1-ripped", "movies", "Test_Movie")
+
+	if _, err := ripper.Rip(context.Background(), req, outputDir, nil, nil); err == nil {
+		t.Fatal("Expected error from interrupted rip")
+	}
+
+	// A different disc in the drive starts over
+	runner.info = &DiscInfo{Name: "Disc Two", ID: "DISC_2", Titles: []TitleInfo{{Index: 0}, {Index: 1}}}
+	runner.failOn = -1
+	runner.ripped = nil
+	if _, err := ripper.Rip(context.Background(), req, outputDir, nil, nil); err != nil {
+		t.Fatalf("Rip failed: %v", err)
+	}
+	if fmt.Sprint(runner.ripped) != "[0 1]" {
+		t.Errorf("ripped titles %v, want [0 1]", runner.ripped)
+	}
+}
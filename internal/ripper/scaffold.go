@@ -7,8 +7,9 @@ import (
 	"time"
 )
 
-// Common extras categories for organization
-var extrasCategories = []string{
+// DefaultExtrasFolders are the _extras subfolders created after a rip when
+// the request names none: every extras type Jellyfin recognizes
+var DefaultExtrasFolders = []string{
 	"behind the scenes",
 	"deleted scenes",
 	"featurettes",
@@ -20,8 +21,8 @@ var extrasCategories = []string{
 }
 
 // CreateOrganizationScaffolding creates the directory structure for manual review
-// after ripping. This includes _discarded, _extras/{categories}, and type-specific
-// directories (_main for movies, _episodes for TV shows).
+// after ripping. This includes _discarded, _extras/{req.ExtrasFolders}, and
+// type-specific directories (_main for movies, _episodes for TV shows).
 func CreateOrganizationScaffolding(outputDir string, req *RipRequest) error {
 	// Create _discarded directory
 	if err := os.MkdirAll(filepath.Join(outputDir, "_discarded"), 0755); err != nil {
@@ -29,7 +30,11 @@ func CreateOrganizationScaffolding(outputDir string, req *RipRequest) error {
 	}
 
 	// Create _extras subdirectories
-	for _, category := range extrasCategories {
+	folders := req.ExtrasFolders
+	if folders == nil {
+		folders = DefaultExtrasFolders
+	}
+	for _, category := range folders {
 		path := filepath.Join(outputDir, "_extras", category)
 		if err := os.MkdirAll(path, 0755); err != nil {
 			return fmt.Errorf("failed to create _extras/%s: %w", category, err)
@@ -174,6 +174,44 @@ func TestCreateOrganizationScaffolding_ExistingDirectory_NoError(t *testing.T) {
 	}
 }
 
+func TestCreateOrganizationScaffolding_ExtrasFolders(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	req := &RipRequest{
+		Type:          MediaTypeMovie,
+		Name:          "The Matrix",
+		ExtrasFolders: []string{"featurettes", "trailers"},
+	}
+	if err := CreateOrganizationScaffolding(tmpDir, req); err != nil {
+		t.Fatalf("CreateOrganizationScaffolding failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(tmpDir, "_extras"))
+	if err != nil {
+		t.Fatalf("Failed to read _extras: %v", err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	if len(got) != 2 || got[0] != "featurettes" || got[1] != "trailers" {
+		t.Errorf("_extras contains %v, want only [featurettes trailers]", got)
+	}
+
+	// An empty list creates no extras folders
+	emptyDir := t.TempDir()
+	req.ExtrasFolders = []string{}
+	if err := CreateOrganizationScaffolding(emptyDir, req); err != nil {
+		t.Fatalf("CreateOrganizationScaffolding failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(emptyDir, "_extras")); !os.IsNotExist(err) {
+		t.Errorf("Expected no _extras directory, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(emptyDir, "_main")); err != nil {
+		t.Errorf("Expected _main to exist: %v", err)
+	}
+}
+
 func TestCreateOrganizationScaffolding_InvalidOutputDir_ReturnsError(t *testing.T) {
 	req := &RipRequest{
 		Type: MediaTypeMovie,
@@ -0,0 +1,114 @@
+package ripper
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Disc sources other than a drive. makemkvcon reads a disc image with
+// "iso:<file>" and a BDMV or VIDEO_TS folder with "file:<dir>".
+const (
+	isoSourcePrefix  = "iso:"
+	fileSourcePrefix = "file:"
+)
+
+// ValidateDiscPath checks that discPath names something MakeMKV can read:
+// a drive ("disc:N", "dev:/dev/sr0" or a /dev path), an ISO image
+// ("iso:/path/to.iso" or a path ending in .iso) or a disc folder
+// ("file:/path" or a plain directory). Images and folders must exist.
+func ValidateDiscPath(discPath string) error {
+	switch {
+	case discPath == "":
+		return fmt.Errorf("disc path is required")
+	case strings.HasPrefix(discPath, "disc:"):
+		n, err := strconv.Atoi(strings.TrimPrefix(discPath, "disc:"))
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid disc path %q: drive number must be a non-negative integer", discPath)
+		}
+		return nil
+	case strings.HasPrefix(discPath, "dev:"):
+		if strings.TrimPrefix(discPath, "dev:") == "" {
+			return fmt.Errorf("invalid disc path %q: missing device", discPath)
+		}
+		return nil
+	case strings.HasPrefix(discPath, "/dev/"):
+		return nil
+	case strings.HasPrefix(discPath, isoSourcePrefix):
+		return checkImage(strings.TrimPrefix(discPath, isoSourcePrefix))
+	case strings.HasPrefix(discPath, fileSourcePrefix):
+		return checkFolder(strings.TrimPrefix(discPath, fileSourcePrefix))
+	}
+
+	info, err := os.Stat(discPath)
+	if err != nil {
+		return fmt.Errorf("invalid disc path %q: not a drive, ISO image or disc folder", discPath)
+	}
+	if info.IsDir() {
+		return nil
+	}
+	if isISOName(discPath) {
+		return nil
+	}
+	return fmt.Errorf("invalid disc path %q: files must be ISO images", discPath)
+}
+
+// checkImage checks that path is an existing ISO image file
+func checkImage(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("ISO image not found: %s", path)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("ISO image is a directory: %s (use file: for disc folders)", path)
+	}
+	return nil
+}
+
+// checkFolder checks that path is an existing directory
+func checkFolder(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("disc folder not found: %s", path)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("disc folder is not a directory: %s", path)
+	}
+	return nil
+}
+
+// isISOName reports whether path has an .iso extension
+func isISOName(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".iso")
+}
+
+// IsImageSource reports whether discPath is an ISO image or disc folder
+// rather than a drive, so there is nothing to eject or swap
+func IsImageSource(discPath string) bool {
+	return makeMKVSource(discPath) != discPath ||
+		strings.HasPrefix(discPath, isoSourcePrefix) ||
+		strings.HasPrefix(discPath, fileSourcePrefix)
+}
+
+// makeMKVSource returns discPath as makemkvcon expects it, adding the iso:
+// or file: prefix to plain image and folder paths. Drives and already
+// prefixed sources are returned unchanged.
+func makeMKVSource(discPath string) string {
+	for _, prefix := range []string{"disc:", "dev:", "/dev/", isoSourcePrefix, fileSourcePrefix} {
+		if strings.HasPrefix(discPath, prefix) {
+			return discPath
+		}
+	}
+	info, err := os.Stat(discPath)
+	if err != nil {
+		return discPath
+	}
+	if info.IsDir() {
+		return fileSourcePrefix + discPath
+	}
+	if isISOName(discPath) {
+		return isoSourcePrefix + discPath
+	}
+	return discPath
+}
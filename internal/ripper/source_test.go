@@ -0,0 +1,85 @@
+package ripper
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidateDiscPath(t *testing.T) {
+	dir := t.TempDir()
+	iso := filepath.Join(dir, "Movie.iso")
+	other := filepath.Join(dir, "notes.txt")
+	for _, f := range []string{iso, other} {
+		if err := os.WriteFile(f, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	missing := filepath.Join(dir, "missing.iso")
+
+	tests := []struct {
+		path    string
+		wantErr string
+	}{
+		{"disc:0", ""},
+		{"disc:2", ""},
+		{"disc:-1", "non-negative"},
+		{"disc:x", "non-negative"},
+		{"dev:/dev/sr0", ""},
+		{"dev:", "missing device"},
+		{"/dev/sr0", ""},
+		{"iso:" + iso, ""},
+		{"iso:" + missing, "not found"},
+		{"iso:" + dir, "is a directory"},
+		{"file:" + dir, ""},
+		{"file:" + iso, "not a directory"},
+		{iso, ""},
+		{dir, ""},
+		{missing, "not a drive"},
+		{other, "ISO images"},
+		{"", "required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			err := ValidateDiscPath(tt.path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateDiscPath(%q) error = %v", tt.path, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateDiscPath(%q) error = %v, want containing %q", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsImageSource(t *testing.T) {
+	dir := t.TempDir()
+	iso := filepath.Join(dir, "Movie.ISO")
+	if err := os.WriteFile(iso, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"disc:0", false},
+		{"dev:/dev/sr0", false},
+		{"/dev/sr0", false},
+		{"iso:/archive/Movie.iso", true},
+		{"file:/archive/Movie", true},
+		{iso, true},
+		{dir, true},
+	}
+
+	for _, tt := range tests {
+		if got := IsImageSource(tt.path); got != tt.want {
+			t.Errorf("IsImageSource(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
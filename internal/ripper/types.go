@@ -3,6 +3,7 @@ package ripper
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 	"unicode"
@@ -22,9 +23,25 @@ const (
 type RipRequest struct {
 	Type     MediaType // movie or tv
 	Name     string    // Human readable name
+	Edition  string    // Optional edition, e.g. "Extended" (movies)
 	Season   int       // Season number (TV only, 0 for movies)
-	Disc     int       // Disc number (TV only, 0 for movies)
-	DiscPath string    // e.g., "disc:0" or "/dev/sr0"
+	Disc     int       // Disc number (TV, or a movie's second disc onwards)
+	DiscPath string    // e.g., "disc:0", "/dev/sr0", "iso:/path/to.iso" or a BDMV folder; see ValidateDiscPath
+
+	// RipMode is titles (one MKV per title, the default) or backup (a
+	// decrypted copy of the whole disc)
+	RipMode model.RipMode
+
+	EjectAfterRip bool // Open the drive tray once the rip succeeds
+	Overwrite     bool // Replace an earlier rip in the output directory once this one succeeds
+
+	// ExtrasFolders are the _extras subfolders the scaffolding creates; nil
+	// uses DefaultExtrasFolders
+	ExtrasFolders []string
+
+	// DiscInfo is the disc's title list from an earlier scan; Rip scans
+	// the disc itself when nil
+	DiscInfo *DiscInfo
 }
 
 // Validate checks that the request has all required fields
@@ -35,20 +52,57 @@ func (r *RipRequest) Validate() error {
 	if r.Type == "" {
 		return errors.New("type is required")
 	}
-	if r.Type == MediaTypeTV {
+	switch r.Type {
+	case MediaTypeMovie:
+	case MediaTypeTV:
 		if r.Season <= 0 {
 			return errors.New("season is required for TV shows")
 		}
 		if r.Disc <= 0 {
 			return errors.New("disc is required for TV shows")
 		}
+	default:
+		return fmt.Errorf("unknown type %q", r.Type)
+	}
+
+	if err := ValidateDiscPath(r.DiscPath); err != nil {
+		return err
+	}
+
+	switch r.RipMode {
+	case "", model.RipModeTitles:
+	case model.RipModeBackup:
+		// An image or folder already is a copy of the disc
+		if IsImageSource(r.DiscPath) {
+			return errors.New("backup mode needs a disc drive, not an ISO image or folder")
+		}
+	default:
+		return fmt.Errorf("unknown rip mode %q", r.RipMode)
+	}
+
+	return ValidateSafeName(r.Name, r.SafeName())
+}
+
+// ValidateSafeName checks the safe name derived from name. It becomes a
+// directory under staging, so it must name something and stay there.
+func ValidateSafeName(name, safeName string) error {
+	if strings.Trim(safeName, "_- ") == "" {
+		return fmt.Errorf("name %q has no filesystem-safe characters", name)
+	}
+	if strings.ContainsAny(safeName, `/\`) || strings.Contains(safeName, "..") {
+		return fmt.Errorf("name %q is not a safe directory name", name)
 	}
 	return nil
 }
 
-// SafeName returns a filesystem-safe version of the name
+// SafeName returns a filesystem-safe version of the name, with the edition
+// as a suffix like "The_Movie (Extended)" so editions get separate directories
 func (r *RipRequest) SafeName() string {
-	return toSafeName(r.Name)
+	name := toSafeName(r.Name)
+	if edition := toSafeName(r.Edition); edition != "" && name != "" {
+		name += " (" + edition + ")"
+	}
+	return name
 }
 
 // toSafeName converts a name to a filesystem-safe format
@@ -108,6 +162,15 @@ type DiscInfo struct {
 	Titles     []TitleInfo // Information about each title
 }
 
+// TotalSize returns the combined size of all titles on the disc in bytes
+func (d *DiscInfo) TotalSize() int64 {
+	var total int64
+	for _, t := range d.Titles {
+		total += t.Size
+	}
+	return total
+}
+
 // Progress represents ripping progress
 type Progress struct {
 	CurrentTitle int     // Current title being ripped (0-based)
@@ -123,6 +186,12 @@ type ProgressCallback func(Progress)
 // LineCallback is called with each line of MakeMKV output for logging
 type LineCallback func(line string)
 
+// ProgressSink receives job progress updates (0-100) alongside the database
+// writes, e.g. to stream them to an external dashboard
+type ProgressSink interface {
+	Progress(jobID int64, percent int)
+}
+
 // MakeMKVRunner abstracts makemkvcon execution for testing
 type MakeMKVRunner interface {
 	// GetDiscInfo retrieves information about a disc
@@ -133,6 +202,9 @@ type MakeMKVRunner interface {
 	// onLine is called with each line of output for logging
 	// onProgress is called with progress updates
 	RipTitles(ctx context.Context, discPath, outputDir string, titleIndices []int, onLine LineCallback, onProgress ProgressCallback) error
+
+	// Backup writes a decrypted copy of the whole disc into outputDir
+	Backup(ctx context.Context, discPath, outputDir string, onLine LineCallback, onProgress ProgressCallback) error
 }
 
 // Logger provides logging for ripper operations
@@ -2,6 +2,8 @@ package ripper
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -81,6 +83,61 @@ func TestRipRequest_Validate_MissingName(t *testing.T) {
 	}
 }
 
+func TestRipRequest_Validate_UnsafeName(t *testing.T) {
+	tests := []struct {
+		name    string
+		reqType MediaType
+	}{
+		{"!!!", MediaTypeMovie},
+		{"../..", MediaTypeMovie},
+		{"", MediaTypeMovie},
+		{"Movie", "documentary"},
+	}
+
+	for _, tt := range tests {
+		req := &RipRequest{Type: tt.reqType, Name: tt.name, DiscPath: "disc:0"}
+		if err := req.Validate(); err == nil {
+			t.Errorf("Validate() for name %q type %q should fail", tt.name, tt.reqType)
+		}
+	}
+}
+
+func TestRipRequest_Validate_NameWithPathCharacters(t *testing.T) {
+	// Separators are stripped by SafeName, so the result stays inside staging
+	req := &RipRequest{Type: MediaTypeMovie, Name: "../../etc", DiscPath: "disc:0"}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if got := req.SafeName(); got != "etc" {
+		t.Errorf("SafeName() = %q, want %q", got, "etc")
+	}
+}
+
+func TestRipRequest_Validate_RipMode(t *testing.T) {
+	iso := filepath.Join(t.TempDir(), "movie.iso")
+	if err := os.WriteFile(iso, []byte("iso"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		mode     model.RipMode
+		discPath string
+		wantErr  bool
+	}{
+		{"", "disc:0", false},
+		{model.RipModeTitles, "iso:" + iso, false},
+		{model.RipModeBackup, "disc:0", false},
+		{model.RipModeBackup, "iso:" + iso, true},
+		{"full", "disc:0", true},
+	}
+	for _, tt := range tests {
+		req := &RipRequest{Type: MediaTypeMovie, Name: "Test Movie", DiscPath: tt.discPath, RipMode: tt.mode}
+		if err := req.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%q, %s) error = %v, wantErr %v", tt.mode, tt.discPath, err, tt.wantErr)
+		}
+	}
+}
+
 func TestRipRequest_SafeName(t *testing.T) {
 	tests := []struct {
 		name string
@@ -100,6 +157,29 @@ func TestRipRequest_SafeName(t *testing.T) {
 	}
 }
 
+func TestRipRequest_SafeName_Edition(t *testing.T) {
+	tests := []struct {
+		name    string
+		edition string
+		want    string
+	}{
+		{"The Movie", "", "The_Movie"},
+		{"The Movie", "Extended", "The_Movie (Extended)"},
+		{"The Movie", "Director's Cut", "The_Movie (Directors_Cut)"},
+		{"The Movie", "!!!", "The_Movie"},
+	}
+
+	for _, tt := range tests {
+		req := &RipRequest{Type: MediaTypeMovie, Name: tt.name, Edition: tt.edition, DiscPath: "disc:0"}
+		if got := req.SafeName(); got != tt.want {
+			t.Errorf("SafeName(%q, %q) = %q, want %q", tt.name, tt.edition, got, tt.want)
+		}
+		if err := req.Validate(); err != nil {
+			t.Errorf("Validate(%q, %q) error = %v", tt.name, tt.edition, err)
+		}
+	}
+}
+
 func TestRipResult_Duration(t *testing.T) {
 	result := &RipResult{
 		StartedAt:   time.Now().Add(-5 * time.Minute),
@@ -139,3 +219,7 @@ func (m *mockRunner) GetDiscInfo(ctx context.Context, discPath string) (*DiscInf
 func (m *mockRunner) RipTitles(ctx context.Context, discPath, outputDir string, titleIndices []int, onLine LineCallback, onProgress ProgressCallback) error {
 	return nil
 }
+
+func (m *mockRunner) Backup(ctx context.Context, discPath, outputDir string, onLine LineCallback, onProgress ProgressCallback) error {
+	return nil
+}
@@ -0,0 +1,28 @@
+package transcode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ChecksumAlgorithm names the hash recorded for transcoded files, matching
+// what sha256sum prints
+const ChecksumAlgorithm = "sha256"
+
+// FileChecksum returns the hex ChecksumAlgorithm digest of the file at path
+func FileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -4,31 +4,87 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 // TranscodeOptions configures the transcoding operation
 type TranscodeOptions struct {
-	CRF         int
-	Mode        string // "software" or "hardware"
-	Preset      string // libx265 preset
-	HWPreset    string // QSV preset
-	DurationSec float64
+	CRF          int
+	ExtrasCRF    int    // CRF for files under _extras/ (0 = CRF)
+	Mode         string // "software" or "hardware" ("auto" must be resolved by the caller)
+	Preset       string // libx265 preset
+	HWPreset     string // QSV preset
+	AudioCodec   string // ffmpeg audio encoder (default "copy")
+	AudioBitrate string // Audio bitrate like "160k", ignored when copying audio
+	DurationSec  float64
+	FFmpegPath   string // ffmpeg binary (default "ffmpeg" from PATH)
+	FFprobePath  string // ffprobe binary (default "ffprobe" from PATH)
+}
+
+// ffmpegBinary returns the configured ffmpeg path, falling back to PATH lookup
+func (o TranscodeOptions) ffmpegBinary() string {
+	if o.FFmpegPath == "" {
+		return "ffmpeg"
+	}
+	return o.FFmpegPath
+}
+
+// ffprobeBinary returns the configured ffprobe path, falling back to PATH lookup
+func (o TranscodeOptions) ffprobeBinary() string {
+	if o.FFprobePath == "" {
+		return "ffprobe"
+	}
+	return o.FFprobePath
+}
+
+// crfFor returns the CRF to encode relPath with: ExtrasCRF for bonus content
+// under _extras/, when set, and CRF for everything else
+func (o TranscodeOptions) crfFor(relPath string) int {
+	if o.ExtrasCRF > 0 && IsExtra(relPath) {
+		return o.ExtrasCRF
+	}
+	return o.CRF
+}
+
+// IsExtra reports whether relPath, relative to the stage directory, is
+// bonus content under _extras/<type>/
+func IsExtra(relPath string) bool {
+	first, _, _ := strings.Cut(filepath.ToSlash(relPath), "/")
+	return first == "_extras"
+}
+
+// encoder returns the ffmpeg video encoder for the mode
+func (o TranscodeOptions) encoder() string {
+	if o.Mode == "hardware" {
+		return "hevc_qsv"
+	}
+	return "libx265"
+}
+
+// preset returns the encoder preset for the mode
+func (o TranscodeOptions) preset() string {
+	if o.Mode == "hardware" {
+		return o.HWPreset
+	}
+	return o.Preset
 }
 
 // ProgressCallback is called with progress updates (0-100)
 type ProgressCallback func(percent int)
 
-// timeRegex matches ffmpeg's time= output
-var timeRegex = regexp.MustCompile(`time=(\d{2}):(\d{2}):(\d{2})\.(\d{2})`)
+// LineCallback is called with each line of ffmpeg output
+type LineCallback func(line string)
 
 // TranscodeFile transcodes a single file using ffmpeg
-func TranscodeFile(ctx context.Context, inputPath, outputPath string, opts TranscodeOptions, onProgress ProgressCallback) error {
+// onLine is called with each line of ffmpeg output for logging
+// onProgress is called with progress updates (0-100)
+func TranscodeFile(ctx context.Context, inputPath, outputPath string, opts TranscodeOptions, onLine LineCallback, onProgress ProgressCallback) error {
 	// Ensure output directory exists
 	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
@@ -36,9 +92,14 @@ func TranscodeFile(ctx context.Context, inputPath, outputPath string, opts Trans
 
 	args := buildFFmpegArgs(inputPath, outputPath, opts)
 
-	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+	cmd := exec.CommandContext(ctx, opts.ffmpegBinary(), args...)
 
-	// ffmpeg writes progress to stderr
+	// -progress pipe:1 writes machine-readable progress to stdout, leaving
+	// stderr for ffmpeg's log
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to get stdout pipe: %w", err)
+	}
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
 		return fmt.Errorf("failed to get stderr pipe: %w", err)
@@ -48,24 +109,32 @@ func TranscodeFile(ctx context.Context, inputPath, outputPath string, opts Trans
 		return fmt.Errorf("failed to start ffmpeg: %w", err)
 	}
 
-	// Parse progress from stderr
-	lastPercent := 0
-	scanner := bufio.NewScanner(stderr)
-	scanner.Split(scanFFmpegLines)
+	// Forward the log while progress is read; both pipes must be drained
+	// before Wait
+	var logDone sync.WaitGroup
+	logDone.Add(1)
+	go func() {
+		defer logDone.Done()
+		scanner := bufio.NewScanner(stderr)
+		scanner.Split(scanFFmpegLines)
+		for scanner.Scan() {
+			if onLine != nil {
+				onLine(scanner.Text())
+			}
+		}
+	}()
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		if percent := parseProgress(line, opts.DurationSec); percent > lastPercent {
+	lastPercent := 0
+	// A read error surfaces as ffmpeg's exit status from cmd.Wait()
+	_ = readProgress(stdout, func(p ffmpegProgress) {
+		if percent := p.percent(opts.DurationSec); percent > lastPercent {
 			lastPercent = percent
 			if onProgress != nil {
 				onProgress(percent)
 			}
 		}
-	}
-
-	if err := scanner.Err(); err != nil {
-		// Continue - we'll rely on cmd.Wait() for actual errors
-	}
+	})
+	logDone.Wait()
 
 	if err := cmd.Wait(); err != nil {
 		// Clean up partial output
@@ -84,12 +153,63 @@ func TranscodeFile(ctx context.Context, inputPath, outputPath string, opts Trans
 	return nil
 }
 
+// ffmpegProgress is one update from ffmpeg's -progress output
+type ffmpegProgress struct {
+	OutTimeUS int64 // Position reached in the input, in microseconds
+	Frame     int64
+	FPS       float64
+	End       bool // Set on the final update (progress=end)
+}
+
+// percent returns how far through a file of durationSec the update is (0-100)
+func (p ffmpegProgress) percent(durationSec float64) int {
+	if durationSec <= 0 || p.OutTimeUS <= 0 {
+		return 0
+	}
+	percent := int(float64(p.OutTimeUS) / (durationSec * 1e6) * 100)
+	return min(percent, 100)
+}
+
+// readProgress parses ffmpeg's -progress stream of key=value lines, calling
+// onUpdate at the progress=continue or progress=end line closing each block.
+// Keys not present in a block keep their previous value.
+func readProgress(r io.Reader, onUpdate func(ffmpegProgress)) error {
+	var p ffmpegProgress
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "out_time_us", "out_time_ms":
+			// Despite its name out_time_ms is in microseconds too; both
+			// read "N/A" before the first frame is written
+			if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+				p.OutTimeUS = v
+			}
+		case "frame":
+			if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+				p.Frame = v
+			}
+		case "fps":
+			if v, err := strconv.ParseFloat(value, 64); err == nil {
+				p.FPS = v
+			}
+		case "progress":
+			p.End = value == "end"
+			onUpdate(p)
+		}
+	}
+	return scanner.Err()
+}
+
 // buildFFmpegArgs constructs the ffmpeg command arguments
 func buildFFmpegArgs(inputPath, outputPath string, opts TranscodeOptions) []string {
 	var args []string
 
-	// Common input args
-	args = append(args, "-nostdin", "-y")
+	// Common input args; progress goes to stdout as key=value lines
+	args = append(args, "-nostdin", "-y", "-progress", "pipe:1", "-nostats")
 
 	if opts.Mode == "hardware" {
 		// Intel QSV hardware encoding
@@ -100,8 +220,8 @@ func buildFFmpegArgs(inputPath, outputPath string, opts TranscodeOptions) []stri
 			"-map", "0:v:0",
 			"-map", "0:a",
 			"-map", "0:s?",
-			"-c:v", "hevc_qsv",
-			"-preset", opts.HWPreset,
+			"-c:v", opts.encoder(),
+			"-preset", opts.preset(),
 			"-global_quality", strconv.Itoa(opts.CRF),
 		)
 	} else {
@@ -111,15 +231,23 @@ func buildFFmpegArgs(inputPath, outputPath string, opts TranscodeOptions) []stri
 			"-map", "0:v:0",
 			"-map", "0:a",
 			"-map", "0:s?",
-			"-c:v", "libx265",
-			"-preset", opts.Preset,
+			"-c:v", opts.encoder(),
+			"-preset", opts.preset(),
 			"-crf", strconv.Itoa(opts.CRF),
 		)
 	}
 
-	// Common output args: copy audio and subtitles
+	// Common output args: audio is copied unless a profile re-encodes it,
+	// subtitles are always copied
+	audioCodec := opts.AudioCodec
+	if audioCodec == "" {
+		audioCodec = "copy"
+	}
+	args = append(args, "-c:a", audioCodec)
+	if audioCodec != "copy" && opts.AudioBitrate != "" {
+		args = append(args, "-b:a", opts.AudioBitrate)
+	}
 	args = append(args,
-		"-c:a", "copy",
 		"-c:s", "copy",
 		outputPath,
 	)
@@ -127,33 +255,7 @@ func buildFFmpegArgs(inputPath, outputPath string, opts TranscodeOptions) []stri
 	return args
 }
 
-// parseProgress extracts progress percentage from ffmpeg output
-func parseProgress(line string, totalDuration float64) int {
-	if totalDuration <= 0 {
-		return 0
-	}
-
-	matches := timeRegex.FindStringSubmatch(line)
-	if matches == nil {
-		return 0
-	}
-
-	hours, _ := strconv.Atoi(matches[1])
-	minutes, _ := strconv.Atoi(matches[2])
-	seconds, _ := strconv.Atoi(matches[3])
-	centisecs, _ := strconv.Atoi(matches[4])
-
-	currentSecs := float64(hours*3600+minutes*60+seconds) + float64(centisecs)/100.0
-	percent := int((currentSecs / totalDuration) * 100)
-
-	if percent > 100 {
-		percent = 100
-	}
-
-	return percent
-}
-
-// scanFFmpegLines is a split function for bufio.Scanner that handles ffmpeg's CR-delimited progress output
+// scanFFmpegLines is a split function for bufio.Scanner that splits ffmpeg's log on CR as well as LF
 func scanFFmpegLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
 	if atEOF && len(data) == 0 {
 		return 0, nil, nil
@@ -1,59 +1,122 @@
 package transcode
 
 import (
+	"strings"
 	"testing"
 )
 
-func TestParseProgress(t *testing.T) {
+// capturedProgress is ffmpeg -progress pipe:1 output for a 100 second file,
+// trimmed to three updates
+const capturedProgress = `frame=0
+fps=0.00
+stream_0_0_q=0.0
+bitrate=N/A
+total_size=44
+out_time_us=N/A
+out_time_ms=N/A
+out_time=N/A
+dup_frames=0
+drop_frames=0
+speed=N/A
+progress=continue
+frame=1200
+fps=24.01
+stream_0_0_q=28.0
+bitrate=1677.7kbits/s
+total_size=10485760
+out_time_us=50000000
+out_time_ms=50000000
+out_time=00:00:50.000000
+dup_frames=0
+drop_frames=0
+speed=1.0x
+progress=continue
+frame=2400
+fps=23.98
+stream_0_0_q=28.0
+bitrate=1677.7kbits/s
+total_size=20971520
+out_time_us=100000000
+out_time_ms=100000000
+out_time=00:01:40.000000
+dup_frames=0
+drop_frames=0
+speed=1.0x
+progress=end
+`
+
+func TestReadProgress(t *testing.T) {
+	var updates []ffmpegProgress
+	err := readProgress(strings.NewReader(capturedProgress), func(p ffmpegProgress) {
+		updates = append(updates, p)
+	})
+	if err != nil {
+		t.Fatalf("readProgress() error = %v", err)
+	}
+
+	want := []ffmpegProgress{
+		{},
+		{OutTimeUS: 50000000, Frame: 1200, FPS: 24.01},
+		{OutTimeUS: 100000000, Frame: 2400, FPS: 23.98, End: true},
+	}
+	if len(updates) != len(want) {
+		t.Fatalf("got %d updates, want %d: %+v", len(updates), len(want), updates)
+	}
+	for i := range want {
+		if updates[i] != want[i] {
+			t.Errorf("update %d = %+v, want %+v", i, updates[i], want[i])
+		}
+	}
+}
+
+func TestFFmpegProgress_Percent(t *testing.T) {
 	tests := []struct {
 		name     string
-		line     string
+		outTime  int64
 		duration float64
 		want     int
 	}{
-		{
-			name:     "beginning",
-			line:     "frame=  120 fps=24 q=28.0 size=    1024kB time=00:00:05.00 bitrate=1677.7kbits/s speed=1.0x",
-			duration: 100.0,
-			want:     5,
-		},
-		{
-			name:     "middle",
-			line:     "frame= 1200 fps=24 q=28.0 size=   10240kB time=00:00:50.00 bitrate=1677.7kbits/s speed=1.0x",
-			duration: 100.0,
-			want:     50,
-		},
-		{
-			name:     "near end",
-			line:     "frame= 2400 fps=24 q=28.0 size=   20480kB time=00:01:35.00 bitrate=1677.7kbits/s speed=1.0x",
-			duration: 100.0,
-			want:     95,
-		},
-		{
-			name:     "long video",
-			line:     "frame=86400 fps=24 q=28.0 size=  204800kB time=01:00:00.00 bitrate=1677.7kbits/s speed=1.0x",
-			duration: 7200.0, // 2 hours
-			want:     50,
-		},
-		{
-			name:     "no time field",
-			line:     "frame=  120 fps=24 q=28.0 size=    1024kB",
-			duration: 100.0,
-			want:     0,
-		},
-		{
-			name:     "zero duration",
-			line:     "frame=  120 fps=24 q=28.0 size=    1024kB time=00:00:05.00 bitrate=1677.7kbits/s speed=1.0x",
-			duration: 0,
-			want:     0,
-		},
+		{"not started", 0, 100.0, 0},
+		{"beginning", 5000000, 100.0, 5},
+		{"middle", 50000000, 100.0, 50},
+		{"long video", 3600000000, 7200.0, 50},
+		{"past the probed duration", 101000000, 100.0, 100},
+		{"zero duration", 5000000, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := ffmpegProgress{OutTimeUS: tt.outTime}
+			if got := p.percent(tt.duration); got != tt.want {
+				t.Errorf("percent() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFFmpegArgs_Progress(t *testing.T) {
+	args := strings.Join(buildFFmpegArgs("/input/movie.mkv", "/output/movie.mkv", TranscodeOptions{}), " ")
+	if !strings.Contains(args, "-progress pipe:1 -nostats") {
+		t.Errorf("args = %q, want progress on stdout", args)
+	}
+}
+
+func TestBuildFFmpegArgs_Audio(t *testing.T) {
+	tests := []struct {
+		name string
+		opts TranscodeOptions
+		want string
+	}{
+		{"copied by default", TranscodeOptions{AudioBitrate: "160k"}, "-c:a copy -c:s copy"},
+		{"re-encoded", TranscodeOptions{AudioCodec: "aac", AudioBitrate: "160k"}, "-c:a aac -b:a 160k -c:s copy"},
+		{"re-encoded at encoder default bitrate", TranscodeOptions{AudioCodec: "aac"}, "-c:a aac -c:s copy"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := parseProgress(tt.line, tt.duration)
-			if got != tt.want {
-				t.Errorf("parseProgress() = %d, want %d", got, tt.want)
+			args := strings.Join(buildFFmpegArgs("/input/movie.mkv", "/output/movie.mkv", tt.opts), " ")
+			if !strings.Contains(args, tt.want) {
+				t.Errorf("args = %q, want %q", args, tt.want)
 			}
 		})
 	}
@@ -117,3 +180,46 @@ func TestBuildFFmpegArgs_Hardware(t *testing.T) {
 		t.Error("expected qsv hwaccel")
 	}
 }
+
+func TestTranscodeOptions_Binaries(t *testing.T) {
+	var opts TranscodeOptions
+	if got := opts.ffmpegBinary(); got != "ffmpeg" {
+		t.Errorf("ffmpegBinary() default = %q, want %q", got, "ffmpeg")
+	}
+	if got := opts.ffprobeBinary(); got != "ffprobe" {
+		t.Errorf("ffprobeBinary() default = %q, want %q", got, "ffprobe")
+	}
+
+	opts.FFmpegPath = "/usr/lib/jellyfin-ffmpeg/ffmpeg"
+	opts.FFprobePath = "/usr/lib/jellyfin-ffmpeg/ffprobe"
+	if got := opts.ffmpegBinary(); got != opts.FFmpegPath {
+		t.Errorf("ffmpegBinary() = %q, want %q", got, opts.FFmpegPath)
+	}
+	if got := opts.ffprobeBinary(); got != opts.FFprobePath {
+		t.Errorf("ffprobeBinary() = %q, want %q", got, opts.FFprobePath)
+	}
+}
+
+func TestTranscodeOptions_CRFFor(t *testing.T) {
+	opts := TranscodeOptions{CRF: 20, ExtrasCRF: 26}
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"_main/movie.mkv", 20},
+		{"_episodes/S01E01.mkv", 20},
+		{"_extras/featurettes/making-of.mkv", 26},
+		{"extras/featurettes/making-of.mkv", 20},
+	}
+	for _, tt := range tests {
+		if got := opts.crfFor(tt.path); got != tt.want {
+			t.Errorf("crfFor(%q) = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+
+	// Without an extras CRF everything uses CRF
+	opts.ExtrasCRF = 0
+	if got := opts.crfFor("_extras/featurettes/making-of.mkv"); got != 20 {
+		t.Errorf("crfFor(extra) without ExtrasCRF = %d, want 20", got)
+	}
+}
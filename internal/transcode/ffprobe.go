@@ -8,8 +8,12 @@ import (
 )
 
 // GetDuration returns the duration of a media file in seconds
-func GetDuration(inputPath string) (float64, error) {
-	cmd := exec.Command("ffprobe",
+// If ffprobePath is empty, uses "ffprobe" from PATH
+func GetDuration(ffprobePath, inputPath string) (float64, error) {
+	if ffprobePath == "" {
+		ffprobePath = "ffprobe"
+	}
+	cmd := exec.Command(ffprobePath,
 		"-v", "error",
 		"-show_entries", "format=duration",
 		"-of", "csv=p=0",
@@ -38,8 +42,12 @@ func GetDuration(inputPath string) (float64, error) {
 }
 
 // CheckHardwareSupport checks if Intel QSV is available
-func CheckHardwareSupport() error {
-	cmd := exec.Command("ffmpeg",
+// If ffmpegPath is empty, uses "ffmpeg" from PATH
+func CheckHardwareSupport(ffmpegPath string) error {
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	cmd := exec.Command(ffmpegPath,
 		"-hide_banner",
 		"-init_hw_device", "qsv=hw",
 		"-f", "lavfi",
@@ -22,7 +22,7 @@ func TestCheckHardwareSupport(t *testing.T) {
 		t.Skip("ffmpeg not available")
 	}
 
-	err := CheckHardwareSupport()
+	err := CheckHardwareSupport("")
 	// Just log the result - don't fail if QSV not available
 	if err != nil {
 		t.Logf("QSV not available: %v", err)
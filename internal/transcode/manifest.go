@@ -0,0 +1,102 @@
+package transcode
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// Manifest location inside a transcode output directory
+const (
+	ManifestDir  = ".transcode"
+	ManifestName = "manifest.json"
+)
+
+// Manifest records what a transcode job produced, for verification by later
+// stages and for rebuilding database state from the filesystem
+type Manifest struct {
+	JobID       int64           `json:"job_id"`
+	CompletedAt time.Time       `json:"completed_at"`
+	Files       []ManifestEntry `json:"files"`
+}
+
+// ManifestEntry describes one transcoded output file
+type ManifestEntry struct {
+	Path         string  `json:"path"` // Relative to the output directory
+	InputSize    int64   `json:"input_size"`
+	OutputSize   int64   `json:"output_size"`
+	DurationSecs float64 `json:"duration_secs"`
+	CRF          int     `json:"crf"`
+	Encoder      string  `json:"encoder"`
+	Preset       string  `json:"preset"`
+
+	Checksum          string `json:"checksum,omitempty"`
+	ChecksumAlgorithm string `json:"checksum_algorithm,omitempty"`
+}
+
+// ManifestPath returns the manifest path for a transcode output directory
+func ManifestPath(outputDir string) string {
+	return filepath.Join(outputDir, ManifestDir, ManifestName)
+}
+
+// buildManifest lists the completed files of a job with the options they were encoded with
+func buildManifest(jobID int64, files []model.TranscodeFile, opts TranscodeOptions) *Manifest {
+	m := &Manifest{
+		JobID:       jobID,
+		CompletedAt: time.Now().UTC(),
+		Files:       []ManifestEntry{},
+	}
+	for _, f := range files {
+		if f.Status != model.TranscodeFileStatusCompleted {
+			continue
+		}
+		m.Files = append(m.Files, ManifestEntry{
+			Path:         filepath.ToSlash(f.RelativePath),
+			InputSize:    f.InputSize,
+			OutputSize:   f.OutputSize,
+			DurationSecs: f.DurationSecs,
+			CRF:          opts.CRF,
+			Encoder:      opts.encoder(),
+			Preset:       opts.preset(),
+
+			Checksum:          f.Checksum,
+			ChecksumAlgorithm: f.ChecksumAlgorithm,
+		})
+	}
+	return m
+}
+
+// WriteManifest writes m to outputDir/.transcode/manifest.json
+func WriteManifest(outputDir string, m *Manifest) error {
+	path := ManifestPath(outputDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create manifest directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadManifest reads the manifest from a transcode output directory
+func ReadManifest(outputDir string) (*Manifest, error) {
+	data, err := os.ReadFile(ManifestPath(outputDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	return &m, nil
+}
@@ -0,0 +1,91 @@
+package transcode
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/cuivienor/media-pipeline/internal/mediainfo"
+)
+
+// textSubtitleCodecs are the subtitle codecs ffmpeg can convert to SRT.
+// Image-based ones (PGS, VOBSUB, DVB) would need OCR.
+var textSubtitleCodecs = map[string]bool{
+	"subrip":   true,
+	"srt":      true,
+	"ass":      true,
+	"ssa":      true,
+	"mov_text": true,
+	"webvtt":   true,
+	"text":     true,
+}
+
+// isTextSubtitle reports whether a subtitle stream can be converted to SRT
+func isTextSubtitle(s mediainfo.Stream) bool {
+	return textSubtitleCodecs[s.Codec]
+}
+
+// ProbeSubtitles lists the subtitle streams of a media file
+// If ffprobePath is empty, uses "ffprobe" from PATH
+func ProbeSubtitles(ffprobePath, inputPath string) ([]mediainfo.Stream, error) {
+	info, err := mediainfo.ProbeWith(ffprobePath, inputPath)
+	if err != nil {
+		return nil, err
+	}
+	return info.Subtitles(), nil
+}
+
+// SidecarPaths returns the SRT path for each stream next to videoPath, named
+// the way players and FileBot pair them with the video: "Movie.eng.srt",
+// "Movie.eng.forced.srt", and "Movie.eng.2.srt" for a second English track.
+// Untagged streams use "und".
+func SidecarPaths(videoPath string, streams []mediainfo.Stream) []string {
+	base := strings.TrimSuffix(videoPath, ".mkv")
+	seen := make(map[string]int)
+
+	paths := make([]string, len(streams))
+	for i, s := range streams {
+		lang := strings.ToLower(s.Language)
+		if lang == "" {
+			lang = "und"
+		}
+		name := lang
+		if s.Forced {
+			name += ".forced"
+		}
+		seen[name]++
+		if n := seen[name]; n > 1 {
+			name += "." + strconv.Itoa(n)
+		}
+		paths[i] = base + "." + name + ".srt"
+	}
+	return paths
+}
+
+// buildSubtitleArgs constructs the ffmpeg arguments converting one subtitle
+// stream to an SRT file
+func buildSubtitleArgs(inputPath string, streamIndex int, outputPath string) []string {
+	return []string{
+		"-nostdin", "-y",
+		"-hide_banner", "-loglevel", "error",
+		"-i", inputPath,
+		"-map", fmt.Sprintf("0:%d", streamIndex),
+		"-c:s", "srt",
+		outputPath,
+	}
+}
+
+// ExtractSubtitle converts one subtitle stream of inputPath to an SRT file
+func ExtractSubtitle(ctx context.Context, opts TranscodeOptions, inputPath string, streamIndex int, outputPath string) error {
+	cmd := exec.CommandContext(ctx, opts.ffmpegBinary(), buildSubtitleArgs(inputPath, streamIndex, outputPath)...)
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package transcode
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/mediainfo"
+)
+
+func TestIsTextSubtitle(t *testing.T) {
+	tests := []struct {
+		codec string
+		want  bool
+	}{
+		{"subrip", true},
+		{"ass", true},
+		{"hdmv_pgs_subtitle", false},
+		{"dvd_subtitle", false},
+	}
+	for _, tt := range tests {
+		if got := isTextSubtitle(mediainfo.Stream{Codec: tt.codec}); got != tt.want {
+			t.Errorf("isTextSubtitle(%s) = %v, want %v", tt.codec, got, tt.want)
+		}
+	}
+}
+
+func TestSidecarPaths(t *testing.T) {
+	streams := []mediainfo.Stream{
+		{Index: 2, Language: "eng"},
+		{Index: 3, Language: "eng", Forced: true},
+		{Index: 4, Language: "ENG"},
+		{Index: 5, Language: "bul"},
+		{Index: 6, Language: "und"},
+	}
+
+	got := SidecarPaths("/out/Movie/Movie.mkv", streams)
+	want := []string{
+		"/out/Movie/Movie.eng.srt",
+		"/out/Movie/Movie.eng.forced.srt",
+		"/out/Movie/Movie.eng.2.srt",
+		"/out/Movie/Movie.bul.srt",
+		"/out/Movie/Movie.und.srt",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SidecarPaths() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildSubtitleArgs(t *testing.T) {
+	got := buildSubtitleArgs("/in/Movie.mkv", 3, "/out/Movie.eng.srt")
+	want := []string{
+		"-nostdin", "-y",
+		"-hide_banner", "-loglevel", "error",
+		"-i", "/in/Movie.mkv",
+		"-map", "0:3",
+		"-c:s", "srt",
+		"/out/Movie.eng.srt",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildSubtitleArgs() = %v, want %v", got, want)
+	}
+}
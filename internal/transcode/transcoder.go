@@ -2,12 +2,14 @@ package transcode
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/mediainfo"
 	"github.com/cuivienor/media-pipeline/internal/model"
 )
 
@@ -17,22 +19,66 @@ type Logger interface {
 	Error(format string, args ...interface{})
 }
 
+// ProgressSink receives overall job progress updates (0-100) alongside the
+// database writes, e.g. to stream them to an external dashboard
+type ProgressSink interface {
+	Progress(jobID int64, percent int)
+}
+
+// FileProgressCallback is called as a file's encode advances (0-100)
+type FileProgressCallback func(file *model.TranscodeFile, percent int)
+
 // Transcoder handles video transcoding operations
 type Transcoder struct {
-	repo   db.Repository
-	logger Logger
-	opts   TranscodeOptions
+	repo           db.Repository
+	logger         Logger
+	opts           TranscodeOptions
+	sink           ProgressSink
+	onLine         LineCallback
+	onFileProgress FileProgressCallback
+	extractSubs    bool
+
+	// continueOnError moves on to the next file after one fails
+	continueOnError bool
 }
 
 // NewTranscoder creates a new Transcoder
 func NewTranscoder(repo db.Repository, logger Logger, opts TranscodeOptions) *Transcoder {
 	return &Transcoder{
-		repo:   repo,
-		logger: logger,
-		opts:   opts,
+		repo:            repo,
+		logger:          logger,
+		opts:            opts,
+		continueOnError: true,
 	}
 }
 
+// SetProgressSink sets an optional sink for overall job progress; nil disables it
+func (t *Transcoder) SetProgressSink(sink ProgressSink) {
+	t.sink = sink
+}
+
+// SetLineCallback sets an optional callback for each line of ffmpeg output
+func (t *Transcoder) SetLineCallback(onLine LineCallback) {
+	t.onLine = onLine
+}
+
+// SetExtractSubs enables writing each text subtitle track to a sidecar SRT
+// file next to the transcoded output
+func (t *Transcoder) SetExtractSubs(extract bool) {
+	t.extractSubs = extract
+}
+
+// SetContinueOnError sets whether the remaining files are still transcoded
+// after one fails (the default). Either way the job fails if any file did.
+func (t *Transcoder) SetContinueOnError(continueOnError bool) {
+	t.continueOnError = continueOnError
+}
+
+// SetFileProgressCallback sets an optional callback for each file's progress
+func (t *Transcoder) SetFileProgressCallback(onFileProgress FileProgressCallback) {
+	t.onFileProgress = onFileProgress
+}
+
 // TranscodeJob processes all files for a transcode job
 func (t *Transcoder) TranscodeJob(ctx context.Context, job *model.Job, inputDir, outputDir string, isTV bool) error {
 	// Build queue of files to process
@@ -66,37 +112,96 @@ func (t *Transcoder) TranscodeJob(ctx context.Context, job *model.Job, inputDir,
 
 	// Process each file
 	var lastErr error
+	var failed []string
 	for i, file := range files {
+		if err := ctx.Err(); err != nil {
+			t.logger.Info("Cancelled before %s", file.RelativePath)
+			lastErr = err
+			break
+		}
 		if file.Status == model.TranscodeFileStatusCompleted {
 			continue
 		}
 		if file.Status == model.TranscodeFileStatusSkipped {
 			continue
 		}
+		if file.Status == model.TranscodeFileStatusFailed {
+			// Failed on an earlier run; it is retried once reset to pending
+			t.logger.Info("[%d/%d] Skipping previously failed: %s", i+1, len(files), file.RelativePath)
+			failed = append(failed, file.RelativePath)
+			continue
+		}
 
 		inputPath := filepath.Join(inputDir, file.RelativePath)
 		outputPath := filepath.Join(outputDir, file.RelativePath)
 
 		t.logger.Info("[%d/%d] Transcoding: %s", i+1, len(files), file.RelativePath)
 
-		if err := t.transcodeFile(ctx, &file, inputPath, outputPath); err != nil {
+		// Report overall job progress with each file weighted equally
+		fileIndex := i
+		onProgress := func(percent int) {
+			if t.sink != nil {
+				t.sink.Progress(job.ID, (fileIndex*100+percent)/len(files))
+			}
+		}
+
+		if err := t.transcodeFile(ctx, &file, inputPath, outputPath, onProgress); err != nil {
+			if ctx.Err() != nil {
+				t.logger.Info("Cancelled: %s reset to pending", file.RelativePath)
+				lastErr = ctx.Err()
+				break
+			}
 			t.logger.Error("Failed: %s - %v", file.RelativePath, err)
 			lastErr = err
-			// Continue with other files
+			failed = append(failed, file.RelativePath)
+			if !t.continueOnError {
+				t.logger.Info("Stopping after the first failure (continue_on_error is off)")
+				break
+			}
 		} else {
 			ratio := file.CompressionRatio()
 			savedMB := file.SizeSaved() / (1024 * 1024)
 			t.logger.Info("Completed: %s (%.1f%% of original, saved %dMB)",
 				file.RelativePath, ratio*100, savedMB)
+			if t.extractSubs {
+				t.extractSubtitles(ctx, file.RelativePath, inputPath, outputPath)
+			}
 		}
 	}
 
 	// Log summary
-	t.logSummary(ctx, job.ID)
+	t.logSummary(context.WithoutCancel(ctx), job.ID)
 
+	if lastErr == nil && len(failed) == 0 {
+		t.writeManifest(context.WithoutCancel(ctx), job.ID, outputDir)
+	}
+
+	if len(failed) > 0 && ctx.Err() == nil {
+		summary := fmt.Sprintf("%d of %d files failed (%s)", len(failed), len(files), strings.Join(failed, ", "))
+		if lastErr == nil {
+			// Only files that failed on an earlier run are left
+			return errors.New(summary)
+		}
+		return fmt.Errorf("%s: %w", summary, lastErr)
+	}
 	return lastErr
 }
 
+// writeManifest records the job's output files in outputDir. The files are
+// already in place, so a failure is logged rather than failing the job.
+func (t *Transcoder) writeManifest(ctx context.Context, jobID int64, outputDir string) {
+	files, err := t.repo.ListTranscodeFiles(ctx, jobID)
+	if err != nil {
+		t.logger.Error("Failed to write manifest: %v", err)
+		return
+	}
+	if err := WriteManifest(outputDir, buildManifest(jobID, files, t.opts)); err != nil {
+		t.logger.Error("Failed to write manifest: %v", err)
+		return
+	}
+	t.logger.Info("Wrote manifest: %s", ManifestPath(outputDir))
+}
+
 // buildQueue discovers files and creates/updates database records
 func (t *Transcoder) buildQueue(ctx context.Context, jobID int64, inputDir string, isTV bool) ([]model.TranscodeFile, error) {
 	// Check for existing files in database (resume case)
@@ -128,25 +233,26 @@ func (t *Transcoder) buildQueue(ctx context.Context, jobID int64, inputDir strin
 
 		// Check if already in database
 		if existing, ok := existingMap[relPath]; ok {
+			// Retry a probe that failed last time, unless there's nothing left to encode
+			if existing.Status != model.TranscodeFileStatusCompleted && existing.DurationSecs == 0 {
+				if existing.DurationSecs = t.probeDuration(existing, path); existing.DurationSecs > 0 {
+					if err := t.repo.UpdateTranscodeFile(ctx, existing); err != nil {
+						return fmt.Errorf("failed to record duration: %w", err)
+					}
+				}
+			}
 			files = append(files, *existing)
 			return nil
 		}
 
-		// Get duration
-		duration, err := GetDuration(path)
-		if err != nil {
-			t.logger.Error("Could not get duration for %s: %v", relPath, err)
-			duration = 0
-		}
-
 		// Create new record
 		file := &model.TranscodeFile{
 			JobID:        jobID,
 			RelativePath: relPath,
 			Status:       model.TranscodeFileStatusPending,
 			InputSize:    info.Size(),
-			DurationSecs: duration,
 		}
+		file.DurationSecs = t.probeDuration(file, path)
 
 		if err := t.repo.CreateTranscodeFile(ctx, file); err != nil {
 			return fmt.Errorf("failed to create transcode file record: %w", err)
@@ -163,8 +269,27 @@ func (t *Transcoder) buildQueue(ctx context.Context, jobID int64, inputDir strin
 	return files, nil
 }
 
-// transcodeFile processes a single file
-func (t *Transcoder) transcodeFile(ctx context.Context, file *model.TranscodeFile, inputPath, outputPath string) error {
+// probeDuration returns the duration recorded for file, running ffprobe on
+// path only when none is recorded yet. A failed probe is logged and returns
+// 0, which disables progress reporting for the file.
+func (t *Transcoder) probeDuration(file *model.TranscodeFile, path string) float64 {
+	if file.DurationSecs > 0 {
+		return file.DurationSecs
+	}
+	duration, err := GetDuration(t.opts.ffprobeBinary(), path)
+	if err != nil {
+		t.logger.Error("Could not get duration for %s: %v", file.RelativePath, err)
+		return 0
+	}
+	return duration
+}
+
+// transcodeFile processes a single file. If ctx is cancelled mid-encode,
+// ffmpeg is killed, the partial output removed and the file reset to pending.
+func (t *Transcoder) transcodeFile(ctx context.Context, file *model.TranscodeFile, inputPath, outputPath string, onProgress ProgressCallback) error {
+	// Database updates must still land after cancellation
+	dbCtx := context.WithoutCancel(ctx)
+
 	// Delete any partial output from previous attempt
 	os.Remove(outputPath)
 
@@ -174,49 +299,103 @@ func (t *Transcoder) transcodeFile(ctx context.Context, file *model.TranscodeFil
 	}
 
 	// Mark as in progress
-	if err := t.repo.UpdateTranscodeFileStatus(ctx, file.ID, model.TranscodeFileStatusInProgress, ""); err != nil {
+	if err := t.repo.UpdateTranscodeFileStatus(dbCtx, file.ID, model.TranscodeFileStatusInProgress, ""); err != nil {
 		return err
 	}
 
 	// Set duration for progress calculation
 	opts := t.opts
 	opts.DurationSec = file.DurationSecs
+	opts.CRF = opts.crfFor(file.RelativePath)
 
 	// Track last progress to avoid too many updates
 	lastProgress := 0
 
 	// Run ffmpeg with progress callback
-	err := TranscodeFile(ctx, inputPath, outputPath, opts, func(percent int) {
+	err := TranscodeFile(ctx, inputPath, outputPath, opts, t.onLine, func(percent int) {
 		// Only update on 1% increments
 		if percent > lastProgress {
 			lastProgress = percent
-			t.repo.UpdateTranscodeFileProgress(ctx, file.ID, percent)
+			t.repo.UpdateTranscodeFileProgress(dbCtx, file.ID, percent)
+			if onProgress != nil {
+				onProgress(percent)
+			}
+			if t.onFileProgress != nil {
+				t.onFileProgress(file, percent)
+			}
 		}
 	})
 
 	if err != nil {
-		t.repo.UpdateTranscodeFileStatus(ctx, file.ID, model.TranscodeFileStatusFailed, err.Error())
+		if ctx.Err() != nil {
+			os.Remove(outputPath)
+			t.repo.UpdateTranscodeFileStatus(dbCtx, file.ID, model.TranscodeFileStatusPending, "")
+			t.repo.UpdateTranscodeFileProgress(dbCtx, file.ID, 0)
+			return ctx.Err()
+		}
+		t.repo.UpdateTranscodeFileStatus(dbCtx, file.ID, model.TranscodeFileStatusFailed, err.Error())
 		return err
 	}
 
 	// Get output size
 	info, err := os.Stat(outputPath)
 	if err != nil {
-		t.repo.UpdateTranscodeFileStatus(ctx, file.ID, model.TranscodeFileStatusFailed, "output file not found")
+		t.repo.UpdateTranscodeFileStatus(dbCtx, file.ID, model.TranscodeFileStatusFailed, "output file not found")
 		return fmt.Errorf("output file not found: %w", err)
 	}
 
+	// Record the output's checksum for verifying copies; the encode is fine
+	// without one
+	file.Checksum, file.ChecksumAlgorithm = "", ""
+	if sum, err := FileChecksum(outputPath); err != nil {
+		t.logger.Error("Failed to checksum %s: %v", file.RelativePath, err)
+	} else {
+		file.Checksum, file.ChecksumAlgorithm = sum, ChecksumAlgorithm
+	}
+
 	// Update file record with results
 	file.Status = model.TranscodeFileStatusCompleted
 	file.OutputSize = info.Size()
 	file.Progress = 100
-	if err := t.repo.UpdateTranscodeFile(ctx, file); err != nil {
+	if err := t.repo.UpdateTranscodeFile(dbCtx, file); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// extractSubtitles writes the text subtitle tracks of inputPath to SRT files
+// named after outputPath. The encode itself succeeded, so problems are only
+// logged.
+func (t *Transcoder) extractSubtitles(ctx context.Context, relPath, inputPath, outputPath string) {
+	streams, err := ProbeSubtitles(t.opts.ffprobeBinary(), inputPath)
+	if err != nil {
+		t.logger.Error("Could not list subtitles of %s: %v", relPath, err)
+		return
+	}
+
+	var text []mediainfo.Stream
+	for _, s := range streams {
+		if !isTextSubtitle(s) {
+			t.logger.Info("Skipping subtitle track %d (%s, %s) of %s: image-based subtitles need OCR",
+				s.Index, s.Language, s.Codec, relPath)
+			continue
+		}
+		text = append(text, s)
+	}
+
+	for i, dest := range SidecarPaths(outputPath, text) {
+		if err := ExtractSubtitle(ctx, t.opts, inputPath, text[i].Index, dest); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			t.logger.Error("Failed to extract subtitle track %d of %s: %v", text[i].Index, relPath, err)
+			continue
+		}
+		t.logger.Info("Extracted subtitles: %s", filepath.Base(dest))
+	}
+}
+
 // logSummary logs the final summary
 func (t *Transcoder) logSummary(ctx context.Context, jobID int64) {
 	files, err := t.repo.ListTranscodeFiles(ctx, jobID)
@@ -227,6 +406,7 @@ func (t *Transcoder) logSummary(ctx context.Context, jobID int64) {
 
 	var completed, failed, skipped int
 	var totalInput, totalOutput int64
+	var failures []string
 
 	for _, f := range files {
 		switch f.Status {
@@ -236,12 +416,16 @@ func (t *Transcoder) logSummary(ctx context.Context, jobID int64) {
 			totalOutput += f.OutputSize
 		case model.TranscodeFileStatusFailed:
 			failed++
+			failures = append(failures, fmt.Sprintf("%s (%s)", f.RelativePath, f.ErrorMessage))
 		case model.TranscodeFileStatusSkipped:
 			skipped++
 		}
 	}
 
 	t.logger.Info("Summary: %d completed, %d failed, %d skipped", completed, failed, skipped)
+	for _, f := range failures {
+		t.logger.Error("Failed file: %s", f)
+	}
 
 	if totalInput > 0 {
 		savedGB := float64(totalInput-totalOutput) / (1024 * 1024 * 1024)
@@ -1,7 +1,16 @@
 package transcode
 
 import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
 )
 
 func TestTranscoder_BuildQueue(t *testing.T) {
@@ -9,3 +18,519 @@ func TestTranscoder_BuildQueue(t *testing.T) {
 	// For now, just verify the package compiles
 	t.Log("Transcoder package compiles correctly")
 }
+
+// discardLogger drops transcoder log output
+type discardLogger struct{}
+
+func (discardLogger) Info(format string, args ...interface{})  {}
+func (discardLogger) Error(format string, args ...interface{}) {}
+
+// writeScript writes an executable shell script for faking ffmpeg/ffprobe
+func writeScript(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestTranscoder_TranscodeJob_Cancel(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputDir := filepath.Join(tmpDir, "output")
+	if err := os.MkdirAll(filepath.Join(inputDir, "_main"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.mkv", "b.mkv"} {
+		if err := os.WriteFile(filepath.Join(inputDir, "_main", name), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// ffmpeg hangs until killed; exec so the kill reaches it directly
+	ffmpeg := filepath.Join(tmpDir, "ffmpeg")
+	ffprobe := filepath.Join(tmpDir, "ffprobe")
+	writeScript(t, ffmpeg, "exec sleep 30")
+	writeScript(t, ffprobe, "echo 60.0")
+
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+	repo := db.NewSQLiteRepository(database)
+
+	bg := context.Background()
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	if err := repo.CreateMediaItem(bg, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	job := &model.Job{MediaItemID: item.ID, Stage: model.StageTranscode, Status: model.JobStatusInProgress}
+	if err := repo.CreateJob(bg, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	transcoder := NewTranscoder(repo, discardLogger{}, TranscodeOptions{
+		Mode:        "software",
+		CRF:         20,
+		Preset:      "slow",
+		FFmpegPath:  ffmpeg,
+		FFprobePath: ffprobe,
+	})
+
+	ctx, cancel := context.WithCancel(bg)
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	start := time.Now()
+	err = transcoder.TranscodeJob(ctx, job, inputDir, outputDir, false)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("TranscodeJob() error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("TranscodeJob() took %s, ffmpeg was not killed", elapsed)
+	}
+
+	files, err := repo.ListTranscodeFiles(bg, job.ID)
+	if err != nil {
+		t.Fatalf("ListTranscodeFiles() error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("got %d files, want 2", len(files))
+	}
+	for _, f := range files {
+		if f.Status != model.TranscodeFileStatusPending {
+			t.Errorf("%s status = %s, want pending", f.RelativePath, f.Status)
+		}
+	}
+
+	if _, err := os.Stat(ManifestPath(outputDir)); !os.IsNotExist(err) {
+		t.Errorf("manifest written for a cancelled job (stat err = %v)", err)
+	}
+}
+
+func TestTranscoder_TranscodeJob_WritesManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputDir := filepath.Join(tmpDir, "output")
+	for _, rel := range []string{"_main/movie.mkv", "_extras/trailers/trailer.mkv"} {
+		path := filepath.Join(inputDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("original data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// ffmpeg writes a small file to its last argument, the output path
+	ffmpeg := filepath.Join(tmpDir, "ffmpeg")
+	ffprobe := filepath.Join(tmpDir, "ffprobe")
+	writeScript(t, ffmpeg, `for last; do :; done; printf encoded > "$last"`)
+	writeScript(t, ffprobe, "echo 60.0")
+
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+	repo := db.NewSQLiteRepository(database)
+
+	ctx := context.Background()
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	job := &model.Job{MediaItemID: item.ID, Stage: model.StageTranscode, Status: model.JobStatusInProgress}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	transcoder := NewTranscoder(repo, discardLogger{}, TranscodeOptions{
+		Mode:        "hardware",
+		CRF:         22,
+		HWPreset:    "medium",
+		FFmpegPath:  ffmpeg,
+		FFprobePath: ffprobe,
+	})
+	if err := transcoder.TranscodeJob(ctx, job, inputDir, outputDir, false); err != nil {
+		t.Fatalf("TranscodeJob() error = %v", err)
+	}
+
+	m, err := ReadManifest(outputDir)
+	if err != nil {
+		t.Fatalf("ReadManifest() error = %v", err)
+	}
+	if m.JobID != job.ID || len(m.Files) != 2 {
+		t.Fatalf("manifest = %+v, want 2 files for job %d", m, job.ID)
+	}
+	want := ManifestEntry{
+		Path:         "_main/movie.mkv",
+		InputSize:    int64(len("original data")),
+		OutputSize:   int64(len("encoded")),
+		DurationSecs: 60,
+		CRF:          22,
+		Encoder:      "hevc_qsv",
+		Preset:       "medium",
+
+		Checksum:          "766adc67b02bf315b9b5057994bfe6cfbd9354c433f259b29ba415dbe0f7afa5", // sha256 of "encoded"
+		ChecksumAlgorithm: "sha256",
+	}
+	found := false
+	for _, f := range m.Files {
+		if f.Path == want.Path {
+			found = true
+			if f != want {
+				t.Errorf("entry = %+v, want %+v", f, want)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("manifest missing %s: %+v", want.Path, m.Files)
+	}
+}
+
+func TestTranscoder_TranscodeJob_Callbacks(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputDir := filepath.Join(tmpDir, "output")
+	if err := os.MkdirAll(filepath.Join(inputDir, "_main"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(inputDir, "_main", "movie.mkv"), []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// ffmpeg logs a line and reports progress halfway through
+	ffmpeg := filepath.Join(tmpDir, "ffmpeg")
+	ffprobe := filepath.Join(tmpDir, "ffprobe")
+	writeScript(t, ffmpeg, `echo "Stream mapping:" >&2
+printf 'frame=10\nout_time_us=30000000\nprogress=continue\n'
+for last; do :; done; printf encoded > "$last"`)
+	writeScript(t, ffprobe, "echo 60.0")
+
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+	repo := db.NewSQLiteRepository(database)
+
+	ctx := context.Background()
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	job := &model.Job{MediaItemID: item.ID, Stage: model.StageTranscode, Status: model.JobStatusInProgress}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	transcoder := NewTranscoder(repo, discardLogger{}, TranscodeOptions{FFmpegPath: ffmpeg, FFprobePath: ffprobe})
+	var lines []string
+	transcoder.SetLineCallback(func(line string) { lines = append(lines, line) })
+	var progress []int
+	transcoder.SetFileProgressCallback(func(file *model.TranscodeFile, percent int) {
+		if file.RelativePath != filepath.Join("_main", "movie.mkv") {
+			t.Errorf("progress for %q, want _main/movie.mkv", file.RelativePath)
+		}
+		progress = append(progress, percent)
+	})
+
+	if err := transcoder.TranscodeJob(ctx, job, inputDir, outputDir, false); err != nil {
+		t.Fatalf("TranscodeJob() error = %v", err)
+	}
+
+	if len(lines) != 1 || lines[0] != "Stream mapping:" {
+		t.Errorf("lines = %q, want only the log line", lines)
+	}
+	if len(progress) != 2 || progress[0] != 50 || progress[1] != 100 {
+		t.Errorf("progress = %v, want [50 100]", progress)
+	}
+}
+
+func TestTranscoder_TranscodeJob_ExtrasCRF(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputDir := filepath.Join(tmpDir, "output")
+	mainFile := filepath.Join("_main", "movie.mkv")
+	extraFile := filepath.Join("_extras", "featurettes", "making-of.mkv")
+	for _, rel := range []string{mainFile, extraFile} {
+		if err := os.MkdirAll(filepath.Join(inputDir, filepath.Dir(rel)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(inputDir, rel), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// ffmpeg records its arguments next to the output it writes
+	ffmpeg := filepath.Join(tmpDir, "ffmpeg")
+	ffprobe := filepath.Join(tmpDir, "ffprobe")
+	writeScript(t, ffmpeg, `for last; do :; done; echo "$@" > "$last.args"; printf encoded > "$last"`)
+	writeScript(t, ffprobe, "echo 60.0")
+
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+	repo := db.NewSQLiteRepository(database)
+
+	ctx := context.Background()
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	job := &model.Job{MediaItemID: item.ID, Stage: model.StageTranscode, Status: model.JobStatusInProgress}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	opts := TranscodeOptions{CRF: 20, ExtrasCRF: 28, FFmpegPath: ffmpeg, FFprobePath: ffprobe}
+	if err := NewTranscoder(repo, discardLogger{}, opts).TranscodeJob(ctx, job, inputDir, outputDir, false); err != nil {
+		t.Fatalf("TranscodeJob() error = %v", err)
+	}
+
+	for rel, wantCRF := range map[string]string{mainFile: "-crf 20", extraFile: "-crf 28"} {
+		args, err := os.ReadFile(filepath.Join(outputDir, rel+".args"))
+		if err != nil {
+			t.Fatalf("%s was not transcoded into the mirrored path: %v", rel, err)
+		}
+		if !strings.Contains(string(args), wantCRF) {
+			t.Errorf("%s args = %q, want %s", rel, args, wantCRF)
+		}
+	}
+}
+
+func TestTranscoder_BuildQueue_ReusesRecordedDurations(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	if err := os.MkdirAll(filepath.Join(inputDir, "_main"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"e01.mkv", "e02.mkv", "e03.mkv"} {
+		if err := os.WriteFile(filepath.Join(inputDir, "_main", name), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// ffprobe records each file it is asked about
+	probes := filepath.Join(tmpDir, "probes")
+	ffprobe := filepath.Join(tmpDir, "ffprobe")
+	writeScript(t, ffprobe, `for last; do :; done; echo "$last" >> `+probes+`; echo 60.0`)
+
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+	repo := db.NewSQLiteRepository(database)
+
+	ctx := context.Background()
+	item := &model.MediaItem{Type: model.MediaTypeTV, Name: "Show", SafeName: "Show"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	job := &model.Job{MediaItemID: item.ID, Stage: model.StageTranscode, Status: model.JobStatusInProgress}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	// A previous run recorded e01's duration but failed to probe e02
+	for _, f := range []*model.TranscodeFile{
+		{JobID: job.ID, RelativePath: filepath.Join("_main", "e01.mkv"), Status: model.TranscodeFileStatusPending, DurationSecs: 42},
+		{JobID: job.ID, RelativePath: filepath.Join("_main", "e02.mkv"), Status: model.TranscodeFileStatusPending},
+	} {
+		if err := repo.CreateTranscodeFile(ctx, f); err != nil {
+			t.Fatalf("CreateTranscodeFile() error = %v", err)
+		}
+	}
+
+	transcoder := NewTranscoder(repo, discardLogger{}, TranscodeOptions{FFprobePath: ffprobe})
+	files, err := transcoder.buildQueue(ctx, job.ID, inputDir, true)
+	if err != nil {
+		t.Fatalf("buildQueue() error = %v", err)
+	}
+
+	data, err := os.ReadFile(probes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	probed := strings.Fields(string(data))
+	if len(probed) != 2 || filepath.Base(probed[0]) != "e02.mkv" || filepath.Base(probed[1]) != "e03.mkv" {
+		t.Errorf("probed %v, want only e02.mkv and e03.mkv", probed)
+	}
+
+	want := map[string]float64{"e01.mkv": 42, "e02.mkv": 60, "e03.mkv": 60}
+	stored, err := repo.ListTranscodeFiles(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("ListTranscodeFiles() error = %v", err)
+	}
+	for _, list := range [][]model.TranscodeFile{files, stored} {
+		if len(list) != 3 {
+			t.Fatalf("got %d files, want 3", len(list))
+		}
+		for _, f := range list {
+			if got := f.DurationSecs; got != want[filepath.Base(f.RelativePath)] {
+				t.Errorf("%s duration = %v, want %v", f.RelativePath, got, want[filepath.Base(f.RelativePath)])
+			}
+		}
+	}
+}
+
+func TestTranscoder_TranscodeJob_FailedFile(t *testing.T) {
+	tests := []struct {
+		name            string
+		continueOnError bool
+		wantStatus      map[string]model.TranscodeFileStatus
+	}{
+		{"continue", true, map[string]model.TranscodeFileStatus{
+			"a.mkv": model.TranscodeFileStatusCompleted,
+			"b.mkv": model.TranscodeFileStatusFailed,
+			"c.mkv": model.TranscodeFileStatusCompleted,
+		}},
+		{"stop", false, map[string]model.TranscodeFileStatus{
+			"a.mkv": model.TranscodeFileStatusCompleted,
+			"b.mkv": model.TranscodeFileStatusFailed,
+			"c.mkv": model.TranscodeFileStatusPending,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			inputDir := filepath.Join(tmpDir, "input")
+			outputDir := filepath.Join(tmpDir, "output")
+			if err := os.MkdirAll(filepath.Join(inputDir, "_main"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			for name := range tt.wantStatus {
+				if err := os.WriteFile(filepath.Join(inputDir, "_main", name), []byte("data"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// ffmpeg chokes on b.mkv and encodes everything else
+			ffmpeg := filepath.Join(tmpDir, "ffmpeg")
+			ffprobe := filepath.Join(tmpDir, "ffprobe")
+			writeScript(t, ffmpeg, `case "$*" in *b.mkv*) echo "Invalid data found" >&2; exit 1;; esac
+for last; do :; done; printf encoded > "$last"`)
+			writeScript(t, ffprobe, "echo 60.0")
+
+			database, err := db.OpenInMemory()
+			if err != nil {
+				t.Fatalf("OpenInMemory() error = %v", err)
+			}
+			defer database.Close()
+			repo := db.NewSQLiteRepository(database)
+
+			ctx := context.Background()
+			item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+			if err := repo.CreateMediaItem(ctx, item); err != nil {
+				t.Fatalf("CreateMediaItem() error = %v", err)
+			}
+			job := &model.Job{MediaItemID: item.ID, Stage: model.StageTranscode, Status: model.JobStatusInProgress}
+			if err := repo.CreateJob(ctx, job); err != nil {
+				t.Fatalf("CreateJob() error = %v", err)
+			}
+
+			transcoder := NewTranscoder(repo, discardLogger{}, TranscodeOptions{FFmpegPath: ffmpeg, FFprobePath: ffprobe})
+			transcoder.SetContinueOnError(tt.continueOnError)
+			err = transcoder.TranscodeJob(ctx, job, inputDir, outputDir, false)
+			if err == nil || !strings.Contains(err.Error(), "1 of 3 files failed ("+filepath.Join("_main", "b.mkv")+")") {
+				t.Errorf("TranscodeJob() error = %v, want it to name the failed file", err)
+			}
+
+			files, err := repo.ListTranscodeFiles(ctx, job.ID)
+			if err != nil {
+				t.Fatalf("ListTranscodeFiles() error = %v", err)
+			}
+			for _, f := range files {
+				name := filepath.Base(f.RelativePath)
+				if f.Status != tt.wantStatus[name] {
+					t.Errorf("%s status = %s, want %s", name, f.Status, tt.wantStatus[name])
+				}
+				if name == "b.mkv" && f.ErrorMessage == "" {
+					t.Error("failed file has no error message")
+				}
+			}
+		})
+	}
+}
+
+func TestTranscoder_TranscodeJob_RetryFailedFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	inputDir := filepath.Join(tmpDir, "input")
+	outputDir := filepath.Join(tmpDir, "output")
+	if err := os.MkdirAll(filepath.Join(inputDir, "_main"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.mkv", "b.mkv", "c.mkv"} {
+		if err := os.WriteFile(filepath.Join(inputDir, "_main", name), []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// ffmpeg logs what it encodes
+	encoded := filepath.Join(tmpDir, "encoded")
+	ffmpeg := filepath.Join(tmpDir, "ffmpeg")
+	ffprobe := filepath.Join(tmpDir, "ffprobe")
+	writeScript(t, ffmpeg, `for last; do :; done; printf encoded > "$last"; echo "$last" >> `+encoded)
+	writeScript(t, ffprobe, "echo 60.0")
+
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+	repo := db.NewSQLiteRepository(database)
+
+	ctx := context.Background()
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	job := &model.Job{MediaItemID: item.ID, Stage: model.StageTranscode, Status: model.JobStatusInProgress}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	// An earlier run encoded a.mkv and failed on the others; b.mkv was reset for a retry
+	for name, status := range map[string]model.TranscodeFileStatus{
+		"a.mkv": model.TranscodeFileStatusCompleted,
+		"b.mkv": model.TranscodeFileStatusPending,
+		"c.mkv": model.TranscodeFileStatusFailed,
+	} {
+		f := &model.TranscodeFile{JobID: job.ID, RelativePath: filepath.Join("_main", name), Status: status, DurationSecs: 60}
+		if err := repo.CreateTranscodeFile(ctx, f); err != nil {
+			t.Fatalf("CreateTranscodeFile() error = %v", err)
+		}
+	}
+
+	transcoder := NewTranscoder(repo, discardLogger{}, TranscodeOptions{FFmpegPath: ffmpeg, FFprobePath: ffprobe})
+	err = transcoder.TranscodeJob(ctx, job, inputDir, outputDir, false)
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 files failed ("+filepath.Join("_main", "c.mkv")+")") {
+		t.Errorf("TranscodeJob() error = %v, want c.mkv still failed", err)
+	}
+
+	data, err := os.ReadFile(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Fields(string(data)); len(got) != 1 || filepath.Base(got[0]) != "b.mkv" {
+		t.Errorf("encoded %v, want only b.mkv", got)
+	}
+
+	files, err := repo.ListTranscodeFiles(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("ListTranscodeFiles() error = %v", err)
+	}
+	want := map[string]model.TranscodeFileStatus{
+		"a.mkv": model.TranscodeFileStatusCompleted,
+		"b.mkv": model.TranscodeFileStatusCompleted,
+		"c.mkv": model.TranscodeFileStatusFailed,
+	}
+	for _, f := range files {
+		if name := filepath.Base(f.RelativePath); f.Status != want[name] {
+			t.Errorf("%s status = %s, want %s", name, f.Status, want[name])
+		}
+	}
+}
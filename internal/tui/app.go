@@ -1,11 +1,15 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/cuivienor/media-pipeline/internal/config"
 	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/dispatch"
+	"github.com/cuivienor/media-pipeline/internal/metadata"
 	"github.com/cuivienor/media-pipeline/internal/model"
 )
 
@@ -18,14 +22,17 @@ const (
 	ViewSeasonDetail             // Season detail for TV
 	ViewOrganize                 // File organization view
 	ViewNewItem                  // Create new item form
+	ViewEditItem                 // Edit existing item form
 )
 
 // App is the main application model
 type App struct {
-	config *config.Config
-	repo   db.Repository
-	state  *AppState
-	err    error
+	config     *config.Config
+	repo       db.Repository
+	dispatcher *dispatch.Dispatcher
+	metadata   metadata.Client // nil when no lookup API keys are configured
+	state      *AppState
+	err        error
 
 	// Navigation state
 	currentView    View
@@ -38,24 +45,73 @@ type App struct {
 	height int
 
 	// Form state
-	newItemForm *NewItemForm
+	newItemForm  *NewItemForm
+	editItemForm *EditItemForm
 
 	// Organize view state
 	organizeView *OrganizeView
+
+	// Transient status line shown on the item list (cleared on next key press)
+	statusMsg string
+
+	// Dispatch targets that failed the startup check, shown above the item list
+	unreachableTargets []error
+
+	// Item list name filter, edited after pressing [/]
+	filter    string
+	filtering bool
+
+	// Item list ordering within each section, cycled with [t]
+	sortBy db.SortField
+
+	// Group the item list by pipeline stage instead of status, toggled with [g]
+	groupByStage bool
+
+	// Last path shown with [p], printed on exit
+	selectedPath string
+
+	// Season whose rips-done warning is showing; a second [d] confirms
+	ripsDoneWarned int64
+
+	// Stuck job awaiting confirmation after pressing [f]
+	forceJob *model.Job
+
+	// Failed job awaiting confirmation after pressing [R]
+	retryJob *model.Job
+
+	// Files of a failed transcode job, listed after pressing [T]
+	transcodeFiles *transcodeFiles
+
+	// Stage override menu opened with [m]
+	stageOverride *stageOverride
+
+	// Transcode profile for jobs started from the TUI, cycled with [P]
+	// ("" uses the transcode config defaults)
+	transcodeProfile string
 }
 
 // NewApp creates a new application instance
 func NewApp(cfg *config.Config, repo db.Repository) *App {
-	return &App{
+	app := &App{
 		config:      cfg,
 		repo:        repo,
+		dispatcher:  dispatch.NewDispatcher(cfg),
 		currentView: ViewItemList,
 	}
+	if client := metadata.NewClient(cfg.TMDBAPIKey, cfg.TVDBAPIKey); client != nil {
+		app.metadata = client
+	}
+	return app
+}
+
+// SetMetadataClient sets the client used for ID lookups (for testing)
+func (a *App) SetMetadataClient(client metadata.Client) {
+	a.metadata = client
 }
 
 // Init implements tea.Model
 func (a *App) Init() tea.Cmd {
-	return a.loadState
+	return tea.Batch(a.loadState, a.checkDispatchTargets())
 }
 
 // stateMsg is sent when state loading completes
@@ -88,6 +144,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.syncSelectedFromState()
 		return a, nil
 
+	case targetsCheckedMsg:
+		a.unreachableTargets = msg.errs
+		return a, nil
+
 	case itemCreatedMsg:
 		if msg.err != nil {
 			a.err = msg.err
@@ -97,22 +157,107 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.newItemForm = nil
 		return a, a.loadState
 
+	case itemUpdatedMsg:
+		if msg.err != nil {
+			// Name collisions are fixable in the form; anything else is fatal
+			if errors.Is(msg.err, db.ErrDuplicateName) && a.editItemForm != nil {
+				a.editItemForm.err = msg.err.Error()
+				return a, nil
+			}
+			a.err = msg.err
+			return a, nil
+		}
+		a.currentView = ViewItemDetail
+		if a.editItemForm != nil {
+			a.currentView = a.editItemForm.back
+		}
+		a.editItemForm = nil
+		a.statusMsg = msg.warning
+		return a, a.loadState
+
+	case lookupResultsMsg:
+		form := a.newItemForm
+		if form == nil {
+			return a, nil
+		}
+		form.searching = false
+		switch {
+		case msg.err != nil:
+			form.err = fmt.Sprintf("Lookup failed: %v", msg.err)
+		case len(msg.matches) == 0:
+			form.err = "No matches found"
+		default:
+			form.matches = msg.matches
+			form.matchCursor = 0
+		}
+		return a, nil
+
 	case organizeLoadedMsg:
 		if msg.err != nil {
 			a.err = msg.err
 			return a, nil
 		}
 		a.organizeView = &OrganizeView{
-			item:      msg.item,
-			season:    msg.season,
-			path:      msg.path,
-			files:     msg.files,
-			discFiles: msg.discFiles,
-			discPaths: msg.discPaths,
+			item:        msg.item,
+			season:      msg.season,
+			path:        msg.path,
+			files:       msg.files,
+			discFiles:   msg.discFiles,
+			discPaths:   msg.discPaths,
+			discOffsets: msg.offsets,
+			discJobs:    msg.discJobs,
 		}
 		a.currentView = ViewOrganize
 		return a, nil
 
+	case episodeOffsetsSetMsg:
+		if msg.err != nil {
+			a.err = msg.err
+			return a, nil
+		}
+		if a.organizeView != nil {
+			a.organizeView.discOffsets = msg.offsets
+			// The episode numbers changed, so validate again
+			a.organizeView.validation = nil
+			if a.organizeView.hasEpisodeOffsets() {
+				a.statusMsg = "Numbering continues across discs"
+			} else {
+				a.statusMsg = "Disc offsets cleared"
+			}
+		}
+		return a, nil
+
+	case suggestionMsg:
+		a.statusMsg = ""
+		switch {
+		case msg.err != nil:
+			a.statusMsg = fmt.Sprintf("Failed to list titles: %v", msg.err)
+		case msg.plan == nil:
+			a.statusMsg = "No titles left to sort"
+		case a.organizeView != nil:
+			a.organizeView.plan = msg.plan
+		}
+		return a, nil
+
+	case suggestionAppliedMsg:
+		if msg.err != nil {
+			a.statusMsg = fmt.Sprintf("Failed to move titles: %v", msg.err)
+		} else if msg.moved > 0 {
+			a.statusMsg = "Titles moved, press [v] to validate"
+		}
+		if a.organizeView == nil {
+			return a, nil
+		}
+		return a, a.loadOrganizeView(a.organizeView.item)
+
+	case reportWrittenMsg:
+		if msg.err != nil {
+			a.statusMsg = fmt.Sprintf("Failed to write report: %v", msg.err)
+		} else {
+			a.statusMsg = fmt.Sprintf("Failure report saved to %s", msg.path)
+		}
+		return a, nil
+
 	case validateMsg:
 		if msg.err != nil {
 			a.err = msg.err
@@ -134,6 +279,10 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return a, a.loadState
 
 	case ripStartedMsg:
+		if errors.Is(msg.err, dispatch.ErrJobActive) {
+			a.statusMsg = msg.err.Error()
+			return a, nil
+		}
 		if msg.err != nil {
 			a.err = msg.err
 			return a, nil
@@ -141,28 +290,90 @@ func (a *App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Stay on current view but refresh state
 		return a, a.loadState
 
-	case seasonAddedMsg:
+	case seasonRipsDoneMsg:
 		if msg.err != nil {
 			a.err = msg.err
 			return a, nil
 		}
-		// Stay on item detail but refresh state
+		if msg.warning != "" {
+			a.statusMsg = msg.warning
+			a.ripsDoneWarned = msg.seasonID
+			return a, nil
+		}
+		// Stay on season detail but refresh state
 		return a, a.loadState
 
-	case seasonRipsDoneMsg:
+	case stageStartedMsg:
+		if errors.Is(msg.err, dispatch.ErrJobActive) {
+			a.statusMsg = msg.err.Error()
+			return a, nil
+		}
 		if msg.err != nil {
 			a.err = msg.err
 			return a, nil
 		}
-		// Stay on season detail but refresh state
+		// Stay on current view but refresh state
 		return a, a.loadState
 
-	case stageStartedMsg:
+	case jobRetriedMsg:
+		if status := retryErrorStatus(msg.err); status != "" {
+			a.statusMsg = status
+			return a, a.loadState
+		}
 		if msg.err != nil {
 			a.err = msg.err
 			return a, nil
 		}
-		// Stay on current view but refresh state
+		return a, a.loadState
+
+	case fileRetriedMsg:
+		if status := retryErrorStatus(msg.err); status != "" {
+			a.statusMsg = status
+			return a, a.loadState
+		}
+		if msg.err != nil {
+			a.err = msg.err
+			return a, nil
+		}
+		a.statusMsg = fmt.Sprintf("Retrying %s", filepath.Base(msg.file.RelativePath))
+		return a, a.loadState
+
+	case stageOverriddenMsg:
+		if msg.err != nil {
+			a.err = msg.err
+			return a, nil
+		}
+		a.statusMsg = fmt.Sprintf("Set %s to %s %s", msg.override.name, msg.override.stage, msg.override.status)
+		return a, a.loadState
+
+	case jobForcedMsg:
+		a.forceJob = nil
+		if errors.Is(msg.err, db.ErrJobNotActive) {
+			a.statusMsg = "Job already finished"
+			return a, a.loadState
+		}
+		if msg.err != nil {
+			a.err = msg.err
+			return a, nil
+		}
+		a.statusMsg = fmt.Sprintf("Marked %s job %d %s", msg.job.Stage, msg.job.ID, msg.status)
+		return a, a.loadState
+
+	case staleJobsFailedMsg:
+		a.statusMsg = fmt.Sprintf("Marked %d stale job(s) failed", msg.failed)
+		if msg.err != nil {
+			a.err = msg.err
+		}
+		return a, a.loadState
+
+	case bulkStageStartedMsg:
+		a.statusMsg = fmt.Sprintf("Started %d item(s)", msg.started)
+		if msg.skipped > 0 {
+			a.statusMsg += fmt.Sprintf(", %d awaiting organize", msg.skipped)
+		}
+		if msg.err != nil {
+			a.err = msg.err
+		}
 		return a, a.loadState
 	}
 
@@ -176,11 +387,46 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a.handleNewItemKey(msg)
 	}
 
+	// Route to edit form handler if in EditItem view
+	if a.currentView == ViewEditItem && a.editItemForm != nil {
+		return a.handleEditItemKey(msg)
+	}
+
+	// Route to force finish prompt while it is open
+	if a.forceJob != nil {
+		return a.handleForceJobKey(msg)
+	}
+
+	// Route to retry prompt while it is open
+	if a.retryJob != nil {
+		return a.handleRetryJobKey(msg)
+	}
+
+	// Route to transcode file view while it is open
+	if a.transcodeFiles != nil {
+		return a.handleTranscodeFilesKey(msg)
+	}
+
+	// Route to stage override menu while it is open
+	if a.stageOverride != nil {
+		return a.handleStageOverrideKey(msg)
+	}
+
+	// Route to filter prompt while it has focus
+	if a.currentView == ViewItemList && a.filtering {
+		return a.handleFilterKey(msg)
+	}
+
 	// Route to organize handler if in Organize view
 	if a.currentView == ViewOrganize {
 		return a.handleOrganizeKey(msg)
 	}
 
+	a.statusMsg = ""
+	// A rips-done warning is only confirmed by the very next key
+	ripsDoneWarned := a.ripsDoneWarned
+	a.ripsDoneWarned = 0
+
 	switch msg.String() {
 	case "q", "ctrl+c":
 		return a, tea.Quit
@@ -209,7 +455,11 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			} else if item.StageStatus == model.StatusCompleted && item.CurrentStage != model.StagePublish {
 				// Skip organize - that has its own flow with [o]
 				if item.CurrentStage == model.StageRip {
-					return a, nil // Use [o] for organize
+					// Rip another disc of the movie; use [o] for organize
+					if item.Type == model.MediaTypeMovie && item.RipMode != model.RipModeBackup {
+						return a, a.startRipForItem(item)
+					}
+					return a, nil
 				}
 				return a, a.startStageForItem(item, item.CurrentStage.NextStage())
 			}
@@ -226,14 +476,61 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return a, a.startStageForSeason(a.selectedItem, season, season.CurrentStage)
 			} else if season.StageStatus == model.StatusCompleted && season.CurrentStage != model.StagePublish {
 				if season.CurrentStage == model.StageRip {
-					return a, nil // Use [o] for organize
+					// Rip a disc that was missed; use [o] for organize
+					return a, a.startRipForSeason(a.selectedItem, season)
 				}
 				return a, a.startStageForSeason(a.selectedItem, season, season.CurrentStage.NextStage())
 			}
 		}
 
+	case "/":
+		// Filter items by name (only from item list view)
+		if a.currentView == ViewItemList {
+			a.filtering = true
+			return a, nil
+		}
+
+	case "t":
+		// Cycle item list sort order (only from item list view)
+		if a.currentView == ViewItemList {
+			a.sortBy = nextSortField(a.sortBy)
+			a.cursor = 0
+			return a, nil
+		}
+
+	case "g":
+		// Toggle grouping the item list by stage (only from item list view)
+		if a.currentView == ViewItemList {
+			a.groupByStage = !a.groupByStage
+			a.cursor = 0
+			return a, nil
+		}
+
+	case "F":
+		// Mark every stale job failed (only from item list view)
+		if a.currentView == ViewItemList {
+			return a, a.failStaleJobs()
+		}
+
+	case "P":
+		// Pick the transcode profile for stages started from here
+		if a.currentView == ViewItemDetail || a.currentView == ViewSeasonDetail {
+			a.transcodeProfile = nextTranscodeProfile(a.config.TranscodeProfileNames(), a.transcodeProfile)
+			return a, nil
+		}
+
+	case "S":
+		// Start next stage for every ready item (only from item list view)
+		if a.currentView == ViewItemList {
+			return a, a.startReadyStages()
+		}
+
 	case "o":
 		// Organize - works for movies (item detail) and TV seasons (season detail)
+		if a.selectedItem != nil && a.selectedItem.RipMode == model.RipModeBackup {
+			a.statusMsg = "Disc backups have no titles to organize"
+			return a, nil
+		}
 		if a.currentView == ViewItemDetail && a.selectedItem != nil {
 			if a.selectedItem.Type == model.MediaTypeMovie {
 				if a.selectedItem.CurrentStage == model.StageRip && a.selectedItem.StageStatus == model.StatusCompleted {
@@ -248,25 +545,106 @@ func (a *App) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case "e":
+		// Rename item (item detail view)
+		if a.currentView == ViewItemDetail && a.selectedItem != nil {
+			a.currentView = ViewEditItem
+			a.editItemForm = newEditItemForm(a.selectedItem, editFieldName)
+			return a, nil
+		}
+		// Set expected episode count (season detail view)
+		if a.currentView == ViewSeasonDetail && a.selectedSeason != nil {
+			a.currentView = ViewEditItem
+			a.editItemForm = newExpectedEpisodesForm(a.selectedSeason)
+			return a, nil
+		}
+
+	case "D":
+		// Set expected disc count (season detail view)
+		if a.currentView == ViewSeasonDetail && a.selectedSeason != nil {
+			a.currentView = ViewEditItem
+			a.editItemForm = newExpectedDiscsForm(a.selectedSeason)
+			return a, nil
+		}
+
+	case "i":
+		// Set TMDB/TVDB ID (only from item detail view)
+		if a.currentView == ViewItemDetail && a.selectedItem != nil {
+			a.currentView = ViewEditItem
+			a.editItemForm = newEditItemForm(a.selectedItem, editFieldDatabaseID)
+			return a, nil
+		}
+
+	case "p":
+		// Show the output path (movie item detail and season detail views)
+		if a.currentView == ViewItemDetail || a.currentView == ViewSeasonDetail {
+			a.showPath()
+			return a, nil
+		}
+
 	case "a":
-		// Add season (only from TV show item detail view)
+		// Add season, asking for its disc count (only from TV show item detail view)
 		if a.currentView == ViewItemDetail && a.selectedItem != nil {
 			if a.selectedItem.Type == model.MediaTypeTV {
-				return a, a.addSeasonToItem(a.selectedItem)
+				a.editItemForm = newSeasonForm()
+				a.currentView = ViewEditItem
+				return a, nil
 			}
 		}
 
+	case "A":
+		// Add seasons 1..N in one go (TV show item detail view)
+		if a.currentView == ViewItemDetail && a.selectedItem != nil && a.selectedItem.Type == model.MediaTypeTV {
+			a.editItemForm = newSeasonCountForm()
+			a.currentView = ViewEditItem
+			return a, nil
+		}
+
+	case "f":
+		// Force finish a stuck job (movie item detail and season detail views)
+		if job := a.stuckJob(); job != nil {
+			a.forceJob = job
+			return a, nil
+		}
+
+	case "R":
+		// Retry a failed stage with a fresh job (movie item detail and season detail views)
+		if job := a.failedJob(); job != nil {
+			a.retryJob = job
+			return a, nil
+		}
+
+	case "B":
+		// Bundle a failed job's log and events into a report (movie item detail and season detail views)
+		if job := a.failedJob(); job != nil {
+			return a, a.writeFailureReport(*job, a.reportSubject())
+		}
+
+	case "T":
+		// Retry single failed files of a failed transcode (movie item detail and season detail views)
+		a.statusMsg = a.openTranscodeFiles()
+		return a, nil
+
+	case "m":
+		// Override the stage and status by hand (movie item detail and season detail views)
+		a.statusMsg = a.openStageOverride()
+		return a, nil
+
 	case "d":
 		// Mark ripping done for season (only from season detail when ripping and not already completed)
 		if a.currentView == ViewSeasonDetail && a.selectedSeason != nil {
 			if a.selectedSeason.CurrentStage == model.StageRip && a.selectedSeason.StageStatus != model.StatusCompleted {
-				return a, a.markSeasonRipsDone(a.selectedItem, a.selectedSeason)
+				force := ripsDoneWarned == a.selectedSeason.ID
+				return a, a.markSeasonRipsDone(a.selectedItem, a.selectedSeason, force)
 			}
 		}
 
 	case "esc":
 		// Go back
 		switch a.currentView {
+		case ViewItemList:
+			a.filter = ""
+			a.cursor = 0
 		case ViewItemDetail:
 			a.currentView = ViewItemList
 			a.selectedItem = nil
@@ -404,6 +782,19 @@ func (a *App) View() string {
 		return "Scanning pipeline..."
 	}
 
+	if a.forceJob != nil {
+		return a.renderForceJobPrompt()
+	}
+	if a.retryJob != nil {
+		return a.renderRetryJobPrompt()
+	}
+	if a.transcodeFiles != nil {
+		return a.renderTranscodeFiles()
+	}
+	if a.stageOverride != nil {
+		return a.renderStageOverride()
+	}
+
 	switch a.currentView {
 	case ViewItemList:
 		return a.renderItemList()
@@ -413,6 +804,8 @@ func (a *App) View() string {
 		return a.renderSeasonDetail()
 	case ViewNewItem:
 		return a.renderNewItemForm()
+	case ViewEditItem:
+		return a.renderEditItemForm()
 	case ViewOrganize:
 		return a.renderOrganizeView()
 	default:
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/mediainfo"
+	"github.com/cuivienor/media-pipeline/internal/organize"
+)
+
+// movePlan is the suggested sorting of a movie rip, opened with [s] in the
+// organize view and edited before it is applied
+type movePlan struct {
+	files      []organize.FileInfo
+	suggestion organize.Suggestion
+	cursor     int
+}
+
+// planDests is the order [Space] cycles a file's destination through
+var planDests = []string{organize.DestMain, organize.DestExtras, organize.DestKeep}
+
+// suggestionMsg is sent when the titles left in a movie rip have been probed
+// and classified
+type suggestionMsg struct {
+	plan *movePlan
+	err  error
+}
+
+// suggestionAppliedMsg is sent once a move plan has been carried out
+type suggestionAppliedMsg struct {
+	moved int // Files moved out of the root
+	err   error
+}
+
+// canSuggest reports whether [s] applies to the organize view: a movie on a
+// single disc, where the titles are sorted in place
+func (ov *OrganizeView) canSuggest() bool {
+	return ov.season == nil && len(ov.discPaths) == 1
+}
+
+// suggestOrganization probes the titles in the rip directory's root and
+// classifies them into main feature and extras
+func (a *App) suggestOrganization(dir string) tea.Cmd {
+	ffprobe := a.config.FFprobePath()
+	return func() tea.Msg {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return suggestionMsg{err: err}
+		}
+
+		var files []organize.FileInfo
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(strings.ToLower(entry.Name()), ".mkv") {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			file := organize.FileInfo{Name: entry.Name(), Size: info.Size()}
+			// An unprobed title falls back to sizes, so a failure isn't fatal
+			if probe, err := mediainfo.ProbeWith(ffprobe, filepath.Join(dir, entry.Name())); err == nil {
+				file.DurationSecs = probe.Format.Duration
+			}
+			files = append(files, file)
+		}
+		if len(files) == 0 {
+			return suggestionMsg{}
+		}
+		return suggestionMsg{plan: &movePlan{files: files, suggestion: organize.Classify(files)}}
+	}
+}
+
+// handleMovePlanKey handles input while a move plan is shown. [Space]
+// changes the selected file's destination and Enter applies the plan.
+func (a *App) handleMovePlanKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	ov := a.organizeView
+	plan := ov.plan
+	switch msg.String() {
+	case "up", "k":
+		if plan.cursor > 0 {
+			plan.cursor--
+		}
+	case "down", "j":
+		if plan.cursor < len(plan.files)-1 {
+			plan.cursor++
+		}
+	case " ":
+		move := &plan.suggestion.Moves[plan.cursor]
+		for i, dest := range planDests {
+			if dest == move.Dest {
+				move.Dest = planDests[(i+1)%len(planDests)]
+				break
+			}
+		}
+	case "enter":
+		ov.plan = nil
+		return a, a.applySuggestion(ov.path, plan.suggestion)
+	case "esc":
+		ov.plan = nil
+	case "ctrl+c":
+		return a, tea.Quit
+	}
+	return a, nil
+}
+
+// applySuggestion moves the files as planned
+func (a *App) applySuggestion(dir string, s organize.Suggestion) tea.Cmd {
+	return func() tea.Msg {
+		moved := 0
+		for _, m := range s.Moves {
+			if m.Dest != organize.DestKeep {
+				moved++
+			}
+		}
+		return suggestionAppliedMsg{moved: moved, err: s.Apply(dir)}
+	}
+}
+
+// renderMovePlan renders the suggested moves in place of the instructions
+func (a *App) renderMovePlan() string {
+	plan := a.organizeView.plan
+	var b strings.Builder
+
+	b.WriteString(sectionHeaderStyle.Render("SUGGESTED MOVES"))
+	b.WriteString("\n")
+	for i, f := range plan.files {
+		dest := plan.suggestion.Moves[i].Dest
+		if dest == organize.DestKeep {
+			dest = "(leave)"
+		} else {
+			dest += "/"
+		}
+		length := formatSize(f.Size)
+		if f.DurationSecs > 0 {
+			length = formatDuration(time.Duration(f.DurationSecs*float64(time.Second))) + ", " + length
+		}
+		line := fmt.Sprintf("%-30s → %-15s %s", f.Name, dest, mutedItemStyle.Render(length))
+		if i == plan.cursor {
+			b.WriteString(selectedItemStyle.Render("> " + line))
+		} else {
+			b.WriteString(normalItemStyle.Render("  " + line))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString("  The longest title is taken for the feature, titles under half its length for extras.\n")
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("[↑/↓] Select  [Space] Change  [Enter] Apply  [Esc] Cancel"))
+
+	return b.String()
+}
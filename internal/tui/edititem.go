@@ -0,0 +1,379 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/model"
+	"github.com/cuivienor/media-pipeline/internal/ripper"
+)
+
+// editField identifies which item attribute the edit form changes
+type editField int
+
+const (
+	editFieldName             editField = iota // Display name (and safe name)
+	editFieldDatabaseID                        // TMDB ID for movies, TVDB ID for TV shows
+	editFieldExpectedEpisodes                  // Episode count of the selected TV season
+	editFieldSeasonCount                       // Number of seasons to create for a TV show
+	editFieldExpectedDiscs                     // Disc count of the selected TV season
+	editFieldNewSeason                         // Disc count of the TV season about to be added
+)
+
+// EditItemForm holds the form state for editing an existing item
+type EditItemForm struct {
+	field editField
+	Value string
+	err   string
+	back  View // View to return to when the form closes
+}
+
+// newEditItemForm creates a form for field, pre-filled with the item's current value
+func newEditItemForm(item *model.MediaItem, field editField) *EditItemForm {
+	form := &EditItemForm{field: field, back: ViewItemDetail}
+	switch field {
+	case editFieldName:
+		form.Value = item.Name
+	case editFieldDatabaseID:
+		if id := item.DatabaseID(); id != 0 {
+			form.Value = strconv.Itoa(id)
+		}
+	}
+	return form
+}
+
+// newExpectedEpisodesForm creates a form for a season's expected episode count
+func newExpectedEpisodesForm(season *model.Season) *EditItemForm {
+	form := &EditItemForm{field: editFieldExpectedEpisodes, back: ViewSeasonDetail}
+	if season.ExpectedEpisodes > 0 {
+		form.Value = strconv.Itoa(season.ExpectedEpisodes)
+	}
+	return form
+}
+
+// newExpectedDiscsForm creates a form for a season's expected disc count
+func newExpectedDiscsForm(season *model.Season) *EditItemForm {
+	form := &EditItemForm{field: editFieldExpectedDiscs, back: ViewSeasonDetail}
+	if season.ExpectedDiscs > 0 {
+		form.Value = strconv.Itoa(season.ExpectedDiscs)
+	}
+	return form
+}
+
+// newSeasonForm creates a form for adding the next season of a TV show,
+// asking how many discs it spans
+func newSeasonForm() *EditItemForm {
+	return &EditItemForm{field: editFieldNewSeason, back: ViewItemDetail}
+}
+
+// newSeasonCountForm creates a form for adding seasons 1..N to a TV show
+func newSeasonCountForm() *EditItemForm {
+	return &EditItemForm{field: editFieldSeasonCount, back: ViewItemDetail}
+}
+
+// Validate returns an error message if the form is invalid
+func (f *EditItemForm) Validate() string {
+	switch f.field {
+	case editFieldName:
+		if strings.TrimSpace(f.Value) == "" {
+			return "Name is required"
+		}
+	case editFieldDatabaseID:
+		if _, err := parseDatabaseID(f.Value); err != nil {
+			return err.Error()
+		}
+	case editFieldExpectedEpisodes:
+		if _, err := parseExpectedEpisodes(f.Value); err != nil {
+			return err.Error()
+		}
+	case editFieldSeasonCount:
+		if _, err := parseSeasonCount(f.Value); err != nil {
+			return err.Error()
+		}
+	case editFieldExpectedDiscs, editFieldNewSeason:
+		if _, err := parseExpectedDiscs(f.Value); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+// parseDatabaseID parses a TMDB/TVDB ID, which must be a positive integer
+func parseDatabaseID(s string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("ID must be a positive integer")
+	}
+	return id, nil
+}
+
+// parseExpectedEpisodes parses an expected episode count; empty means unknown (0)
+func parseExpectedEpisodes(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("Expected episodes must be a number")
+	}
+	return n, nil
+}
+
+// parseExpectedDiscs parses an expected disc count; empty means unknown (0)
+func parseExpectedDiscs(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("Expected discs must be a number")
+	}
+	return n, nil
+}
+
+// maxSeasonCount bounds bulk season creation to catch typos like "100"
+const maxSeasonCount = 50
+
+// parseSeasonCount parses the number of seasons a show has
+func parseSeasonCount(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 1 || n > maxSeasonCount {
+		return 0, fmt.Errorf("Season count must be between 1 and %d", maxSeasonCount)
+	}
+	return n, nil
+}
+
+// databaseIDLabel returns the name of the database ID used for the item's type
+func databaseIDLabel(item *model.MediaItem) string {
+	if item.Type == model.MediaTypeTV {
+		return "TVDB ID"
+	}
+	return "TMDB ID"
+}
+
+// renderEditItemForm renders the edit item form
+func (a *App) renderEditItemForm() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Edit Item"))
+	b.WriteString("\n\n")
+
+	form := a.editItemForm
+	item := a.selectedItem
+	switch form.field {
+	case editFieldName:
+		b.WriteString(fmt.Sprintf("> Name: %s\n", form.Value))
+		b.WriteString(mutedItemStyle.Render(fmt.Sprintf("        (was %q)", item.Name)))
+		b.WriteString("\n")
+	case editFieldDatabaseID:
+		b.WriteString(fmt.Sprintf("> %s: %s\n", databaseIDLabel(item), form.Value))
+		b.WriteString(mutedItemStyle.Render("        (required for FileBot matching at publish)"))
+		b.WriteString("\n")
+	case editFieldExpectedEpisodes:
+		b.WriteString(fmt.Sprintf("> Season %d expected episodes: %s\n", a.selectedSeason.Number, form.Value))
+		b.WriteString(mutedItemStyle.Render("        (checked when validating organize; empty if unknown)"))
+		b.WriteString("\n")
+	case editFieldSeasonCount:
+		b.WriteString(fmt.Sprintf("> Number of seasons: %s\n", form.Value))
+		b.WriteString(mutedItemStyle.Render("        (creates seasons 1..N, skipping any that exist)"))
+		b.WriteString("\n")
+	case editFieldExpectedDiscs:
+		b.WriteString(fmt.Sprintf("> Season %d expected discs: %s\n", a.selectedSeason.Number, form.Value))
+		b.WriteString(mutedItemStyle.Render("        (checked when marking ripping done; empty if unknown)"))
+		b.WriteString("\n")
+	case editFieldNewSeason:
+		b.WriteString(fmt.Sprintf("> Season %d discs: %s\n", nextSeasonNumber(item), form.Value))
+		b.WriteString(mutedItemStyle.Render("        (checked when marking ripping done; empty if unknown)"))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+
+	if form.err != "" {
+		b.WriteString(errorStyle.Render(form.err))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(helpStyle.Render("[Enter] Save  [Esc] Cancel"))
+
+	return b.String()
+}
+
+// handleEditItemKey handles key presses in the edit item form
+func (a *App) handleEditItemKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	form := a.editItemForm
+
+	switch msg.String() {
+	case "enter":
+		if errMsg := form.Validate(); errMsg != "" {
+			form.err = errMsg
+			return a, nil
+		}
+		form.err = ""
+		switch form.field {
+		case editFieldDatabaseID:
+			id, _ := parseDatabaseID(form.Value)
+			return a, a.setDatabaseID(a.selectedItem, id)
+		case editFieldExpectedEpisodes:
+			n, _ := parseExpectedEpisodes(form.Value)
+			return a, a.setExpectedEpisodes(a.selectedSeason, n)
+		case editFieldSeasonCount:
+			n, _ := parseSeasonCount(form.Value)
+			return a, a.addSeasons(a.selectedItem, n)
+		case editFieldExpectedDiscs:
+			n, _ := parseExpectedDiscs(form.Value)
+			return a, a.setExpectedDiscs(a.selectedSeason, n)
+		case editFieldNewSeason:
+			n, _ := parseExpectedDiscs(form.Value)
+			return a, a.addSeasonToItem(a.selectedItem, n)
+		}
+		return a, a.renameItem(a.selectedItem, form.Value)
+
+	case "backspace":
+		if len(form.Value) > 0 {
+			form.Value = form.Value[:len(form.Value)-1]
+		}
+		return a, nil
+
+	case "esc":
+		a.currentView = form.back
+		a.editItemForm = nil
+		return a, nil
+
+	default:
+		if len(msg.String()) == 1 {
+			char := msg.String()
+			// Database IDs and counts are digits only
+			if form.field != editFieldName && (char < "0" || char > "9") {
+				return a, nil
+			}
+			form.Value += char
+		}
+		return a, nil
+	}
+}
+
+// itemUpdatedMsg is sent when an item edit completes
+type itemUpdatedMsg struct {
+	warning string // Status line to show once the edit succeeds
+	err     error
+}
+
+// renameItem updates an item's name and safe name. Staging directories
+// created under the old safe name are left in place; later stages find
+// their input through the recorded job paths, so only a warning is shown.
+func (a *App) renameItem(item *model.MediaItem, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		name = strings.TrimSpace(name)
+		safeName := itemSafeName(name, item.Edition)
+
+		if safeName == item.SafeName && name == item.Name {
+			return itemUpdatedMsg{}
+		}
+		// The edition suffix alone doesn't make a name safe
+		if err := ripper.ValidateSafeName(name, itemSafeName(name, "")); err != nil {
+			return itemUpdatedMsg{err: err}
+		}
+		if err := ripper.ValidateSafeName(name, safeName); err != nil {
+			return itemUpdatedMsg{err: err}
+		}
+
+		if err := a.repo.UpdateMediaItemName(ctx, item.ID, name, safeName); err != nil {
+			return itemUpdatedMsg{err: err}
+		}
+
+		if safeName == item.SafeName {
+			return itemUpdatedMsg{}
+		}
+
+		jobs, err := a.repo.ListJobsForMedia(ctx, item.ID)
+		if err != nil {
+			return itemUpdatedMsg{err: fmt.Errorf("renamed, but failed to list jobs: %w", err)}
+		}
+		existing := 0
+		for _, job := range jobs {
+			if outputUsesSafeName(job.OutputDir, item.SafeName) {
+				existing++
+			}
+		}
+		if existing == 0 {
+			return itemUpdatedMsg{}
+		}
+		return itemUpdatedMsg{
+			warning: fmt.Sprintf("Renamed; %d existing output dir(s) still use %q and were not moved", existing, item.SafeName),
+		}
+	}
+}
+
+// outputUsesSafeName reports whether a job's output directory was named
+// after safeName: one of its path components is safeName, or safeName with
+// the "_DiscN" suffix later discs of a movie get
+func outputUsesSafeName(dir, safeName string) bool {
+	if dir == "" {
+		return false
+	}
+	for _, part := range strings.Split(filepath.ToSlash(dir), "/") {
+		if part == safeName || strings.HasPrefix(part, safeName+"_Disc") {
+			return true
+		}
+	}
+	return false
+}
+
+// setDatabaseID stores the TMDB ID for a movie or the TVDB ID for a TV show
+func (a *App) setDatabaseID(item *model.MediaItem, id int) tea.Cmd {
+	return func() tea.Msg {
+		var tmdbID, tvdbID *int
+		if item.Type == model.MediaTypeTV {
+			tvdbID = &id
+		} else {
+			tmdbID = &id
+		}
+		if err := a.repo.UpdateMediaItemDatabaseID(context.Background(), item.ID, tmdbID, tvdbID); err != nil {
+			return itemUpdatedMsg{err: err}
+		}
+		return itemUpdatedMsg{}
+	}
+}
+
+// setExpectedEpisodes stores how many episodes a season should have
+func (a *App) setExpectedEpisodes(season *model.Season, n int) tea.Cmd {
+	return func() tea.Msg {
+		if err := a.repo.UpdateSeasonExpectedEpisodes(context.Background(), season.ID, n); err != nil {
+			return itemUpdatedMsg{err: err}
+		}
+		return itemUpdatedMsg{}
+	}
+}
+
+// setExpectedDiscs stores how many discs a season spans
+func (a *App) setExpectedDiscs(season *model.Season, n int) tea.Cmd {
+	return func() tea.Msg {
+		if err := a.repo.UpdateSeasonExpectedDiscs(context.Background(), season.ID, n); err != nil {
+			return itemUpdatedMsg{err: err}
+		}
+		return itemUpdatedMsg{}
+	}
+}
+
+// addSeasons creates seasons 1..count for a TV show, keeping any that exist
+func (a *App) addSeasons(item *model.MediaItem, count int) tea.Cmd {
+	return func() tea.Msg {
+		numbers := make([]int, count)
+		for i := range numbers {
+			numbers[i] = i + 1
+		}
+		created, err := a.repo.CreateSeasons(context.Background(), item.ID, numbers)
+		if err != nil {
+			return itemUpdatedMsg{err: err}
+		}
+		return itemUpdatedMsg{warning: fmt.Sprintf("Added %d season(s), %d already existed", len(created), count-len(created))}
+	}
+}
@@ -0,0 +1,306 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestRenameItem(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	typo := &model.MediaItem{Type: model.MediaTypeMovie, Name: "The Matirx", SafeName: "The_Matirx"}
+	other := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception"}
+	for _, item := range []*model.MediaItem{typo, other} {
+		if err := repo.CreateMediaItem(ctx, item); err != nil {
+			t.Fatalf("CreateMediaItem() error = %v", err)
+		}
+	}
+	job := &model.Job{
+		MediaItemID: typo.ID,
+		Stage:       model.StageRip,
+		Status:      model.JobStatusCompleted,
+		OutputDir:   "/mnt/media/staging/1-ripped/movies/The_Matirx",
+	}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	app := NewApp(&config.Config{}, repo)
+
+	t.Run("duplicate name", func(t *testing.T) {
+		msg := app.renameItem(typo, "Inception")().(itemUpdatedMsg)
+		if !errors.Is(msg.err, db.ErrDuplicateName) {
+			t.Errorf("err = %v, want ErrDuplicateName", msg.err)
+		}
+	})
+
+	t.Run("unsafe name", func(t *testing.T) {
+		for _, name := range []string{"___", "- -", "../Inception", `AC/DC`} {
+			if msg := app.renameItem(typo, name)().(itemUpdatedMsg); msg.err == nil {
+				t.Errorf("renameItem(%q) should fail", name)
+			}
+		}
+	})
+
+	t.Run("warns about existing output", func(t *testing.T) {
+		msg := app.renameItem(typo, " The Matrix ")().(itemUpdatedMsg)
+		if msg.err != nil {
+			t.Fatalf("unexpected error: %v", msg.err)
+		}
+		if !strings.Contains(msg.warning, "The_Matirx") {
+			t.Errorf("warning = %q, want it to mention the old safe name", msg.warning)
+		}
+
+		loaded, err := repo.GetMediaItem(ctx, typo.ID)
+		if err != nil {
+			t.Fatalf("GetMediaItem() error = %v", err)
+		}
+		if loaded.Name != "The Matrix" || loaded.SafeName != "The_Matrix" {
+			t.Errorf("got name=%q safeName=%q", loaded.Name, loaded.SafeName)
+		}
+	})
+}
+
+func TestOutputUsesSafeName(t *testing.T) {
+	tests := []struct {
+		dir  string
+		want bool
+	}{
+		{"/mnt/media/staging/1-ripped/movies/Alien", true},
+		{"/mnt/media/staging/1-ripped/movies/Alien_Disc2", true},
+		{"/mnt/media/staging/2-remuxed/tv/Alien/Season_01", true},
+		{"/mnt/media/staging/1-ripped/movies/Aliens", false},
+		{"/mnt/media/staging/1-ripped/movies/Alien_3", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := outputUsesSafeName(tt.dir, "Alien"); got != tt.want {
+			t.Errorf("outputUsesSafeName(%q) = %v, want %v", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestSetDatabaseID(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Show", SafeName: "Show"}
+	if err := repo.CreateMediaItem(ctx, show); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+
+	app := NewApp(&config.Config{}, repo)
+	msg := app.setDatabaseID(show, 81189)().(itemUpdatedMsg)
+	if msg.err != nil {
+		t.Fatalf("unexpected error: %v", msg.err)
+	}
+
+	loaded, err := repo.GetMediaItem(ctx, show.ID)
+	if err != nil {
+		t.Fatalf("GetMediaItem() error = %v", err)
+	}
+	if loaded.TvdbID == nil || *loaded.TvdbID != 81189 || loaded.TmdbID != nil {
+		t.Errorf("TvdbID = %v, TmdbID = %v, want 81189 and nil", loaded.TvdbID, loaded.TmdbID)
+	}
+}
+
+func TestEditItemForm_ValidateDatabaseID(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"603", false},
+		{"", true},
+		{"0", true},
+		{"12a", true},
+	}
+
+	for _, tt := range tests {
+		form := &EditItemForm{field: editFieldDatabaseID, Value: tt.value}
+		if got := form.Validate() != ""; got != tt.wantErr {
+			t.Errorf("Validate(%q) error = %v, want %v", tt.value, got, tt.wantErr)
+		}
+	}
+}
+
+func TestSetExpectedEpisodes(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Test Show", SafeName: "Test_Show"}
+	if err := repo.CreateMediaItem(ctx, show); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	season := &model.Season{ItemID: show.ID, Number: 2, StageStatus: model.StatusPending}
+	if err := repo.CreateSeason(ctx, season); err != nil {
+		t.Fatalf("CreateSeason() error = %v", err)
+	}
+
+	app := NewApp(&config.Config{}, repo)
+	app.state = &AppState{}
+	app.currentView = ViewSeasonDetail
+	app.selectedItem = show
+	app.selectedSeason = season
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("e")})
+	if app.currentView != ViewEditItem || app.editItemForm.field != editFieldExpectedEpisodes {
+		t.Fatalf("[e] on season detail should open the expected episodes form")
+	}
+	for _, r := range "1x0" {
+		app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if app.editItemForm.Value != "10" {
+		t.Errorf("Value = %q, want digits only", app.editItemForm.Value)
+	}
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	app.Update(cmd())
+	if app.currentView != ViewSeasonDetail {
+		t.Errorf("view = %v, want season detail after saving", app.currentView)
+	}
+
+	loaded, err := repo.GetSeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetSeason() error = %v", err)
+	}
+	if loaded.ExpectedEpisodes != 10 {
+		t.Errorf("ExpectedEpisodes = %d, want 10", loaded.ExpectedEpisodes)
+	}
+}
+
+func TestAddSeasons(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Test Show", SafeName: "Test_Show"}
+	if err := repo.CreateMediaItem(ctx, show); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	existing := &model.Season{ItemID: show.ID, Number: 2, StageStatus: model.StatusPending}
+	if err := repo.CreateSeason(ctx, existing); err != nil {
+		t.Fatalf("CreateSeason() error = %v", err)
+	}
+
+	app := NewApp(&config.Config{}, repo)
+	app.state = &AppState{}
+	app.currentView = ViewItemDetail
+	app.selectedItem = show
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")})
+	if app.currentView != ViewEditItem || app.editItemForm.field != editFieldSeasonCount {
+		t.Fatalf("[A] on TV item detail should open the season count form")
+	}
+
+	// Out of range counts are rejected before touching the database
+	app.editItemForm.Value = "0"
+	app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.editItemForm == nil || app.editItemForm.err == "" {
+		t.Fatalf("season count 0 should fail validation")
+	}
+
+	app.editItemForm.Value = "3"
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	app.Update(cmd())
+	if app.currentView != ViewItemDetail {
+		t.Errorf("view = %v, want item detail after adding seasons", app.currentView)
+	}
+	if !strings.Contains(app.statusMsg, "Added 2 season(s)") {
+		t.Errorf("statusMsg = %q, want count of created seasons", app.statusMsg)
+	}
+
+	seasons, err := repo.ListSeasonsForItem(ctx, show.ID)
+	if err != nil {
+		t.Fatalf("ListSeasonsForItem() error = %v", err)
+	}
+	if len(seasons) != 3 {
+		t.Fatalf("got %d seasons, want 3", len(seasons))
+	}
+	for i, s := range seasons {
+		if s.Number != i+1 {
+			t.Errorf("seasons[%d].Number = %d, want %d", i, s.Number, i+1)
+		}
+	}
+	if seasons[1].ID != existing.ID {
+		t.Errorf("season 2 was recreated, want existing ID %d", existing.ID)
+	}
+}
+
+func TestAddSeason_ExpectedDiscs(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Test Show", SafeName: "Test_Show"}
+	if err := repo.CreateMediaItem(ctx, show); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	show.Seasons = []model.Season{{ItemID: show.ID, Number: 1, StageStatus: model.StatusPending}}
+	if err := repo.CreateSeason(ctx, &show.Seasons[0]); err != nil {
+		t.Fatalf("CreateSeason() error = %v", err)
+	}
+
+	app := NewApp(&config.Config{}, repo)
+	app.state = &AppState{}
+	app.currentView = ViewItemDetail
+	app.selectedItem = show
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if app.currentView != ViewEditItem || app.editItemForm.field != editFieldNewSeason {
+		t.Fatalf("[a] on TV item detail should ask for the new season's discs")
+	}
+	if view := app.View(); !strings.Contains(view, "Season 2 discs:") {
+		t.Errorf("form should name the season being added:\n%s", view)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("4")})
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	app.Update(cmd())
+	if app.currentView != ViewItemDetail {
+		t.Errorf("view = %v, want item detail after adding the season", app.currentView)
+	}
+
+	added, err := repo.GetSeasonByNumber(ctx, show.ID, 2)
+	if err != nil {
+		t.Fatalf("GetSeasonByNumber() error = %v", err)
+	}
+	if added == nil || added.ExpectedDiscs != 4 {
+		t.Errorf("season 2 = %+v, want ExpectedDiscs 4", added)
+	}
+}
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/dispatch"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// forceFailReason is recorded on jobs failed by hand from the TUI
+const forceFailReason = "marked failed by user (worker stopped responding)"
+
+// staleFailReason is recorded on stale jobs failed from the item list
+const staleFailReason = "interrupted: worker process exited without finishing"
+
+// jobForcedMsg is sent when a stuck job has been given a terminal status
+type jobForcedMsg struct {
+	job    model.Job
+	status model.JobStatus
+	err    error
+}
+
+// stuckJob returns the most recent pending or in-progress job for the movie
+// or season being viewed, or nil
+func (a *App) stuckJob() *model.Job {
+	if a.state == nil {
+		return nil
+	}
+
+	var jobs []model.Job
+	switch {
+	case a.currentView == ViewItemDetail && a.selectedItem != nil && a.selectedItem.Type == model.MediaTypeMovie:
+		jobs = a.state.MovieJobs[a.selectedItem.ID]
+	case a.currentView == ViewSeasonDetail && a.selectedSeason != nil:
+		jobs = a.state.SeasonJobs[a.selectedSeason.ID]
+	}
+
+	for i := len(jobs) - 1; i >= 0; i-- {
+		if jobs[i].IsActive() {
+			job := jobs[i]
+			return &job
+		}
+	}
+	return nil
+}
+
+// handleForceJobKey handles input while the force finish prompt is open.
+// Choosing an outcome is the confirmation; anything else leaves the job alone.
+func (a *App) handleForceJobKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	job := *a.forceJob
+	switch msg.String() {
+	case "c":
+		return a, a.forceFinishJob(job, model.JobStatusCompleted)
+	case "x":
+		return a, a.forceFinishJob(job, model.JobStatusFailed)
+	case "ctrl+c":
+		return a, tea.Quit
+	}
+	a.forceJob = nil
+	return a, nil
+}
+
+// forceFinishJob gives a stuck job a terminal status
+func (a *App) forceFinishJob(job model.Job, status model.JobStatus) tea.Cmd {
+	return func() tea.Msg {
+		err := dispatch.FinishJob(context.Background(), a.repo, job, status, forceFailReason)
+		return jobForcedMsg{job: job, status: status, err: err}
+	}
+}
+
+// staleJobsFailedMsg is sent when the stale jobs have been marked failed
+type staleJobsFailedMsg struct {
+	failed int
+	err    error
+}
+
+// failStaleJobs marks every stale job failed as interrupted
+func (a *App) failStaleJobs() tea.Cmd {
+	if a.state == nil {
+		return nil
+	}
+	jobs := a.state.AllStaleJobs()
+
+	return func() tea.Msg {
+		ctx := context.Background()
+		var result staleJobsFailedMsg
+		var errs []error
+		for _, job := range jobs {
+			err := dispatch.FinishJob(ctx, a.repo, job, model.JobStatusFailed, staleFailReason)
+			// A job that finished since the list loaded isn't stale any more
+			if errors.Is(err, db.ErrJobNotActive) {
+				continue
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("job %d: %w", job.ID, err))
+				continue
+			}
+			result.failed++
+		}
+		result.err = errors.Join(errs...)
+		return result
+	}
+}
+
+// renderForceJobPrompt renders the confirmation for force finishing a job
+func (a *App) renderForceJobPrompt() string {
+	job := a.forceJob
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Force Finish Job"))
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("  %s job %d is %s.\n", job.Stage.DisplayName(), job.ID, job.Status))
+	if job.PID != 0 {
+		switch alive, known := processAlive(job); {
+		case !known:
+			b.WriteString(mutedItemStyle.Render(fmt.Sprintf("  PID %d ran on %s, can't check it from here.", job.PID, job.WorkerID)))
+		case alive:
+			b.WriteString(errorStyle.Render(fmt.Sprintf("  PID %d is still running. Forcing the job won't stop it.", job.PID)))
+		default:
+			b.WriteString(fmt.Sprintf("  PID %d is no longer running.", job.PID))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("[c] Mark Completed  [x] Mark Failed  [Esc] Cancel"))
+
+	return b.String()
+}
+
+// isStale reports whether job is recorded as running on this host but its
+// process has exited, as happens when a worker crashes or the machine reboots
+func isStale(job *model.Job) bool {
+	if job.Status != model.JobStatusInProgress || job.WorkerID == "" {
+		return false
+	}
+	alive, known := processAlive(job)
+	return known && !alive
+}
+
+// processAlive reports whether the job's recorded process is still running.
+// known is false when the job ran on another host.
+func processAlive(job *model.Job) (alive, known bool) {
+	if job.PID <= 0 {
+		return false, false
+	}
+	if host, err := os.Hostname(); job.WorkerID != "" && (err != nil || job.WorkerID != host) {
+		return false, false
+	}
+	err := syscall.Kill(job.PID, 0)
+	// EPERM means the process exists but belongs to another user
+	return err == nil || errors.Is(err, syscall.EPERM), true
+}
@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestForceFinishJob(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception"}
+	if err := repo.CreateMediaItem(ctx, movie); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	job := &model.Job{MediaItemID: movie.ID, Stage: model.StageRemux, Status: model.JobStatusInProgress, PID: 4242}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	if err := repo.UpdateMediaItemStage(ctx, movie.ID, model.StageRemux, model.StatusInProgress); err != nil {
+		t.Fatalf("UpdateMediaItemStage() error = %v", err)
+	}
+
+	app := NewApp(&config.Config{}, repo)
+	app.Update(app.loadState())
+	app.currentView = ViewItemDetail
+	app.selectedItem = &app.state.Items[0]
+
+	// Esc backs out without touching the job
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if app.forceJob == nil || app.forceJob.ID != job.ID {
+		t.Fatalf("[f] should prompt for the in-progress job")
+	}
+	if view := app.View(); !strings.Contains(view, "[x] Mark Failed") {
+		t.Errorf("prompt view missing choices:\n%s", view)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.forceJob != nil {
+		t.Fatalf("Esc should close the prompt")
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	app.Update(cmd())
+	if app.err != nil {
+		t.Fatalf("unexpected error: %v", app.err)
+	}
+
+	got, err := repo.GetJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if got.Status != model.JobStatusFailed || got.PID != 0 || got.CompletedAt == nil {
+		t.Errorf("job = status %s, pid %d, completed %v; want failed with PID cleared", got.Status, got.PID, got.CompletedAt)
+	}
+	items, err := repo.ListActiveItems(ctx)
+	if err != nil {
+		t.Fatalf("ListActiveItems() error = %v", err)
+	}
+	if len(items) != 1 || items[0].StageStatus != model.StatusFailed {
+		t.Errorf("items = %+v, want movie with failed stage", items)
+	}
+
+	// Nothing left to force
+	app.Update(app.loadState())
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
+	if app.forceJob != nil {
+		t.Errorf("[f] should do nothing without an active job")
+	}
+}
+
+func TestProcessAlive(t *testing.T) {
+	host, _ := os.Hostname()
+
+	tests := []struct {
+		name      string
+		job       model.Job
+		wantAlive bool
+		wantKnown bool
+	}{
+		{"no pid", model.Job{}, false, false},
+		{"this process", model.Job{PID: os.Getpid()}, true, true},
+		{"this host", model.Job{PID: os.Getpid(), WorkerID: host}, true, true},
+		{"other host", model.Job{PID: os.Getpid(), WorkerID: host + "-elsewhere"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alive, known := processAlive(&tt.job)
+			if alive != tt.wantAlive || known != tt.wantKnown {
+				t.Errorf("processAlive() = %v, %v, want %v, %v", alive, known, tt.wantAlive, tt.wantKnown)
+			}
+		})
+	}
+}
+
+// exitedPID returns the PID of a process that has already exited
+func exitedPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("can't run true: %v", err)
+	}
+	return cmd.Process.Pid
+}
+
+func TestFailStaleJobs(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skipf("no hostname: %v", err)
+	}
+
+	crashed := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Crashed", SafeName: "Crashed"}
+	running := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Running", SafeName: "Running"}
+	remote := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Remote", SafeName: "Remote"}
+	for _, item := range []*model.MediaItem{crashed, running, remote} {
+		if err := repo.CreateMediaItem(ctx, item); err != nil {
+			t.Fatalf("CreateMediaItem() error = %v", err)
+		}
+	}
+	jobs := map[*model.MediaItem]*model.Job{
+		crashed: {MediaItemID: crashed.ID, Stage: model.StageRemux, Status: model.JobStatusInProgress, WorkerID: host, PID: exitedPID(t)},
+		running: {MediaItemID: running.ID, Stage: model.StageRemux, Status: model.JobStatusInProgress, WorkerID: host, PID: os.Getpid()},
+		remote:  {MediaItemID: remote.ID, Stage: model.StageRemux, Status: model.JobStatusInProgress, WorkerID: host + "-remote", PID: exitedPID(t)},
+	}
+	for _, job := range jobs {
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+	}
+
+	app := NewApp(&config.Config{}, repo)
+	app.Update(app.loadState())
+
+	stale := app.state.AllStaleJobs()
+	if len(stale) != 1 || stale[0].ID != jobs[crashed].ID {
+		t.Fatalf("AllStaleJobs() = %+v, want only the crashed job", stale)
+	}
+	view := app.View()
+	if !strings.Contains(view, "STALE") || !strings.Contains(view, "[F] Fail Stale") {
+		t.Errorf("item list missing stale section:\n%s", view)
+	}
+	if first := app.getDisplayOrderItems()[0]; first.ID != crashed.ID {
+		t.Errorf("first item = %s, want stale item listed first", first.Name)
+	}
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("F")})
+	_, cmd = app.Update(cmd())
+	if app.err != nil {
+		t.Fatalf("unexpected error: %v", app.err)
+	}
+	if app.statusMsg != "Marked 1 stale job(s) failed" {
+		t.Errorf("statusMsg = %q", app.statusMsg)
+	}
+	app.Update(cmd())
+	if len(app.state.StaleJobs) != 0 {
+		t.Errorf("StaleJobs = %+v after failing them, want none", app.state.StaleJobs)
+	}
+
+	got, err := repo.GetJob(ctx, jobs[crashed].ID)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if got.Status != model.JobStatusFailed || !strings.HasPrefix(got.ErrorMessage, "interrupted") {
+		t.Errorf("crashed job = status %s, error %q; want failed as interrupted", got.Status, got.ErrorMessage)
+	}
+	for _, item := range []*model.MediaItem{running, remote} {
+		got, err := repo.GetJob(ctx, jobs[item].ID)
+		if err != nil {
+			t.Fatalf("GetJob() error = %v", err)
+		}
+		if got.Status != model.JobStatusInProgress {
+			t.Errorf("%s job status = %s, want untouched", item.Name, got.Status)
+		}
+	}
+}
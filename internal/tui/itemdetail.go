@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/cuivienor/media-pipeline/internal/model"
@@ -31,7 +32,14 @@ func (a *App) renderMovieDetail(item *model.MediaItem) string {
 	// Title
 	b.WriteString(titleStyle.Render(item.Name))
 	b.WriteString("\n")
-	b.WriteString(mutedItemStyle.Render("Movie"))
+	subtitle := "Movie"
+	if item.Edition != "" {
+		subtitle += fmt.Sprintf(", %s edition", item.Edition)
+	}
+	if item.RipMode == model.RipModeBackup {
+		subtitle += ", disc backup"
+	}
+	b.WriteString(mutedItemStyle.Render(subtitle))
 	b.WriteString("\n\n")
 
 	// Current State
@@ -50,14 +58,21 @@ func (a *App) renderMovieDetail(item *model.MediaItem) string {
 
 	b.WriteString(fmt.Sprintf("  Stage: %s\n", item.CurrentStage.DisplayName()))
 	b.WriteString(fmt.Sprintf("  Status: %s\n", stageStyle.Render(string(item.StageStatus))))
+	b.WriteString(renderDatabaseID(item))
+	b.WriteString(renderLibraryPath(item.DisplayPath(a.state.MovieJobs[item.ID])))
 	b.WriteString("\n")
 
 	// Next Action
-	if item.StageStatus == model.StatusCompleted && item.CurrentStage == model.StageRip {
+	if item.StageStatus == model.StatusCompleted && item.CurrentStage == model.StageRip && item.RipMode == model.RipModeBackup {
+		b.WriteString(sectionHeaderStyle.Render("NEXT ACTION"))
+		b.WriteString("\n")
+		b.WriteString("  None, the disc backup is the final output\n")
+		b.WriteString("\n")
+	} else if item.StageStatus == model.StatusCompleted && item.CurrentStage == model.StageRip {
 		// Special case: after rip, use [o] for organize
 		b.WriteString(sectionHeaderStyle.Render("NEXT ACTION"))
 		b.WriteString("\n")
-		b.WriteString("  Press [o] to organize files\n")
+		b.WriteString("  Press [o] to organize files, or [s] to rip another disc\n")
 		b.WriteString("\n")
 	} else if item.StageStatus == model.StatusCompleted && item.CurrentStage != model.StagePublish {
 		b.WriteString(sectionHeaderStyle.Render("NEXT ACTION"))
@@ -77,6 +92,11 @@ func (a *App) renderMovieDetail(item *model.MediaItem) string {
 		b.WriteString("\n")
 	}
 
+	if line := a.renderTranscodeProfile(item.CurrentStage, item.StageStatus); line != "" {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
 	// Job History (collapsible in future)
 	jobs := a.state.MovieJobs[item.ID]
 	if len(jobs) > 0 {
@@ -92,7 +112,8 @@ func (a *App) renderMovieDetail(item *model.MediaItem) string {
 			case model.JobStatusFailed:
 				statusIcon = "✗"
 			}
-			b.WriteString(fmt.Sprintf("  %s %s\n", statusIcon, job.Stage.DisplayName()))
+			b.WriteString(fmt.Sprintf("  %s %s%s%s%s%s\n", statusIcon, job.Stage.DisplayName(), formatJobStart(&job), formatJobDuration(&job), formatJobWorker(&job), formatJobRetried(&job)))
+			b.WriteString(renderJobError(&job))
 
 			// Add transcode progress if applicable
 			b.WriteString(a.renderTranscodeProgress(&job))
@@ -103,23 +124,58 @@ func (a *App) renderMovieDetail(item *model.MediaItem) string {
 	// Help
 	var helpText string
 	if item.StageStatus == model.StatusInProgress {
-		helpText = "[r] Refresh  [Esc] Back  [q] Quit"
+		helpText = "[e] Rename  [i] Set ID  [p] Path  [r] Refresh  [Esc] Back  [q] Quit"
+	} else if item.CurrentStage == model.StageRip && item.StageStatus == model.StatusCompleted && item.RipMode == model.RipModeBackup {
+		helpText = "[e] Rename  [i] Set ID  [p] Path  [r] Refresh  [Esc] Back  [q] Quit"
 	} else if item.CurrentStage == model.StageRip && item.StageStatus == model.StatusCompleted {
-		helpText = "[o] Organize  [r] Refresh  [Esc] Back  [q] Quit"
+		helpText = "[o] Organize  [s] Rip Another Disc  [e] Rename  [i] Set ID  [p] Path  [r] Refresh  [Esc] Back  [q] Quit"
 	} else if item.StageStatus == model.StatusCompleted && item.CurrentStage != model.StagePublish {
 		// Ready for next stage (remux, transcode, or publish)
 		nextStage := item.CurrentStage.NextStage()
-		helpText = fmt.Sprintf("[s] Start %s  [r] Refresh  [Esc] Back  [q] Quit", nextStage.String())
+		helpText = fmt.Sprintf("[s] Start %s  [e] Rename  [i] Set ID  [p] Path  [r] Refresh  [Esc] Back  [q] Quit", nextStage.String())
 	} else if item.StageStatus == model.StatusPending || item.StageStatus == model.StatusFailed {
-		helpText = fmt.Sprintf("[s] Start %s  [r] Refresh  [Esc] Back  [q] Quit", item.CurrentStage.String())
+		helpText = fmt.Sprintf("[s] Start %s  [e] Rename  [i] Set ID  [p] Path  [r] Refresh  [Esc] Back  [q] Quit", item.CurrentStage.String())
 	} else {
-		helpText = "[r] Refresh  [Esc] Back  [q] Quit"
+		helpText = "[e] Rename  [i] Set ID  [p] Path  [r] Refresh  [Esc] Back  [q] Quit"
+	}
+	if item.Type == model.MediaTypeMovie && a.stuckJob() == nil {
+		helpText = "[m] Override Stage  " + helpText
+	}
+	if a.stuckJob() != nil {
+		helpText = "[f] Force Finish  " + helpText
+	}
+	if job := a.failedJob(); job != nil {
+		if job.Stage == model.StageTranscode {
+			helpText = "[T] Failed Files  " + helpText
+		}
+		helpText = "[R] Retry  [B] Report  " + helpText
+	}
+	if a.statusMsg != "" {
+		b.WriteString(a.statusMsg)
+		b.WriteString("\n")
 	}
 	b.WriteString(helpStyle.Render(helpText))
 
 	return b.String()
 }
 
+// renderDatabaseID renders the item's TMDB/TVDB ID line, which publish requires
+func renderDatabaseID(item *model.MediaItem) string {
+	label := databaseIDLabel(item)
+	if id := item.DatabaseID(); id != 0 {
+		return fmt.Sprintf("  %s: %d\n", label, id)
+	}
+	return fmt.Sprintf("  %s: %s\n", label, mutedItemStyle.Render("not set, press [i] to add"))
+}
+
+// renderLibraryPath renders the "Library:" line for a published item, or ""
+func renderLibraryPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	return fmt.Sprintf("  Library: %s\n", path)
+}
+
 // renderTVShowDetail renders detail view for a TV show
 func (a *App) renderTVShowDetail(item *model.MediaItem) string {
 	var b strings.Builder
@@ -127,15 +183,21 @@ func (a *App) renderTVShowDetail(item *model.MediaItem) string {
 	// Title
 	b.WriteString(titleStyle.Render(item.Name))
 	b.WriteString("\n")
-	b.WriteString(mutedItemStyle.Render("TV Show"))
-	b.WriteString("\n\n")
+	if item.RipMode == model.RipModeBackup {
+		b.WriteString(mutedItemStyle.Render("TV Show, disc backup"))
+	} else {
+		b.WriteString(mutedItemStyle.Render("TV Show"))
+	}
+	b.WriteString("\n")
+	b.WriteString(renderDatabaseID(item))
+	b.WriteString("\n")
 
 	// Seasons list
 	b.WriteString(sectionHeaderStyle.Render("SEASONS"))
 	b.WriteString("\n")
 
 	if len(item.Seasons) == 0 {
-		b.WriteString(mutedItemStyle.Render("  No seasons. Press [a] to add a season."))
+		b.WriteString(mutedItemStyle.Render("  No seasons. Press [a] to add a season or [A] to add several."))
 		b.WriteString("\n")
 	} else {
 		for i, season := range item.Seasons {
@@ -187,10 +249,10 @@ func (a *App) renderTVShowDetail(item *model.MediaItem) string {
 
 			// Next action hint for this season
 			var actionHint string
-			if season.StageStatus == model.StatusCompleted && season.CurrentStage != model.StagePublish {
+			if season.StageStatus == model.StatusCompleted {
 				actionHint = mutedItemStyle.Render(fmt.Sprintf(" → %s", season.CurrentStage.NextAction()))
 			} else if season.StageStatus == model.StatusPending {
-				actionHint = mutedItemStyle.Render(" → start rip")
+				actionHint = mutedItemStyle.Render(fmt.Sprintf(" → start %s", season.CurrentStage.String()))
 			}
 
 			b.WriteString(fmt.Sprintf("%s%s %s - %s %s%s\n",
@@ -205,11 +267,75 @@ func (a *App) renderTVShowDetail(item *model.MediaItem) string {
 	b.WriteString("\n")
 
 	// Help
-	b.WriteString(helpStyle.Render("[Enter] View Season  [a] Add Season  [r] Refresh  [Esc] Back  [q] Quit"))
+	if a.statusMsg != "" {
+		b.WriteString(a.statusMsg)
+		b.WriteString("\n")
+	}
+	b.WriteString(helpStyle.Render("[Enter] View Season  [a] Add Season  [A] Add Seasons  [e] Rename  [i] Set ID  [r] Refresh  [Esc] Back  [q] Quit"))
 
 	return b.String()
 }
 
+// formatJobStart returns a muted " Jan 2 15:04" suffix for when a job
+// started, or "" if it hasn't
+func formatJobStart(job *model.Job) string {
+	if job.StartedAt == nil {
+		return ""
+	}
+	return mutedItemStyle.Render(" " + job.StartedAt.Local().Format("Jan 2 15:04"))
+}
+
+// maxJobErrorLen caps the error shown under a failed job; the log has the rest
+const maxJobErrorLen = 100
+
+// renderJobError renders the first line of a failed job's error beneath it,
+// or "" for jobs that didn't fail
+func renderJobError(job *model.Job) string {
+	if job.Status != model.JobStatusFailed || job.ErrorMessage == "" {
+		return ""
+	}
+	msg, _, _ := strings.Cut(strings.TrimSpace(job.ErrorMessage), "\n")
+	if runes := []rune(msg); len(runes) > maxJobErrorLen {
+		msg = string(runes[:maxJobErrorLen-1]) + "…"
+	}
+	return "    " + errorStyle.Render(msg) + "\n"
+}
+
+// formatJobDuration returns a " (1h02m)" suffix for a started job, or "" if not started
+func formatJobDuration(job *model.Job) string {
+	d := job.Duration()
+	if d <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", formatDuration(d))
+}
+
+// formatJobWorker returns a muted " on host" suffix naming the machine that ran
+// the job, or "" if it never started
+func formatJobWorker(job *model.Job) string {
+	if job.WorkerID == "" {
+		return ""
+	}
+	return mutedItemStyle.Render(" on " + job.WorkerID)
+}
+
+// formatDuration formats a duration compactly, e.g. "45s", "5m30s", "1h02m"
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	s := int(d.Seconds()) % 60
+
+	switch {
+	case h > 0:
+		return fmt.Sprintf("%dh%02dm", h, m)
+	case m > 0:
+		return fmt.Sprintf("%dm%02ds", m, s)
+	default:
+		return fmt.Sprintf("%ds", s)
+	}
+}
+
 // renderTranscodeProgress renders transcode progress for a job
 func (a *App) renderTranscodeProgress(job *model.Job) string {
 	// Only show progress for transcode jobs that are in progress
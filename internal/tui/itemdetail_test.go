@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{45 * time.Second, "45s"},
+		{5*time.Minute + 30*time.Second, "5m30s"},
+		{time.Hour + 2*time.Minute + 40*time.Second, "1h02m"},
+		{26 * time.Hour, "26h00m"},
+	}
+
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestFormatJobDuration(t *testing.T) {
+	if got := formatJobDuration(&model.Job{}); got != "" {
+		t.Errorf("formatJobDuration(unstarted) = %q, want empty", got)
+	}
+
+	start := time.Now().Add(-90 * time.Minute)
+	end := start.Add(42 * time.Minute)
+	job := &model.Job{StartedAt: &start, CompletedAt: &end}
+	if got := formatJobDuration(job); got != " (42m00s)" {
+		t.Errorf("formatJobDuration(completed) = %q, want %q", got, " (42m00s)")
+	}
+}
+
+func TestFormatJobStart(t *testing.T) {
+	if got := formatJobStart(&model.Job{}); got != "" {
+		t.Errorf("formatJobStart(unstarted) = %q, want empty", got)
+	}
+
+	start := time.Date(2024, time.March, 5, 21, 7, 0, 0, time.Local)
+	if got := formatJobStart(&model.Job{StartedAt: &start}); !strings.Contains(got, "Mar 5 21:07") {
+		t.Errorf("formatJobStart() = %q, want Mar 5 21:07", got)
+	}
+}
+
+func TestRenderJobError(t *testing.T) {
+	if got := renderJobError(&model.Job{Status: model.JobStatusCompleted, ErrorMessage: "stale"}); got != "" {
+		t.Errorf("renderJobError(completed) = %q, want empty", got)
+	}
+
+	job := &model.Job{
+		Status:       model.JobStatusFailed,
+		ErrorMessage: "filebot failed: no match\nOutput: " + strings.Repeat("x", 200),
+	}
+	got := renderJobError(job)
+	if !strings.Contains(got, "filebot failed: no match") || strings.Contains(got, "Output:") {
+		t.Errorf("renderJobError() = %q, want only the first line", got)
+	}
+
+	job.ErrorMessage = strings.Repeat("y", 150)
+	if got := renderJobError(job); strings.Count(got, "y") != maxJobErrorLen-1 || !strings.Contains(got, "…") {
+		t.Errorf("renderJobError() = %q, want truncated to %d characters", got, maxJobErrorLen)
+	}
+}
+
+func TestRenderLibraryPath(t *testing.T) {
+	if got := renderLibraryPath(""); got != "" {
+		t.Errorf("renderLibraryPath(\"\") = %q, want empty", got)
+	}
+	if got := renderLibraryPath("/library/movies/Movie (2020)"); got != "  Library: /library/movies/Movie (2020)\n" {
+		t.Errorf("renderLibraryPath() = %q", got)
+	}
+}
+
+func TestItemDetail_BackupHasNothingToOrganize(t *testing.T) {
+	app := NewApp(&config.Config{}, nil)
+	movie := model.MediaItem{ID: 1, Type: model.MediaTypeMovie, Name: "Inception", RipMode: model.RipModeBackup,
+		CurrentStage: model.StageRip, StageStatus: model.StatusCompleted}
+	app.state = &AppState{Items: []model.MediaItem{movie}}
+	app.currentView = ViewItemDetail
+	app.selectedItem = &app.state.Items[0]
+
+	view := app.View()
+	if !strings.Contains(view, "Movie, disc backup") || strings.Contains(view, "[o] Organize") {
+		t.Errorf("backup detail should not offer organize:\n%s", view)
+	}
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if cmd != nil || app.currentView != ViewItemDetail {
+		t.Error("[o] should not open the organize view for a backup")
+	}
+	if !strings.Contains(app.statusMsg, "no titles to organize") {
+		t.Errorf("statusMsg = %q", app.statusMsg)
+	}
+}
@@ -2,15 +2,23 @@ package tui
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/cuivienor/media-pipeline/internal/db"
 	"github.com/cuivienor/media-pipeline/internal/model"
 )
 
 // statusDone is a special status for fully completed items (publish done)
 const statusDone model.Status = "done"
 
+// statusStale is a special status for items with a job whose worker exited
+// while the job was in progress
+const statusStale model.Status = "stale"
+
 // renderItemList renders the main item list view
 func (a *App) renderItemList() string {
 	var b strings.Builder
@@ -18,6 +26,14 @@ func (a *App) renderItemList() string {
 	b.WriteString(titleStyle.Render("Media Pipeline"))
 	b.WriteString("\n\n")
 
+	if len(a.unreachableTargets) > 0 {
+		for _, err := range a.unreachableTargets {
+			b.WriteString(errorStyle.Render("⚠ " + err.Error()))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
 	if a.state == nil || len(a.state.Items) == 0 {
 		b.WriteString(mutedItemStyle.Render("No active items. Press [n] to add one."))
 		b.WriteString("\n\n")
@@ -25,83 +41,202 @@ func (a *App) renderItemList() string {
 		return b.String()
 	}
 
-	// Group items by category (each item appears in exactly one section)
-	needsAction := a.filterItemsByCategory(model.StatusCompleted)
-	inProgress := a.filterItemsByCategory(model.StatusInProgress)
-	failed := a.filterItemsByCategory(model.StatusFailed)
-	notStarted := a.filterItemsByCategory(model.StatusPending)
-	done := a.filterItemsByCategory(statusDone)
+	if a.filtering || a.filter != "" {
+		prompt := "Filter: " + a.filter
+		if a.filtering {
+			prompt += "█"
+		}
+		b.WriteString(prompt)
+		b.WriteString("\n\n")
+	}
 
+	var lines []listLine
 	cursorIndex := 0
-
-	// Needs Action section
-	if len(needsAction) > 0 {
-		b.WriteString(sectionHeaderStyle.Render("NEEDS ACTION"))
-		b.WriteString("\n")
-		for _, item := range needsAction {
+	for _, section := range a.itemListSections() {
+		if len(section.items) == 0 {
+			continue
+		}
+		lines = append(lines, listLine{text: sectionHeaderStyle.Render(section.title), section: section.title, item: -1})
+		for _, item := range section.items {
 			selected := cursorIndex == a.cursor
-			b.WriteString(a.renderItemRow(item, selected))
-			b.WriteString("\n")
+			lines = append(lines, listLine{text: a.renderItemRow(item, selected), section: section.title, item: cursorIndex})
 			cursorIndex++
 		}
-		b.WriteString("\n")
+		lines = append(lines, listLine{section: section.title, item: -1})
 	}
 
-	// In Progress section
-	if len(inProgress) > 0 {
-		b.WriteString(sectionHeaderStyle.Render("IN PROGRESS"))
-		b.WriteString("\n")
-		for _, item := range inProgress {
-			selected := cursorIndex == a.cursor
-			b.WriteString(a.renderItemRow(item, selected))
-			b.WriteString("\n")
-			cursorIndex++
+	// Page the list when the terminal is too short to show all of it
+	pages := paginateLines(lines, a.itemListPageSize())
+	page := 0
+	for i, p := range pages {
+		for _, line := range p {
+			if line.item == a.cursor {
+				page = i
+			}
 		}
-		b.WriteString("\n")
 	}
-
-	// Failed section
-	if len(failed) > 0 {
-		b.WriteString(sectionHeaderStyle.Render("FAILED"))
-		b.WriteString("\n")
-		for _, item := range failed {
-			selected := cursorIndex == a.cursor
-			b.WriteString(a.renderItemRow(item, selected))
+	if len(pages) > 0 {
+		for _, line := range pages[page] {
+			b.WriteString(line.text)
 			b.WriteString("\n")
-			cursorIndex++
 		}
+	}
+	if len(pages) > 1 {
+		b.WriteString(mutedItemStyle.Render(fmt.Sprintf("Page %d of %d", page+1, len(pages))))
 		b.WriteString("\n")
 	}
+	b.WriteString(mutedItemStyle.Render(a.summaryLine()))
+	b.WriteString("\n")
 
-	// Not Started section
-	if len(notStarted) > 0 {
-		b.WriteString(sectionHeaderStyle.Render("NOT STARTED"))
+	if a.statusMsg != "" {
+		b.WriteString(a.statusMsg)
 		b.WriteString("\n")
-		for _, item := range notStarted {
-			selected := cursorIndex == a.cursor
-			b.WriteString(a.renderItemRow(item, selected))
-			b.WriteString("\n")
-			cursorIndex++
+	}
+	if cursorIndex == 0 {
+		b.WriteString(mutedItemStyle.Render("No items match the filter."))
+		b.WriteString("\n\n")
+	}
+	if a.filtering {
+		b.WriteString(helpStyle.Render("[Enter] Apply  [Esc] Clear"))
+	} else {
+		help := fmt.Sprintf("[Enter] View  [/] Filter  [t] Sort: %s  [g] Group: %s  [n] New Item  [S] Start Ready  [r] Refresh  [q] Quit",
+			a.sortLabel(), a.groupLabel())
+		if len(a.state.StaleJobs) > 0 {
+			help = "[F] Fail Stale  " + help
 		}
-		b.WriteString("\n")
+		b.WriteString(helpStyle.Render(help))
 	}
 
-	// Done section (fully completed items)
-	if len(done) > 0 {
-		b.WriteString(sectionHeaderStyle.Render("DONE"))
-		b.WriteString("\n")
-		for _, item := range done {
-			selected := cursorIndex == a.cursor
-			b.WriteString(a.renderItemRow(item, selected))
-			b.WriteString("\n")
-			cursorIndex++
+	return b.String()
+}
+
+// itemListSection is a titled group of items in the item list
+type itemListSection struct {
+	title string
+	items []model.MediaItem
+}
+
+// itemListSections groups the filtered, sorted items for display. Each item
+// appears in exactly one section: by category, or by stage after [g].
+func (a *App) itemListSections() []itemListSection {
+	if a.groupByStage {
+		var sections []itemListSection
+		for stage := model.StageRip; stage <= model.StagePublish; stage++ {
+			var items []model.MediaItem
+			for _, item := range a.state.ItemsAtStage(stage) {
+				if a.matchesFilter(item) {
+					items = append(items, item)
+				}
+			}
+			a.sortItems(items)
+			title := fmt.Sprintf("%s (%d)", strings.ToUpper(stage.String()), len(items))
+			sections = append(sections, itemListSection{title, items})
 		}
-		b.WriteString("\n")
+		return sections
 	}
 
-	b.WriteString(helpStyle.Render("[Enter] View  [n] New Item  [r] Refresh  [q] Quit"))
+	return []itemListSection{
+		{"STALE", a.filterItemsByCategory(statusStale)},
+		{"NEEDS ACTION", a.filterItemsByCategory(model.StatusCompleted)},
+		{"IN PROGRESS", a.filterItemsByCategory(model.StatusInProgress)},
+		{"FAILED", a.filterItemsByCategory(model.StatusFailed)},
+		{"NOT STARTED", a.filterItemsByCategory(model.StatusPending)},
+		{"DONE", a.filterItemsByCategory(statusDone)}, // fully completed items
+	}
+}
 
-	return b.String()
+// summaryLine counts the items in each category, e.g. "2 needs action ·
+// 1 in progress · 0 failed · 5 done". Stale items are only mentioned when
+// there are any.
+func (a *App) summaryLine() string {
+	parts := []string{
+		fmt.Sprintf("%d needs action", len(a.filterItemsByCategory(model.StatusCompleted))),
+		fmt.Sprintf("%d in progress", len(a.filterItemsByCategory(model.StatusInProgress))),
+		fmt.Sprintf("%d failed", len(a.filterItemsByCategory(model.StatusFailed))),
+		fmt.Sprintf("%d done", len(a.filterItemsByCategory(statusDone))),
+	}
+	if stale := len(a.filterItemsByCategory(statusStale)); stale > 0 {
+		parts = append([]string{fmt.Sprintf("%d stale", stale)}, parts...)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// groupLabel describes the current item list grouping for the help line
+func (a *App) groupLabel() string {
+	if a.groupByStage {
+		return "stage"
+	}
+	return "status"
+}
+
+// listLine is one line of the item list: a section header, an item row or
+// the blank line closing a section
+type listLine struct {
+	text    string
+	section string // section the line belongs to
+	item    int    // display index of the item, or -1 for non-item lines
+}
+
+// itemListPageSize returns how many terminal lines the list may use around
+// the title, filter prompt, page footer, status and help lines. Zero means the
+// window size is unknown and the list is not paged.
+func (a *App) itemListPageSize() int {
+	if a.height <= 0 {
+		return 0
+	}
+	reserved := lipgloss.Height(titleStyle.Render("Media Pipeline")) + 1 // + blank line
+	reserved += lipgloss.Height(helpStyle.Render("")) + 2                // + page footer, summary
+	if a.filtering || a.filter != "" {
+		reserved += 2
+	}
+	if a.statusMsg != "" {
+		reserved++
+	}
+	if len(a.unreachableTargets) > 0 {
+		reserved += len(a.unreachableTargets) + 1
+	}
+	return max(a.height-reserved, 4)
+}
+
+// paginateLines splits lines into pages whose rendered height is at most size
+// terminal lines. A page that starts partway through a section repeats the
+// section header so every item stays under its category; blank separators are
+// dropped at page starts.
+func paginateLines(lines []listLine, size int) [][]listLine {
+	if size <= 0 {
+		if len(lines) == 0 {
+			return nil
+		}
+		return [][]listLine{lines}
+	}
+
+	var pages [][]listLine
+	var current []listLine
+	used := 0
+	for _, line := range lines {
+		height := lipgloss.Height(line.text)
+		isHeader := line.item < 0 && line.text != ""
+		// Flush when the line doesn't fit, or when a header would be left
+		// alone at the bottom of the page without its first item
+		if len(current) > 0 && (used+height > size || (isHeader && used+height >= size)) {
+			pages = append(pages, current)
+			current, used = nil, 0
+		}
+		if len(current) == 0 && line.item >= 0 {
+			header := sectionHeaderStyle.Render(line.section + " (cont.)")
+			current = append(current, listLine{text: header, section: line.section, item: -1})
+			used += lipgloss.Height(header)
+		}
+		if len(current) == 0 && line.text == "" {
+			continue
+		}
+		current = append(current, line)
+		used += height
+	}
+	if len(current) > 0 {
+		pages = append(pages, current)
+	}
+	return pages
 }
 
 // renderItemRow renders a single item row
@@ -130,6 +265,9 @@ func (a *App) renderItemRow(item model.MediaItem, selected bool) string {
 	case statusDone:
 		statusIcon = "✓"
 		statusStyle = lipgloss.NewStyle().Foreground(colorSuccess)
+	case statusStale:
+		statusIcon = "!"
+		statusStyle = lipgloss.NewStyle().Foreground(colorError)
 	default:
 		statusIcon = "○"
 		statusStyle = lipgloss.NewStyle().Foreground(colorMuted)
@@ -149,12 +287,13 @@ func (a *App) renderItemRow(item model.MediaItem, selected bool) string {
 
 	// Next action hint
 	var actionHint string
-	if item.Type == model.MediaTypeMovie {
+	if effectiveStatus == statusStale {
+		job := a.state.StaleJobs[item.ID][0]
+		actionHint = mutedItemStyle.Render(fmt.Sprintf(" [%s interrupted]", job.Stage.String()))
+	} else if item.Type == model.MediaTypeMovie {
 		switch effectiveStatus {
 		case model.StatusCompleted:
-			if item.CurrentStage != model.StagePublish {
-				actionHint = mutedItemStyle.Render(fmt.Sprintf(" → %s", item.CurrentStage.NextAction()))
-			}
+			actionHint = mutedItemStyle.Render(fmt.Sprintf(" → %s", item.CurrentStage.NextAction()))
 		case model.StatusInProgress:
 			actionHint = mutedItemStyle.Render(fmt.Sprintf(" [%s]", item.CurrentStage.String()))
 		case model.StatusPending:
@@ -226,8 +365,12 @@ func (a *App) getTVActionHint(item model.MediaItem, effectiveStatus model.Status
 
 // categorizeItem returns the display category for an item based on its most urgent status
 // Priority: Failed > InProgress > Mixed (treated as InProgress) > AllCompleted > AllPending
-// Items at publish stage with completed status are categorized as "done".
+// Items at publish stage with completed status are categorized as "done", and
+// items with a stale job as "stale" ahead of everything else.
 func (a *App) categorizeItem(item model.MediaItem) model.Status {
+	if len(a.state.StaleJobs[item.ID]) > 0 {
+		return statusStale
+	}
 	if item.Type == model.MediaTypeMovie {
 		// If publish is complete, the item is fully done
 		if item.CurrentStage == model.StagePublish && item.StageStatus == model.StatusCompleted {
@@ -287,21 +430,126 @@ func (a *App) categorizeItem(item model.MediaItem) model.Status {
 func (a *App) filterItemsByCategory(targetStatus model.Status) []model.MediaItem {
 	var result []model.MediaItem
 	for _, item := range a.state.Items {
+		if !a.matchesFilter(item) {
+			continue
+		}
 		if a.categorizeItem(item) == targetStatus {
 			result = append(result, item)
 		}
 	}
+	a.sortItems(result)
 	return result
 }
 
+// sortFields is the order [t] cycles through. Items load most recently
+// updated first, so that is the default.
+var sortFields = []db.SortField{db.SortByUpdated, db.SortByName, db.SortByCreated}
+
+// nextSortField returns the sort field after current in sortFields
+func nextSortField(current db.SortField) db.SortField {
+	i := slices.Index(sortFields, current)
+	if i < 0 {
+		i = 0
+	}
+	return sortFields[(i+1)%len(sortFields)]
+}
+
+// sortLabel describes the current item list order for the help line
+func (a *App) sortLabel() string {
+	switch a.sortBy {
+	case db.SortByName:
+		return "name"
+	case db.SortByCreated:
+		return "newest"
+	default:
+		return "recently updated"
+	}
+}
+
+// sortItems orders items in place: names A-Z, timestamps newest first
+func (a *App) sortItems(items []model.MediaItem) {
+	switch a.sortBy {
+	case db.SortByName:
+		sort.SliceStable(items, func(i, j int) bool {
+			return strings.ToLower(items[i].Name) < strings.ToLower(items[j].Name)
+		})
+	case db.SortByCreated:
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].CreatedAt.After(items[j].CreatedAt)
+		})
+	case db.SortByUpdated:
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].UpdatedAt.After(items[j].UpdatedAt)
+		})
+	}
+}
+
 // getDisplayOrderItems returns all items in the order they appear on screen
-// (NEEDS ACTION, IN PROGRESS, FAILED, NOT STARTED, DONE)
+// (STALE, NEEDS ACTION, IN PROGRESS, FAILED, NOT STARTED, DONE, or by stage)
 func (a *App) getDisplayOrderItems() []model.MediaItem {
 	var result []model.MediaItem
-	result = append(result, a.filterItemsByCategory(model.StatusCompleted)...)
-	result = append(result, a.filterItemsByCategory(model.StatusInProgress)...)
-	result = append(result, a.filterItemsByCategory(model.StatusFailed)...)
-	result = append(result, a.filterItemsByCategory(model.StatusPending)...)
-	result = append(result, a.filterItemsByCategory(statusDone)...)
+	for _, section := range a.itemListSections() {
+		result = append(result, section.items...)
+	}
 	return result
 }
+
+// matchesFilter reports whether item's name or safe name contains the item
+// list filter, ignoring case. A filter starting with "@" matches items with a
+// job run on a worker host containing the rest instead.
+func (a *App) matchesFilter(item model.MediaItem) bool {
+	if a.filter == "" {
+		return true
+	}
+	if host, ok := strings.CutPrefix(a.filter, "@"); ok {
+		return a.ranOnWorker(item, strings.ToLower(host))
+	}
+	needle := strings.ToLower(a.filter)
+	return strings.Contains(strings.ToLower(item.Name), needle) ||
+		strings.Contains(strings.ToLower(item.SafeName), needle)
+}
+
+// ranOnWorker reports whether any of item's jobs ran on a worker whose host
+// name contains host
+func (a *App) ranOnWorker(item model.MediaItem, host string) bool {
+	var jobs []model.Job
+	jobs = append(jobs, a.state.MovieJobs[item.ID]...)
+	for _, season := range item.Seasons {
+		jobs = append(jobs, a.state.SeasonJobs[season.ID]...)
+	}
+	for _, job := range jobs {
+		if job.WorkerID != "" && strings.Contains(strings.ToLower(job.WorkerID), host) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleFilterKey edits the item list filter while the prompt has focus
+func (a *App) handleFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return a, tea.Quit
+
+	case "enter":
+		a.filtering = false
+
+	case "esc":
+		a.filtering = false
+		a.filter = ""
+
+	case "backspace":
+		if runes := []rune(a.filter); len(runes) > 0 {
+			a.filter = string(runes[:len(runes)-1])
+		}
+
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			a.filter += msg.String()
+		}
+	}
+
+	// The visible list changed, so start again from the top
+	a.cursor = 0
+	return a, nil
+}
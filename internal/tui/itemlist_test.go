@@ -0,0 +1,242 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestItemList_Filter(t *testing.T) {
+	app := NewApp(&config.Config{}, nil)
+	app.state = &AppState{Items: []model.MediaItem{
+		{ID: 1, Type: model.MediaTypeMovie, Name: "The Matrix", SafeName: "The_Matrix", StageStatus: model.StatusPending},
+		{ID: 2, Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception", StageStatus: model.StatusPending},
+		{ID: 3, Type: model.MediaTypeTV, Name: "The Expanse", SafeName: "The_Expanse"},
+	}}
+
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			app.Update(k)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	press(runes("/"), runes("T"), runes("H"), runes("e"), tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	if app.filter != "THe " {
+		t.Fatalf("filter = %q, want %q", app.filter, "THe ")
+	}
+	names := func() []string {
+		var out []string
+		for _, item := range app.getDisplayOrderItems() {
+			out = append(out, item.Name)
+		}
+		return out
+	}
+	if got := names(); len(got) != 2 {
+		t.Errorf("filtered items = %v, want The Matrix and The Expanse", got)
+	}
+
+	// Keys go to the prompt, not to list shortcuts
+	press(runes("m"))
+	if app.currentView != ViewItemList || app.filter != "THe m" {
+		t.Fatalf("view = %v filter = %q", app.currentView, app.filter)
+	}
+	if got := names(); len(got) != 1 || got[0] != "The Matrix" {
+		t.Errorf("filtered items = %v, want [The Matrix]", got)
+	}
+
+	// Enter applies the filter and returns keys to the list
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.filtering {
+		t.Error("enter should close the filter prompt")
+	}
+	if !strings.Contains(app.renderItemList(), "Filter: THe m") {
+		t.Error("applied filter should stay visible")
+	}
+
+	// Esc on the list clears it
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.filter != "" || len(names()) != 3 {
+		t.Errorf("esc should clear the filter, got %q with %d items", app.filter, len(names()))
+	}
+}
+
+func TestItemList_FilterByWorker(t *testing.T) {
+	app := NewApp(&config.Config{}, nil)
+	app.state = &AppState{
+		Items: []model.MediaItem{
+			{ID: 1, Type: model.MediaTypeMovie, Name: "The Matrix", SafeName: "The_Matrix", StageStatus: model.StatusPending},
+			{ID: 2, Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception", StageStatus: model.StatusPending},
+			{ID: 3, Type: model.MediaTypeTV, Name: "The Expanse", SafeName: "The_Expanse", Seasons: []model.Season{{ID: 30}}},
+		},
+		MovieJobs: map[int64][]model.Job{
+			1: {{Stage: model.StageRip, WorkerID: "Ripper-1"}},
+			2: {{Stage: model.StageRip, WorkerID: "nas"}},
+		},
+		SeasonJobs: map[int64][]model.Job{
+			30: {{Stage: model.StageRip, WorkerID: "ripper-2"}},
+		},
+	}
+
+	app.filter = "@ripper"
+	var names []string
+	for _, item := range app.getDisplayOrderItems() {
+		names = append(names, item.Name)
+	}
+	if len(names) != 2 || slices.Contains(names, "Inception") {
+		t.Errorf("items on ripper hosts = %v, want The Matrix and The Expanse", names)
+	}
+}
+
+func TestItemList_SortToggle(t *testing.T) {
+	now := time.Now()
+	app := NewApp(&config.Config{}, nil)
+	app.state = &AppState{Items: []model.MediaItem{
+		{ID: 1, Type: model.MediaTypeMovie, Name: "inception", StageStatus: model.StatusPending,
+			CreatedAt: now.Add(-3 * time.Hour), UpdatedAt: now},
+		{ID: 2, Type: model.MediaTypeMovie, Name: "Alien", StageStatus: model.StatusPending,
+			CreatedAt: now.Add(-1 * time.Hour), UpdatedAt: now.Add(-time.Hour)},
+		{ID: 3, Type: model.MediaTypeMovie, Name: "Zodiac", StageStatus: model.StatusPending,
+			CreatedAt: now.Add(-2 * time.Hour), UpdatedAt: now.Add(-2 * time.Hour)},
+	}}
+
+	order := func() string {
+		var names []string
+		for _, item := range app.getDisplayOrderItems() {
+			names = append(names, item.Name)
+		}
+		return strings.Join(names, ",")
+	}
+	sortKey := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")}
+
+	if got := order(); got != "inception,Alien,Zodiac" {
+		t.Errorf("default order = %s, want load order", got)
+	}
+
+	app.Update(sortKey)
+	if got := order(); got != "Alien,inception,Zodiac" {
+		t.Errorf("name order = %s", got)
+	}
+
+	app.Update(sortKey)
+	if got := order(); got != "Alien,Zodiac,inception" {
+		t.Errorf("created order = %s", got)
+	}
+
+	app.Update(sortKey)
+	if got := order(); got != "inception,Alien,Zodiac" {
+		t.Errorf("updated order = %s", got)
+	}
+	if !strings.Contains(app.renderItemList(), "[t] Sort: recently updated") {
+		t.Error("help line should show the current sort")
+	}
+}
+
+func TestItemList_Pagination(t *testing.T) {
+	app := NewApp(&config.Config{}, nil)
+	var items []model.MediaItem
+	for i := 0; i < 12; i++ {
+		status := model.StatusPending
+		if i < 2 {
+			status = model.StatusFailed
+		}
+		items = append(items, model.MediaItem{
+			ID: int64(i + 1), Type: model.MediaTypeMovie,
+			Name: fmt.Sprintf("Movie %02d", i), StageStatus: status,
+		})
+	}
+	app.state = &AppState{Items: items}
+	app.Update(tea.WindowSizeMsg{Width: 80, Height: 12})
+
+	view := app.renderItemList()
+	if got := strings.Count(view, "\n") + 1; got > 12 {
+		t.Errorf("rendered %d lines, want at most the window height 12:\n%s", got, view)
+	}
+	if !strings.Contains(view, "Page 1 of") {
+		t.Errorf("expected page footer, got:\n%s", view)
+	}
+	if !strings.Contains(view, "> ") {
+		t.Error("cursor row should be on the first page")
+	}
+
+	// Moving to the last item follows the cursor onto the last page
+	for i := 0; i < len(items); i++ {
+		app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	view = app.renderItemList()
+	if !strings.Contains(view, "> ") || !strings.Contains(view, "Movie 11") {
+		t.Errorf("last page should show the cursor on Movie 11:\n%s", view)
+	}
+	if !strings.Contains(view, "NOT STARTED (cont.)") {
+		t.Errorf("continued section should repeat its header:\n%s", view)
+	}
+
+	// Without a known window size everything is shown
+	app.height = 0
+	if view := app.renderItemList(); strings.Contains(view, "Page ") {
+		t.Errorf("unexpected paging without a window size:\n%s", view)
+	}
+}
+
+func TestItemList_GroupByStage(t *testing.T) {
+	app := NewApp(&config.Config{}, nil)
+	app.state = &AppState{Items: []model.MediaItem{
+		{ID: 1, Type: model.MediaTypeMovie, Name: "Alien", CurrentStage: model.StageTranscode, StageStatus: model.StatusFailed},
+		{ID: 2, Type: model.MediaTypeMovie, Name: "Brazil", CurrentStage: model.StageRemux, StageStatus: model.StatusCompleted},
+		{ID: 3, Type: model.MediaTypeMovie, Name: "Cube", CurrentStage: model.StageTranscode, StageStatus: model.StatusInProgress},
+	}}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if !app.groupByStage {
+		t.Fatal("[g] should group the list by stage")
+	}
+
+	var names []string
+	for _, item := range app.getDisplayOrderItems() {
+		names = append(names, item.Name)
+	}
+	if got := strings.Join(names, ","); got != "Brazil,Alien,Cube" {
+		t.Errorf("stage order = %s, want Brazil,Alien,Cube", got)
+	}
+
+	view := app.renderItemList()
+	for _, want := range []string{"REMUX (1)", "TRANSCODE (2)", "Group: stage"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("grouped view missing %q", want)
+		}
+	}
+	if strings.Contains(view, "RIP (0)") {
+		t.Error("empty stages should be hidden")
+	}
+}
+
+func TestItemList_SummaryLine(t *testing.T) {
+	app := NewApp(&config.Config{}, nil)
+	app.state = &AppState{Items: []model.MediaItem{
+		{ID: 1, Type: model.MediaTypeMovie, Name: "Alien", CurrentStage: model.StageRemux, StageStatus: model.StatusCompleted},
+		{ID: 2, Type: model.MediaTypeMovie, Name: "Brazil", CurrentStage: model.StageRip, StageStatus: model.StatusCompleted},
+		{ID: 3, Type: model.MediaTypeMovie, Name: "Cube", CurrentStage: model.StageTranscode, StageStatus: model.StatusInProgress},
+		{ID: 4, Type: model.MediaTypeMovie, Name: "Dune", CurrentStage: model.StagePublish, StageStatus: model.StatusCompleted},
+		{ID: 5, Type: model.MediaTypeMovie, Name: "Heat", StageStatus: model.StatusPending},
+	}}
+
+	want := "2 needs action · 1 in progress · 0 failed · 1 done"
+	if got := app.summaryLine(); got != want {
+		t.Errorf("summaryLine() = %q, want %q", got, want)
+	}
+	if !strings.Contains(app.renderItemList(), want) {
+		t.Error("item list should end with the summary line")
+	}
+
+	// The summary follows a reload
+	app.state.Items[2].StageStatus = model.StatusFailed
+	if got := app.summaryLine(); got != "2 needs action · 0 in progress · 1 failed · 1 done" {
+		t.Errorf("summaryLine() after reload = %q", got)
+	}
+}
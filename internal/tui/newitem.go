@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/metadata"
 	"github.com/cuivienor/media-pipeline/internal/model"
 )
 
@@ -14,18 +16,25 @@ import (
 type NewItemForm struct {
 	Type       string // "movie" or "tv"
 	Name       string
+	Edition    string // Movies only, e.g. "Extended"
 	Seasons    string // For TV: "1-5" or "1,2,3" or "1"
 	DatabaseID string // TMDB ID for movies, TVDB ID for TV shows
+	Backup     bool   // Back up whole discs instead of ripping titles
 	focusIndex int
 	err        string
+
+	// ID lookup picker state
+	searching   bool
+	matches     []metadata.Match
+	matchCursor int
 }
 
 // fields returns the list of field names in order
 func (f *NewItemForm) fields() []string {
 	if f.Type == "tv" {
-		return []string{"type", "name", "seasons", "dbid"}
+		return []string{"type", "name", "seasons", "dbid", "ripmode"}
 	}
-	return []string{"type", "name", "dbid"}
+	return []string{"type", "name", "edition", "dbid", "ripmode"}
 }
 
 // Validate returns an error message if the form is invalid
@@ -137,6 +146,10 @@ func (a *App) renderNewItemForm() string {
 			b.WriteString(fmt.Sprintf("%sType: %s\n", prefix, typeStr))
 		case "name":
 			b.WriteString(fmt.Sprintf("%sName: %s\n", prefix, form.Name))
+		case "edition":
+			b.WriteString(fmt.Sprintf("%sEdition: %s\n", prefix, form.Edition))
+			b.WriteString(mutedItemStyle.Render("        (optional, e.g. 'Extended' to keep editions apart)"))
+			b.WriteString("\n")
 		case "seasons":
 			b.WriteString(fmt.Sprintf("%sSeasons: %s\n", prefix, form.Seasons))
 			b.WriteString(mutedItemStyle.Render("        (e.g., '1-5' or '1,2,3')"))
@@ -149,17 +162,49 @@ func (a *App) renderNewItemForm() string {
 			b.WriteString(fmt.Sprintf("%s%s: %s\n", prefix, label, form.DatabaseID))
 			b.WriteString(mutedItemStyle.Render("        (optional, for FileBot matching)"))
 			b.WriteString("\n")
+		case "ripmode":
+			modeStr := "[titles]  backup"
+			if form.Backup {
+				modeStr = " titles  [backup]"
+			}
+			b.WriteString(fmt.Sprintf("%sRip: %s\n", prefix, modeStr))
+			b.WriteString(mutedItemStyle.Render("        (backup keeps a decrypted copy of the whole disc, for archiving)"))
+			b.WriteString("\n")
 		}
 	}
 
 	b.WriteString("\n")
 
+	if form.searching {
+		b.WriteString(mutedItemStyle.Render("Searching..."))
+		b.WriteString("\n\n")
+	}
+
+	if len(form.matches) > 0 {
+		b.WriteString(sectionHeaderStyle.Render("MATCHES"))
+		b.WriteString("\n")
+		for i, m := range form.matches {
+			prefix := "  "
+			if i == form.matchCursor {
+				prefix = "> "
+			}
+			b.WriteString(fmt.Sprintf("%s%s\n", prefix, formatMatch(m)))
+		}
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("[Enter] Use match  [↑/↓] Select  [Esc] Back to form"))
+		return b.String()
+	}
+
 	if form.err != "" {
 		b.WriteString(errorStyle.Render(form.err))
 		b.WriteString("\n\n")
 	}
 
-	b.WriteString(helpStyle.Render("[Enter] Create  [Tab] Next field  [Esc] Cancel"))
+	if a.metadata != nil {
+		b.WriteString(helpStyle.Render("[Enter] Create  [Tab] Next field  [Ctrl+F] Look up ID  [Esc] Cancel"))
+	} else {
+		b.WriteString(helpStyle.Render("[Enter] Create  [Tab] Next field  [Esc] Cancel"))
+	}
 
 	return b.String()
 }
@@ -169,7 +214,23 @@ func (a *App) handleNewItemKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	form := a.newItemForm
 	fields := form.fields()
 
+	if len(form.matches) > 0 {
+		return a.handleMatchPickerKey(msg)
+	}
+
 	switch msg.String() {
+	case "ctrl+f":
+		if a.metadata == nil || form.searching {
+			return a, nil
+		}
+		if strings.TrimSpace(form.Name) == "" {
+			form.err = "Enter a name to look up"
+			return a, nil
+		}
+		form.err = ""
+		form.searching = true
+		return a, a.lookupMatches(form.Type, form.Name)
+
 	case "tab", "down":
 		form.focusIndex = (form.focusIndex + 1) % len(fields)
 		return a, nil
@@ -182,12 +243,15 @@ func (a *App) handleNewItemKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return a, nil
 
 	case "left", "right":
-		if fields[form.focusIndex] == "type" {
+		switch fields[form.focusIndex] {
+		case "type":
 			if form.Type == "movie" {
 				form.Type = "tv"
 			} else {
 				form.Type = "movie"
 			}
+		case "ripmode":
+			form.Backup = !form.Backup
 		}
 		return a, nil
 
@@ -205,6 +269,10 @@ func (a *App) handleNewItemKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if len(form.Name) > 0 {
 				form.Name = form.Name[:len(form.Name)-1]
 			}
+		case "edition":
+			if len(form.Edition) > 0 {
+				form.Edition = form.Edition[:len(form.Edition)-1]
+			}
 		case "seasons":
 			if len(form.Seasons) > 0 {
 				form.Seasons = form.Seasons[:len(form.Seasons)-1]
@@ -228,6 +296,8 @@ func (a *App) handleNewItemKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			switch field {
 			case "name":
 				form.Name += char
+			case "edition":
+				form.Edition += char
 			case "seasons":
 				// Allow digits, comma, dash
 				if (char >= "0" && char <= "9") || char == "," || char == "-" {
@@ -244,6 +314,70 @@ func (a *App) handleNewItemKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// handleMatchPickerKey handles key presses while choosing a lookup match
+func (a *App) handleMatchPickerKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	form := a.newItemForm
+
+	switch msg.String() {
+	case "up", "k":
+		if form.matchCursor > 0 {
+			form.matchCursor--
+		}
+	case "down", "j":
+		if form.matchCursor < len(form.matches)-1 {
+			form.matchCursor++
+		}
+	case "enter":
+		m := form.matches[form.matchCursor]
+		form.Name = m.Title
+		form.DatabaseID = strconv.Itoa(m.ID)
+		form.matches = nil
+	case "esc":
+		form.matches = nil
+	}
+	return a, nil
+}
+
+// formatMatch renders a lookup match as "Title (Year) #ID"
+func formatMatch(m metadata.Match) string {
+	if m.Year == 0 {
+		return fmt.Sprintf("%s #%d", m.Title, m.ID)
+	}
+	return fmt.Sprintf("%s (%d) #%d", m.Title, m.Year, m.ID)
+}
+
+// lookupResultsMsg is sent when an ID lookup completes
+type lookupResultsMsg struct {
+	matches []metadata.Match
+	err     error
+}
+
+// lookupMatches searches TMDB (movies) or TVDB (TV shows) for name
+func (a *App) lookupMatches(itemType, name string) tea.Cmd {
+	client := a.metadata
+	return func() tea.Msg {
+		var matches []metadata.Match
+		var err error
+		if itemType == "tv" {
+			matches, err = client.SearchTV(strings.TrimSpace(name))
+		} else {
+			matches, err = client.SearchMovie(strings.TrimSpace(name))
+		}
+		return lookupResultsMsg{matches: matches, err: err}
+	}
+}
+
+// itemSafeName derives the directory-friendly name stored alongside an item's
+// display name. An edition is appended as " (Extended)" so several editions of
+// one title don't collide.
+func itemSafeName(name, edition string) string {
+	safeName := strings.ReplaceAll(strings.TrimSpace(name), " ", "_")
+	if edition = strings.TrimSpace(edition); edition != "" {
+		safeName += " (" + strings.ReplaceAll(edition, " ", "_") + ")"
+	}
+	return safeName
+}
+
 // itemCreatedMsg is sent when item creation completes
 type itemCreatedMsg struct {
 	item *model.MediaItem
@@ -256,14 +390,24 @@ func (a *App) createNewItem() tea.Cmd {
 		form := a.newItemForm
 		ctx := context.Background()
 
-		safeName := strings.ReplaceAll(form.Name, " ", "_")
+		// Editions only apply to movies; ignore one typed before switching to TV
+		edition := ""
+		if form.Type == "movie" {
+			edition = strings.TrimSpace(form.Edition)
+		}
+		safeName := itemSafeName(form.Name, edition)
 
 		item := &model.MediaItem{
 			Type:       model.MediaType(form.Type),
 			Name:       form.Name,
 			SafeName:   safeName,
+			Edition:    edition,
+			RipMode:    model.RipModeTitles,
 			ItemStatus: model.ItemStatusNotStarted,
 		}
+		if form.Backup {
+			item.RipMode = model.RipModeBackup
+		}
 
 		// Set database ID if provided
 		if form.DatabaseID != "" {
@@ -282,24 +426,21 @@ func (a *App) createNewItem() tea.Cmd {
 			item.StageStatus = model.StatusPending
 		}
 
-		if err := a.repo.CreateMediaItem(ctx, item); err != nil {
-			return itemCreatedMsg{err: err}
-		}
-
-		// For TV shows, create seasons
-		if form.Type == "tv" {
-			seasons, _ := parseSeasons(form.Seasons)
-			for _, num := range seasons {
-				season := &model.Season{
-					ItemID:       item.ID,
-					Number:       num,
-					CurrentStage: model.StageRip,
-					StageStatus:  model.StatusPending,
-				}
-				if err := a.repo.CreateSeason(ctx, season); err != nil {
-					return itemCreatedMsg{err: fmt.Errorf("failed to create season %d: %w", num, err)}
+		// Create the item and any seasons together so a failure leaves nothing behind
+		err := a.repo.WithTx(ctx, func(repo db.Repository) error {
+			if err := repo.CreateMediaItem(ctx, item); err != nil {
+				return err
+			}
+			if form.Type == "tv" {
+				seasons, _ := parseSeasons(form.Seasons)
+				if _, err := repo.CreateSeasons(ctx, item.ID, seasons); err != nil {
+					return err
 				}
 			}
+			return nil
+		})
+		if err != nil {
+			return itemCreatedMsg{err: err}
 		}
 
 		return itemCreatedMsg{item: item}
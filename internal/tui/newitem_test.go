@@ -0,0 +1,146 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/metadata"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// fakeMetadataClient returns canned lookup results
+type fakeMetadataClient struct {
+	movies   []metadata.Match
+	searched string
+}
+
+func (f *fakeMetadataClient) SearchMovie(title string) ([]metadata.Match, error) {
+	f.searched = title
+	return f.movies, nil
+}
+
+func (f *fakeMetadataClient) SearchTV(title string) ([]metadata.Match, error) {
+	f.searched = title
+	return nil, nil
+}
+
+func TestNewItemForm_LookupFillsIDAndName(t *testing.T) {
+	client := &fakeMetadataClient{movies: []metadata.Match{
+		{ID: 603, Title: "The Matrix", Year: 1999},
+		{ID: 604, Title: "The Matrix Reloaded", Year: 2003},
+	}}
+
+	app := NewApp(&config.Config{}, nil)
+	app.SetMetadataClient(client)
+	app.currentView = ViewNewItem
+	app.newItemForm = &NewItemForm{Type: "movie", Name: "the matrix"}
+
+	_, cmd := app.handleNewItemKey(tea.KeyMsg{Type: tea.KeyCtrlF})
+	if cmd == nil {
+		t.Fatal("expected lookup command")
+	}
+	app.Update(cmd())
+	if client.searched != "the matrix" {
+		t.Errorf("searched %q, want %q", client.searched, "the matrix")
+	}
+	if len(app.newItemForm.matches) != 2 {
+		t.Fatalf("got %d matches, want 2", len(app.newItemForm.matches))
+	}
+
+	app.handleNewItemKey(tea.KeyMsg{Type: tea.KeyDown})
+	app.handleNewItemKey(tea.KeyMsg{Type: tea.KeyEnter})
+
+	form := app.newItemForm
+	if form.Name != "The Matrix Reloaded" || form.DatabaseID != "604" {
+		t.Errorf("Name = %q, DatabaseID = %q, want the selected match", form.Name, form.DatabaseID)
+	}
+	if form.matches != nil {
+		t.Error("picker should close after selecting a match")
+	}
+}
+
+func TestNewItemForm_LookupDisabledWithoutClient(t *testing.T) {
+	app := NewApp(&config.Config{}, nil)
+	app.currentView = ViewNewItem
+	app.newItemForm = &NewItemForm{Type: "movie", Name: "The Matrix"}
+
+	if _, cmd := app.handleNewItemKey(tea.KeyMsg{Type: tea.KeyCtrlF}); cmd != nil {
+		t.Error("lookup should be disabled without API keys")
+	}
+}
+
+func TestCreateNewItem_Editions(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+	repo := db.NewSQLiteRepository(database)
+	app := NewApp(&config.Config{}, repo)
+
+	var ids []int64
+	for _, edition := range []string{"", "Extended"} {
+		app.newItemForm = &NewItemForm{Type: "movie", Name: "The Movie", Edition: edition}
+		msg := app.createNewItem()().(itemCreatedMsg)
+		if msg.err != nil {
+			t.Fatalf("createNewItem(edition %q) error = %v", edition, msg.err)
+		}
+		ids = append(ids, msg.item.ID)
+	}
+
+	item, err := repo.GetMediaItem(context.Background(), ids[1])
+	if err != nil {
+		t.Fatalf("GetMediaItem() error = %v", err)
+	}
+	if item.SafeName != "The_Movie (Extended)" || item.Edition != "Extended" {
+		t.Errorf("SafeName = %q, Edition = %q, want %q, %q", item.SafeName, item.Edition, "The_Movie (Extended)", "Extended")
+	}
+}
+
+func TestNewItemForm_EditionOnlyForMovies(t *testing.T) {
+	form := &NewItemForm{Type: "movie"}
+	if fields := form.fields(); len(fields) != 5 || fields[2] != "edition" {
+		t.Errorf("movie fields = %v, want edition after name", fields)
+	}
+	form.Type = "tv"
+	for _, f := range form.fields() {
+		if f == "edition" {
+			t.Error("TV form should not have an edition field")
+		}
+	}
+}
+
+func TestCreateNewItem_Backup(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+	repo := db.NewSQLiteRepository(database)
+	app := NewApp(&config.Config{}, repo)
+
+	app.state = &AppState{}
+	app.currentView = ViewNewItem
+	app.newItemForm = &NewItemForm{Type: "movie", Name: "The Movie"}
+	app.newItemForm.focusIndex = len(app.newItemForm.fields()) - 1
+	app.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if view := app.View(); !strings.Contains(view, "Rip:  titles  [backup]") {
+		t.Errorf("form should show backup selected:\n%s", view)
+	}
+
+	msg := app.createNewItem()().(itemCreatedMsg)
+	if msg.err != nil {
+		t.Fatalf("createNewItem() error = %v", msg.err)
+	}
+	item, err := repo.GetMediaItem(context.Background(), msg.item.ID)
+	if err != nil {
+		t.Fatalf("GetMediaItem() error = %v", err)
+	}
+	if item.RipMode != model.RipModeBackup {
+		t.Errorf("RipMode = %q, want backup", item.RipMode)
+	}
+}
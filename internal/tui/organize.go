@@ -11,6 +11,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/cuivienor/media-pipeline/internal/db"
 	"github.com/cuivienor/media-pipeline/internal/model"
 	"github.com/cuivienor/media-pipeline/internal/organize"
 )
@@ -20,10 +21,18 @@ type OrganizeView struct {
 	item       *model.MediaItem
 	season     *model.Season // nil for movies, set for TV seasons
 	files      []fileInfo
-	discFiles  map[string][]fileInfo // files grouped by disc (for TV seasons)
+	discFiles  map[string][]fileInfo // files grouped by disc (TV seasons and multi-disc movies)
 	validation *organize.ValidationResult
-	path       string   // base path (season directory for TV)
-	discPaths  []string // disc directories within season (for TV)
+	path       string   // base path (season directory for TV, first disc for movies)
+	discPaths  []string // every ripped disc directory
+
+	// TV seasons: each disc's episode offset and the rip job storing it,
+	// keyed by disc path
+	discOffsets map[string]int
+	discJobs    map[string]int64
+
+	// Suggested moves for a movie's titles, shown after pressing [s]
+	plan *movePlan
 }
 
 type fileInfo struct {
@@ -54,10 +63,14 @@ func (a *App) renderOrganizeView() string {
 	b.WriteString("\n")
 	b.WriteString(fmt.Sprintf("  %s\n\n", ov.path))
 
-	// Files - different display for TV seasons with multiple discs
-	if ov.season != nil && len(ov.discFiles) > 0 {
-		// Show season-level files first (like _episodes, _extras)
-		b.WriteString(sectionHeaderStyle.Render("SEASON FILES"))
+	// Files - different display for TV seasons and multi-disc movies
+	if len(ov.discFiles) > 0 {
+		// Show base directory files first (like _episodes, _extras, _main)
+		header := "SEASON FILES"
+		if ov.season == nil {
+			header = "FILES"
+		}
+		b.WriteString(sectionHeaderStyle.Render(header))
 		b.WriteString("\n")
 		for _, f := range ov.files {
 			icon := "  "
@@ -82,6 +95,9 @@ func (a *App) renderOrganizeView() string {
 		for _, discName := range discNames {
 			files := ov.discFiles[discName]
 			b.WriteString(sectionHeaderStyle.Render(discName))
+			if offset := ov.discOffsets[filepath.Join(ov.path, discName)]; offset > 0 {
+				b.WriteString(mutedItemStyle.Render(fmt.Sprintf(" episodes +%d", offset)))
+			}
 			b.WriteString("\n")
 			for _, f := range files {
 				icon := "  "
@@ -128,16 +144,32 @@ func (a *App) renderOrganizeView() string {
 				b.WriteString(fmt.Sprintf("  • %s\n", err))
 			}
 		}
+		warnStyle := lipgloss.NewStyle().Foreground(colorWarning)
+		for _, w := range ov.validation.Warnings {
+			b.WriteString(warnStyle.Render(fmt.Sprintf("  ! %s", w)))
+			b.WriteString("\n")
+		}
 		b.WriteString("\n")
 	}
 
+	if ov.plan != nil {
+		b.WriteString(a.renderMovePlan())
+		return b.String()
+	}
+
 	// Instructions
 	b.WriteString(sectionHeaderStyle.Render("INSTRUCTIONS"))
 	b.WriteString("\n")
-	if ov.item.Type == model.MediaTypeMovie {
+	if ov.item.Type == model.MediaTypeMovie && len(ov.discPaths) > 1 {
+		// Multi-disc movie, everything is merged into the first disc
+		b.WriteString("  1. Move the main feature from every disc to _main/ in the path above\n")
+		b.WriteString("  2. Move extras to _extras/ there (optional)\n")
+		b.WriteString("  3. Delete unwanted files from its root\n")
+	} else if ov.item.Type == model.MediaTypeMovie {
 		b.WriteString("  1. Move main feature to _main/\n")
 		b.WriteString("  2. Move extras to _extras/ (optional)\n")
 		b.WriteString("  3. Delete unwanted files from root\n")
+		b.WriteString("  Press [s] for a suggested sorting by title length\n")
 	} else if len(ov.discPaths) > 0 {
 		// Multi-disc TV season
 		b.WriteString("  For each disc folder:\n")
@@ -146,6 +178,9 @@ func (a *App) renderOrganizeView() string {
 		b.WriteString("  3. Name files: 01.mkv, 02.mkv, etc.\n")
 		b.WriteString("  4. Move extras to _extras/ (optional)\n")
 		b.WriteString("  5. Delete unwanted files from disc root\n")
+		if len(ov.discPaths) > 1 {
+			b.WriteString("  Numbered each disc from 01? Press [a] to continue the numbering across discs\n")
+		}
 	} else {
 		// Single disc
 		b.WriteString("  1. Create _episodes/ in season folder\n")
@@ -157,9 +192,23 @@ func (a *App) renderOrganizeView() string {
 	b.WriteString("\n")
 
 	// Help
-	helpText := "[v] Validate  [r] Refresh  [Esc] Back"
+	helpText := "[v] Validate  [p] Path  [r] Refresh  [Esc] Back"
 	if ov.validation != nil && ov.validation.Valid {
-		helpText = "[c] Mark Complete  [v] Re-validate  [r] Refresh  [Esc] Back"
+		helpText = "[c] Mark Complete  [v] Re-validate  [p] Path  [r] Refresh  [Esc] Back"
+	}
+	if ov.canSuggest() {
+		helpText = "[s] Suggest  " + helpText
+	}
+	if ov.season != nil && len(ov.discPaths) > 1 {
+		if ov.hasEpisodeOffsets() {
+			helpText = "[a] Clear Disc Offsets  " + helpText
+		} else {
+			helpText = "[a] Number Across Discs  " + helpText
+		}
+	}
+	if a.statusMsg != "" {
+		b.WriteString(a.statusMsg)
+		b.WriteString("\n")
 	}
 	b.WriteString(helpStyle.Render(helpText))
 
@@ -168,6 +217,11 @@ func (a *App) renderOrganizeView() string {
 
 // handleOrganizeKey handles key presses in the organize view
 func (a *App) handleOrganizeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	a.statusMsg = ""
+	if a.organizeView != nil && a.organizeView.plan != nil {
+		return a.handleMovePlanKey(msg)
+	}
+
 	switch msg.String() {
 	case "q", "ctrl+c":
 		return a, tea.Quit
@@ -182,10 +236,34 @@ func (a *App) handleOrganizeKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		a.organizeView = nil
 		return a, nil
 
+	case "p":
+		// Show the directory being organized
+		a.showPath()
+		return a, nil
+
 	case "v":
 		// Validate organization
 		return a, a.validateOrganization()
 
+	case "s":
+		// Suggest which titles are the feature and which are extras (movies)
+		if ov := a.organizeView; ov != nil && ov.canSuggest() {
+			a.statusMsg = "Probing titles..."
+			return a, a.suggestOrganization(ov.path)
+		}
+		return a, nil
+
+	case "a":
+		// Continue episode numbering across discs, or undo it (multi-disc seasons)
+		if ov := a.organizeView; ov != nil && ov.season != nil && len(ov.discPaths) > 1 {
+			offsets := map[string]int{}
+			if !ov.hasEpisodeOffsets() {
+				offsets = organize.AutoNumber(ov.discPaths)
+			}
+			return a, a.setEpisodeOffsets(ov.discJobs, offsets)
+		}
+		return a, nil
+
 	case "c":
 		// Mark complete (only if validated)
 		if a.organizeView != nil && a.organizeView.validation != nil && a.organizeView.validation.Valid {
@@ -214,28 +292,44 @@ type organizeLoadedMsg struct {
 	files     []fileInfo
 	discFiles map[string][]fileInfo
 	discPaths []string
+	offsets   map[string]int
+	discJobs  map[string]int64
 	err       error
 }
 
-// loadOrganizeView loads file list for organize view (movies)
+// loadOrganizeView loads file list for organize view (movies). A movie split
+// across several discs is organized in its first disc's directory; the other
+// discs are listed by disc so their files can be moved over.
 func (a *App) loadOrganizeView(item *model.MediaItem) tea.Cmd {
 	return func() tea.Msg {
-		// Find the rip output directory
-		path := a.findItemPath(item, model.StageRip)
-		if path == "" {
+		ctx := context.Background()
+
+		jobs, err := a.repo.ListJobsForMedia(ctx, item.ID)
+		if err != nil {
+			return organizeLoadedMsg{err: fmt.Errorf("failed to list jobs: %w", err)}
+		}
+
+		discPaths := completedRipDirs(jobs, nil)
+		if len(discPaths) == 0 {
 			return organizeLoadedMsg{err: fmt.Errorf("could not find rip output for %s", item.Name)}
 		}
+		path := discPaths[0]
 
 		files, err := listDirectory(path)
 		if err != nil {
 			return organizeLoadedMsg{err: err}
 		}
 
-		return organizeLoadedMsg{
-			item:  item,
-			path:  path,
-			files: files,
+		msg := organizeLoadedMsg{
+			item:      item,
+			path:      path,
+			files:     files,
+			discPaths: discPaths,
 		}
+		if len(discPaths) > 1 {
+			msg.discFiles = listDiscs(discPaths[1:])
+		}
+		return msg
 	}
 }
 
@@ -251,21 +345,8 @@ func (a *App) loadOrganizeViewForSeason(item *model.MediaItem, season *model.Sea
 		}
 
 		// Collect disc paths from completed rip jobs
-		var discPaths []string
-		discFiles := make(map[string][]fileInfo)
-
-		for _, job := range jobs {
-			if job.Stage == model.StageRip && job.Status == model.JobStatusCompleted {
-				if job.SeasonID != nil && *job.SeasonID == season.ID && job.OutputDir != "" {
-					discPaths = append(discPaths, job.OutputDir)
-					files, err := listDirectory(job.OutputDir)
-					if err == nil {
-						discName := filepath.Base(job.OutputDir)
-						discFiles[discName] = files
-					}
-				}
-			}
-		}
+		discPaths := completedRipDirs(jobs, &season.ID)
+		discFiles := listDiscs(discPaths)
 
 		if len(discPaths) == 0 {
 			return organizeLoadedMsg{err: fmt.Errorf("no completed rip jobs found for %s Season %d", item.Name, season.Number)}
@@ -277,6 +358,24 @@ func (a *App) loadOrganizeViewForSeason(item *model.MediaItem, season *model.Sea
 		// Also list the season directory itself (for _episodes, _extras that user creates)
 		seasonFiles, _ := listDirectory(seasonPath)
 
+		// Episode offsets are kept in each disc's rip job options
+		offsets := make(map[string]int)
+		discJobs := make(map[string]int64)
+		for _, job := range jobs {
+			if job.Stage != model.StageRip || job.Status != model.JobStatusCompleted ||
+				job.SeasonID == nil || *job.SeasonID != season.ID || job.OutputDir == "" {
+				continue
+			}
+			opts, err := a.repo.GetJobOptions(ctx, job.ID)
+			if err != nil {
+				return organizeLoadedMsg{err: err}
+			}
+			discJobs[job.OutputDir] = job.ID
+			if offset := organize.EpisodeOffset(opts); offset != 0 {
+				offsets[job.OutputDir] = offset
+			}
+		}
+
 		return organizeLoadedMsg{
 			item:      item,
 			season:    season,
@@ -284,8 +383,49 @@ func (a *App) loadOrganizeViewForSeason(item *model.MediaItem, season *model.Sea
 			files:     seasonFiles,
 			discFiles: discFiles,
 			discPaths: discPaths,
+			offsets:   offsets,
+			discJobs:  discJobs,
+		}
+	}
+}
+
+// completedRipDirs returns the output directories of the completed rip jobs
+// for a season, or for a movie when seasonID is nil, in rip order. A disc
+// ripped again is listed once.
+func completedRipDirs(jobs []model.Job, seasonID *int64) []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, job := range jobs {
+		if job.Stage != model.StageRip || job.Status != model.JobStatusCompleted || job.OutputDir == "" {
+			continue
+		}
+		if seasonID == nil {
+			if job.SeasonID != nil {
+				continue
+			}
+		} else if job.SeasonID == nil || *job.SeasonID != *seasonID {
+			continue
+		}
+		if seen[job.OutputDir] {
+			continue
 		}
+		seen[job.OutputDir] = true
+		dirs = append(dirs, job.OutputDir)
 	}
+	return dirs
+}
+
+// listDiscs lists each disc directory's files keyed by the directory name.
+// Discs that can't be read are left out.
+func listDiscs(discPaths []string) map[string][]fileInfo {
+	discFiles := make(map[string][]fileInfo)
+	for _, dir := range discPaths {
+		files, err := listDirectory(dir)
+		if err == nil {
+			discFiles[filepath.Base(dir)] = files
+		}
+	}
+	return discFiles
 }
 
 type validateMsg struct {
@@ -300,7 +440,7 @@ func (a *App) validateOrganization() tea.Cmd {
 			return validateMsg{err: fmt.Errorf("no item selected")}
 		}
 
-		validator := &organize.Validator{}
+		validator := &organize.Validator{EpisodeOffsets: a.organizeView.discOffsets}
 		var result organize.ValidationResult
 
 		if a.organizeView.item.Type == model.MediaTypeMovie {
@@ -308,7 +448,11 @@ func (a *App) validateOrganization() tea.Cmd {
 		} else {
 			// For TV seasons, use multi-disc validation if we have disc paths
 			if len(a.organizeView.discPaths) > 0 {
-				result = validator.ValidateTVSeason(a.organizeView.discPaths)
+				expected := 0
+				if a.organizeView.season != nil {
+					expected = a.organizeView.season.ExpectedEpisodes
+				}
+				result = validator.ValidateTVSeason(a.organizeView.discPaths, expected)
 			} else {
 				// Single disc or legacy - validate season directory directly
 				result = validator.ValidateTV(a.organizeView.path)
@@ -319,11 +463,59 @@ func (a *App) validateOrganization() tea.Cmd {
 	}
 }
 
+// hasEpisodeOffsets reports whether any disc of the season has an episode offset
+func (ov *OrganizeView) hasEpisodeOffsets() bool {
+	for _, offset := range ov.discOffsets {
+		if offset != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// episodeOffsetsSetMsg is sent when the discs' episode offsets have been saved
+type episodeOffsetsSetMsg struct {
+	offsets map[string]int
+	err     error
+}
+
+// setEpisodeOffsets records each disc's episode offset in its rip job's
+// options, keeping the other options. Discs missing from offsets go back
+// to 0.
+func (a *App) setEpisodeOffsets(discJobs map[string]int64, offsets map[string]int) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		err := a.repo.WithTx(ctx, func(repo db.Repository) error {
+			for discPath, jobID := range discJobs {
+				opts, err := repo.GetJobOptions(ctx, jobID)
+				if err != nil {
+					return err
+				}
+				if opts == nil {
+					opts = map[string]interface{}{}
+				}
+				if offset := offsets[discPath]; offset != 0 {
+					opts[organize.EpisodeOffsetOption] = offset
+				} else {
+					delete(opts, organize.EpisodeOffsetOption)
+				}
+				if err := repo.SetJobOptions(ctx, jobID, opts); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		return episodeOffsetsSetMsg{offsets: offsets, err: err}
+	}
+}
+
 type organizeCompleteMsg struct {
 	err error
 }
 
-// markOrganizeComplete creates an organize job and marks it complete
+// markOrganizeComplete creates an organize job and marks it complete. Disc
+// episode offsets are applied to the file names first, as later stages
+// number episodes by file name, and then cleared from the rip jobs.
 func (a *App) markOrganizeComplete() tea.Cmd {
 	return func() tea.Msg {
 		if a.organizeView == nil || a.organizeView.validation == nil || !a.organizeView.validation.Valid {
@@ -349,21 +541,59 @@ func (a *App) markOrganizeComplete() tea.Cmd {
 			job.SeasonID = &ov.season.ID
 		}
 
-		if err := a.repo.CreateJob(ctx, job); err != nil {
-			return organizeCompleteMsg{err: err}
+		var renumbered []string
+		undoRenumber := func() {
+			for _, discPath := range renumbered {
+				organize.ApplyEpisodeOffset(discPath, -ov.discOffsets[discPath])
+			}
+		}
+		for _, discPath := range ov.discPaths {
+			if offset := ov.discOffsets[discPath]; offset != 0 {
+				if err := organize.ApplyEpisodeOffset(discPath, offset); err != nil {
+					undoRenumber()
+					return organizeCompleteMsg{err: fmt.Errorf("failed to renumber %s: %w", filepath.Base(discPath), err)}
+				}
+				renumbered = append(renumbered, discPath)
+			}
 		}
 
-		// Update stage to organize completed
-		if ov.season != nil {
-			// TV season - update season stage
-			if err := a.repo.UpdateSeasonStage(ctx, ov.season.ID, model.StageOrganize, model.StatusCompleted); err != nil {
-				return organizeCompleteMsg{err: fmt.Errorf("failed to update season stage: %w", err)}
+		// Record the job and advance the stage together, so a failure can't
+		// leave a completed organize job behind a stale stage
+		err := a.repo.WithTx(ctx, func(repo db.Repository) error {
+			if err := repo.CreateJob(ctx, job); err != nil {
+				return err
 			}
-		} else {
-			// Movie - update item stage
-			if err := a.repo.UpdateMediaItemStage(ctx, ov.item.ID, model.StageOrganize, model.StatusCompleted); err != nil {
-				return organizeCompleteMsg{err: fmt.Errorf("failed to update item stage: %w", err)}
+
+			// The file names carry the offsets now
+			for _, discPath := range renumbered {
+				jobID := ov.discJobs[discPath]
+				opts, err := repo.GetJobOptions(ctx, jobID)
+				if err != nil {
+					return err
+				}
+				delete(opts, organize.EpisodeOffsetOption)
+				if err := repo.SetJobOptions(ctx, jobID, opts); err != nil {
+					return err
+				}
+			}
+
+			// Update stage to organize completed
+			if ov.season != nil {
+				// TV season - update season stage
+				if err := repo.UpdateSeasonStage(ctx, ov.season.ID, model.StageOrganize, model.StatusCompleted); err != nil {
+					return fmt.Errorf("failed to update season stage: %w", err)
+				}
+			} else {
+				// Movie - update item stage
+				if err := repo.UpdateMediaItemStage(ctx, ov.item.ID, model.StageOrganize, model.StatusCompleted); err != nil {
+					return fmt.Errorf("failed to update item stage: %w", err)
+				}
 			}
+			return nil
+		})
+		if err != nil {
+			undoRenumber()
+			return organizeCompleteMsg{err: err}
 		}
 
 		return organizeCompleteMsg{}
@@ -411,16 +641,3 @@ func formatSize(bytes int64) string {
 	}
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
-
-// findItemPath finds the output path for an item at a given stage
-func (a *App) findItemPath(item *model.MediaItem, stage model.Stage) string {
-	ctx := context.Background()
-	jobs, _ := a.repo.ListJobsForMedia(ctx, item.ID)
-
-	for _, job := range jobs {
-		if job.Stage == stage && job.Status == model.JobStatusCompleted {
-			return job.OutputDir
-		}
-	}
-	return ""
-}
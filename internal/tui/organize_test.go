@@ -0,0 +1,284 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+	"github.com/cuivienor/media-pipeline/internal/organize"
+)
+
+func TestCompletedRipDirs(t *testing.T) {
+	seasonID := int64(7)
+	otherSeason := int64(8)
+	jobs := []model.Job{
+		{Stage: model.StageRip, Status: model.JobStatusCompleted, OutputDir: "/rip/movie"},
+		{Stage: model.StageRip, Status: model.JobStatusFailed, OutputDir: "/rip/failed"},
+		{Stage: model.StageRip, Status: model.JobStatusCompleted, OutputDir: "/rip/S01/Disc1", SeasonID: &seasonID},
+		{Stage: model.StageRip, Status: model.JobStatusCompleted, OutputDir: "/rip/S02/Disc1", SeasonID: &otherSeason},
+		{Stage: model.StageRemux, Status: model.JobStatusCompleted, OutputDir: "/remuxed/movie"},
+		{Stage: model.StageRip, Status: model.JobStatusCompleted, OutputDir: "/rip/movie-disc2"},
+		{Stage: model.StageRip, Status: model.JobStatusCompleted, OutputDir: "/rip/movie"},
+	}
+
+	movie := completedRipDirs(jobs, nil)
+	if len(movie) != 2 || movie[0] != "/rip/movie" || movie[1] != "/rip/movie-disc2" {
+		t.Errorf("completedRipDirs(movie) = %v", movie)
+	}
+	season := completedRipDirs(jobs, &seasonID)
+	if len(season) != 1 || season[0] != "/rip/S01/Disc1" {
+		t.Errorf("completedRipDirs(season) = %v", season)
+	}
+}
+
+func TestLoadOrganizeView_MultiDiscMovie(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Long Film", SafeName: "Long_Film"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	var discs []string
+	for _, name := range []string{"Long_Film", "Long_Film_Disc2"} {
+		dir := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "title_t00.mkv"), []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		discs = append(discs, dir)
+		job := &model.Job{MediaItemID: item.ID, Stage: model.StageRip, Status: model.JobStatusCompleted, OutputDir: dir}
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+	}
+
+	app := NewApp(&config.Config{}, repo)
+	msg := app.loadOrganizeView(item)().(organizeLoadedMsg)
+	if msg.err != nil {
+		t.Fatalf("loadOrganizeView() error = %v", msg.err)
+	}
+
+	if msg.path != discs[0] {
+		t.Errorf("path = %q, want first disc %q", msg.path, discs[0])
+	}
+	if len(msg.discPaths) != 2 {
+		t.Errorf("discPaths = %v, want both discs", msg.discPaths)
+	}
+	if len(msg.discFiles) != 1 || len(msg.discFiles["Long_Film_Disc2"]) != 1 {
+		t.Errorf("discFiles = %v, want only the second disc", msg.discFiles)
+	}
+}
+
+func TestRenderOrganizeView_ShowsWarnings(t *testing.T) {
+	app := NewApp(&config.Config{}, nil)
+	app.state = &AppState{}
+	app.currentView = ViewOrganize
+	app.organizeView = &OrganizeView{
+		item:   &model.MediaItem{Type: model.MediaTypeTV, Name: "Show"},
+		season: &model.Season{Number: 1},
+		validation: &organize.ValidationResult{
+			Valid:    true,
+			Warnings: []string{"episode 1 appears on more than one disc: Disc1, Disc2"},
+		},
+	}
+
+	view := app.View()
+	if !strings.Contains(view, "Organization valid") || !strings.Contains(view, "episode 1 appears on more than one disc") {
+		t.Errorf("organize view missing validation warnings:\n%s", view)
+	}
+}
+
+func TestOrganizeView_NumberAcrossDiscs(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Show", SafeName: "Show"}
+	if err := repo.CreateMediaItem(ctx, show); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	season := &model.Season{ItemID: show.ID, Number: 1, StageStatus: model.StatusCompleted, ExpectedEpisodes: 4}
+	if err := repo.CreateSeason(ctx, season); err != nil {
+		t.Fatalf("CreateSeason() error = %v", err)
+	}
+
+	// Both discs numbered from 01
+	seasonDir := t.TempDir()
+	var ripJobs []*model.Job
+	for _, name := range []string{"Disc1", "Disc2"} {
+		dir := filepath.Join(seasonDir, name)
+		if err := os.MkdirAll(filepath.Join(dir, "_episodes"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		for _, ep := range []string{"01.mkv", "02.mkv"} {
+			if err := os.WriteFile(filepath.Join(dir, "_episodes", ep), []byte("x"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		job := &model.Job{MediaItemID: show.ID, SeasonID: &season.ID, Stage: model.StageRip, Status: model.JobStatusCompleted, OutputDir: dir}
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+		ripJobs = append(ripJobs, job)
+	}
+	if err := repo.SetJobOptions(ctx, ripJobs[1].ID, map[string]interface{}{"drive": "disc:0"}); err != nil {
+		t.Fatalf("SetJobOptions() error = %v", err)
+	}
+
+	app := NewApp(&config.Config{}, repo)
+	app.state = &AppState{}
+	run := func(cmd tea.Cmd) {
+		for cmd != nil {
+			_, cmd = app.Update(cmd())
+		}
+	}
+	press := func(key string) {
+		_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		run(cmd)
+	}
+	run(app.loadOrganizeViewForSeason(show, season))
+
+	press("v")
+	if app.organizeView.validation.Valid {
+		t.Fatal("per-disc numbering should not reach the 4 expected episodes")
+	}
+
+	press("a")
+	if view := app.View(); !strings.Contains(view, "episodes +2") {
+		t.Errorf("organize view missing disc offset:\n%s", view)
+	}
+	opts, err := repo.GetJobOptions(ctx, ripJobs[1].ID)
+	if err != nil {
+		t.Fatalf("GetJobOptions() error = %v", err)
+	}
+	if organize.EpisodeOffset(opts) != 2 || opts["drive"] != "disc:0" {
+		t.Errorf("disc 2 options = %v, want episode offset 2 alongside the existing options", opts)
+	}
+
+	press("v")
+	if !app.organizeView.validation.Valid {
+		t.Errorf("validation errors with offsets: %v", app.organizeView.validation.Errors)
+	}
+
+	// Offsets are loaded again with the view, and [a] clears them
+	run(app.loadOrganizeViewForSeason(show, season))
+	if !app.organizeView.hasEpisodeOffsets() {
+		t.Fatal("offsets not loaded from the rip jobs")
+	}
+	press("a")
+	opts, _ = repo.GetJobOptions(ctx, ripJobs[1].ID)
+	if _, ok := opts[organize.EpisodeOffsetOption]; ok {
+		t.Errorf("offset should be cleared, options = %v", opts)
+	}
+
+	// Completing renames disc 2's episodes, which is what remux and
+	// publish number them by, and drops the offset that is now applied
+	press("a")
+	press("v")
+	discPaths := app.organizeView.discPaths
+	press("c")
+	disc2 := filepath.Join(seasonDir, "Disc2", "_episodes")
+	for _, ep := range []string{"03.mkv", "04.mkv"} {
+		if _, err := os.Stat(filepath.Join(disc2, ep)); err != nil {
+			t.Errorf("disc 2 episode not renumbered: %v", err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(disc2, "01.mkv")); err == nil {
+		t.Error("disc 2 still has 01.mkv after completing")
+	}
+	opts, _ = repo.GetJobOptions(ctx, ripJobs[1].ID)
+	if _, ok := opts[organize.EpisodeOffsetOption]; ok || opts["drive"] != "disc:0" {
+		t.Errorf("disc 2 options = %v, want the applied offset dropped and the rest kept", opts)
+	}
+	loaded, err := repo.GetSeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetSeason() error = %v", err)
+	}
+	if loaded.CurrentStage != model.StageOrganize || loaded.StageStatus != model.StatusCompleted {
+		t.Errorf("season = %s/%s, want organize/completed", loaded.CurrentStage, loaded.StageStatus)
+	}
+
+	// The renamed files validate on their own, without any offsets
+	result := (&organize.Validator{}).ValidateTVSeason(discPaths, 4)
+	if !result.Valid || len(result.Warnings) != 0 {
+		t.Errorf("renumbered season validation = %+v", result)
+	}
+}
+
+func TestOrganizeView_SuggestMoves(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "The Matrix", SafeName: "The_Matrix"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	dir := t.TempDir()
+	for name, size := range map[string]int{"title_t00.mkv": 100, "title_t01.mkv": 5000} {
+		if err := os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	job := &model.Job{MediaItemID: item.ID, Stage: model.StageRip, Status: model.JobStatusCompleted, OutputDir: dir}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	// Without ffprobe the titles are classified by size
+	cfg := &config.Config{Transcode: config.TranscodeConfig{FFprobePath: filepath.Join(dir, "missing-ffprobe")}}
+	app := NewApp(cfg, repo)
+	app.Update(app.loadState())
+	app.Update(app.loadOrganizeView(item)())
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	app.Update(cmd())
+	if app.organizeView.plan == nil {
+		t.Fatalf("[s] should show a move plan, status %q", app.statusMsg)
+	}
+	if view := app.View(); !strings.Contains(view, "title_t01.mkv") || !strings.Contains(view, "→ _main/") {
+		t.Errorf("plan missing main feature:\n%s", view)
+	}
+
+	// Leave the short title in place instead of moving it to extras
+	app.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if got := app.organizeView.plan.suggestion.Moves[0].Dest; got != organize.DestKeep {
+		t.Fatalf("title_t00.mkv destination = %q, want it left in place", got)
+	}
+
+	_, cmd = app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	_, cmd = app.Update(cmd())
+	if !strings.HasPrefix(app.statusMsg, "Titles moved") {
+		t.Errorf("statusMsg = %q", app.statusMsg)
+	}
+	app.Update(cmd())
+	for _, path := range []string{"_main/title_t01.mkv", "title_t00.mkv"} {
+		if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+			t.Errorf("expected %s: %v", path, err)
+		}
+	}
+}
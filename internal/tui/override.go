@@ -0,0 +1,274 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// overrideStatuses are the statuses the stage override menu offers.
+// In progress is left to running jobs.
+var overrideStatuses = []model.Status{model.StatusPending, model.StatusCompleted, model.StatusFailed}
+
+// stageOverride is the stage and status being chosen after pressing [m]
+type stageOverride struct {
+	itemID     int64
+	seasonID   *int64 // Set when overriding a season
+	name       string
+	stage      model.Stage
+	status     model.Status
+	skipInput  string // Set when the stage is skipped: the input it passes on
+	confirming bool   // Enter was pressed and [y] will apply it
+}
+
+// stageOverriddenMsg is sent when a stage override has been written
+type stageOverriddenMsg struct {
+	override stageOverride
+	err      error
+}
+
+// openStageOverride opens the override menu for the movie or season being
+// viewed, starting from its current stage and status. It returns a status
+// line instead when the stage can't be overridden right now.
+func (a *App) openStageOverride() string {
+	if a.state == nil {
+		return ""
+	}
+
+	var ov stageOverride
+	switch {
+	case a.currentView == ViewItemDetail && a.selectedItem != nil && a.selectedItem.Type == model.MediaTypeMovie:
+		ov = stageOverride{
+			itemID: a.selectedItem.ID,
+			name:   a.selectedItem.Name,
+			stage:  a.selectedItem.CurrentStage,
+			status: a.selectedItem.StageStatus,
+		}
+	case a.currentView == ViewSeasonDetail && a.selectedItem != nil && a.selectedSeason != nil:
+		seasonID := a.selectedSeason.ID
+		ov = stageOverride{
+			itemID:   a.selectedItem.ID,
+			seasonID: &seasonID,
+			name:     fmt.Sprintf("%s Season %d", a.selectedItem.Name, a.selectedSeason.Number),
+			stage:    a.selectedSeason.CurrentStage,
+			status:   a.selectedSeason.StageStatus,
+		}
+	default:
+		return ""
+	}
+
+	if job := a.stuckJob(); job != nil {
+		return fmt.Sprintf("Can't override while %s job %d is %s", job.Stage, job.ID, job.Status)
+	}
+	if ov.status == model.StatusInProgress {
+		ov.status = model.StatusPending
+	}
+	a.stageOverride = &ov
+	return ""
+}
+
+// overrideJobs returns the job history of the movie or season being overridden
+func (a *App) overrideJobs(ov *stageOverride) []model.Job {
+	if ov.seasonID != nil {
+		return a.state.SeasonJobs[*ov.seasonID]
+	}
+	return a.state.MovieJobs[ov.itemID]
+}
+
+// overrideProblem returns why stage and status make no sense given the job
+// history, or "". A stage can only be reached once the one before it has a
+// completed job. It can be marked completed without a job of its own only
+// when it is skipped (see overrideSkipInput), so the next stage can still
+// find its input.
+func overrideProblem(stage model.Stage, status model.Status, jobs []model.Job) string {
+	if status == model.StatusInProgress {
+		return "In progress is only set by a running job"
+	}
+
+	prev, hasPrev := stage.PreviousStage()
+	if hasPrev && completedJob(jobs, prev) == nil {
+		return fmt.Sprintf("No completed %s job, so %s can't have started", prev, stage)
+	}
+	if status != model.StatusCompleted || completedJob(jobs, stage) != nil {
+		return ""
+	}
+	if !hasPrev || stage == model.StagePublish {
+		return fmt.Sprintf("No completed %s job to mark %s completed", stage, stage)
+	}
+	if overrideSkipInput(stage, status, jobs) == "" {
+		return fmt.Sprintf("Completed %s job has no output for %s to pass on", prev, stage)
+	}
+	return ""
+}
+
+// overrideSkipInput returns the input a stage marked completed without a job
+// of its own passes on to the next stage: the previous stage's output. It
+// returns "" when the override doesn't skip the stage. Rip has no input and
+// publish has nowhere to pass it, so neither can be skipped.
+func overrideSkipInput(stage model.Stage, status model.Status, jobs []model.Job) string {
+	if status != model.StatusCompleted || completedJob(jobs, stage) != nil || stage == model.StagePublish {
+		return ""
+	}
+	prev, ok := stage.PreviousStage()
+	if !ok {
+		return ""
+	}
+	if job := completedJob(jobs, prev); job != nil {
+		return job.OutputDir
+	}
+	return ""
+}
+
+// completedJob returns the most recent completed job for stage, or nil
+func completedJob(jobs []model.Job, stage model.Stage) *model.Job {
+	for i := len(jobs) - 1; i >= 0; i-- {
+		if jobs[i].Stage == stage && jobs[i].Status == model.JobStatusCompleted {
+			return &jobs[i]
+		}
+	}
+	return nil
+}
+
+// handleStageOverrideKey handles input while the override menu is open.
+// Arrows choose the stage and status, Enter asks for confirmation and [y]
+// applies it; anything else at the confirmation goes back to the menu.
+func (a *App) handleStageOverrideKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	ov := a.stageOverride
+	key := msg.String()
+	if key == "ctrl+c" {
+		return a, tea.Quit
+	}
+
+	if ov.confirming {
+		if key == "y" {
+			a.stageOverride = nil
+			return a, a.applyStageOverride(*ov)
+		}
+		ov.confirming = false
+		return a, nil
+	}
+
+	switch key {
+	case "left", "h":
+		if ov.stage > model.StageRip {
+			ov.stage--
+		} else {
+			ov.stage = model.StagePublish
+		}
+	case "right", "l":
+		if ov.stage < model.StagePublish {
+			ov.stage++
+		} else {
+			ov.stage = model.StageRip
+		}
+	case "up", "k":
+		ov.status = cycleOverrideStatus(ov.status, -1)
+	case "down", "j":
+		ov.status = cycleOverrideStatus(ov.status, 1)
+	case "enter":
+		jobs := a.overrideJobs(ov)
+		if overrideProblem(ov.stage, ov.status, jobs) == "" {
+			ov.skipInput = overrideSkipInput(ov.stage, ov.status, jobs)
+			ov.confirming = true
+		}
+	case "esc":
+		a.stageOverride = nil
+	}
+	return a, nil
+}
+
+// cycleOverrideStatus returns the status step places after status in
+// overrideStatuses, wrapping around
+func cycleOverrideStatus(status model.Status, step int) model.Status {
+	i := 0
+	for j, s := range overrideStatuses {
+		if s == status {
+			i = j
+			break
+		}
+	}
+	n := len(overrideStatuses)
+	return overrideStatuses[((i+step)%n+n)%n]
+}
+
+// applyStageOverride writes the chosen stage and status. Existing jobs are
+// left as they are; a skipped stage gets a completed job recording its input
+// as its output, where the next stage looks for it.
+func (a *App) applyStageOverride(ov stageOverride) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		err := a.repo.WithTx(ctx, func(tx db.Repository) error {
+			if ov.skipInput != "" {
+				if err := createSkippedJob(ctx, tx, ov); err != nil {
+					return err
+				}
+			}
+			if ov.seasonID != nil {
+				return tx.UpdateSeasonStage(ctx, *ov.seasonID, ov.stage, ov.status)
+			}
+			return tx.UpdateMediaItemStage(ctx, ov.itemID, ov.stage, ov.status)
+		})
+		return stageOverriddenMsg{override: ov, err: err}
+	}
+}
+
+// createSkippedJob records ov's stage as completed without running, passing
+// its input through as its output. The job's options mark it skipped.
+func createSkippedJob(ctx context.Context, repo db.Repository, ov stageOverride) error {
+	now := time.Now()
+	job := &model.Job{
+		MediaItemID: ov.itemID,
+		SeasonID:    ov.seasonID,
+		Stage:       ov.stage,
+		Status:      model.JobStatusCompleted,
+		InputDir:    ov.skipInput,
+		OutputDir:   ov.skipInput,
+		StartedAt:   &now,
+		CompletedAt: &now,
+	}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		return fmt.Errorf("failed to record skipped %s job: %w", ov.stage, err)
+	}
+	if err := repo.SetJobOptions(ctx, job.ID, map[string]interface{}{"skipped": true}); err != nil {
+		return fmt.Errorf("failed to mark %s job %d skipped: %w", ov.stage, job.ID, err)
+	}
+	return nil
+}
+
+// renderStageOverride renders the override menu or its confirmation
+func (a *App) renderStageOverride() string {
+	ov := a.stageOverride
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Override Stage"))
+	b.WriteString("\n\n")
+
+	if ov.confirming {
+		b.WriteString(fmt.Sprintf("  Set %s to %s %s?\n", ov.name, ov.stage, ov.status))
+		if ov.skipInput != "" {
+			b.WriteString(fmt.Sprintf("  %s is skipped: a completed job passes %s on to %s.\n", ov.stage, ov.skipInput, ov.stage.NextStage()))
+		} else {
+			b.WriteString("  Jobs are not changed; this only moves where the pipeline thinks it is.\n")
+		}
+		b.WriteString("\n")
+		b.WriteString(helpStyle.Render("[y] Confirm  [Esc] Back"))
+		return b.String()
+	}
+
+	b.WriteString(fmt.Sprintf("  %s\n\n", ov.name))
+	b.WriteString(fmt.Sprintf("  Stage:  < %s >\n", ov.stage))
+	b.WriteString(fmt.Sprintf("  Status: < %s >\n", ov.status))
+	b.WriteString("\n")
+	if problem := overrideProblem(ov.stage, ov.status, a.overrideJobs(ov)); problem != "" {
+		b.WriteString(errorStyle.Render("  " + problem))
+		b.WriteString("\n\n")
+	}
+	b.WriteString(helpStyle.Render("[←/→] Stage  [↑/↓] Status  [Enter] Apply  [Esc] Cancel"))
+
+	return b.String()
+}
@@ -0,0 +1,236 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestOverrideProblem(t *testing.T) {
+	jobs := []model.Job{
+		{Stage: model.StageRip, Status: model.JobStatusCompleted},
+		{Stage: model.StageOrganize, Status: model.JobStatusCompleted},
+		{Stage: model.StageRemux, Status: model.JobStatusFailed},
+	}
+
+	tests := []struct {
+		stage   model.Stage
+		status  model.Status
+		wantErr bool
+	}{
+		{model.StageRip, model.StatusPending, false},
+		{model.StageOrganize, model.StatusCompleted, false},
+		{model.StageRemux, model.StatusPending, false},
+		{model.StageRemux, model.StatusFailed, false},
+		{model.StageRemux, model.StatusCompleted, true},   // remux never completed
+		{model.StageTranscode, model.StatusPending, true}, // nothing to transcode from
+		{model.StageRip, model.StatusInProgress, true},
+	}
+	for _, tt := range tests {
+		got := overrideProblem(tt.stage, tt.status, jobs)
+		if (got != "") != tt.wantErr {
+			t.Errorf("overrideProblem(%s, %s) = %q, want problem: %v", tt.stage, tt.status, got, tt.wantErr)
+		}
+	}
+
+	// With the organize output recorded, remux can be skipped; rip and
+	// publish never can
+	jobs[1].OutputDir = "/staging/1-ripped/movies/Inception"
+	if got := overrideProblem(model.StageRemux, model.StatusCompleted, jobs); got != "" {
+		t.Errorf("overrideProblem(remux, completed) = %q, want remux skippable", got)
+	}
+	if got := overrideSkipInput(model.StageRemux, model.StatusCompleted, jobs); got != jobs[1].OutputDir {
+		t.Errorf("overrideSkipInput(remux) = %q, want the organize output", got)
+	}
+	if got := overrideSkipInput(model.StageOrganize, model.StatusCompleted, jobs); got != "" {
+		t.Errorf("overrideSkipInput(organize) = %q, want none: organize has its own job", got)
+	}
+	if got := overrideProblem(model.StageRip, model.StatusCompleted, nil); got == "" {
+		t.Error("overrideProblem(rip, completed) without a rip job should be a problem")
+	}
+}
+
+func TestStageOverride_SkipRemux(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception"}
+	if err := repo.CreateMediaItem(ctx, movie); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	organized := "/staging/1-ripped/movies/Inception"
+	for _, stage := range []model.Stage{model.StageRip, model.StageOrganize} {
+		job := &model.Job{MediaItemID: movie.ID, Stage: stage, Status: model.JobStatusCompleted, OutputDir: organized}
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+	}
+	if err := repo.UpdateMediaItemStage(ctx, movie.ID, model.StageOrganize, model.StatusCompleted); err != nil {
+		t.Fatalf("UpdateMediaItemStage() error = %v", err)
+	}
+
+	app := NewApp(&config.Config{}, repo)
+	app.Update(app.loadState())
+	app.currentView = ViewItemDetail
+	app.selectedItem = &app.state.Items[0]
+
+	press := func(key tea.KeyMsg) {
+		_, cmd := app.Update(key)
+		for cmd != nil {
+			_, cmd = app.Update(cmd())
+		}
+	}
+
+	// Organize completed -> remux completed, without a remux job
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	press(tea.KeyMsg{Type: tea.KeyRight})
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !app.stageOverride.confirming {
+		t.Fatalf("Enter should ask for confirmation:\n%s", app.View())
+	}
+	if view := app.View(); !strings.Contains(view, "remux is skipped") {
+		t.Errorf("confirmation should say remux is skipped:\n%s", view)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if app.err != nil {
+		t.Fatalf("override error = %v", app.err)
+	}
+
+	jobs, err := repo.ListJobsForMedia(ctx, movie.ID)
+	if err != nil {
+		t.Fatalf("ListJobsForMedia() error = %v", err)
+	}
+	skipped := completedJob(jobs, model.StageRemux)
+	if skipped == nil || skipped.OutputDir != organized {
+		t.Fatalf("remux jobs = %+v, want a completed job passing on %s", jobs, organized)
+	}
+	opts, err := repo.GetJobOptions(ctx, skipped.ID)
+	if err != nil || opts["skipped"] != true {
+		t.Errorf("skipped job options = %v, %v", opts, err)
+	}
+	if ok, reason := repo.CanStartStage(ctx, movie.ID, model.StageTranscode, nil); !ok {
+		t.Errorf("transcode should be able to start after the skip: %s", reason)
+	}
+}
+
+func TestStageOverride_Movie(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception"}
+	if err := repo.CreateMediaItem(ctx, movie); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	for _, stage := range []model.Stage{model.StageRip, model.StageOrganize, model.StageRemux, model.StageTranscode} {
+		job := &model.Job{MediaItemID: movie.ID, Stage: stage, Status: model.JobStatusCompleted}
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+	}
+	if err := repo.UpdateMediaItemStage(ctx, movie.ID, model.StageTranscode, model.StatusCompleted); err != nil {
+		t.Fatalf("UpdateMediaItemStage() error = %v", err)
+	}
+
+	app := NewApp(&config.Config{}, repo)
+	app.Update(app.loadState())
+	app.currentView = ViewItemDetail
+	app.selectedItem = &app.state.Items[0]
+
+	if view := app.View(); !strings.Contains(view, "[m] Override Stage") {
+		t.Errorf("item detail missing override hint:\n%s", view)
+	}
+
+	press := func(key tea.KeyMsg) {
+		_, cmd := app.Update(key)
+		for cmd != nil {
+			_, cmd = app.Update(cmd())
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	press(runes("m"))
+	if app.stageOverride == nil {
+		t.Fatal("[m] should open the override menu")
+	}
+
+	// Back to remux, then pending: re-run transcode from the remux output
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	press(tea.KeyMsg{Type: tea.KeyUp})
+	if app.stageOverride.stage != model.StageRemux || app.stageOverride.status != model.StatusPending {
+		t.Fatalf("menu = %s %s, want remux pending", app.stageOverride.stage, app.stageOverride.status)
+	}
+
+	// There is no publish job to mark completed, so Enter does nothing
+	press(tea.KeyMsg{Type: tea.KeyRight})
+	press(tea.KeyMsg{Type: tea.KeyRight})
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	if app.stageOverride.status != model.StatusCompleted {
+		t.Fatalf("status = %s, want completed", app.stageOverride.status)
+	}
+	if view := app.View(); !strings.Contains(view, "No completed publish job") {
+		t.Errorf("menu should explain the problem:\n%s", view)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.stageOverride.confirming {
+		t.Fatal("Enter should not confirm a nonsensical combination")
+	}
+
+	// Back to remux completed and confirm
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if !app.stageOverride.confirming {
+		t.Fatal("Enter should ask for confirmation")
+	}
+	press(runes("y"))
+	if app.stageOverride != nil {
+		t.Error("override menu should close after applying")
+	}
+
+	items, err := repo.ListActiveItems(ctx)
+	if err != nil {
+		t.Fatalf("ListActiveItems() error = %v", err)
+	}
+	if got := items[0]; got.CurrentStage != model.StageRemux || got.StageStatus != model.StatusCompleted {
+		t.Errorf("item at %s %s, want remux completed", got.CurrentStage, got.StageStatus)
+	}
+	if !strings.Contains(app.statusMsg, "Set Inception to remux completed") {
+		t.Errorf("statusMsg = %q", app.statusMsg)
+	}
+}
+
+func TestStageOverride_RefusedWithActiveJob(t *testing.T) {
+	app := NewApp(&config.Config{}, nil)
+	movie := model.MediaItem{ID: 1, Type: model.MediaTypeMovie, Name: "Inception", CurrentStage: model.StageRemux, StageStatus: model.StatusInProgress}
+	app.state = &AppState{
+		Items:     []model.MediaItem{movie},
+		MovieJobs: map[int64][]model.Job{1: {{ID: 7, MediaItemID: 1, Stage: model.StageRemux, Status: model.JobStatusInProgress}}},
+	}
+	app.currentView = ViewItemDetail
+	app.selectedItem = &app.state.Items[0]
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("m")})
+	if app.stageOverride != nil {
+		t.Fatal("override menu should not open while a job is active")
+	}
+	if !strings.Contains(app.statusMsg, "remux job 7 is in_progress") {
+		t.Errorf("statusMsg = %q", app.statusMsg)
+	}
+}
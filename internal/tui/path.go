@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// PathFile is where [p] writes the selected path, so a shell can pick it up
+// with e.g. cd "$(cat /tmp/media-pipeline-path)" without a clipboard
+func PathFile() string {
+	return filepath.Join(os.TempDir(), "media-pipeline-path")
+}
+
+// SelectedPath returns the last path chosen with [p], or "" if none
+func (a *App) SelectedPath() string {
+	return a.selectedPath
+}
+
+// currentPath returns the filesystem path for what the current view shows:
+// the directory being organized, or the newest stage output for the selected
+// movie or season
+func (a *App) currentPath() string {
+	switch a.currentView {
+	case ViewOrganize:
+		if a.organizeView != nil {
+			return a.organizeView.path
+		}
+	case ViewItemDetail:
+		if a.selectedItem != nil && a.selectedItem.Type == model.MediaTypeMovie && a.state != nil {
+			return latestOutputDir(a.state.MovieJobs[a.selectedItem.ID])
+		}
+	case ViewSeasonDetail:
+		if a.selectedSeason != nil && a.state != nil {
+			return latestOutputDir(a.state.SeasonJobs[a.selectedSeason.ID])
+		}
+	}
+	return ""
+}
+
+// latestOutputDir returns the output directory of the most recent job that has one
+func latestOutputDir(jobs []model.Job) string {
+	for i := len(jobs) - 1; i >= 0; i-- {
+		if jobs[i].OutputDir != "" {
+			return jobs[i].OutputDir
+		}
+	}
+	return ""
+}
+
+// showPath remembers the current view's path, writes it to PathFile and
+// shows it on the status line
+func (a *App) showPath() {
+	path := a.currentPath()
+	if path == "" {
+		a.statusMsg = "No output path yet"
+		return
+	}
+	a.selectedPath = path
+	if err := os.WriteFile(PathFile(), []byte(path+"\n"), 0644); err != nil {
+		a.statusMsg = fmt.Sprintf("Path: %s (failed to write %s: %v)", path, PathFile(), err)
+		return
+	}
+	a.statusMsg = fmt.Sprintf("Path: %s (saved to %s)", path, PathFile())
+}
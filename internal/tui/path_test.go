@@ -0,0 +1,62 @@
+package tui
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestShowPath(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	movie := model.MediaItem{ID: 1, Type: model.MediaTypeMovie, Name: "Alien", StageStatus: model.StatusCompleted}
+	app := NewApp(&config.Config{}, nil)
+	app.state = &AppState{
+		Items: []model.MediaItem{movie},
+		MovieJobs: map[int64][]model.Job{1: {
+			{Stage: model.StageRip, OutputDir: "/staging/1-ripped/movies/Alien"},
+			{Stage: model.StageOrganize},
+			{Stage: model.StageRemux, OutputDir: "/staging/3-remuxed/movies/Alien"},
+		}},
+	}
+	app.currentView = ViewItemDetail
+	app.selectedItem = &app.state.Items[0]
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+
+	want := "/staging/3-remuxed/movies/Alien"
+	if got := app.SelectedPath(); got != want {
+		t.Errorf("SelectedPath() = %q, want %q", got, want)
+	}
+	if !strings.Contains(app.renderItemDetail(), want) {
+		t.Error("item detail should show the path on the status line")
+	}
+	data, err := os.ReadFile(PathFile())
+	if err != nil {
+		t.Fatalf("reading path file: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != want {
+		t.Errorf("path file = %q, want %q", data, want)
+	}
+}
+
+func TestShowPath_NoOutput(t *testing.T) {
+	app := NewApp(&config.Config{}, nil)
+	app.state = &AppState{}
+	app.currentView = ViewSeasonDetail
+	app.selectedItem = &model.MediaItem{ID: 1, Type: model.MediaTypeTV, Name: "Show"}
+	app.selectedSeason = &model.Season{ID: 7, Number: 1}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+
+	if app.SelectedPath() != "" {
+		t.Errorf("SelectedPath() = %q, want empty", app.SelectedPath())
+	}
+	if app.statusMsg != "No output path yet" {
+		t.Errorf("statusMsg = %q", app.statusMsg)
+	}
+}
@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// Limits on what a failure report includes
+const (
+	reportLogEvents = 50      // Most recent log events
+	reportLogBytes  = 1 << 20 // Tail of job.log
+)
+
+// reportWrittenMsg is sent once a failure report has been written
+type reportWrittenMsg struct {
+	path string
+	err  error
+}
+
+// reportSubject names the movie or season being viewed, for a report header
+func (a *App) reportSubject() string {
+	if a.selectedItem == nil {
+		return ""
+	}
+	if a.currentView == ViewSeasonDetail && a.selectedSeason != nil {
+		return fmt.Sprintf("%s S%02d", a.selectedItem.Name, a.selectedSeason.Number)
+	}
+	return a.selectedItem.Name
+}
+
+// writeFailureReport bundles a failed job's error, its recent log events and
+// the end of its log into one file for a bug report
+func (a *App) writeFailureReport(job model.Job, subject string) tea.Cmd {
+	return func() tea.Msg {
+		events, err := a.repo.ListLogEvents(context.Background(), job.ID, reportLogEvents)
+		if err != nil {
+			return reportWrittenMsg{err: err}
+		}
+
+		logPath := job.LogPath
+		if logPath == "" {
+			logPath = a.config.JobLogPath(job.ID)
+		}
+
+		var b bytes.Buffer
+		buildFailureReport(&b, job, subject, events, logPath)
+
+		path := a.config.FailureReportPath(job.ID)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return reportWrittenMsg{err: err}
+		}
+		if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
+			return reportWrittenMsg{err: err}
+		}
+		return reportWrittenMsg{path: path}
+	}
+}
+
+// buildFailureReport writes the report for job to w. events are newest
+// first, as ListLogEvents returns them.
+func buildFailureReport(w io.Writer, job model.Job, subject string, events []model.LogEvent, logPath string) {
+	fmt.Fprintf(w, "Failure report for %s job %d\n", job.Stage, job.ID)
+	fmt.Fprintf(w, "Generated: %s\n\n", time.Now().Format(time.RFC3339))
+	if subject != "" {
+		fmt.Fprintf(w, "Item:      %s\n", subject)
+	}
+	fmt.Fprintf(w, "Stage:     %s\n", job.Stage)
+	fmt.Fprintf(w, "Status:    %s\n", job.Status)
+	if job.WorkerID != "" {
+		fmt.Fprintf(w, "Worker:    %s\n", job.WorkerID)
+	}
+	if job.StartedAt != nil {
+		fmt.Fprintf(w, "Started:   %s\n", job.StartedAt.Format(time.RFC3339))
+	}
+	if job.CompletedAt != nil {
+		fmt.Fprintf(w, "Completed: %s\n", job.CompletedAt.Format(time.RFC3339))
+	}
+	if job.InputDir != "" {
+		fmt.Fprintf(w, "Input:     %s\n", job.InputDir)
+	}
+	if job.OutputDir != "" {
+		fmt.Fprintf(w, "Output:    %s\n", job.OutputDir)
+	}
+	fmt.Fprintf(w, "Error:     %s\n", job.ErrorMessage)
+
+	fmt.Fprintf(w, "\n== Recent events ==\n")
+	if len(events) == 0 {
+		fmt.Fprintln(w, "(none)")
+	}
+	for _, e := range slices.Backward(events) {
+		fmt.Fprintf(w, "%s [%s] %s\n", e.Timestamp.Format(time.RFC3339), e.Level, e.Message)
+	}
+
+	fmt.Fprintf(w, "\n== %s ==\n", logPath)
+	if err := tailFile(w, logPath, reportLogBytes); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// Jobs dispatched over SSH log on the host that ran them
+			fmt.Fprintln(w, "(not found on this host)")
+		} else {
+			fmt.Fprintf(w, "(unreadable: %v)\n", err)
+		}
+	}
+}
+
+// tailFile copies the last max bytes of path to w, noting any cut
+func tailFile(w io.Writer, path string, max int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if skipped := info.Size() - max; skipped > 0 {
+		fmt.Fprintf(w, "(first %d bytes omitted)\n", skipped)
+		if _, err := f.Seek(skipped, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestWriteFailureReport(t *testing.T) {
+	mediaBase := t.TempDir()
+	t.Setenv("MEDIA_BASE", mediaBase)
+
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception"}
+	if err := repo.CreateMediaItem(ctx, movie); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	failed := &model.Job{MediaItemID: movie.ID, Stage: model.StageTranscode, Status: model.JobStatusFailed, ErrorMessage: "ffmpeg exited with status 1"}
+	if err := repo.CreateJob(ctx, failed); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	event := &model.LogEvent{JobID: failed.ID, Level: "error", Message: "Invalid data found when processing input"}
+	if err := repo.CreateLogEvent(ctx, event); err != nil {
+		t.Fatalf("CreateLogEvent() error = %v", err)
+	}
+
+	cfg := &config.Config{}
+	if err := cfg.EnsureJobLogDir(failed.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfg.JobLogPath(failed.ID), []byte("frame=  100 fps=24\nConversion failed!\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	app := NewApp(cfg, repo)
+	app.Update(app.loadState())
+	app.currentView = ViewItemDetail
+	app.selectedItem = &app.state.Items[0]
+
+	if view := app.View(); !strings.Contains(view, "[B] Report") {
+		t.Errorf("item detail missing report hint:\n%s", view)
+	}
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("B")})
+	if cmd == nil {
+		t.Fatal("[B] should write a report for the failed job")
+	}
+	app.Update(cmd())
+
+	path := filepath.Join(mediaBase, "pipeline", "reports", "failure-report-1.txt")
+	if !strings.Contains(app.statusMsg, path) {
+		t.Errorf("statusMsg = %q, want the report path", app.statusMsg)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("report not written: %v", err)
+	}
+	report := string(data)
+	for _, want := range []string{"Item:      Inception", "Error:     ffmpeg exited with status 1", "[error] Invalid data found", "Conversion failed!"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestBuildFailureReport_EventsOldestFirst(t *testing.T) {
+	base := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	events := []model.LogEvent{
+		{Level: "error", Message: "Conversion failed", Timestamp: base.Add(time.Minute)},
+		{Level: "info", Message: "Starting transcode", Timestamp: base},
+	}
+
+	var b bytes.Buffer
+	buildFailureReport(&b, model.Job{ID: 3, Stage: model.StageTranscode, Status: model.JobStatusFailed}, "", events, filepath.Join(t.TempDir(), "job.log"))
+	report := b.String()
+	if !strings.Contains(report, "2026-01-02T03:04:00Z [info] Starting transcode\n2026-01-02T03:05:00Z [error] Conversion failed\n") {
+		t.Errorf("events should read oldest first:\n%s", report)
+	}
+}
+
+func TestBuildFailureReport_LogTail(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "job.log")
+	if err := os.WriteFile(logPath, []byte(strings.Repeat("x", reportLogBytes)+"last line\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	buildFailureReport(&b, model.Job{ID: 3, Stage: model.StageRip, Status: model.JobStatusFailed}, "", nil, logPath)
+	if report := b.String(); !strings.Contains(report, "(first 10 bytes omitted)") || !strings.HasSuffix(report, "last line\n") {
+		t.Errorf("report should end with the log's last %d bytes, got %d bytes", reportLogBytes, len(report))
+	}
+
+	b.Reset()
+	buildFailureReport(&b, model.Job{ID: 3, Stage: model.StageRip, Status: model.JobStatusFailed}, "", nil, logPath+".missing")
+	if !strings.Contains(b.String(), "(not found on this host)") {
+		t.Errorf("missing log not reported:\n%s", b.String())
+	}
+}
@@ -0,0 +1,159 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/dispatch"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// jobRetriedMsg is sent when a failed job has been replaced by a new one
+type jobRetriedMsg struct {
+	job model.Job // the failed job
+	err error
+}
+
+// failedJob returns the latest job of the movie or season being viewed if it
+// failed and hasn't been retried yet, or nil
+func (a *App) failedJob() *model.Job {
+	if a.state == nil {
+		return nil
+	}
+
+	var jobs []model.Job
+	switch {
+	case a.currentView == ViewItemDetail && a.selectedItem != nil && a.selectedItem.Type == model.MediaTypeMovie:
+		jobs = a.state.MovieJobs[a.selectedItem.ID]
+	case a.currentView == ViewSeasonDetail && a.selectedSeason != nil:
+		jobs = a.state.SeasonJobs[a.selectedSeason.ID]
+	}
+
+	if len(jobs) == 0 {
+		return nil
+	}
+	job := jobs[len(jobs)-1]
+	if job.Status != model.JobStatusFailed || job.SupersededBy != nil {
+		return nil
+	}
+	return &job
+}
+
+// handleRetryJobKey handles input while the retry prompt is open.
+// Anything but [y] leaves the failed job alone.
+func (a *App) handleRetryJobKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	job := *a.retryJob
+	a.retryJob = nil
+	switch msg.String() {
+	case "y":
+		return a, a.retryFailedJob(job)
+	case "ctrl+c":
+		return a, tea.Quit
+	}
+	return a, nil
+}
+
+// retryFailedJob replaces a failed job with a fresh pending one for the same
+// stage (and disc), resets the stage to pending and dispatches the new job.
+// The failed job stays in the history, marked as superseded, and its options
+// (such as the transcode profile) carry over.
+func (a *App) retryFailedJob(failed model.Job) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		// Fail fast if the dispatch target is down, before creating a job
+		target := a.config.DispatchTarget(failed.Stage.String())
+		if err := a.dispatcher.CheckTarget(ctx, target); err != nil {
+			return jobRetriedMsg{job: failed, err: err}
+		}
+
+		job := &model.Job{
+			MediaItemID: failed.MediaItemID,
+			SeasonID:    failed.SeasonID,
+			Stage:       failed.Stage,
+			Disc:        failed.Disc,
+			Status:      model.JobStatusPending,
+		}
+		if err := a.replaceJob(ctx, failed, job); err != nil {
+			return jobRetriedMsg{job: failed, err: err}
+		}
+
+		if err := a.dispatcher.Launch(ctx, a.repo, job, target); err != nil {
+			return jobRetriedMsg{job: failed, err: err}
+		}
+		err := dispatch.SetStageStatus(ctx, a.repo, job, model.StatusInProgress)
+		return jobRetriedMsg{job: failed, err: err}
+	}
+}
+
+// replaceJob creates job in place of failed in one transaction
+func (a *App) replaceJob(ctx context.Context, failed model.Job, job *model.Job) error {
+	return a.repo.WithTx(ctx, func(tx db.Repository) error {
+		active, err := dispatch.FindActiveJob(ctx, tx, job)
+		if err != nil {
+			return fmt.Errorf("failed to check for active jobs: %w", err)
+		}
+		if active != nil {
+			return fmt.Errorf("%w: %s job %d is %s", dispatch.ErrJobActive, active.Stage, active.ID, active.Status)
+		}
+
+		if err := tx.CreateJob(ctx, job); err != nil {
+			return err
+		}
+		if err := tx.SupersedeJob(ctx, failed.ID, job.ID); err != nil {
+			return err
+		}
+		opts, err := tx.GetJobOptions(ctx, failed.ID)
+		if err != nil {
+			return err
+		}
+		if len(opts) > 0 {
+			if err := tx.SetJobOptions(ctx, job.ID, opts); err != nil {
+				return err
+			}
+		}
+		return dispatch.SetStageStatus(ctx, tx, job, model.StatusPending)
+	})
+}
+
+// renderRetryJobPrompt renders the confirmation for retrying a failed job
+func (a *App) renderRetryJobPrompt() string {
+	job := a.retryJob
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Retry Failed Job"))
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("  %s job %d failed.\n", job.Stage.DisplayName(), job.ID))
+	b.WriteString(renderJobError(job))
+	b.WriteString("\n")
+	b.WriteString(fmt.Sprintf("  A new %s job will be started. Job %d stays in the history as retried.\n", job.Stage, job.ID))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("[y] Retry  [Esc] Cancel"))
+
+	return b.String()
+}
+
+// formatJobRetried returns a muted " retried as #N" suffix for a failed job
+// that has been replaced, or ""
+func formatJobRetried(job *model.Job) string {
+	if job.SupersededBy == nil {
+		return ""
+	}
+	return mutedItemStyle.Render(fmt.Sprintf(" retried as #%d", *job.SupersededBy))
+}
+
+// retryErrorStatus returns the status line for a retry that couldn't start
+// because of a race with another start or retry, or "" for other errors
+func retryErrorStatus(err error) string {
+	switch {
+	case errors.Is(err, dispatch.ErrJobActive):
+		return err.Error()
+	case errors.Is(err, db.ErrJobNotRetryable):
+		return "Job was already retried"
+	}
+	return ""
+}
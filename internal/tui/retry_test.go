@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestRetryFailedJob(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	movie := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Inception", SafeName: "Inception"}
+	if err := repo.CreateMediaItem(ctx, movie); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	for _, stage := range []model.Stage{model.StageRip, model.StageOrganize, model.StageRemux} {
+		job := &model.Job{MediaItemID: movie.ID, Stage: stage, Status: model.JobStatusCompleted}
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+	}
+	failed := &model.Job{MediaItemID: movie.ID, Stage: model.StageTranscode, Status: model.JobStatusFailed, ErrorMessage: "ffmpeg exited with status 1"}
+	if err := repo.CreateJob(ctx, failed); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	if err := repo.SetJobOptions(ctx, failed.ID, map[string]interface{}{"profile": "mobile"}); err != nil {
+		t.Fatalf("SetJobOptions() error = %v", err)
+	}
+	if err := repo.UpdateMediaItemStage(ctx, movie.ID, model.StageTranscode, model.StatusFailed); err != nil {
+		t.Fatalf("UpdateMediaItemStage() error = %v", err)
+	}
+
+	runner := &recordingRunner{}
+	app := NewApp(&config.Config{}, repo)
+	app.dispatcher.SetCommandRunner(runner)
+	app.Update(app.loadState())
+	app.currentView = ViewItemDetail
+	app.selectedItem = &app.state.Items[0]
+
+	if view := app.View(); !strings.Contains(view, "[R] Retry") || !strings.Contains(view, "ffmpeg exited with status 1") {
+		t.Errorf("item detail missing retry hint or error:\n%s", view)
+	}
+
+	// Anything but [y] cancels
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	if app.retryJob == nil || app.retryJob.ID != failed.ID {
+		t.Fatalf("[R] should prompt for the failed job")
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if app.retryJob != nil || len(runner.started) != 0 {
+		t.Fatalf("Esc should close the prompt without retrying")
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("R")})
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	_, cmd = app.Update(cmd())
+	if app.err != nil {
+		t.Fatalf("unexpected error: %v", app.err)
+	}
+	if len(runner.started) != 1 {
+		t.Fatalf("dispatched %d commands, want 1", len(runner.started))
+	}
+
+	jobs, err := repo.ListJobsForMedia(ctx, movie.ID)
+	if err != nil {
+		t.Fatalf("ListJobsForMedia() error = %v", err)
+	}
+	retry := jobs[len(jobs)-1]
+	if retry.Stage != model.StageTranscode || retry.Status != model.JobStatusPending {
+		t.Fatalf("retry job = %+v, want pending transcode", retry)
+	}
+	got, err := repo.GetJob(ctx, failed.ID)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if got.SupersededBy == nil || *got.SupersededBy != retry.ID {
+		t.Errorf("failed job superseded_by = %v, want %d", got.SupersededBy, retry.ID)
+	}
+	opts, err := repo.GetJobOptions(ctx, retry.ID)
+	if err != nil {
+		t.Fatalf("GetJobOptions() error = %v", err)
+	}
+	if opts["profile"] != "mobile" {
+		t.Errorf("retry job options = %v, want the failed job's profile", opts)
+	}
+	items, err := repo.ListActiveItems(ctx)
+	if err != nil {
+		t.Fatalf("ListActiveItems() error = %v", err)
+	}
+	if len(items) != 1 || items[0].CurrentStage != model.StageTranscode || items[0].StageStatus != model.StatusInProgress {
+		t.Errorf("items = %+v, want movie with transcode in progress", items)
+	}
+
+	// The failed job is dealt with, so there is nothing left to retry
+	app.Update(cmd())
+	app.selectedItem = &app.state.Items[0]
+	if app.failedJob() != nil {
+		t.Error("failedJob() should be nil once retried")
+	}
+}
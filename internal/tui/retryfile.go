@@ -0,0 +1,175 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/dispatch"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// transcodeFiles is the per-file view of a failed transcode job opened with
+// [T], from which single failed files are retried
+type transcodeFiles struct {
+	job    model.Job
+	files  []model.TranscodeFile
+	cursor int
+}
+
+// fileRetriedMsg is sent when a failed transcode file has been reset and its
+// job dispatched again
+type fileRetriedMsg struct {
+	file model.TranscodeFile
+	err  error
+}
+
+// openTranscodeFiles opens the file view for the failed transcode job of the
+// movie or season being viewed. It returns a status line instead when there
+// is no such job or none of its files failed.
+func (a *App) openTranscodeFiles() string {
+	job := a.failedJob()
+	if job == nil || job.Stage != model.StageTranscode {
+		return ""
+	}
+
+	files, err := a.repo.ListTranscodeFiles(context.Background(), job.ID)
+	if err != nil {
+		return fmt.Sprintf("Failed to load transcode files: %v", err)
+	}
+
+	view := &transcodeFiles{job: *job, files: files, cursor: -1}
+	for i, f := range files {
+		if f.Status == model.TranscodeFileStatusFailed {
+			view.cursor = i
+			break
+		}
+	}
+	if view.cursor < 0 {
+		return "No failed files to retry, use [R] to retry the job"
+	}
+	a.transcodeFiles = view
+	return ""
+}
+
+// handleTranscodeFilesKey handles input while the file view is open. The
+// cursor moves between failed files and Enter retries the selected one.
+func (a *App) handleTranscodeFilesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	view := a.transcodeFiles
+	switch msg.String() {
+	case "up", "k":
+		view.moveCursor(-1)
+	case "down", "j":
+		view.moveCursor(1)
+	case "enter":
+		a.transcodeFiles = nil
+		return a, a.retryTranscodeFile(view.job, view.files[view.cursor])
+	case "esc":
+		a.transcodeFiles = nil
+	case "ctrl+c":
+		return a, tea.Quit
+	}
+	return a, nil
+}
+
+// moveCursor moves the cursor to the next failed file in direction dir,
+// staying put if there is none
+func (v *transcodeFiles) moveCursor(dir int) {
+	for i := v.cursor + dir; i >= 0 && i < len(v.files); i += dir {
+		if v.files[i].Status == model.TranscodeFileStatusFailed {
+			v.cursor = i
+			return
+		}
+	}
+}
+
+// retryTranscodeFile resets a failed file to pending and dispatches its job
+// again. The transcoder skips files that completed or failed before, so only
+// this file (and any never reached) is encoded.
+func (a *App) retryTranscodeFile(job model.Job, file model.TranscodeFile) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+
+		// Fail fast if the dispatch target is down, before touching the job
+		target := a.config.DispatchTarget(job.Stage.String())
+		if err := a.dispatcher.CheckTarget(ctx, target); err != nil {
+			return fileRetriedMsg{file: file, err: err}
+		}
+
+		if err := a.reopenJob(ctx, &job, file); err != nil {
+			return fileRetriedMsg{file: file, err: err}
+		}
+
+		if err := a.dispatcher.Launch(ctx, a.repo, &job, target); err != nil {
+			return fileRetriedMsg{file: file, err: err}
+		}
+		err := dispatch.SetStageStatus(ctx, a.repo, &job, model.StatusInProgress)
+		return fileRetriedMsg{file: file, err: err}
+	}
+}
+
+// reopenJob sets the failed job back to pending with file reset, in one
+// transaction
+func (a *App) reopenJob(ctx context.Context, job *model.Job, file model.TranscodeFile) error {
+	return a.repo.WithTx(ctx, func(tx db.Repository) error {
+		active, err := dispatch.FindActiveJob(ctx, tx, job)
+		if err != nil {
+			return fmt.Errorf("failed to check for active jobs: %w", err)
+		}
+		if active != nil {
+			return fmt.Errorf("%w: %s job %d is %s", dispatch.ErrJobActive, active.Stage, active.ID, active.Status)
+		}
+
+		if err := tx.ReopenJob(ctx, job.ID); err != nil {
+			return err
+		}
+		if err := tx.UpdateTranscodeFileStatus(ctx, file.ID, model.TranscodeFileStatusPending, ""); err != nil {
+			return err
+		}
+		job.Status = model.JobStatusPending
+		return dispatch.SetStageStatus(ctx, tx, job, model.StatusPending)
+	})
+}
+
+// renderTranscodeFiles renders the files of the failed transcode job
+func (a *App) renderTranscodeFiles() string {
+	view := a.transcodeFiles
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Transcode Files"))
+	b.WriteString("\n\n")
+	b.WriteString(fmt.Sprintf("  Transcode job %d failed.\n\n", view.job.ID))
+
+	for i, f := range view.files {
+		statusIcon := "○"
+		switch f.Status {
+		case model.TranscodeFileStatusCompleted:
+			statusIcon = "✓"
+		case model.TranscodeFileStatusSkipped:
+			statusIcon = "-"
+		case model.TranscodeFileStatusFailed:
+			statusIcon = "✗"
+		}
+		line := fmt.Sprintf("%s %s", statusIcon, filepath.Base(f.RelativePath))
+		if i == view.cursor {
+			b.WriteString(selectedItemStyle.Render("> " + line))
+		} else {
+			b.WriteString(normalItemStyle.Render("  " + line))
+		}
+		b.WriteString("\n")
+		if f.ErrorMessage != "" {
+			b.WriteString(errorStyle.Render("      " + f.ErrorMessage))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n")
+	b.WriteString("  Only the selected file is encoded again; completed files are kept.\n")
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("[↑/↓] Select  [Enter] Retry File  [Esc] Cancel"))
+
+	return b.String()
+}
@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestRetryTranscodeFile(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "The Office", SafeName: "The_Office"}
+	if err := repo.CreateMediaItem(ctx, show); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	season := &model.Season{ItemID: show.ID, Number: 1, CurrentStage: model.StageTranscode, StageStatus: model.StatusFailed}
+	if err := repo.CreateSeason(ctx, season); err != nil {
+		t.Fatalf("CreateSeason() error = %v", err)
+	}
+	failed := &model.Job{MediaItemID: show.ID, SeasonID: &season.ID, Stage: model.StageTranscode, Status: model.JobStatusFailed, ErrorMessage: "2 of 3 files failed"}
+	if err := repo.CreateJob(ctx, failed); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+	files := map[string]*model.TranscodeFile{
+		"e01": {JobID: failed.ID, RelativePath: "_episodes/S01E01.mkv", Status: model.TranscodeFileStatusCompleted},
+		"e02": {JobID: failed.ID, RelativePath: "_episodes/S01E02.mkv", Status: model.TranscodeFileStatusFailed},
+		"e03": {JobID: failed.ID, RelativePath: "_episodes/S01E03.mkv", Status: model.TranscodeFileStatusFailed},
+	}
+	for _, key := range []string{"e01", "e02", "e03"} {
+		if err := repo.CreateTranscodeFile(ctx, files[key]); err != nil {
+			t.Fatalf("CreateTranscodeFile() error = %v", err)
+		}
+		if files[key].Status == model.TranscodeFileStatusFailed {
+			if err := repo.UpdateTranscodeFileStatus(ctx, files[key].ID, model.TranscodeFileStatusFailed, "Invalid data found"); err != nil {
+				t.Fatalf("UpdateTranscodeFileStatus() error = %v", err)
+			}
+		}
+	}
+
+	runner := &recordingRunner{}
+	app := NewApp(&config.Config{}, repo)
+	app.dispatcher.SetCommandRunner(runner)
+	app.Update(app.loadState())
+	app.currentView = ViewSeasonDetail
+	app.selectedItem = &app.state.Items[0]
+	app.selectedSeason = &app.state.Items[0].Seasons[0]
+
+	if view := app.View(); !strings.Contains(view, "[T] Failed Files") {
+		t.Errorf("season detail missing failed files hint:\n%s", view)
+	}
+
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if app.transcodeFiles == nil {
+		t.Fatalf("[T] should open the file view")
+	}
+	if view := app.View(); !strings.Contains(view, "> ✗ S01E02.mkv") || !strings.Contains(view, "Invalid data found") {
+		t.Errorf("file view should select the first failed file:\n%s", view)
+	}
+
+	// The cursor only stops on failed files
+	app.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if got := app.transcodeFiles.files[app.transcodeFiles.cursor].ID; got != files["e02"].ID {
+		t.Errorf("cursor moved to file %d, want it to stay on S01E02", got)
+	}
+	app.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if app.transcodeFiles != nil {
+		t.Error("Enter should close the file view")
+	}
+	_, cmd = app.Update(cmd())
+	if app.err != nil {
+		t.Fatalf("unexpected error: %v", app.err)
+	}
+	if len(runner.started) != 1 {
+		t.Fatalf("dispatched %d commands, want 1", len(runner.started))
+	}
+	if !strings.Contains(app.statusMsg, "Retrying S01E03.mkv") {
+		t.Errorf("statusMsg = %q", app.statusMsg)
+	}
+
+	// The same job runs again, with only the selected file reset
+	job, err := repo.GetJob(ctx, failed.ID)
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+	if job.Status != model.JobStatusPending || job.ErrorMessage != "" {
+		t.Errorf("job = %+v, want pending without error", job)
+	}
+	want := map[int64]model.TranscodeFileStatus{
+		files["e01"].ID: model.TranscodeFileStatusCompleted,
+		files["e02"].ID: model.TranscodeFileStatusFailed,
+		files["e03"].ID: model.TranscodeFileStatusPending,
+	}
+	stored, err := repo.ListTranscodeFiles(ctx, failed.ID)
+	if err != nil {
+		t.Fatalf("ListTranscodeFiles() error = %v", err)
+	}
+	for _, f := range stored {
+		if f.Status != want[f.ID] {
+			t.Errorf("%s status = %s, want %s", f.RelativePath, f.Status, want[f.ID])
+		}
+	}
+	got, err := repo.GetSeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetSeason() error = %v", err)
+	}
+	if got.StageStatus != model.StatusInProgress {
+		t.Errorf("season status = %s, want in_progress", got.StageStatus)
+	}
+
+	// With the job running again there is nothing to retry
+	app.Update(cmd())
+	app.selectedSeason = &app.state.Items[0].Seasons[0]
+	app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("T")})
+	if app.transcodeFiles != nil {
+		t.Error("[T] should do nothing while the job is pending")
+	}
+}
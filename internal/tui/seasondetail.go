@@ -24,6 +24,13 @@ func (a *App) renderSeasonDetail() string {
 	b.WriteString(titleStyle.Render(title))
 	b.WriteString("\n\n")
 
+	// Disc progress at a glance (Disc 1 ✓  Disc 2 ◐)
+	if summary := renderDiscSummary(a.state.DiscProgress[season.ID]); summary != "" {
+		b.WriteString("  ")
+		b.WriteString(summary)
+		b.WriteString("\n")
+	}
+
 	// Current State
 	b.WriteString(sectionHeaderStyle.Render("CURRENT STATE"))
 	b.WriteString("\n")
@@ -40,10 +47,23 @@ func (a *App) renderSeasonDetail() string {
 
 	b.WriteString(fmt.Sprintf("  Stage: %s\n", season.CurrentStage.DisplayName()))
 	b.WriteString(fmt.Sprintf("  Status: %s\n", stageStyle.Render(string(season.StageStatus))))
+	if season.ExpectedEpisodes > 0 {
+		b.WriteString(fmt.Sprintf("  Episodes: %d expected\n", season.ExpectedEpisodes))
+	}
+	if season.ExpectedDiscs > 0 {
+		ripped := rippedDiscs(a.state.DiscProgress[season.ID])
+		b.WriteString(fmt.Sprintf("  Discs: %d/%d ripped\n", ripped, season.ExpectedDiscs))
+	}
+	b.WriteString(renderLibraryPath(item.DisplayPath(a.state.SeasonJobs[season.ID])))
 	b.WriteString("\n")
 
 	// Next Action
-	if season.StageStatus == model.StatusCompleted && season.CurrentStage != model.StagePublish {
+	if season.StageStatus == model.StatusCompleted && season.CurrentStage == model.StageRip && item.RipMode == model.RipModeBackup {
+		b.WriteString(sectionHeaderStyle.Render("NEXT ACTION"))
+		b.WriteString("\n")
+		b.WriteString("  None, the disc backups are the final output, or [s] to back up another disc\n")
+		b.WriteString("\n")
+	} else if season.StageStatus == model.StatusCompleted && season.CurrentStage != model.StagePublish {
 		b.WriteString(sectionHeaderStyle.Render("NEXT ACTION"))
 		b.WriteString("\n")
 		nextStage := season.CurrentStage.NextStage()
@@ -62,6 +82,11 @@ func (a *App) renderSeasonDetail() string {
 		b.WriteString("\n")
 	}
 
+	if line := a.renderTranscodeProfile(season.CurrentStage, season.StageStatus); line != "" {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
 	// Rip Jobs (for TV seasons, multiple discs)
 	jobs := a.state.SeasonJobs[season.ID]
 	ripJobs := filterJobsByStage(jobs, model.StageRip)
@@ -82,7 +107,8 @@ func (a *App) renderSeasonDetail() string {
 			if job.Disc != nil {
 				discLabel = fmt.Sprintf("Disc %d", *job.Disc)
 			}
-			b.WriteString(fmt.Sprintf("  %s %s\n", statusIcon, discLabel))
+			b.WriteString(fmt.Sprintf("  %s %s%s%s%s%s\n", statusIcon, discLabel, formatJobStart(&job), formatJobDuration(&job), formatJobWorker(&job), formatJobRetried(&job)))
+			b.WriteString(renderJobError(&job))
 		}
 		b.WriteString("\n")
 	}
@@ -102,26 +128,65 @@ func (a *App) renderSeasonDetail() string {
 			case model.JobStatusFailed:
 				statusIcon = "✗"
 			}
-			b.WriteString(fmt.Sprintf("  %s %s\n", statusIcon, job.Stage.DisplayName()))
+			b.WriteString(fmt.Sprintf("  %s %s%s%s%s%s\n", statusIcon, job.Stage.DisplayName(), formatJobStart(&job), formatJobDuration(&job), formatJobWorker(&job), formatJobRetried(&job)))
+			b.WriteString(renderJobError(&job))
 		}
 		b.WriteString("\n")
 	}
 
 	// Help - show different options based on state
 	var helpText string
-	if season.CurrentStage == model.StageRip && season.StageStatus == model.StatusCompleted {
-		helpText = "[o] Organize  [s] Rip Another Disc  [r] Refresh  [Esc] Back  [q] Quit"
+	if season.CurrentStage == model.StageRip && season.StageStatus == model.StatusCompleted && item.RipMode == model.RipModeBackup {
+		helpText = "[s] Rip Another Disc  [p] Path  [r] Refresh  [Esc] Back  [q] Quit"
+	} else if season.CurrentStage == model.StageRip && season.StageStatus == model.StatusCompleted {
+		helpText = "[o] Organize  [s] Rip Another Disc  [e] Set Episodes  [p] Path  [r] Refresh  [Esc] Back  [q] Quit"
 	} else if season.CurrentStage == model.StageRip && len(ripJobs) > 0 {
 		// Has rip jobs, can mark done or add more
-		helpText = "[s] Rip Disc  [d] Done Ripping  [r] Refresh  [Esc] Back  [q] Quit"
+		helpText = "[s] Rip Disc  [d] Done Ripping  [e] Set Episodes  [D] Set Discs  [p] Path  [r] Refresh  [Esc] Back  [q] Quit"
 	} else {
-		helpText = "[s] Start Rip  [r] Refresh  [Esc] Back  [q] Quit"
+		helpText = "[s] Start Rip  [e] Set Episodes  [D] Set Discs  [p] Path  [r] Refresh  [Esc] Back  [q] Quit"
+	}
+	if a.stuckJob() == nil {
+		helpText = "[m] Override Stage  " + helpText
+	}
+	if a.stuckJob() != nil {
+		helpText = "[f] Force Finish  " + helpText
+	}
+	if job := a.failedJob(); job != nil {
+		if job.Stage == model.StageTranscode {
+			helpText = "[T] Failed Files  " + helpText
+		}
+		helpText = "[R] Retry  [B] Report  " + helpText
+	}
+	if a.statusMsg != "" {
+		b.WriteString(a.statusMsg)
+		b.WriteString("\n")
 	}
 	b.WriteString(helpStyle.Render(helpText))
 
 	return b.String()
 }
 
+// renderDiscSummary renders one status icon per disc on a single line
+func renderDiscSummary(progress []model.DiscProgress) string {
+	parts := make([]string, 0, len(progress))
+	for _, p := range progress {
+		var icon string
+		switch p.Status {
+		case model.JobStatusCompleted:
+			icon = lipgloss.NewStyle().Foreground(colorSuccess).Render("✓")
+		case model.JobStatusInProgress:
+			icon = lipgloss.NewStyle().Foreground(colorWarning).Render("◐")
+		case model.JobStatusFailed:
+			icon = lipgloss.NewStyle().Foreground(colorError).Render("✗")
+		default:
+			icon = mutedItemStyle.Render("○")
+		}
+		parts = append(parts, fmt.Sprintf("Disc %d %s", p.Disc, icon))
+	}
+	return strings.Join(parts, "  ")
+}
+
 // filterJobsByStage returns jobs for a specific stage
 func filterJobsByStage(jobs []model.Job, stage model.Stage) []model.Job {
 	var result []model.Job
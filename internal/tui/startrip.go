@@ -3,11 +3,10 @@ package tui
 import (
 	"context"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/dispatch"
 	"github.com/cuivienor/media-pipeline/internal/model"
 )
 
@@ -16,103 +15,98 @@ type ripStartedMsg struct {
 	err error
 }
 
-// startRipForItem starts a rip job for an existing media item
+// startRipForItem starts a rip job for an existing media item. Once a movie
+// has a completed rip, each further rip is numbered as its next disc.
 func (a *App) startRipForItem(item *model.MediaItem) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
 
-		// Create pending job
+		jobs, err := a.repo.ListJobsForMedia(ctx, item.ID)
+		if err != nil {
+			return ripStartedMsg{err: fmt.Errorf("failed to list jobs: %w", err)}
+		}
+
+		// Fail fast if the dispatch target is down, before creating a job
+		target := a.config.DispatchTarget("rip")
+		if err := a.dispatcher.CheckTarget(ctx, target); err != nil {
+			return ripStartedMsg{err: err}
+		}
+
+		// Create pending job, moving the item to in_progress (if not
+		// already), so a movie isn't organized while another of its discs is
+		// ripping
 		job := &model.Job{
 			MediaItemID: item.ID,
 			Stage:       model.StageRip,
 			Status:      model.JobStatusPending,
+			Disc:        dispatch.NextRipDisc(jobs, nil),
 		}
-		if err := a.repo.CreateJob(ctx, job); err != nil {
+		markStage := item.StageStatus == model.StatusPending || item.StageStatus == model.StatusCompleted
+		if err := dispatch.CreateJob(ctx, a.repo, job, markStage); err != nil {
 			return ripStartedMsg{err: err}
 		}
 
-		// Update item status to in_progress (if not already)
-		if item.StageStatus == model.StatusPending {
-			if err := a.repo.UpdateMediaItemStage(ctx, item.ID, model.StageRip, model.StatusInProgress); err != nil {
-				return ripStartedMsg{err: fmt.Errorf("failed to update item status: %w", err)}
-			}
-		}
-
-		// Build command args
-		args := []string{
-			"-job-id", fmt.Sprintf("%d", job.ID),
-			"-db", a.config.DatabasePath(),
-		}
-
-		// Find ripper binary - look in same directory as current executable
-		ripperPath := "ripper"
-		if exe, err := os.Executable(); err == nil {
-			siblingPath := filepath.Join(filepath.Dir(exe), "ripper")
-			if _, err := os.Stat(siblingPath); err == nil {
-				ripperPath = siblingPath
-			}
-		}
-
-		target := a.config.DispatchTarget("rip")
-		if target == "" {
-			// Local execution
-			cmd := exec.Command(ripperPath, args...)
-			if err := cmd.Start(); err != nil {
-				return ripStartedMsg{err: fmt.Errorf("failed to start ripper: %w", err)}
-			}
-		} else {
-			// SSH dispatch - assume ripper is in PATH on remote
-			sshArgs := append([]string{target, "ripper"}, args...)
-			cmd := exec.Command("ssh", sshArgs...)
-			if err := cmd.Start(); err != nil {
-				return ripStartedMsg{err: fmt.Errorf("failed to SSH dispatch ripper: %w", err)}
-			}
+		// Launch ripper locally or via SSH dispatch target
+		if err := a.dispatcher.Launch(ctx, a.repo, job, target); err != nil {
+			return ripStartedMsg{err: err}
 		}
 
 		return ripStartedMsg{err: nil}
 	}
 }
 
-// seasonAddedMsg is sent when a season is added
-type seasonAddedMsg struct {
-	err error
+// nextSeasonNumber returns the number after the TV show's highest season
+func nextSeasonNumber(item *model.MediaItem) int {
+	next := 1
+	for _, s := range item.Seasons {
+		if s.Number >= next {
+			next = s.Number + 1
+		}
+	}
+	return next
 }
 
-// addSeasonToItem adds the next season number to a TV show
-func (a *App) addSeasonToItem(item *model.MediaItem) tea.Cmd {
+// addSeasonToItem adds the next season number to a TV show, expecting
+// discs discs (0 if unknown)
+func (a *App) addSeasonToItem(item *model.MediaItem, discs int) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
-
-		// Determine next season number
-		nextSeasonNum := 1
-		for _, s := range item.Seasons {
-			if s.Number >= nextSeasonNum {
-				nextSeasonNum = s.Number + 1
-			}
-		}
-
-		// Create new season
 		season := &model.Season{
-			ItemID:       item.ID,
-			Number:       nextSeasonNum,
-			CurrentStage: model.StageRip,
-			StageStatus:  model.StatusPending,
+			ItemID:        item.ID,
+			Number:        nextSeasonNumber(item),
+			CurrentStage:  model.StageRip,
+			StageStatus:   model.StatusPending,
+			ExpectedDiscs: discs,
 		}
-		if err := a.repo.CreateSeason(ctx, season); err != nil {
-			return seasonAddedMsg{err: err}
+		if err := a.repo.CreateSeason(context.Background(), season); err != nil {
+			return itemUpdatedMsg{err: err}
 		}
-
-		return seasonAddedMsg{err: nil}
+		return itemUpdatedMsg{}
 	}
 }
 
-// seasonRipsDoneMsg is sent when ripping is marked done for a season
+// seasonRipsDoneMsg is sent when ripping is marked done for a season, or
+// with a warning when fewer discs than expected have been ripped
 type seasonRipsDoneMsg struct {
-	err error
+	seasonID int64
+	warning  string
+	err      error
+}
+
+// rippedDiscs counts the discs with a completed rip
+func rippedDiscs(progress []model.DiscProgress) int {
+	done := make(map[int]bool)
+	for _, p := range progress {
+		if p.Status == model.JobStatusCompleted {
+			done[p.Disc] = true
+		}
+	}
+	return len(done)
 }
 
-// markSeasonRipsDone marks all rip jobs as complete and updates season status
-func (a *App) markSeasonRipsDone(item *model.MediaItem, season *model.Season) tea.Cmd {
+// markSeasonRipsDone marks all rip jobs as complete and updates season status.
+// Unless force is set, it only warns while fewer discs than the season
+// expects have been ripped.
+func (a *App) markSeasonRipsDone(item *model.MediaItem, season *model.Season, force bool) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
 
@@ -137,9 +131,48 @@ func (a *App) markSeasonRipsDone(item *model.MediaItem, season *model.Season) te
 			return seasonRipsDoneMsg{err: fmt.Errorf("no completed rip jobs for this season")}
 		}
 
-		// Update season status to completed (for rip stage)
-		if err := a.repo.UpdateSeasonStage(ctx, season.ID, model.StageRip, model.StatusCompleted); err != nil {
-			return seasonRipsDoneMsg{err: fmt.Errorf("failed to update season status: %w", err)}
+		if season.ExpectedDiscs > 0 && !force {
+			progress, err := a.repo.GetDiscProgress(ctx, item.ID)
+			if err != nil {
+				return seasonRipsDoneMsg{err: err}
+			}
+			var seasonProgress []model.DiscProgress
+			for _, p := range progress {
+				if p.SeasonID != nil && *p.SeasonID == season.ID {
+					seasonProgress = append(seasonProgress, p)
+				}
+			}
+			if ripped := rippedDiscs(seasonProgress); ripped < season.ExpectedDiscs {
+				return seasonRipsDoneMsg{
+					seasonID: season.ID,
+					warning: fmt.Sprintf("Only %d of %d discs ripped, press [d] again to mark ripping done anyway",
+						ripped, season.ExpectedDiscs),
+				}
+			}
+		}
+
+		// Update season status to completed (for rip stage). A backup's rip
+		// is its only stage, so the show is done once all its seasons are.
+		err = a.repo.WithTx(ctx, func(tx db.Repository) error {
+			if err := tx.UpdateSeasonStage(ctx, season.ID, model.StageRip, model.StatusCompleted); err != nil {
+				return fmt.Errorf("failed to update season status: %w", err)
+			}
+			if item.RipMode != model.RipModeBackup {
+				return nil
+			}
+			seasons, err := tx.ListSeasonsForItem(ctx, item.ID)
+			if err != nil {
+				return fmt.Errorf("failed to list seasons: %w", err)
+			}
+			for _, s := range seasons {
+				if s.StageStatus != model.StatusCompleted {
+					return nil
+				}
+			}
+			return tx.UpdateMediaItemStatus(ctx, item.ID, model.ItemStatusCompleted)
+		})
+		if err != nil {
+			return seasonRipsDoneMsg{err: err}
 		}
 
 		return seasonRipsDoneMsg{err: nil}
@@ -152,70 +185,36 @@ func (a *App) startRipForSeason(item *model.MediaItem, season *model.Season) tea
 	return func() tea.Msg {
 		ctx := context.Background()
 
-		// Determine next disc number by counting existing rip jobs for this season
+		// Determine next disc number from existing rip jobs for this season
 		jobs, err := a.repo.ListJobsForMedia(ctx, item.ID)
 		if err != nil {
 			return ripStartedMsg{err: fmt.Errorf("failed to list jobs: %w", err)}
 		}
 
-		// Count rip jobs for this season
-		discNum := 1
-		for _, job := range jobs {
-			if job.Stage == model.StageRip && job.SeasonID != nil && *job.SeasonID == season.ID {
-				if job.Disc != nil && *job.Disc >= discNum {
-					discNum = *job.Disc + 1
-				}
-			}
+		// Fail fast if the dispatch target is down, before creating a job
+		target := a.config.DispatchTarget("rip")
+		if err := a.dispatcher.CheckTarget(ctx, target); err != nil {
+			return ripStartedMsg{err: err}
 		}
 
-		// Create pending job with season and disc info
+		// Create pending job with season and disc info, moving the season to
+		// in_progress (if not already), including a season whose ripping was
+		// marked done, so it can be marked done again
 		job := &model.Job{
 			MediaItemID: item.ID,
 			SeasonID:    &season.ID,
 			Stage:       model.StageRip,
 			Status:      model.JobStatusPending,
-			Disc:        &discNum,
+			Disc:        dispatch.NextRipDisc(jobs, &season.ID),
 		}
-		if err := a.repo.CreateJob(ctx, job); err != nil {
+		markStage := season.StageStatus == model.StatusPending || season.StageStatus == model.StatusCompleted
+		if err := dispatch.CreateJob(ctx, a.repo, job, markStage); err != nil {
 			return ripStartedMsg{err: err}
 		}
 
-		// Update season status to in_progress (if not already)
-		if season.StageStatus == model.StatusPending {
-			if err := a.repo.UpdateSeasonStage(ctx, season.ID, model.StageRip, model.StatusInProgress); err != nil {
-				return ripStartedMsg{err: fmt.Errorf("failed to update season status: %w", err)}
-			}
-		}
-
-		// Build command args
-		args := []string{
-			"-job-id", fmt.Sprintf("%d", job.ID),
-			"-db", a.config.DatabasePath(),
-		}
-
-		// Find ripper binary - look in same directory as current executable
-		ripperPath := "ripper"
-		if exe, err := os.Executable(); err == nil {
-			siblingPath := filepath.Join(filepath.Dir(exe), "ripper")
-			if _, err := os.Stat(siblingPath); err == nil {
-				ripperPath = siblingPath
-			}
-		}
-
-		target := a.config.DispatchTarget("rip")
-		if target == "" {
-			// Local execution
-			cmd := exec.Command(ripperPath, args...)
-			if err := cmd.Start(); err != nil {
-				return ripStartedMsg{err: fmt.Errorf("failed to start ripper: %w", err)}
-			}
-		} else {
-			// SSH dispatch - assume ripper is in PATH on remote
-			sshArgs := append([]string{target, "ripper"}, args...)
-			cmd := exec.Command("ssh", sshArgs...)
-			if err := cmd.Start(); err != nil {
-				return ripStartedMsg{err: fmt.Errorf("failed to SSH dispatch ripper: %w", err)}
-			}
+		// Launch ripper locally or via SSH dispatch target
+		if err := a.dispatcher.Launch(ctx, a.repo, job, target); err != nil {
+			return ripStartedMsg{err: err}
 		}
 
 		return ripStartedMsg{err: nil}
@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+func TestMarkSeasonRipsDone_ExpectedDiscs(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Test Show", SafeName: "Test_Show"}
+	if err := repo.CreateMediaItem(ctx, show); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	season := &model.Season{
+		ItemID:        show.ID,
+		Number:        1,
+		CurrentStage:  model.StageRip,
+		StageStatus:   model.StatusInProgress,
+		ExpectedDiscs: 3,
+	}
+	if err := repo.CreateSeason(ctx, season); err != nil {
+		t.Fatalf("CreateSeason() error = %v", err)
+	}
+	for disc, status := range map[int]model.JobStatus{1: model.JobStatusCompleted, 2: model.JobStatusFailed} {
+		job := &model.Job{MediaItemID: show.ID, SeasonID: &season.ID, Stage: model.StageRip, Status: status, Disc: &disc}
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+	}
+
+	app := NewApp(&config.Config{}, repo)
+	app.Update(app.loadState())
+	app.currentView = ViewSeasonDetail
+	app.selectedItem = &app.state.Items[0]
+	app.selectedSeason = &app.selectedItem.Seasons[0]
+
+	if view := app.View(); !strings.Contains(view, "Discs: 1/3 ripped") {
+		t.Errorf("season detail missing disc count:\n%s", view)
+	}
+
+	press := func(key string) {
+		t.Helper()
+		_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		if cmd != nil {
+			app.Update(cmd())
+		}
+	}
+	seasonStatus := func() model.Status {
+		t.Helper()
+		loaded, err := repo.GetSeason(ctx, season.ID)
+		if err != nil {
+			t.Fatalf("GetSeason() error = %v", err)
+		}
+		return loaded.StageStatus
+	}
+
+	press("d")
+	if !strings.Contains(app.statusMsg, "Only 1 of 3 discs ripped") {
+		t.Errorf("statusMsg = %q, want a warning about missing discs", app.statusMsg)
+	}
+	if got := seasonStatus(); got != model.StatusInProgress {
+		t.Fatalf("season status = %s after the warning, want it unchanged", got)
+	}
+
+	// Another key in between drops the warning
+	press("p")
+	press("d")
+	if got := seasonStatus(); got != model.StatusInProgress {
+		t.Fatalf("season status = %s, want [d] to warn again after another key", got)
+	}
+
+	press("d")
+	if got := seasonStatus(); got != model.StatusCompleted {
+		t.Errorf("season status = %s, want completed after confirming", got)
+	}
+}
+
+func TestMarkSeasonRipsDone_BackupCompletesShow(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Test Show", SafeName: "Test_Show", RipMode: model.RipModeBackup}
+	if err := repo.CreateMediaItem(ctx, show); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	season := &model.Season{ItemID: show.ID, Number: 1, CurrentStage: model.StageRip, StageStatus: model.StatusInProgress}
+	if err := repo.CreateSeason(ctx, season); err != nil {
+		t.Fatalf("CreateSeason() error = %v", err)
+	}
+	disc := 1
+	job := &model.Job{MediaItemID: show.ID, SeasonID: &season.ID, Stage: model.StageRip, Status: model.JobStatusCompleted, Disc: &disc}
+	if err := repo.CreateJob(ctx, job); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	app := NewApp(&config.Config{}, repo)
+	app.Update(app.loadState())
+	app.currentView = ViewSeasonDetail
+	app.selectedItem = &app.state.Items[0]
+	app.selectedSeason = &app.selectedItem.Seasons[0]
+
+	_, cmd := app.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if cmd == nil {
+		t.Fatal("[d] should mark ripping done")
+	}
+	app.Update(cmd())
+
+	loaded, err := repo.GetMediaItem(ctx, show.ID)
+	if err != nil {
+		t.Fatalf("GetMediaItem() error = %v", err)
+	}
+	if loaded.ItemStatus != model.ItemStatusCompleted {
+		t.Errorf("item status = %s, want completed once the backup's only season is done", loaded.ItemStatus)
+	}
+	loadedSeason, err := repo.GetSeason(ctx, season.ID)
+	if err != nil {
+		t.Fatalf("GetSeason() error = %v", err)
+	}
+	if loadedSeason.StageStatus != model.StatusCompleted {
+		t.Errorf("season status = %s, want completed", loadedSeason.StageStatus)
+	}
+
+	// Completed items drop out of the list, but the detail view stays open
+	loaded.Seasons = []model.Season{*loadedSeason}
+	app.selectedItem = loaded
+	app.selectedSeason = &loaded.Seasons[0]
+	if view := app.View(); !strings.Contains(view, "[s] Rip Another Disc") {
+		t.Errorf("backup season should still offer another disc:\n%s", view)
+	}
+}
@@ -2,12 +2,11 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
-	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cuivienor/media-pipeline/internal/dispatch"
 	"github.com/cuivienor/media-pipeline/internal/model"
 )
 
@@ -17,6 +16,42 @@ type stageStartedMsg struct {
 	err   error
 }
 
+// bulkStageStartedMsg is sent when a batch of stage jobs has been dispatched
+type bulkStageStartedMsg struct {
+	started int
+	skipped int
+	err     error
+}
+
+// startReadyStages starts the next stage for every item that needs action.
+// Items whose next stage is organize are skipped since organizing is manual.
+func (a *App) startReadyStages() tea.Cmd {
+	if a.state == nil {
+		return nil
+	}
+	items := a.state.ItemsNeedingAction()
+
+	return func() tea.Msg {
+		var result bulkStageStartedMsg
+		var errs []error
+		for i := range items {
+			item := &items[i]
+			next := item.CurrentStage.NextStage()
+			if next == model.StageOrganize {
+				result.skipped++
+				continue
+			}
+			if msg, ok := a.startStageForItem(item, next)().(stageStartedMsg); ok && msg.err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", item.Name, msg.err))
+				continue
+			}
+			result.started++
+		}
+		result.err = errors.Join(errs...)
+		return result
+	}
+}
+
 // startStageForItem starts a stage job for a movie
 func (a *App) startStageForItem(item *model.MediaItem, stage model.Stage) tea.Cmd {
 	return func() tea.Msg {
@@ -28,52 +63,25 @@ func (a *App) startStageForItem(item *model.MediaItem, stage model.Stage) tea.Cm
 			return a.startRipForItem(item)()
 		}
 
-		// Create pending job
+		// Fail fast if the dispatch target is down, before creating a job
+		target := a.config.DispatchTarget(stage.String())
+		if err := a.dispatcher.CheckTarget(ctx, target); err != nil {
+			return stageStartedMsg{stage: stage, err: err}
+		}
+
+		// Create pending job, moving the item to the stage
 		job := &model.Job{
 			MediaItemID: item.ID,
 			Stage:       stage,
 			Status:      model.JobStatusPending,
 		}
-		if err := a.repo.CreateJob(ctx, job); err != nil {
-			return stageStartedMsg{stage: stage, err: fmt.Errorf("failed to create job: %w", err)}
+		if err := dispatch.CreateJobWithOptions(ctx, a.repo, job, true, a.transcodeProfileOptions(job)); err != nil {
+			return stageStartedMsg{stage: stage, err: err}
 		}
 
-		// Update item stage and status
-		if err := a.repo.UpdateMediaItemStage(ctx, item.ID, stage, model.StatusInProgress); err != nil {
-			return stageStartedMsg{stage: stage, err: fmt.Errorf("failed to update item stage: %w", err)}
-		}
-
-		// Find the binary for this stage
-		binaryName := stage.String()
-		binaryPath := binaryName
-		if exe, err := os.Executable(); err == nil {
-			siblingPath := filepath.Join(filepath.Dir(exe), binaryName)
-			if _, err := os.Stat(siblingPath); err == nil {
-				binaryPath = siblingPath
-			}
-		}
-
-		// Build command args
-		args := []string{
-			"-job-id", fmt.Sprintf("%d", job.ID),
-			"-db", a.config.DatabasePath(),
-		}
-
-		// Check for SSH dispatch target
-		target := a.config.DispatchTarget(stage.String())
-		if target == "" {
-			// Local execution
-			cmd := exec.Command(binaryPath, args...)
-			if err := cmd.Start(); err != nil {
-				return stageStartedMsg{stage: stage, err: fmt.Errorf("failed to start %s: %w", binaryName, err)}
-			}
-		} else {
-			// SSH dispatch
-			sshArgs := append([]string{target, binaryName}, args...)
-			cmd := exec.Command("ssh", sshArgs...)
-			if err := cmd.Start(); err != nil {
-				return stageStartedMsg{stage: stage, err: fmt.Errorf("failed to SSH dispatch %s: %w", binaryName, err)}
-			}
+		// Launch locally or via SSH dispatch target
+		if err := a.dispatcher.Launch(ctx, a.repo, job, target); err != nil {
+			return stageStartedMsg{stage: stage, err: err}
 		}
 
 		return stageStartedMsg{stage: stage, err: nil}
@@ -91,55 +99,75 @@ func (a *App) startStageForSeason(item *model.MediaItem, season *model.Season, s
 			return a.startRipForSeason(item, season)()
 		}
 
-		// Create pending job with season reference
+		// Fail fast if the dispatch target is down, before creating a job
+		target := a.config.DispatchTarget(stage.String())
+		if err := a.dispatcher.CheckTarget(ctx, target); err != nil {
+			return stageStartedMsg{stage: stage, err: err}
+		}
+
+		// Create pending job with season reference, moving the season to
+		// the stage
 		job := &model.Job{
 			MediaItemID: item.ID,
 			SeasonID:    &season.ID,
 			Stage:       stage,
 			Status:      model.JobStatusPending,
 		}
-		if err := a.repo.CreateJob(ctx, job); err != nil {
-			return stageStartedMsg{stage: stage, err: fmt.Errorf("failed to create job: %w", err)}
+		if err := dispatch.CreateJobWithOptions(ctx, a.repo, job, true, a.transcodeProfileOptions(job)); err != nil {
+			return stageStartedMsg{stage: stage, err: err}
 		}
 
-		// Update season stage and status
-		if err := a.repo.UpdateSeasonStage(ctx, season.ID, stage, model.StatusInProgress); err != nil {
-			return stageStartedMsg{stage: stage, err: fmt.Errorf("failed to update season stage: %w", err)}
+		// Launch locally or via SSH dispatch target
+		if err := a.dispatcher.Launch(ctx, a.repo, job, target); err != nil {
+			return stageStartedMsg{stage: stage, err: err}
 		}
 
-		// Find the binary for this stage
-		binaryName := stage.String()
-		binaryPath := binaryName
-		if exe, err := os.Executable(); err == nil {
-			siblingPath := filepath.Join(filepath.Dir(exe), binaryName)
-			if _, err := os.Stat(siblingPath); err == nil {
-				binaryPath = siblingPath
-			}
-		}
+		return stageStartedMsg{stage: stage, err: nil}
+	}
+}
 
-		// Build command args
-		args := []string{
-			"-job-id", fmt.Sprintf("%d", job.ID),
-			"-db", a.config.DatabasePath(),
-		}
+// transcodeProfileOptions returns the job options recording the chosen
+// transcode profile for a transcode job, where the transcode binary picks it
+// up, or nil
+func (a *App) transcodeProfileOptions(job *model.Job) map[string]interface{} {
+	if job.Stage != model.StageTranscode || a.transcodeProfile == "" {
+		return nil
+	}
+	return map[string]interface{}{"profile": a.transcodeProfile}
+}
 
-		// Check for SSH dispatch target
-		target := a.config.DispatchTarget(stage.String())
-		if target == "" {
-			// Local execution
-			cmd := exec.Command(binaryPath, args...)
-			if err := cmd.Start(); err != nil {
-				return stageStartedMsg{stage: stage, err: fmt.Errorf("failed to start %s: %w", binaryName, err)}
-			}
-		} else {
-			// SSH dispatch
-			sshArgs := append([]string{target, binaryName}, args...)
-			cmd := exec.Command("ssh", sshArgs...)
-			if err := cmd.Start(); err != nil {
-				return stageStartedMsg{stage: stage, err: fmt.Errorf("failed to SSH dispatch %s: %w", binaryName, err)}
+// nextTranscodeProfile cycles from current to the next profile name, with
+// the config defaults ("") before the first
+func nextTranscodeProfile(names []string, current string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	for i, name := range names {
+		if name == current {
+			if i == len(names)-1 {
+				return ""
 			}
+			return names[i+1]
 		}
+	}
+	return names[0]
+}
 
-		return stageStartedMsg{stage: stage, err: nil}
+// renderTranscodeProfile renders the profile the next transcode will use,
+// when the stage about to start is transcode and profiles are configured
+func (a *App) renderTranscodeProfile(stage model.Stage, status model.Status) string {
+	switch status {
+	case model.StatusInProgress:
+		return ""
+	case model.StatusCompleted:
+		stage = stage.NextStage()
+	}
+	if stage != model.StageTranscode || len(a.config.TranscodeProfileNames()) == 0 {
+		return ""
+	}
+	profile := a.transcodeProfile
+	if profile == "" {
+		profile = "default"
 	}
+	return fmt.Sprintf("  Transcode profile: %s %s\n", profile, mutedItemStyle.Render("([P] to change)"))
 }
@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+	"github.com/cuivienor/media-pipeline/internal/dispatch"
+	"github.com/cuivienor/media-pipeline/internal/model"
+)
+
+// recordingRunner records dispatched commands instead of running them
+type recordingRunner struct {
+	started []string
+}
+
+func (r *recordingRunner) Start(name string, args []string) error {
+	r.started = append(r.started, name)
+	return nil
+}
+
+func (r *recordingRunner) Run(ctx context.Context, name string, args []string) (string, error) {
+	return "", nil
+}
+
+func TestStartReadyStages(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	// One movie ready for transcode, one waiting on manual organize
+	items := []struct {
+		name  string
+		stage model.Stage
+	}{
+		{"Remuxed", model.StageRemux},
+		{"Ripped", model.StageRip},
+	}
+	for _, it := range items {
+		item := &model.MediaItem{Type: model.MediaTypeMovie, Name: it.name, SafeName: it.name}
+		if err := repo.CreateMediaItem(ctx, item); err != nil {
+			t.Fatalf("CreateMediaItem() error = %v", err)
+		}
+		if err := repo.UpdateMediaItemStage(ctx, item.ID, it.stage, model.StatusCompleted); err != nil {
+			t.Fatalf("UpdateMediaItemStage() error = %v", err)
+		}
+		job := &model.Job{MediaItemID: item.ID, Stage: it.stage, Status: model.JobStatusCompleted}
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
+	}
+
+	app := NewApp(&config.Config{}, repo)
+	runner := &recordingRunner{}
+	app.dispatcher.SetCommandRunner(runner)
+
+	state, err := LoadState(repo)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	app.state = state
+
+	msg, ok := app.startReadyStages()().(bulkStageStartedMsg)
+	if !ok {
+		t.Fatal("expected bulkStageStartedMsg")
+	}
+	if msg.err != nil {
+		t.Fatalf("unexpected error: %v", msg.err)
+	}
+	if msg.started != 1 || msg.skipped != 1 {
+		t.Errorf("started=%d skipped=%d, want 1 and 1", msg.started, msg.skipped)
+	}
+	if len(runner.started) != 1 || runner.started[0] != "transcode" {
+		t.Errorf("dispatched %v, want [transcode]", runner.started)
+	}
+}
+
+func TestNextTranscodeProfile(t *testing.T) {
+	names := []string{"archive", "mobile"}
+	want := []string{"archive", "mobile", "", "archive"}
+	current := ""
+	for i, w := range want {
+		current = nextTranscodeProfile(names, current)
+		if current != w {
+			t.Errorf("step %d: nextTranscodeProfile() = %q, want %q", i, current, w)
+		}
+	}
+	if got := nextTranscodeProfile(nil, "mobile"); got != "" {
+		t.Errorf("nextTranscodeProfile(no profiles) = %q, want empty", got)
+	}
+}
+
+func TestStartStageForItem_RecordsTranscodeProfile(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	item := &model.MediaItem{Type: model.MediaTypeMovie, Name: "Movie", SafeName: "Movie"}
+	if err := repo.CreateMediaItem(ctx, item); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+
+	remux := &model.Job{MediaItemID: item.ID, Stage: model.StageRemux, Status: model.JobStatusCompleted}
+	if err := repo.CreateJob(ctx, remux); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	cfg := &config.Config{Transcode: config.TranscodeConfig{
+		Profiles: map[string]config.TranscodeProfile{"mobile": {CRF: 26}},
+	}}
+	app := NewApp(cfg, repo)
+	app.dispatcher.SetCommandRunner(&recordingRunner{})
+	app.transcodeProfile = "mobile"
+
+	msg := app.startStageForItem(item, model.StageTranscode)().(stageStartedMsg)
+	if msg.err != nil {
+		t.Fatalf("startStageForItem() error = %v", msg.err)
+	}
+
+	jobs, err := repo.ListJobsForMedia(ctx, item.ID)
+	if err != nil || len(jobs) != 2 {
+		t.Fatalf("ListJobsForMedia() = %v, %v; want two jobs", jobs, err)
+	}
+	opts, err := repo.GetJobOptions(ctx, jobs[1].ID)
+	if err != nil {
+		t.Fatalf("GetJobOptions() error = %v", err)
+	}
+	if opts["profile"] != "mobile" {
+		t.Errorf("job options = %v, want profile mobile", opts)
+	}
+}
+
+func TestStageStarted_JobActiveShowsStatus(t *testing.T) {
+	app := NewApp(&config.Config{}, nil)
+
+	_, _ = app.Update(stageStartedMsg{stage: model.StageRemux, err: dispatch.ErrJobActive})
+	if app.err != nil {
+		t.Errorf("err = %v, want nil", app.err)
+	}
+	if app.statusMsg != dispatch.ErrJobActive.Error() {
+		t.Errorf("statusMsg = %q, want %q", app.statusMsg, dispatch.ErrJobActive.Error())
+	}
+}
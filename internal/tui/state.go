@@ -10,9 +10,11 @@ import (
 
 // AppState holds the current application state
 type AppState struct {
-	Items      []model.MediaItem
-	MovieJobs  map[int64][]model.Job  // itemID -> jobs (for movies)
-	SeasonJobs map[int64][]model.Job  // seasonID -> jobs (for TV seasons)
+	Items        []model.MediaItem
+	MovieJobs    map[int64][]model.Job          // itemID -> jobs (for movies)
+	SeasonJobs   map[int64][]model.Job          // seasonID -> jobs (for TV seasons)
+	DiscProgress map[int64][]model.DiscProgress // seasonID -> rip status per disc (for TV seasons)
+	StaleJobs    map[int64][]model.Job          // itemID -> in-progress jobs whose local worker has exited
 }
 
 // LoadState loads application state from the database
@@ -26,8 +28,10 @@ func LoadState(repo db.Repository) (*AppState, error) {
 
 	state := &AppState{
 		Items:      items,
-		MovieJobs:  make(map[int64][]model.Job),
-		SeasonJobs: make(map[int64][]model.Job),
+		MovieJobs:    make(map[int64][]model.Job),
+		SeasonJobs:   make(map[int64][]model.Job),
+		DiscProgress: make(map[int64][]model.DiscProgress),
+		StaleJobs:    make(map[int64][]model.Job),
 	}
 
 	// Load seasons for TV shows, jobs for all
@@ -46,6 +50,7 @@ func LoadState(repo db.Repository) (*AppState, error) {
 			if err != nil {
 				return nil, fmt.Errorf("failed to list jobs for %s: %w", item.Name, err)
 			}
+			state.addStaleJobs(item.ID, jobs)
 
 			// Assign jobs to each season
 			for _, season := range seasons {
@@ -58,6 +63,17 @@ func LoadState(repo db.Repository) (*AppState, error) {
 				}
 				state.SeasonJobs[season.ID] = seasonJobs
 			}
+
+			// Disc rip status, split by season
+			progress, err := repo.GetDiscProgress(ctx, item.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get disc progress for %s: %w", item.Name, err)
+			}
+			for _, p := range progress {
+				if p.SeasonID != nil {
+					state.DiscProgress[*p.SeasonID] = append(state.DiscProgress[*p.SeasonID], p)
+				}
+			}
 		} else {
 			// Movie - load jobs directly
 			jobs, err := repo.ListJobsForMedia(ctx, item.ID)
@@ -65,19 +81,36 @@ func LoadState(repo db.Repository) (*AppState, error) {
 				return nil, fmt.Errorf("failed to list jobs for %s: %w", item.Name, err)
 			}
 			state.MovieJobs[item.ID] = jobs
+			state.addStaleJobs(item.ID, jobs)
 
-			// Update movie's current stage from jobs
-			if len(jobs) > 0 {
-				latestJob := jobs[len(jobs)-1]
-				item.CurrentStage = latestJob.Stage
-				item.StageStatus = jobStatusToStatus(latestJob.Status)
-			}
+			// Update movie's current stage from jobs, unless it was
+			// overridden by hand since the latest job
+			item.CurrentStage, item.StageStatus = item.JobStage(jobs)
 		}
 	}
 
 	return state, nil
 }
 
+// addStaleJobs records the jobs of an item that are stuck in progress after
+// their worker on this host exited
+func (s *AppState) addStaleJobs(itemID int64, jobs []model.Job) {
+	for _, job := range jobs {
+		if isStale(&job) {
+			s.StaleJobs[itemID] = append(s.StaleJobs[itemID], job)
+		}
+	}
+}
+
+// AllStaleJobs returns the stale jobs of every item
+func (s *AppState) AllStaleJobs() []model.Job {
+	var result []model.Job
+	for _, item := range s.Items {
+		result = append(result, s.StaleJobs[item.ID]...)
+	}
+	return result
+}
+
 // ItemsNeedingAction returns movies that need user action.
 // Note: Currently only handles movies. TV show seasons are handled in the display logic
 // (Task 5 itemlist.go) by checking season.StageStatus directly.
@@ -121,18 +154,32 @@ func (s *AppState) ItemsFailed() []model.MediaItem {
 	return result
 }
 
-// jobStatusToStatus converts JobStatus to Status
-func jobStatusToStatus(js model.JobStatus) model.Status {
-	switch js {
-	case model.JobStatusCompleted:
-		return model.StatusCompleted
-	case model.JobStatusInProgress:
-		return model.StatusInProgress
-	case model.JobStatusFailed:
-		return model.StatusFailed
-	default:
-		return model.StatusPending
+// ItemsAtStage returns the items currently at stage, whatever its status.
+// A TV show is at the stage of its least advanced season.
+func (s *AppState) ItemsAtStage(stage model.Stage) []model.MediaItem {
+	var result []model.MediaItem
+	for _, item := range s.Items {
+		if itemStage(item) == stage {
+			result = append(result, item)
+		}
 	}
+	return result
+}
+
+// itemStage returns the stage a movie is at, or the earliest stage of a TV
+// show's seasons. A show without seasons has nothing ripped yet.
+func itemStage(item model.MediaItem) model.Stage {
+	if item.Type == model.MediaTypeMovie {
+		return item.CurrentStage
+	}
+	if len(item.Seasons) == 0 {
+		return model.StageRip
+	}
+	stage := model.StagePublish
+	for _, season := range item.Seasons {
+		stage = min(stage, season.CurrentStage)
+	}
+	return stage
 }
 
 // Legacy compatibility methods for old views.
@@ -149,17 +196,6 @@ func (s *AppState) CountByStage() map[model.Stage]int {
 	return counts
 }
 
-// ItemsAtStage returns all items currently at the specified stage (legacy method)
-func (s *AppState) ItemsAtStage(stage model.Stage) []model.MediaItem {
-	var result []model.MediaItem
-	for _, item := range s.Items {
-		if item.Type == model.MediaTypeMovie && item.CurrentStage == stage {
-			result = append(result, item)
-		}
-	}
-	return result
-}
-
 // ItemsReadyForNextStage returns all items that have completed their current stage (legacy method)
 func (s *AppState) ItemsReadyForNextStage() []model.MediaItem {
 	var result []model.MediaItem
@@ -2,6 +2,8 @@ package tui
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/cuivienor/media-pipeline/internal/db"
@@ -174,41 +176,70 @@ func TestLoadState_ItemsWithMultipleJobs(t *testing.T) {
 	}
 }
 
-func TestJobStatusToStatus(t *testing.T) {
-	tests := []struct {
-		name           string
-		jobStatus      model.JobStatus
-		expectedStatus model.Status
+func TestLoadState_DiscProgress(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	repo := db.NewSQLiteRepository(database)
+	ctx := context.Background()
+
+	show := &model.MediaItem{Type: model.MediaTypeTV, Name: "Test Show", SafeName: "Test_Show"}
+	if err := repo.CreateMediaItem(ctx, show); err != nil {
+		t.Fatalf("CreateMediaItem() error = %v", err)
+	}
+	s1 := &model.Season{ItemID: show.ID, Number: 1, StageStatus: model.StatusInProgress}
+	s2 := &model.Season{ItemID: show.ID, Number: 2, StageStatus: model.StatusCompleted}
+	for _, season := range []*model.Season{s1, s2} {
+		if err := repo.CreateSeason(ctx, season); err != nil {
+			t.Fatalf("CreateSeason() error = %v", err)
+		}
+	}
+
+	rips := []struct {
+		season *model.Season
+		disc   int
+		status model.JobStatus
 	}{
-		{
-			name:           "JobStatusCompleted maps to StatusCompleted",
-			jobStatus:      model.JobStatusCompleted,
-			expectedStatus: model.StatusCompleted,
-		},
-		{
-			name:           "JobStatusInProgress maps to StatusInProgress",
-			jobStatus:      model.JobStatusInProgress,
-			expectedStatus: model.StatusInProgress,
-		},
-		{
-			name:           "JobStatusFailed maps to StatusFailed",
-			jobStatus:      model.JobStatusFailed,
-			expectedStatus: model.StatusFailed,
-		},
-		{
-			name:           "JobStatusPending maps to StatusPending",
-			jobStatus:      model.JobStatusPending,
-			expectedStatus: model.StatusPending,
-		},
+		{s1, 1, model.JobStatusCompleted},
+		{s1, 2, model.JobStatusInProgress},
+		{s2, 1, model.JobStatusCompleted},
+	}
+	for _, r := range rips {
+		disc := r.disc
+		job := &model.Job{
+			MediaItemID: show.ID,
+			SeasonID:    &r.season.ID,
+			Stage:       model.StageRip,
+			Status:      r.status,
+			Disc:        &disc,
+		}
+		if err := repo.CreateJob(ctx, job); err != nil {
+			t.Fatalf("CreateJob() error = %v", err)
+		}
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := jobStatusToStatus(tt.jobStatus)
-			if result != tt.expectedStatus {
-				t.Errorf("jobStatusToStatus(%v) = %v, want %v", tt.jobStatus, result, tt.expectedStatus)
-			}
-		})
+	state, err := LoadState(repo)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+
+	discs := state.DiscProgress[s1.ID]
+	if len(discs) != 2 {
+		t.Fatalf("season 1 discs = %+v, want 2 entries", discs)
+	}
+	if discs[1].Disc != 2 || discs[1].Status != model.JobStatusInProgress {
+		t.Errorf("disc 2 = %+v, want in progress", discs[1])
+	}
+	if len(state.DiscProgress[s2.ID]) != 1 {
+		t.Errorf("season 2 discs = %+v, want 1 entry", state.DiscProgress[s2.ID])
+	}
+
+	summary := renderDiscSummary(discs)
+	if !strings.Contains(summary, "Disc 1") || !strings.Contains(summary, "Disc 2") || !strings.Contains(summary, "◐") {
+		t.Errorf("renderDiscSummary() = %q", summary)
 	}
 }
 
@@ -316,3 +347,36 @@ func TestAppState_ItemsFailed(t *testing.T) {
 		}
 	}
 }
+
+func TestAppState_ItemsAtStage(t *testing.T) {
+	state := &AppState{
+		Items: []model.MediaItem{
+			{ID: 1, Type: model.MediaTypeMovie, CurrentStage: model.StageTranscode, StageStatus: model.StatusPending},
+			{ID: 2, Type: model.MediaTypeMovie, CurrentStage: model.StageRemux, StageStatus: model.StatusCompleted},
+			{ID: 3, Type: model.MediaTypeTV, Seasons: []model.Season{
+				{ID: 30, CurrentStage: model.StagePublish},
+				{ID: 31, CurrentStage: model.StageTranscode},
+			}},
+			{ID: 4, Type: model.MediaTypeTV},
+		},
+	}
+
+	tests := []struct {
+		stage model.Stage
+		want  []int64
+	}{
+		{model.StageRip, []int64{4}},
+		{model.StageRemux, []int64{2}},
+		{model.StageTranscode, []int64{1, 3}}, // the show waits on its second season
+		{model.StagePublish, nil},
+	}
+	for _, tt := range tests {
+		var got []int64
+		for _, item := range state.ItemsAtStage(tt.stage) {
+			got = append(got, item.ID)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ItemsAtStage(%s) = %v, want %v", tt.stage, got, tt.want)
+		}
+	}
+}
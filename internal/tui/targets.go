@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// targetsCheckedMsg carries the dispatch targets that failed the startup
+// reachability check
+type targetsCheckedMsg struct {
+	errs []error
+}
+
+// checkDispatchTargets checks every configured SSH dispatch target is
+// reachable, so a host that is down shows up before a job is started on it.
+// It returns nil when every stage runs locally.
+func (a *App) checkDispatchTargets() tea.Cmd {
+	var targets []string
+	for _, stage := range slices.Sorted(maps.Keys(a.config.Dispatch)) {
+		if target := a.config.Dispatch[stage]; target != "" && !slices.Contains(targets, target) {
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	return func() tea.Msg {
+		results := make([]error, len(targets))
+		var wg sync.WaitGroup
+		for i, target := range targets {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = a.dispatcher.CheckTarget(context.Background(), target)
+			}()
+		}
+		wg.Wait()
+
+		var errs []error
+		for _, err := range results {
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return targetsCheckedMsg{errs: errs}
+	}
+}
@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cuivienor/media-pipeline/internal/config"
+	"github.com/cuivienor/media-pipeline/internal/db"
+)
+
+// downHostRunner fails SSH connections to one host and records the others
+type downHostRunner struct {
+	down    string
+	checked chan string
+}
+
+func (r *downHostRunner) Start(name string, args []string) error {
+	return nil
+}
+
+func (r *downHostRunner) Run(ctx context.Context, name string, args []string) (string, error) {
+	target := args[len(args)-2]
+	r.checked <- target
+	if target == r.down {
+		return "ssh: connect to host " + target + ": Connection refused", errors.New("exit status 255")
+	}
+	return "", nil
+}
+
+func TestCheckDispatchTargets(t *testing.T) {
+	database, err := db.OpenInMemory()
+	if err != nil {
+		t.Fatalf("OpenInMemory() error = %v", err)
+	}
+	defer database.Close()
+
+	cfg := &config.Config{Dispatch: map[string]string{
+		"rip":       "ripper-host",
+		"remux":     "",
+		"transcode": "encoder-host",
+		"publish":   "encoder-host",
+	}}
+	runner := &downHostRunner{down: "ripper-host", checked: make(chan string, 4)}
+	app := NewApp(cfg, db.NewSQLiteRepository(database))
+	app.dispatcher.SetCommandRunner(runner)
+
+	app.Update(app.loadState())
+	app.Update(app.checkDispatchTargets()())
+
+	close(runner.checked)
+	var checked []string
+	for target := range runner.checked {
+		checked = append(checked, target)
+	}
+	slices.Sort(checked)
+	if want := []string{"encoder-host", "ripper-host"}; !slices.Equal(checked, want) {
+		t.Errorf("checked %v, want each SSH target once: %v", checked, want)
+	}
+
+	if len(app.unreachableTargets) != 1 {
+		t.Fatalf("unreachableTargets = %v, want only ripper-host", app.unreachableTargets)
+	}
+	if view := app.View(); !strings.Contains(view, "dispatch target ripper-host unreachable") {
+		t.Errorf("item list missing unreachable target warning:\n%s", view)
+	}
+}
+
+func TestCheckDispatchTargets_AllLocal(t *testing.T) {
+	app := NewApp(&config.Config{Dispatch: map[string]string{"rip": ""}}, nil)
+	if cmd := app.checkDispatchTargets(); cmd != nil {
+		t.Error("expected no check when every stage runs locally")
+	}
+}
@@ -42,7 +42,7 @@ func (s *StateDir) ReadStatus() (model.Status, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to read status file: %w", err)
 	}
-	return model.Status(strings.TrimSpace(string(content))), nil
+	return model.ParseStatus(strings.TrimSpace(string(content)))
 }
 
 // ReadMetadata reads and returns the metadata from metadata.json